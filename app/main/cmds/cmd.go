@@ -24,6 +24,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/client/specs"
 	"github.com/spyroot/tcactl/pkg/io"
 	"os"
 	"path/filepath"
@@ -62,8 +64,30 @@ const (
 
 	// CliShow output spec to stdio
 	CliShow = "show"
+
+	// CliPatch spec patch file flag
+	CliPatch = "patch"
+
+	// cliPatchUsage usage of spec patch flag
+	cliPatchUsage = "Patch file applied to a spec, json patch list or merge patch object, " +
+		"lists of objects merged by name. Flag can be repeated."
 )
 
+// patchSpec applies patch files in order to a spec
+func patchSpec(spec specs.RequestSpec, files []string) (specs.RequestSpec, error) {
+
+	if len(files) == 0 {
+		return spec, nil
+	}
+
+	patched, err := specs.PatchSpecsFromFiles([]specs.RequestSpec{spec}, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	return patched[0], nil
+}
+
 // Chunks splits string to chunks,
 // it uses sep to split near chunkSize limit.
 // Each chunk is variable size. Method used to partition
@@ -234,8 +258,6 @@ Command retrieves tca entities. Tenant, Cluster, Cnfs, Cluster Catalog etc.
 Command updates or apply changes to tca entity (cnf, cnf catalog , cluster or node pool.)
 
 `),
-		Aliases: []string{"apply"},
-
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			err := ctl.Authorize()
 			if err != nil {
@@ -250,13 +272,24 @@ Command updates or apply changes to tca entity (cnf, cnf catalog , cluster or no
 		},
 	}
 
+	var (
+		createSpecFile  string
+		createIsDry     bool
+		createIsBlock   bool
+		createIsVerbose bool
+		createCluster   string
+		createPatches   []string
+	)
+
 	// create root command
 	var cmdCreate = &cobra.Command{
 		Use:   "create",
 		Short: "Command creates a new object in TCA.",
 		Long: templates.LongDesc(`
 
-Command creates a new object in TCA. For example new CNF instance, cluster , cluster template etc.`),
+Command creates a new object in TCA. For example new CNF instance, cluster , cluster template etc.
+If spec file or directory provided via -f, command creates all objects in order of specs.`),
+		Example: "\t - tcactl create -f site/edge01/",
 
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			err := ctl.Authorize()
@@ -267,11 +300,49 @@ Command creates a new object in TCA. For example new CNF instance, cluster , clu
 				ctl.GetApi().SetTrace(ctl.IsTrace)
 			}
 		},
-		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if len(createSpecFile) == 0 {
+				CheckErrLogError(cmd.Help())
+				return
+			}
+
+			if len(createCluster) == 0 {
+				createCluster = ctl.DefaultClusterName
+			}
+
+			_specs, err := specs.RequestSpecsFromPath(createSpecFile)
+			CheckErrLogError(err)
+
+			_specs, err = specs.PatchSpecsFromFiles(_specs, createPatches...)
+			CheckErrLogError(err)
+
+			for _, spec := range _specs {
+				r, err := ctl.tca.Apply(ctl.Context(), &api.ApplyApiReq{
+					Spec:         spec,
+					Cluster:      createCluster,
+					IsDryRun:     createIsDry,
+					IsBlocking:   createIsBlock,
+					IsVerbose:    createIsVerbose,
+					IsCreateOnly: true,
+				})
+				CheckErrLogError(err)
+				fmt.Printf("%s/%s %s\n", r.Kind, r.Name, r.Action)
+			}
 		},
 	}
 
+	cmdCreate.Flags().StringVarP(&createSpecFile, "file", "f", "",
+		"Spec file or directory.")
+	cmdCreate.Flags().StringVar(&createCluster, "cluster", "",
+		"Cluster name or id, required for node pool spec.")
+	cmdCreate.Flags().BoolVar(&createIsDry, CliDryRun, false,
+		"Outputs objects that would be created without creating them, specs are not validated by TCA.")
+	cmdCreate.Flags().BoolVarP(&createIsBlock, CliBlock, "b", false,
+		"Blocks and wait task to finish.")
+	cmdCreate.Flags().BoolVar(&createIsVerbose, CliProgress, true,
+		"Show task progress.")
+	cmdCreate.Flags().StringArrayVar(&createPatches, CliPatch, nil, cliPatchUsage)
+
 	// set root command
 	var cmdSet = &cobra.Command{
 		Use:   "set",
@@ -330,6 +401,20 @@ Command sets config variables (Username, Password etc) for tcactl.`),
 		ctl.CmdUpdateClusterTemplates(),
 		ctl.CmdUpdateInstance())
 
+	// update was aliased as apply, update sub-commands kept under
+	// apply so existing "tcactl apply <sub-command>" scripts still work.
+	cmdApply := ctl.CmdApply()
+	for _, c := range []*cobra.Command{
+		ctl.CmdUpdateExtension(),
+		ctl.CmdUpdatePoolNodes(),
+		ctl.CmdUpdateTenant(),
+		ctl.CmdUpdateClusterTemplates(),
+		ctl.CmdUpdateInstance()} {
+		c.Hidden = true
+		c.Deprecated = fmt.Sprintf("use \"tcactl update %s\" instead", c.Name())
+		cmdApply.AddCommand(c)
+	}
+
 	// TCA root command menu
 	ctl.RootCmd.AddCommand(
 		describe,
@@ -338,6 +423,18 @@ Command sets config variables (Username, Password etc) for tcactl.`),
 		cmdCreate,
 		cmdDelete,
 		cmdSet,
+		cmdApply,
+		ctl.CmdDiff(),
+		ctl.CmdWait(),
+		ctl.CmdRetry(),
+		ctl.CmdAbort(),
+		ctl.CmdUpgrade(),
+		ctl.CmdExport(),
+		ctl.CmdBackup(),
+		ctl.CmdRestore(),
+		ctl.CmdValidate(),
+		ctl.CmdExplain(),
+		ctl.CmdRender(),
 		ctl.CmdSaveConfig(),
 		ctl.CmdInitConfig())
 
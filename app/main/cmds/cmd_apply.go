@@ -0,0 +1,136 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"os"
+)
+
+// CmdApply - command reconciles a spec of any kind against TCA.
+// Spec kind detected from a kind field, object is created if
+// it not found, updated if it differs or left unchanged. Command
+// exits with ExitDrift status if object that TCA can't update differs.
+func (ctl *TcaCtl) CmdApply() *cobra.Command {
+
+	var (
+		specFile     string
+		clusterName  string
+		isDry        bool
+		doBlock      bool
+		showProgress bool
+		patches      []string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "apply -f [spec file or directory]",
+		Short: "Command applies a spec (cluster, node pool, template, extension etc) to TCA.",
+		Long: templates.LongDesc(`
+Command applies a spec to TCA. Spec kind detected from a kind field,
+if object not found it created, if object differs from a spec it updated,
+otherwise object left unchanged. Node pool spec requires a cluster name,
+if not provided a default cluster used.
+
+TCA can't update a cluster, cloud provider or CNF instance, if such object
+differs from a spec it reported as a drift and command exits with status 8.
+
+A file can hold several yaml documents separated by ---, if a directory
+provided all spec files applied in order of a file name.`),
+		Example: "\t - tcactl apply -f examples/template_spec_mgmt.yaml\n" +
+			"\t - tcactl apply -f examples/node_pool.yaml --cluster edge --dry\n" +
+			"\t - tcactl apply -f site/edge01/\n" +
+			"\t - tcactl apply -f base/cluster.yaml --patch site/edge01/cluster_patch.yaml",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			if len(specFile) == 0 {
+				CheckErrLogError(fmt.Errorf("provide spec file, -f flag"))
+			}
+
+			_specs, err := specs.RequestSpecsFromPath(specFile)
+			CheckErrLogError(err)
+
+			_specs, err = specs.PatchSpecsFromFiles(_specs, patches...)
+			CheckErrLogError(err)
+
+			if len(clusterName) == 0 {
+				clusterName = ctl.DefaultClusterName
+			}
+
+			hasDrift := false
+			for _, spec := range _specs {
+				r, err := ctl.tca.Apply(ctx, &api.ApplyApiReq{
+					Spec:       spec,
+					Cluster:    clusterName,
+					IsDryRun:   isDry,
+					IsBlocking: doBlock,
+					IsVerbose:  showProgress,
+				})
+				CheckErrLogError(err)
+
+				if r.Action == api.ApplyDrift {
+					hasDrift = true
+				}
+
+				if isDry {
+					fmt.Printf("%s/%s %s (dry run)\n", r.Kind, r.Name, r.Action)
+					continue
+				}
+
+				fmt.Printf("%s/%s %s\n", r.Kind, r.Name, r.Action)
+			}
+
+			if hasDrift {
+				os.Exit(ExitDrift)
+			}
+		},
+	}
+
+	_cmd.Flags().StringVarP(&specFile, "file", "f", "",
+		"Spec file or directory.")
+
+	_cmd.Flags().StringVar(&clusterName, "cluster", "",
+		"Cluster name or id, required for node pool spec.")
+
+	_cmd.Flags().BoolVar(&isDry, CliDryRun, false,
+		"Resolves an action without applying any changes.")
+
+	_cmd.Flags().BoolVarP(&doBlock, CliBlock, "b", false,
+		"Blocks and wait task to finish.")
+
+	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
+		"Show task progress.")
+
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
+	return _cmd
+}
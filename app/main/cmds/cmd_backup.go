@@ -0,0 +1,178 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"os"
+)
+
+// CmdBackup - command stores TCA configuration in a backup archive.
+func (ctl *TcaCtl) CmdBackup() *cobra.Command {
+
+	var (
+		_defaultPrinter = ctl.Printer
+		_defaultStyler  = ctl.DefaultStyle
+		fileName        string
+		skipCatalog     bool
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "backup -f [archive file]",
+		Short: "Command stores TCA configuration in a backup archive.",
+		Long: templates.LongDesc(`
+Command stores cloud providers, cluster templates, clusters, node pools,
+extensions, repositories, catalog entities including CSAR and CNF
+instance parameters in a single versioned zip archive. Passwords that
+TCA doesn't return are not stored and must be provided to restore.`),
+		Example: "\t - tcactl backup -f tca-backup.zip\n" +
+			"\t - tcactl backup -f tca-backup.zip --skip-catalog -o yaml",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			// global output type
+			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+			_defaultStyler.SetColor(ctl.IsColorTerm)
+			_defaultStyler.SetWide(ctl.IsWideTerm)
+
+			if len(fileName) == 0 {
+				CheckErrLogError(fmt.Errorf("provide archive file, -f flag"))
+			}
+
+			m, err := ctl.tca.Backup(ctx, &api.BackupApiReq{
+				FileName:    fileName,
+				SkipCatalog: skipCatalog,
+			})
+			CheckErrLogError(err)
+
+			if printer, ok := ctl.BackupManifestPrinter[_defaultPrinter]; ok {
+				printer(m, _defaultStyler)
+			}
+		},
+	}
+
+	_cmd.Flags().StringVarP(&fileName, "file", "f", "",
+		"Backup archive file.")
+
+	_cmd.Flags().BoolVar(&skipCatalog, "skip-catalog", false,
+		"Skips catalog entities and CSAR files.")
+
+	return _cmd
+}
+
+// CmdRestore - command replays a backup archive in order of dependencies.
+func (ctl *TcaCtl) CmdRestore() *cobra.Command {
+
+	var (
+		_defaultPrinter = ctl.Printer
+		_defaultStyler  = ctl.DefaultStyle
+		fileName        string
+		isDry           bool
+		showProgress    bool
+		patches         []string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "restore -f [archive file]",
+		Short: "Command restores TCA configuration from a backup archive.",
+		Long: templates.LongDesc(`
+Command restores a backup archive to an empty or different TCA.
+Catalog entities uploaded first, all other objects applied in order of
+dependencies, providers and templates first, CNF instances last.
+Object that already exists and doesn't differ left unchanged.
+Backup doesn't store passwords, cloud provider and cluster passwords
+provided by patch files. Command exits with non-zero status if
+any step failed.`),
+		Example: "\t - tcactl restore -f tca-backup.zip --patch secrets.yaml --dry\n" +
+			"\t - tcactl restore -f tca-backup.zip --patch secrets.yaml --patch lab02.yaml",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			// global output type
+			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+			_defaultStyler.SetColor(ctl.IsColorTerm)
+			_defaultStyler.SetWide(ctl.IsWideTerm)
+
+			if len(fileName) == 0 {
+				CheckErrLogError(fmt.Errorf("provide archive file, -f flag"))
+			}
+
+			var _patches []*specs.SpecPatch
+			for _, f := range patches {
+				p, err := specs.SpecPatchFromFile(f)
+				CheckErrLogError(err)
+				_patches = append(_patches, p)
+			}
+
+			results, err := ctl.tca.Restore(ctx, &api.RestoreApiReq{
+				FileName:  fileName,
+				Patches:   _patches,
+				IsDryRun:  isDry,
+				IsVerbose: showProgress,
+			})
+			if results == nil {
+				CheckErrLogError(err)
+			}
+
+			if printer, ok := ctl.SiteStepPrinter[_defaultPrinter]; ok {
+				printer(results, _defaultStyler)
+			}
+
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	_cmd.Flags().StringVarP(&fileName, "file", "f", "",
+		"Backup archive file.")
+
+	_cmd.Flags().BoolVar(&isDry, CliDryRun, false,
+		"Resolves an action for each object without applying any changes.")
+
+	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
+		"Show task progress.")
+
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
+	return _cmd
+}
@@ -19,7 +19,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
@@ -41,6 +40,8 @@ func (ctl *TcaCtl) CmdGetClouds() *cobra.Command {
 		_outputFilter   string
 		vimType         = ""
 		hcxUuid         = ""
+		filter          string
+		limit           int
 	)
 
 	// cloud - tenants
@@ -53,7 +54,8 @@ Command retrieve a list of cloud providers currently attached to Telco Cloud Aut
 Workload and Tenant Kubernetes clusters must be active in the target cloud provider.
 `),
 		Example: "\t- tcactl get clouds \n" +
-			"\t- tcactl get clouds edge",
+			"\t- tcactl get clouds edge\n" +
+			"\t- tcactl get clouds --filter \"(eq,vimType,vmware)\" --limit 10",
 		Args: cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 
@@ -70,8 +72,8 @@ Workload and Tenant Kubernetes clusters must be active in the target cloud provi
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
-			ctx := context.Background()
-			tenants, vimErr := ctl.tca.GetVimTenants(ctx)
+			ctx := ctl.Context()
+			tenants, vimErr := ctl.tca.FilterVimTenants(ctx, filter, limit)
 			CheckErrLogError(vimErr)
 
 			if len(args) > 0 {
@@ -120,6 +122,12 @@ Workload and Tenant Kubernetes clusters must be active in the target cloud provi
 	//
 	_cmd.Flags().StringVar(&hcxUuid,
 		"hcx_uuid", "", "filter by HCX UUID.")
+	//
+	_cmd.Flags().StringVar(&filter, FlagFilter, "",
+		"SOL013 filter, example --filter \"(eq,vimName,edge)\"")
+	//
+	_cmd.Flags().IntVar(&limit, FlagLimit, 0,
+		"Max number of cloud providers to return, 0 returns all.")
 
 	fields := strings.Join(api.TenantFields(), ",")
 	chunks := Chunks(fields, 50, ',')
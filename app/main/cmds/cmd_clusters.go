@@ -17,7 +17,6 @@
 package cmds
 
 import (
-	"context"
 	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -29,6 +28,7 @@ import (
 	"github.com/spyroot/tcactl/lib/api/kubernetes"
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
 	ioutils "github.com/spyroot/tcactl/pkg/io"
 	osutil "github.com/spyroot/tcactl/pkg/os"
 	"github.com/spyroot/tcactl/pkg/str"
@@ -91,7 +91,7 @@ Command returns a list kubernetes node pool for a tenant cluster, Use cluster na
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
-				ctx  = context.Background()
+				ctx  = ctl.Context()
 				pool *response.NodePool
 				err  error
 			)
@@ -145,7 +145,7 @@ func (ctl *TcaCtl) CmdGetCluster() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
-				ctx = context.Background()
+				ctx = ctl.Context()
 				cid string
 				err error
 			)
@@ -212,7 +212,7 @@ func (ctl *TcaCtl) CmdGetClustersPoolNodes() *cobra.Command {
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup("output").Value.String()
@@ -232,7 +232,7 @@ func (ctl *TcaCtl) CmdGetClustersPoolNodes() *cobra.Command {
 			clusterId, err := clusters.GetClusterId(args[0])
 			CheckErrLogError(err)
 
-			pool, err := ctl.tca.GetClusterNodePools(clusterId)
+			pool, err := ctl.tca.GetClusterNodePools(ctx, clusterId)
 			if err != nil {
 				glog.Errorf("Failed retrieve node pools %v", err)
 				return
@@ -269,7 +269,7 @@ func (ctl *TcaCtl) CmdDescClusterNodePool() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup("output").Value.String()
 
@@ -308,7 +308,7 @@ func (ctl *TcaCtl) CmdDescClusterNodePool() *cobra.Command {
 				// try to fetch all.
 				cluster, err := ctl.tca.GetCluster(ctx, clusterEntry.Value.String())
 				CheckErrLogError(err)
-				pools, err := ctl.tca.GetClusterNodePools(cluster.Id)
+				pools, err := ctl.tca.GetClusterNodePools(ctx, cluster.Id)
 				CheckErrLogError(err)
 				if _printer, ok := ctl.NodesPrinter[_defaultPrinter]; ok {
 					_printer(pools, _defaultStyler)
@@ -347,7 +347,7 @@ func (ctl *TcaCtl) CmdDescClusterNodes() *cobra.Command {
 		//Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
@@ -357,7 +357,7 @@ func (ctl *TcaCtl) CmdDescClusterNodes() *cobra.Command {
 
 			var allSpecs []response.NodesSpecs
 			for _, c := range clusters.Clusters {
-				pools, err := ctl.tca.GetClusterNodePools(c.Id)
+				pools, err := ctl.tca.GetClusterNodePools(ctx, c.Id)
 				CheckErrLogError(err)
 				for _, p := range pools.Pools {
 					pool, err := ctl.tca.GetClusterNodePool(ctx, c.Id, p.Id)
@@ -384,6 +384,8 @@ func (ctl *TcaCtl) CmdGetClustersList() *cobra.Command {
 		_defaultPrinter = ctl.Printer
 		_defaultStyler  = ctl.DefaultStyle
 		_isWide         = false
+		filter          string
+		limit           int
 	)
 
 	var _cmd = &cobra.Command{
@@ -392,25 +394,30 @@ func (ctl *TcaCtl) CmdGetClustersList() *cobra.Command {
 		Long: templates.LongDesc(
 			`Command returns kubernetes clusters or cluster information.
 Without argument it will output list.`),
+		Example: "\t - tcactl get clusters info\n" +
+			"\t - tcactl get clusters info --filter \"(eq,clusterType,WORKLOAD)\" --limit 10",
 		Run: func(cmd *cobra.Command, args []string) {
 
 			// global output type
-			ctx := context.Background()
+			ctx := ctl.Context()
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
-			clusters, err := ctl.tca.GetClusters(ctx)
-			CheckErrLogError(err)
 			// no arg get all
 			if len(args) == 0 {
+				clusters, err := ctl.tca.FilterClusters(ctx, filter, limit)
+				CheckErrLogError(err)
 				if printer, ok := ctl.ClustersPrinter[_defaultPrinter]; ok {
 					printer(clusters, _defaultStyler)
 				}
 				return
 			}
 
+			clusters, err := ctl.tca.GetClusters(ctx)
+			CheckErrLogError(err)
+
 			// either get all or lookup by name
 			cluster, err := clusters.GetClusterSpec(args[0])
 			// if cluster not found do fuzzy
@@ -432,6 +439,10 @@ Without argument it will output list.`),
 
 	_cmd.Flags().BoolVarP(&_isWide,
 		"wide", "w", true, "Wide output")
+	_cmd.Flags().StringVar(&filter, FlagFilter, "",
+		"SOL013 filter, example --filter \"(eq,clusterName,edge-test01)\"")
+	_cmd.Flags().IntVar(&limit, FlagLimit, 0,
+		"Max number of clusters to return, 0 returns all.")
 	return _cmd
 }
 
@@ -458,7 +469,7 @@ func (ctl *TcaCtl) CmdGetClustersK8SConfig() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 
 			// global output type
-			ctx := context.Background()
+			ctx := ctl.Context()
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
@@ -540,6 +551,7 @@ func (ctl *TcaCtl) CmdCreateCluster() *cobra.Command {
 		doBlock         bool
 		showProgress    bool
 		showSpec        bool
+		patches         []string
 	)
 
 	var _cmd = &cobra.Command{
@@ -559,7 +571,7 @@ that will wait for the task to finish.'
 		Aliases: []string{"cluster", "cl"},
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -582,19 +594,25 @@ that will wait for the task to finish.'
 				CheckErrLogError(fmt.Errorf("%v not found", args[0]))
 			}
 
+			patched, err := patchSpec(&spec, patches)
+			CheckErrLogError(err)
+			spec = *patched.(*specs.SpecCluster)
+
 			if showSpec {
 				err := ioutils.PrettyPrint(spec)
 				CheckErrLogError(err)
 				return
 			}
 
-			// otherwise create
-			task, err := ctl.tca.CreateClusters(ctx, &api.ClusterCreateApiReq{
+			// otherwise create, blocking call shows live task view
+			tca, done := ctl.progressApi(doBlock && showProgress && !isDry)
+			task, err := tca.CreateClusters(ctx, &api.ClusterCreateApiReq{
 				Spec:          &spec,
 				IsBlocking:    doBlock,
 				IsDryRun:      isDry,
 				IsVerbose:     showProgress,
 				IsFixConflict: true})
+			done()
 			CheckErrLogError(err)
 
 			if task != nil {
@@ -626,6 +644,8 @@ that will wait for the task to finish.'
 	_cmd.Flags().BoolVar(&showSpec, CliShow, false,
 		"Show spec only.")
 
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
 	return _cmd
 }
 
@@ -649,7 +669,7 @@ func (ctl *TcaCtl) CmdDeleteCluster() *cobra.Command {
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type, and terminal wide or not
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -705,7 +725,7 @@ Command returns currently running task on a particular cluster.`),
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type, and terminal wide or not
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -732,23 +752,35 @@ func (ctl *TcaCtl) CmdDescribeTask() *cobra.Command {
 	var (
 		_defaultPrinter = ctl.Printer
 		_defaultStyler  = ctl.DefaultStyle
+		watch           bool
 	)
 
 	var _cmd = &cobra.Command{
 		Use:     "task [task_id]",
+		Aliases: []string{"tasks"},
 		Short:   "Command return current running task list.",
-		Long:    `Command return current running task list.`,
-		Example: "- tcactl desc task 9411f70f-d24d-4842-ab56-b7214d",
-		Args:    cobra.MinimumNArgs(1),
+		Long: `Command return current running task list.
+With --watch command shows live task steps until task finish.`,
+		Example: "- tcactl desc task 9411f70f-d24d-4842-ab56-b7214d\n" +
+			"- tcactl desc tasks 9411f70f-d24d-4842-ab56-b7214d --watch",
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type, and terminal wide or not
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
+			if watch {
+				tca, done := ctl.progressApi(true)
+				err := tca.WaitTask(ctx, &models.TcaTask{Id: args[0]}, api.TaskStateSuccess)
+				done()
+				CheckErrLogError(err)
+				return
+			}
+
 			task, err := ctl.tca.GetCurrentClusterTask(ctx, args[0])
 			CheckErrLogError(err)
 			if _printer, ok := ctl.TaskClusterPrinter[_defaultPrinter]; ok {
@@ -757,5 +789,8 @@ func (ctl *TcaCtl) CmdDescribeTask() *cobra.Command {
 		},
 	}
 
+	_cmd.Flags().BoolVar(&watch, "watch", false,
+		"Watch task steps until task finish.")
+
 	return _cmd
 }
@@ -0,0 +1,110 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"os"
+)
+
+// CmdDiff - command outputs field level difference between
+// a local spec and live object in TCA. Command exits with
+// ExitDrift status if drift found.
+func (ctl *TcaCtl) CmdDiff() *cobra.Command {
+
+	var (
+		_defaultPrinter = ctl.Printer
+		_defaultStyler  = ctl.DefaultStyle
+		specFile        string
+		clusterName     string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "diff -f [spec file or directory]",
+		Short: "Command outputs difference between a spec and TCA object.",
+		Long: templates.LongDesc(`
+Command compares a cluster, node pool, cluster template or extension spec
+with a live object in TCA and outputs field level difference.
+Command exits with status 8 if object differs from a spec.`),
+		Example: "\t - tcactl diff -f examples/template_spec_mgmt.yaml\n" +
+			"\t - tcactl diff -f examples/node_pool.yaml --cluster edge",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			// global output type
+			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+			_defaultStyler.SetColor(ctl.IsColorTerm)
+			_defaultStyler.SetWide(ctl.IsWideTerm)
+
+			if len(specFile) == 0 {
+				CheckErrLogError(fmt.Errorf("provide spec file, -f flag"))
+			}
+
+			_specs, err := specs.RequestSpecsFromPath(specFile)
+			CheckErrLogError(err)
+
+			if len(clusterName) == 0 {
+				clusterName = ctl.DefaultClusterName
+			}
+
+			hasDrift := false
+			for _, spec := range _specs {
+				d, err := ctl.tca.Diff(ctx, &api.DiffApiReq{
+					Spec:    spec,
+					Cluster: clusterName,
+				})
+				CheckErrLogError(err)
+
+				if !d.HasDrift() {
+					continue
+				}
+
+				hasDrift = true
+				if printer, ok := ctl.SpecDiffPrinter[_defaultPrinter]; ok {
+					printer(d, _defaultStyler)
+				}
+			}
+
+			if hasDrift {
+				os.Exit(ExitDrift)
+			}
+		},
+	}
+
+	_cmd.Flags().StringVarP(&specFile, "file", "f", "",
+		"Spec file or directory.")
+
+	_cmd.Flags().StringVar(&clusterName, "cluster", "",
+		"Cluster name or id, required for node pool spec.")
+
+	return _cmd
+}
@@ -0,0 +1,99 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/client/specs"
+)
+
+// CmdExport - command exports a live cluster, node pool or
+// cluster template as a spec, output can be used to create a
+// copy of object or kept under version control.
+func (ctl *TcaCtl) CmdExport() *cobra.Command {
+
+	var (
+		_defaultPrinter = ctl.Printer
+		_defaultStyler  = ctl.DefaultStyle
+		clusterName     string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "export [cluster|node_pool|template|provider|extensions|instance] [name or id]",
+		Short: "Command exports TCA object as a spec.",
+		Long: templates.LongDesc(`
+Command exports a cluster, node pool, cluster template, cloud provider,
+extension or CNF instance as a spec.
+Fields that TCA manages, ids, status and nodes, removed and ids
+resolved to names.  Passwords that TCA doesn't return never exported
+and must be provided before spec used to create an object.
+Default output yaml.`),
+		Example: "\t - tcactl export cluster edge\n" +
+			"\t - tcactl export node_pool pool01 --cluster edge -o json\n" +
+			"\t - tcactl export template myworkload > template.yaml",
+		Args: cobra.ExactArgs(2),
+		ValidArgs: []string{
+			string(specs.SpecKindCluster),
+			string(specs.SpecKindNodePool),
+			string(specs.SpecKindTemplate),
+			string(specs.SpecKindProvider),
+			string(specs.SpecKindExtension),
+			string(specs.SpecKindInstance),
+		},
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			// global output type
+			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+			_defaultStyler.SetColor(ctl.IsColorTerm)
+			_defaultStyler.SetWide(ctl.IsWideTerm)
+
+			if len(clusterName) == 0 {
+				clusterName = ctl.DefaultClusterName
+			}
+
+			spec, err := ctl.tca.Export(ctx, &api.ExportApiReq{
+				Kind:    specs.SpecType(args[0]),
+				Name:    args[1],
+				Cluster: clusterName,
+			})
+			CheckErrLogError(err)
+
+			if printer, ok := ctl.SpecPrinter[_defaultPrinter]; ok {
+				printer(spec, _defaultStyler)
+			}
+		},
+	}
+
+	_cmd.Flags().StringVar(&clusterName, "cluster", "",
+		"Cluster name or id, required for node pool.")
+
+	return _cmd
+}
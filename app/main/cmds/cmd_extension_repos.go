@@ -18,7 +18,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
@@ -45,7 +44,7 @@ func (ctl *TcaCtl) CmdGetRepos() *cobra.Command {
 		Aliases: []string{"repo", "rp"},
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 			//	_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 
 			// swap filter if output filter required
@@ -95,7 +94,7 @@ Command attaches cloud provider to TCA.`),
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 			// global output type
 			//	_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup("output").Value.String()
 
@@ -144,7 +143,7 @@ Command delete extension from TCA.`),
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			//	_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup("output").Value.String()
@@ -187,7 +186,7 @@ Harbor repository to selected workload cluster.`),
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup("output").Value.String()
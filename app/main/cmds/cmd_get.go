@@ -18,7 +18,6 @@
 package cmds
 
 import (
-	"context"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 )
@@ -38,7 +37,7 @@ func (ctl *TcaCtl) CmdGetExtensions() *cobra.Command {
 		Long:  `Command retrieves CNF/VNF VDU information, The default output format tabular for detail output -o json`,
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 			//
@@ -18,7 +18,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
@@ -85,7 +84,7 @@ func (ctl *TcaCtl) PrintRunningInstances() error {
 	)
 
 	// rest call
-	genericRespond, err = ctl.tca.GetVnflcm("")
+	genericRespond, err = ctl.tca.GetVnflcm(ctl.Context(), "")
 	if err != nil {
 		return err
 	}
@@ -115,6 +114,7 @@ func (ctl *TcaCtl) CmdGetInstances() *cobra.Command {
 		_defaultFilter string
 		_instanceID    string
 		_outputFilter  string
+		_limit         int
 	)
 
 	var cmdCnfInstance = &cobra.Command{
@@ -122,7 +122,8 @@ func (ctl *TcaCtl) CmdGetInstances() *cobra.Command {
 		Short: "Command returns cnf instance or all instances",
 		Long:  templates.LongDesc(`Command returns cnf instance or all instance.`),
 
-		Example: "tcactl get cnfi -o json --filter \"{eq,id,5c11bd9c-085d-4913-a453-572457ddffe2}\"",
+		Example: "\t - tcactl get cnfi -o json --filter \"(eq,id,5c11bd9c-085d-4913-a453-572457ddffe2)\"\n" +
+			"\t - tcactl get cnfi --filter \"(cont,vnfInstanceName,test)\" --limit 10",
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
@@ -145,9 +146,9 @@ func (ctl *TcaCtl) CmdGetInstances() *cobra.Command {
 
 			// rest call
 			if len(args) > 0 {
-				genericRespond, err = ctl.tca.GetVnflcm(_defaultFilter, args[0])
+				genericRespond, err = ctl.tca.GetVnflcm(ctl.Context(), _defaultFilter, args[0])
 			} else {
-				genericRespond, err = ctl.tca.GetVnflcm(_defaultFilter)
+				genericRespond, err = ctl.tca.Instances(ctl.Context(), _defaultFilter).Collect(_limit)
 			}
 			CheckErrLogError(err)
 
@@ -176,8 +177,12 @@ func (ctl *TcaCtl) CmdGetInstances() *cobra.Command {
 
 	//
 	cmdCnfInstance.Flags().StringVar(&_defaultFilter,
-		"filter", "",
-		"filter for query example, filter by id --filter \"{eq,id,5c11bd9c-085d-4913-a453-572457ddffe2}\"")
+		FlagFilter, "",
+		"filter for query example, filter by id --filter \"(eq,id,5c11bd9c-085d-4913-a453-572457ddffe2)\"")
+
+	//
+	cmdCnfInstance.Flags().IntVar(&_limit, FlagLimit, 0,
+		"Max number of instances to return, 0 returns all.")
 
 	// output filter , filter specific value from data structure
 	cmdCnfInstance.Flags().StringVar(&_outputFilter, "ofilter", "",
@@ -248,7 +253,7 @@ and node pool.
 			newInstanceReq.AdditionalParams.DisableGrant = disableGrantFlag
 			newInstanceReq.SetAutoName(doAutoName)
 
-			instance, err := ctl.tca.CreateCnfNewInstance(context.Background(), newInstanceReq, isDryRun, doBlock)
+			instance, err := ctl.tca.CreateCnfNewInstance(ctl.Context(), newInstanceReq, isDryRun, doBlock)
 			CheckErrLogError(err)
 
 			if isDryRun == false {
@@ -330,7 +335,7 @@ The command reconfigures the existing CNF active instance.
 				return
 			}
 
-			err := ctl.tca.CnfReconfigure(context.Background(), args[0], args[1], args[2], isDryRun)
+			err := ctl.tca.CnfReconfigure(ctl.Context(), args[0], args[1], args[2], isDryRun)
 			CheckErrLogError(err)
 		},
 	}
@@ -386,7 +391,7 @@ The command terminates active CNF or VNF instances
 				return
 			}
 
-			err = ctl.tca.TerminateCnfInstance(context.Background(),
+			err = ctl.tca.TerminateCnfInstance(ctl.Context(),
 				&api.TerminateInstanceApiReq{
 					InstanceName: args[0],
 					ClusterName:  ctl.DefaultClusterName,
@@ -468,7 +473,7 @@ wait when task will finished.
 					DisableAutoRollback: disableAutoRollback,
 				},
 			}
-			err = ctl.tca.CreateCnfInstance(context.Background(), &req)
+			err = ctl.tca.CreateCnfInstance(ctl.Context(), &req)
 			CheckErrLogError(err)
 
 			fmt.Println("Successfully updated state. Scheduled in node pool", poolName.Value.String())
@@ -522,7 +527,7 @@ The instance must be in a  active state.
 		Run: func(cmd *cobra.Command, args []string) {
 
 			ctl.checkDefaultsConfig()
-			err := ctl.tca.DeleteCnfInstance(context.Background(), args[0], ctl.DefaultClusterName, _isForce)
+			err := ctl.tca.DeleteCnfInstance(ctl.Context(), args[0], ctl.DefaultClusterName, _isForce)
 			CheckErrLogError(err)
 
 			fmt.Printf("Instance '%s' delete\n", args[0])
@@ -553,7 +558,7 @@ func (ctl *TcaCtl) CmdRollbackInstances() *cobra.Command {
 
 			ctl.checkDefaultsConfig()
 
-			err := ctl.tca.RollbackCnf(context.Background(), args[0], _doBlock, true)
+			err := ctl.tca.RollbackCnf(ctl.Context(), args[0], _doBlock, true)
 			CheckErrLogError(err)
 
 			fmt.Println("Successfully rollback state.")
@@ -584,7 +589,7 @@ func (ctl *TcaCtl) CmdResetInstances() *cobra.Command {
 
 			ctl.checkDefaultsConfig()
 
-			err := ctl.tca.ResetState(context.Background(), &api.ResetInstanceApiReq{
+			err := ctl.tca.ResetState(ctl.Context(), &api.ResetInstanceApiReq{
 				InstanceName: args[0],
 				ClusterName:  ctl.DefaultClusterName,
 			})
@@ -645,7 +650,7 @@ The command moves the existing CNF instance to designate target node pool.
 				fmt.Println("Please indicate node pool, default is empty.")
 				return
 			}
-			err := ctl.tca.CnfMove(context.Background(), args[0], &api.CnfMoveReq{
+			err := ctl.tca.CnfMove(ctl.Context(), args[0], &api.CnfMoveReq{
 				NodeSelector: api.HelmNodeSelector{
 					Label: args[1],
 				},
@@ -18,7 +18,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
@@ -49,7 +48,7 @@ func (ctl *TcaCtl) CmdDescClusterNodePools() *cobra.Command {
 		Example: "tcactl describe pools",
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -100,7 +99,7 @@ func (ctl *TcaCtl) CmdGetPoolNodes() *cobra.Command {
 		Args:    cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -124,7 +123,7 @@ func (ctl *TcaCtl) CmdGetPoolNodes() *cobra.Command {
 			clusterId, err := clusters.GetClusterId(args[0])
 			CheckErrLogError(err)
 
-			pool, err := ctl.tca.GetClusterNodePools(clusterId)
+			pool, err := ctl.tca.GetClusterNodePools(ctx, clusterId)
 			if err != nil {
 				glog.Errorf("Failed retrieve node pools %v", err)
 				return
@@ -161,7 +160,7 @@ func (ctl *TcaCtl) CmdDeletePoolNodes() *cobra.Command {
 		Args:    cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -196,6 +195,7 @@ func (ctl *TcaCtl) CmdCreatePoolNodes() *cobra.Command {
 		isDry          bool
 		doBlock        bool
 		showProgress   bool
+		patches        []string
 	)
 
 	var _cmd = &cobra.Command{
@@ -209,7 +209,7 @@ Command create additional node pool on target kubernetes cluster.
 		Args:    cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			//_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -227,7 +227,10 @@ Command create additional node pool on target kubernetes cluster.
 			_spec, err := specs.SpecNodePool{}.SpecsFromFile(args[1])
 			CheckErrLogError(err)
 
-			spec, ok := (*_spec).(*specs.SpecNodePool)
+			patched, err := patchSpec(*_spec, patches)
+			CheckErrLogError(err)
+
+			spec, ok := patched.(*specs.SpecNodePool)
 			if !ok {
 				CheckErrLogError(fmt.Errorf("invalid spec"))
 				return
@@ -238,13 +241,15 @@ Command create additional node pool on target kubernetes cluster.
 				CheckErrLogError(err)
 			}
 
-			task, err := ctl.tca.CreateNewNodePool(ctx, &api.NodePoolCreateApiReq{
+			tca, done := ctl.progressApi(doBlock && showProgress && !isDry)
+			task, err := tca.CreateNewNodePool(ctx, &api.NodePoolCreateApiReq{
 				Spec:       spec,
 				Cluster:    args[0],
 				IsDryRun:   isDry,
 				IsVerbose:  showProgress,
 				IsBlocking: doBlock,
 			})
+			done()
 
 			CheckErrLogError(err)
 			fmt.Printf("Node Pool task %v created.\n", task.OperationId)
@@ -262,6 +267,8 @@ Command create additional node pool on target kubernetes cluster.
 	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
 		"Show task progress.")
 
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
 	return _cmd
 }
 
@@ -277,6 +284,7 @@ func (ctl *TcaCtl) CmdUpdatePoolNodes() *cobra.Command {
 		isDry          bool
 		doBlock        bool
 		showProgress   bool
+		patches        []string
 	)
 
 	var _cmd = &cobra.Command{
@@ -289,7 +297,7 @@ Command update node pool for target kubernetes cluster.`),
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			//	_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
@@ -307,7 +315,10 @@ Command update node pool for target kubernetes cluster.`),
 			_spec, err := specs.SpecNodePool{}.SpecsFromFile(args[0])
 			CheckErrLogError(err)
 
-			spec, ok := (*_spec).(*specs.SpecNodePool)
+			patched, err := patchSpec(*_spec, patches)
+			CheckErrLogError(err)
+
+			spec, ok := patched.(*specs.SpecNodePool)
 			if !ok {
 				CheckErrLogError(fmt.Errorf("invalid spec"))
 				return
@@ -318,13 +329,15 @@ Command update node pool for target kubernetes cluster.`),
 				CheckErrLogError(err)
 			}
 
-			task, err := ctl.tca.UpdateNodePool(ctx, &api.NodePoolCreateApiReq{
+			tca, done := ctl.progressApi(doBlock && showProgress && !isDry)
+			task, err := tca.UpdateNodePool(ctx, &api.NodePoolCreateApiReq{
 				Spec:       spec,
 				Cluster:    args[0],
 				IsDryRun:   isDry,
 				IsVerbose:  showProgress,
 				IsBlocking: doBlock,
 			})
+			done()
 			CheckErrLogError(err)
 			fmt.Printf("Node Pool task %v created.\n", task.OperationId)
 		},
@@ -342,5 +355,7 @@ Command update node pool for target kubernetes cluster.`),
 	_cmd.Flags().BoolVarP(&showProgress, CliProgress, "u", true,
 		"Show task progress.")
 
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
 	return _cmd
 }
@@ -0,0 +1,138 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+)
+
+// operationReq returns operation request from cluster and optional pool argument
+func operationReq(args []string, operationId string) *api.OperationApiReq {
+	req := &api.OperationApiReq{
+		Cluster:     args[0],
+		OperationId: operationId,
+	}
+	if len(args) > 1 {
+		req.NodePool = args[1]
+	}
+	return req
+}
+
+// CmdRetry - command retries failed cluster or node pool operation.
+func (ctl *TcaCtl) CmdRetry() *cobra.Command {
+
+	var (
+		operationId  string
+		doBlock      bool
+		showProgress bool
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "retry [cluster name or id] [node pool name or id]",
+		Short: "Command retries failed cluster or node pool operation.",
+		Long: templates.LongDesc(`
+Command retries failed cluster or node pool operation. By default,
+last failed operation of a cluster or a node pool is retried,
+--operation retries a particular operation. --block waits until
+retried operation finish.`),
+		Example: "\t - tcactl retry edge-test01 default-pool01 --block\n" +
+			"\t - tcactl retry edge-test01 --operation 7234f273-2945-4684-be64-9262a8530182",
+		Args: cobra.RangeArgs(1, 2),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			req := operationReq(args, operationId)
+			req.IsBlocking = doBlock
+			req.IsVerbose = showProgress
+
+			tca, done := ctl.progressApi(doBlock && showProgress)
+			task, err := tca.RetryOperation(ctx, req)
+			done()
+			CheckErrLogError(err)
+
+			fmt.Printf("Operation %s retried.\n", task.OperationId)
+		},
+	}
+
+	_cmd.Flags().StringVar(&operationId, "operation", "",
+		"Operation id, default last failed operation.")
+
+	_cmd.Flags().BoolVarP(&doBlock, CliBlock, "b", false,
+		"Blocks and wait task to finish.")
+
+	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
+		"Show task progress.")
+
+	return _cmd
+}
+
+// CmdAbort - command aborts running cluster or node pool operation.
+func (ctl *TcaCtl) CmdAbort() *cobra.Command {
+
+	var (
+		operationId string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "abort [cluster name or id] [node pool name or id]",
+		Short: "Command aborts running cluster or node pool operation.",
+		Long: templates.LongDesc(`
+Command aborts running cluster or node pool operation. By default,
+last running operation of a cluster or a node pool is aborted,
+--operation aborts a particular operation.`),
+		Example: "\t - tcactl abort edge-test01 default-pool01\n" +
+			"\t - tcactl abort edge-test01 --operation 7234f273-2945-4684-be64-9262a8530182",
+		Args: cobra.RangeArgs(1, 2),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			task, err := ctl.tca.AbortOperation(ctx, operationReq(args, operationId))
+			CheckErrLogError(err)
+
+			fmt.Printf("Operation %s aborted.\n", task.OperationId)
+		},
+	}
+
+	_cmd.Flags().StringVar(&operationId, "operation", "",
+		"Operation id, default last running operation.")
+
+	return _cmd
+}
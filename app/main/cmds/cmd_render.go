@@ -0,0 +1,66 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/client/specs"
+)
+
+// CmdRender - command renders spec template with values
+// from --set, --values and environment and outputs a final spec.
+func (ctl *TcaCtl) CmdRender() *cobra.Command {
+
+	var (
+		specFile string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "render -f [spec file]",
+		Short: "Command renders spec template and outputs a final spec.",
+		Long: templates.LongDesc(`
+Command renders a spec file as Go template and outputs a final spec.
+Template values are .Values, read from --values files and --set key=value
+pairs, and .Env environment variables. Template functions env, default,
+required, quote, lower and upper. In --strict mode a missing value is
+an error, otherwise it rendered as empty string. Rendered spec
+must be a valid spec document. Command doesn't connect to TCA.`),
+		Example: "\t - tcactl render -f examples/site_template/edge_workload_cluster.yaml --values examples/values/edge01.yaml\n" +
+			"\t - tcactl render -f edge_cluster.yaml --set site.name=edge01 --set site.ip=10.241.7.222 --strict",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			if len(specFile) == 0 {
+				CheckErrLogError(fmt.Errorf("provide spec file, -f flag"))
+			}
+
+			b, err := specs.RenderSpecFile(specFile)
+			CheckErrLogError(err)
+
+			_, err = specs.RequestSpecsFromFile(specFile)
+			CheckErrLogError(err)
+
+			fmt.Print(string(b))
+		},
+	}
+
+	_cmd.Flags().StringVarP(&specFile, "file", "f", "",
+		"Spec template file.")
+
+	return _cmd
+}
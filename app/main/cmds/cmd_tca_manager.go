@@ -17,7 +17,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
@@ -74,7 +73,7 @@ Command retrieves a license consumption.
 		Args:    cobra.MinimumNArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			// global output type
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
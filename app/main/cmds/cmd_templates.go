@@ -65,7 +65,7 @@ func (ctl *TcaCtl) CmdGetClusterTemplates() *cobra.Command {
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
-			tmpl, err := ctl.tca.GetClusterTemplates()
+			tmpl, err := ctl.tca.GetClusterTemplates(ctl.Context())
 			CheckErrLogError(err)
 			if len(_templateType) > 0 {
 				_templateType = strings.ToUpper(_templateType)
@@ -105,7 +105,8 @@ func (ctl *TcaCtl) CmdCreateClusterTemplates() *cobra.Command {
 
 	var (
 		//_defaultPrinter = ctl.Printer
-		isDry = false
+		isDry   = false
+		patches []string
 	)
 	// cloud - tenants
 	var _cmd = &cobra.Command{
@@ -125,7 +126,10 @@ Command creates a cluster template from input spec.
 			_spec, err := specs.SpecClusterTemplate{}.SpecsFromFile(args[0])
 			CheckErrLogError(err)
 
-			spec, ok := (*_spec).(*specs.SpecClusterTemplate)
+			patched, err := patchSpec(*_spec, patches)
+			CheckErrLogError(err)
+
+			spec, ok := patched.(*specs.SpecClusterTemplate)
 			if !ok {
 				CheckErrLogError(fmt.Errorf("invalid spec"))
 				return
@@ -138,7 +142,7 @@ Command creates a cluster template from input spec.
 			//	return
 			//}
 
-			name, err := ctl.tca.CreateClusterTemplate(spec)
+			name, err := ctl.tca.CreateClusterTemplate(ctl.Context(), spec)
 			CheckErrLogError(err)
 			fmt.Printf("Template %v created.\n", name)
 		},
@@ -148,6 +152,8 @@ Command creates a cluster template from input spec.
 		"dry", false, "Parses template spec and validate, dry run outputs spec "+
 			"to terminal screen and format based based on -o.")
 
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
 	return _cmd
 }
 
@@ -185,10 +191,10 @@ func (ctl *TcaCtl) CmdDescribeTemplate() *cobra.Command {
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
 			var templateId = args[0]
-			t, err := ctl.tca.GetNamedClusterTemplate(templateId)
+			t, err := ctl.tca.GetNamedClusterTemplate(ctl.Context(), templateId)
 			if err != nil {
 				fmt.Println("Unknown template name or id. List of templates")
-				clusterTemplates, err := ctl.tca.GetClusterTemplates()
+				clusterTemplates, err := ctl.tca.GetClusterTemplates(ctl.Context())
 				CheckErrLogError(err)
 				for _, template := range clusterTemplates.ClusterTemplates {
 					fmt.Println("* ", template.Name)
@@ -224,7 +230,7 @@ Template command deletes a cluster template.
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			err := ctl.tca.DeleteTemplate(args[0])
+			err := ctl.tca.DeleteTemplate(ctl.Context(), args[0])
 			CheckErrLogError(err)
 
 			fmt.Printf("Template %v deleted.", args[0])
@@ -238,7 +244,10 @@ Template command deletes a cluster template.
 // from a file spec.
 func (ctl *TcaCtl) CmdUpdateClusterTemplates() *cobra.Command {
 
-	var templateId = ""
+	var (
+		templateId = ""
+		patches    []string
+	)
 
 	// delete template
 	var _cmd = &cobra.Command{
@@ -256,7 +265,10 @@ It take input spec and apply to existing cluster template.`),
 			_spec, err := specs.SpecClusterTemplate{}.SpecsFromFile(args[0])
 			CheckErrLogError(err)
 
-			spec, ok := (*_spec).(*specs.SpecClusterTemplate)
+			patched, err := patchSpec(*_spec, patches)
+			CheckErrLogError(err)
+
+			spec, ok := patched.(*specs.SpecClusterTemplate)
 			if !ok {
 				CheckErrLogError(fmt.Errorf("invalid spec"))
 				return
@@ -274,7 +286,7 @@ It take input spec and apply to existing cluster template.`),
 				spec.Id = templateId
 			}
 
-			err = ctl.tca.UpdateClusterTemplate(spec)
+			err = ctl.tca.UpdateClusterTemplate(ctl.Context(), spec)
 			CheckErrLogError(err)
 
 			fmt.Printf("Template %v Updated.", templateId)
@@ -285,5 +297,7 @@ It take input spec and apply to existing cluster template.`),
 	_cmd.Flags().StringVar(&templateId, "template_id", "",
 		"template id.")
 
+	_cmd.Flags().StringArrayVar(&patches, CliPatch, nil, cliPatchUsage)
+
 	return _cmd
 }
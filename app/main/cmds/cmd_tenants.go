@@ -18,7 +18,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
@@ -48,7 +47,7 @@ Tenant command retrieves particular cloud provider or lists all attached cloud p
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
-				ctx = context.Background()
+				ctx = ctl.Context()
 				t   *response.Tenants
 				err error
 			)
@@ -119,7 +118,7 @@ Command delete cloud provider. Note all entity must be removed.`),
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
-				ctx = context.Background()
+				ctx = ctl.Context()
 				t   *response.Tenants
 				err error
 			)
@@ -198,7 +197,7 @@ tenant command attaches cloud provider to TCA.`),
 				return
 			}
 
-			registration, err := ctl.tca.CreateTenantProvider(spec)
+			registration, err := ctl.tca.CreateTenantProvider(ctl.Context(), spec)
 			CheckErrLogError(err)
 
 			fmt.Printf("Cloud provider %s registered, cloud provider %s, location %s, %s",
@@ -231,7 +230,7 @@ Command update cloud provider details and trigger re-attach.`),
 		Run: func(cmd *cobra.Command, args []string) {
 
 			var (
-				ctx = context.Background()
+				ctx = ctl.Context()
 				t   *response.Tenants
 				err error
 			)
@@ -299,7 +298,7 @@ active CNF instance must be removed first.
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			ctx := context.Background()
+			ctx := ctl.Context()
 
 			task, err := ctl.tca.DeleteTenantCluster(ctx, args[0])
 			CheckErrLogError(err)
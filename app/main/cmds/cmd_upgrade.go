@@ -0,0 +1,161 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+)
+
+// runUpgrade runs or plans upgrade and outputs plan,
+// plan printed before an error so failed checks and steps visible.
+func (ctl *TcaCtl) runUpgrade(req *api.UpgradeApiReq, showProgress bool) {
+
+	ctx := ctl.Context()
+
+	_defaultPrinter := ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+	_defaultStyler := ctl.DefaultStyle
+	_defaultStyler.SetColor(ctl.IsColorTerm)
+	_defaultStyler.SetWide(ctl.IsWideTerm)
+
+	tca, done := ctl.progressApi(showProgress && !req.IsDryRun)
+	plan, err := tca.Upgrade(ctx, req)
+	done()
+
+	if plan != nil {
+		if printer, ok := ctl.UpgradePlanPrinter[_defaultPrinter]; ok {
+			printer(plan, _defaultStyler)
+		}
+	}
+	CheckErrLogError(err)
+}
+
+// CmdUpgrade - command upgrades kubernetes version of a cluster or node pool.
+func (ctl *TcaCtl) CmdUpgrade() *cobra.Command {
+
+	var _cmd = &cobra.Command{
+		Use:   "upgrade [cluster, pool]",
+		Short: "Command upgrades kubernetes version of a cluster or node pool.",
+		Long: templates.LongDesc(`
+Command upgrades kubernetes version of a cluster or node pool. Before
+upgrade, command runs pre-flight checks and upgrade blocked if any check
+failed. --dry outputs upgrade plan, allowed upgrade paths and
+pre-flight checks without upgrading.`),
+		Example: "\t - tcactl upgrade cluster edge-test01 --dry\n" +
+			"\t - tcactl upgrade pool edge-test01 default-pool01",
+		Args: cobra.MinimumNArgs(1),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("%s requires a subcommand", cmd.Name())
+		},
+	}
+
+	_cmd.AddCommand(ctl.CmdUpgradeCluster())
+	_cmd.AddCommand(ctl.CmdUpgradePool())
+
+	return _cmd
+}
+
+// CmdUpgradeCluster - command upgrades cluster control plane and each node pool.
+func (ctl *TcaCtl) CmdUpgradeCluster() *cobra.Command {
+
+	var (
+		req          api.UpgradeApiReq
+		showProgress bool
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "cluster [cluster name or id]",
+		Short: "Command upgrades cluster control plane and each node pool.",
+		Long: templates.LongDesc(`
+Command upgrades cluster control plane and then each node pool in turn,
+waiting for each to become active before next one. By default, cluster
+upgraded to latest allowed version with a cluster template that matches
+cluster type and target version.`),
+		Example: "\t - tcactl upgrade cluster edge-test01 --dry\n" +
+			"\t - tcactl upgrade cluster edge-test01 --version v1.21.2+vmware.1",
+		Aliases: []string{"clusters", "cl"},
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			req.Cluster = args[0]
+			req.IsVerbose = showProgress
+			ctl.runUpgrade(&req, showProgress)
+		},
+	}
+
+	_cmd.Flags().StringVar(&req.Version, "version", "",
+		"Target kubernetes version, default latest allowed version.")
+
+	_cmd.Flags().StringVar(&req.Template, "template", "",
+		"Cluster template name or id, default template with target version.")
+
+	_cmd.Flags().StringVar(&req.VmTemplate, "vm-template", "",
+		"VM template used for upgraded nodes.")
+
+	_cmd.Flags().BoolVar(&req.IsDryRun, CliDryRun, false,
+		"Output upgrade plan and pre-flight checks without upgrading.")
+
+	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
+		"Show task progress.")
+
+	return _cmd
+}
+
+// CmdUpgradePool - command upgrades node pool to cluster control plane version.
+func (ctl *TcaCtl) CmdUpgradePool() *cobra.Command {
+
+	var (
+		req          api.UpgradeApiReq
+		showProgress bool
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "pool [cluster name or id] [node pool name or id]",
+		Short: "Command upgrades node pool to cluster control plane version.",
+		Long: templates.LongDesc(`
+Command upgrades node pool to kubernetes version of cluster control plane,
+i.e. to finish cluster upgrade if a node pool upgrade failed.`),
+		Example: "\t - tcactl upgrade pool edge-test01 default-pool01 --dry",
+		Aliases: []string{"pools", "nodepool"},
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			req.Cluster = args[0]
+			req.NodePool = args[1]
+			req.IsVerbose = showProgress
+			ctl.runUpgrade(&req, showProgress)
+		},
+	}
+
+	_cmd.Flags().BoolVar(&req.IsDryRun, CliDryRun, false,
+		"Output upgrade plan and pre-flight checks without upgrading.")
+
+	_cmd.Flags().BoolVar(&showProgress, CliProgress, true,
+		"Show task progress.")
+
+	return _cmd
+}
@@ -0,0 +1,126 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"os"
+	"strings"
+)
+
+// specKindsString all spec kinds comma separated
+func specKindsString() string {
+	var kinds []string
+	for _, k := range specs.SpecKinds {
+		kinds = append(kinds, string(k))
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// CmdValidate - command validates spec files offline against
+// json schema of each spec kind. Command exits with
+// non-zero status if any error found.
+func (ctl *TcaCtl) CmdValidate() *cobra.Command {
+
+	var (
+		specFile string
+		kind     string
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "validate -f [spec file or directory]",
+		Short: "Command validates spec files without connecting to TCA.",
+		Long: templates.LongDesc(`
+Command validates spec files against json schema of a spec kind and
+reports each error with a file, line and column. Validation is offline,
+command doesn't connect to TCA. Spec without kind field validated
+as a kind provided by --kind flag.`),
+		Example: "\t - tcactl validate -f examples/template_spec_mgmt.yaml\n" +
+			"\t - tcactl validate -f examples/clusters/ --kind cluster\n" +
+			"\t - tcactl validate -f site/edge01/",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			if len(specFile) == 0 {
+				CheckErrLogError(fmt.Errorf("provide spec file, -f flag"))
+			}
+
+			errs, err := specs.ValidateSpecPath(specFile, specs.SpecType(kind))
+			CheckErrLogError(err)
+
+			for _, e := range errs {
+				fmt.Println(e)
+			}
+
+			if len(errs) > 0 {
+				os.Exit(1)
+			}
+
+			fmt.Printf("%s is valid\n", specFile)
+		},
+	}
+
+	_cmd.Flags().StringVarP(&specFile, "file", "f", "",
+		"Spec file or directory.")
+
+	_cmd.Flags().StringVar(&kind, "kind", "",
+		"Spec kind for a spec without kind field, one of "+specKindsString()+".")
+
+	return _cmd
+}
+
+// CmdExplain - command outputs documented fields of a spec kind.
+func (ctl *TcaCtl) CmdExplain() *cobra.Command {
+
+	var (
+		_defaultPrinter = ctl.Printer
+		_defaultStyler  = ctl.DefaultStyle
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "explain [kind]",
+		Short: "Command outputs fields of a spec kind.",
+		Long: templates.LongDesc(`
+Command outputs all fields of a spec kind, a field type, if field is
+mandatory and a field description. Nested fields are dotted path,
+list items are denoted by []. Json or yaml output is json schema of a kind.
+Spec kinds: ` + specKindsString() + `.`),
+		Example: "\t - tcactl explain cluster\n" +
+			"\t - tcactl explain node_pool -o json > node_pool.schema.json",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: strings.Split(specKindsString(), ", "),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			// global output type
+			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
+			_defaultStyler.SetColor(ctl.IsColorTerm)
+			_defaultStyler.SetWide(ctl.IsWideTerm)
+
+			s, err := specs.NewJsonSchema(specs.SpecType(args[0]))
+			CheckErrLogError(err)
+
+			if printer, ok := ctl.SpecSchemaPrinter[_defaultPrinter]; ok {
+				printer(s, _defaultStyler)
+			}
+		},
+	}
+
+	return _cmd
+}
@@ -17,7 +17,6 @@
 package cmds
 
 import (
-	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spyroot/tcactl/app/main/cmds/templates"
@@ -77,7 +76,7 @@ Command retrieves list of all datastores or datastores for particular path or da
 		Run: func(cmd *cobra.Command, args []string) {
 
 			// global output type
-			ctx := context.Background()
+			ctx := ctl.Context()
 			_defaultPrinter = ctl.RootCmd.PersistentFlags().Lookup(FlagOutput).Value.String()
 
 			// swap filter if output filter is required
@@ -65,7 +65,7 @@ The default output format tabular for detail output -o json`),
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
-			vnfd, err := ctl.tca.GetVdu(args[0])
+			vnfd, err := ctl.tca.GetVdu(ctl.Context(), args[0])
 			CheckErrLogError(err)
 
 			if printer, ok := ctl.VduPrinter[ctl.Printer]; ok {
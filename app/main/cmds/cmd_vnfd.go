@@ -40,6 +40,7 @@ func (ctl *TcaCtl) CmdGetPackages() *cobra.Command {
 		vnfProductNameFlag string
 		vnfdIdFlag         string
 		_outputFilter      string
+		limit              int
 
 		_defaultPrinter = ctl.Printer
 		_defaultStyler  = ctl.DefaultStyle
@@ -54,7 +55,8 @@ func (ctl *TcaCtl) CmdGetPackages() *cobra.Command {
 Command retrieves a list of CNFs or VNFs catalog entities or single element if -i id provide.`,
 
 		Example: "\t - tcactl get catalog df5f3ba2-62f1-4c47-9498-6f7e1acc35cc -o json\n" +
-			"\t - tcactl get catalog --vnfd_id nfd_1b6bed2e-6c93-4fd7-83a9-4a8d060fe728 --ofilter PID",
+			"\t - tcactl get catalog --vnfd_id nfd_1b6bed2e-6c93-4fd7-83a9-4a8d060fe728 --ofilter PID\n" +
+			"\t - tcactl get catalog --filter \"(eq,onboardingState,ONBOARDED)\" --limit 10",
 
 		Run: func(cmd *cobra.Command, args []string) {
 
@@ -75,7 +77,15 @@ Command retrieves a list of CNFs or VNFs catalog entities or single element if -
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
 
-			p, err := ctl.tca.GetVnfPkgm(filter, packageId)
+			var (
+				p   *response.VnfPackages
+				err error
+			)
+			if len(packageId) > 0 {
+				p, err = ctl.tca.GetVnfPkgm(ctl.Context(), filter, packageId)
+			} else {
+				p, err = ctl.tca.Packages(ctl.Context(), filter).Collect(limit)
+			}
 			CheckErrLogError(err)
 
 			// filter by name
@@ -113,8 +123,11 @@ Command retrieves a list of CNFs or VNFs catalog entities or single element if -
 	}
 
 	//
-	_cmd.Flags().StringVar(&filter, "filter", "",
-		"Adds filter for query to limit a scope of the query.")
+	_cmd.Flags().StringVar(&filter, FlagFilter, "",
+		"Adds SOL013 filter for query to limit a scope of the query.")
+	//
+	_cmd.Flags().IntVar(&limit, FlagLimit, 0,
+		"Max number of catalog entities to return, 0 returns all.")
 	//
 	_cmd.Flags().StringVar(&vnfProductNameFlag, "vnf_name", "",
 		"Filters by product name.")
@@ -242,7 +255,7 @@ Command allow to overwrite some of CSAR Tosca values. Check flags.
 				substitution[models.PropertyVnfmInfo] = _PropertyVnfmInfo
 			}
 
-			ok, err := ctl.tca.CreateCatalogEntity(args[0], args[1], substitution)
+			ok, err := ctl.tca.CreateCatalogEntity(ctl.Context(), args[0], args[1], substitution)
 			if err != nil {
 				glog.Errorf("Failed create new package. Error: %v", err)
 				return
@@ -332,7 +345,7 @@ Command delete a CNF or VNF package.`),
 
 			_defaultStyler.SetColor(ctl.IsColorTerm)
 			_defaultStyler.SetWide(ctl.IsWideTerm)
-			ok, err := ctl.tca.DeleteCatalogEntity(args[0])
+			ok, err := ctl.tca.DeleteCatalogEntity(ctl.Context(), args[0])
 			if err != nil {
 				glog.Errorf("Failed create new package. Error: %v", err)
 				return
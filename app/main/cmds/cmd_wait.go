@@ -0,0 +1,125 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spyroot/tcactl/app/main/cmds/templates"
+	"github.com/spyroot/tcactl/lib/api"
+	"strings"
+	"time"
+)
+
+// parseWaitTarget splits kind/name or kind name arguments
+func parseWaitTarget(args []string) (string, string, error) {
+
+	if len(args) == 2 {
+		return args[0], args[1], nil
+	}
+
+	if len(args) == 1 {
+		if i := strings.Index(args[0], "/"); i > 0 && i < len(args[0])-1 {
+			return args[0][:i], args[0][i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("expected kind/name, for example cluster/edge")
+}
+
+// CmdWait - command blocks until cluster, node pool,
+// instance or task reaches a state.
+func (ctl *TcaCtl) CmdWait() *cobra.Command {
+
+	var (
+		waitFor     string
+		clusterName string
+		timeout     time.Duration
+		interval    time.Duration
+	)
+
+	var _cmd = &cobra.Command{
+		Use:   "wait [kind/name] --for [state]",
+		Short: "Command blocks until object reaches a state.",
+		Long: templates.LongDesc(`
+Command blocks until a cluster, node pool, CNF instance or cluster task
+reaches a state. Clusters and node pools wait for ACTIVE, instances for
+INSTANTIATED, NOT_INSTANTIATED or COMPLETED LCM operation, tasks for SUCCESS.
+Command exits with non-zero status if object reached failure state,
+i.e. FAILED_TEMP or FAILED, or a timeout expired.`),
+		Example: "\t - tcactl wait cluster/edge --for=ACTIVE --timeout=30m\n" +
+			"\t - tcactl wait nodepool/edge/pool01 --for=ACTIVE\n" +
+			"\t - tcactl wait cnf/unique_name --for=INSTANTIATED\n" +
+			"\t - tcactl wait cnf/unique_name --for=COMPLETED\n" +
+			"\t - tcactl wait task/1a2b3c --for=SUCCESS",
+		Args: cobra.RangeArgs(1, 2),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			err := ctl.Authorize()
+			if err != nil {
+				CheckErrLogError(err)
+			}
+			if ctl.IsTrace {
+				ctl.GetApi().SetTrace(ctl.IsTrace)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			ctx := ctl.Context()
+
+			kind, name, err := parseWaitTarget(args)
+			CheckErrLogError(err)
+
+			if len(clusterName) == 0 {
+				clusterName = ctl.DefaultClusterName
+			}
+
+			w := *ctl.tca.Waiter()
+			if timeout > 0 {
+				w.Timeout = timeout
+			}
+			if interval > 0 {
+				w.Interval = interval
+			}
+
+			req := &api.WaitApiReq{
+				Kind:    kind,
+				Name:    name,
+				Cluster: clusterName,
+				For:     waitFor,
+			}
+			CheckErrLogError(ctl.tca.WithWaiter(&w).WaitFor(ctx, req))
+
+			fmt.Printf("%s/%s condition met\n", kind, name)
+		},
+	}
+
+	_cmd.Flags().StringVar(&waitFor, "for", "",
+		"State to wait for, default ACTIVE for cluster and node pool, "+
+			"INSTANTIATED for instance and SUCCESS for task.")
+
+	_cmd.Flags().DurationVar(&timeout, "timeout", api.DefaultWaitTimeout,
+		"Time to wait before giving up.")
+
+	_cmd.Flags().DurationVar(&interval, "interval", api.DefaultWaitInterval,
+		"Initial interval between polls.")
+
+	_cmd.Flags().StringVar(&clusterName, "cluster", "",
+		"Cluster name or id node pool belongs to.")
+
+	return _cmd
+}
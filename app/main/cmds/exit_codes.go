@@ -0,0 +1,95 @@
+// Package cmds
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package cmds
+
+import (
+	"context"
+	"errors"
+	"github.com/spyroot/tcactl/lib/api_errors"
+)
+
+// tcactl exit codes, scripts can distinguish
+// kind of failure without parsing error message.
+const (
+	// ExitOk command succeed
+	ExitOk = 0
+
+	// ExitError generic error
+	ExitError = 1
+
+	// ExitValidation spec or argument rejected by tcactl or TCA
+	ExitValidation = 2
+
+	// ExitNotFound object not found
+	ExitNotFound = 3
+
+	// ExitConflict object already exists or in conflicting state
+	ExitConflict = 4
+
+	// ExitUnauthorized authentication failed or access forbidden
+	ExitUnauthorized = 5
+
+	// ExitUnavailable TCA unavailable or rate limited request
+	ExitUnavailable = 6
+
+	// ExitServer TCA internal error
+	ExitServer = 7
+
+	// ExitDrift diff found difference between a spec and TCA object
+	ExitDrift = 8
+
+	// ExitCanceled command interrupted
+	ExitCanceled = 130
+)
+
+// ExitCode returns exit code for an error
+func ExitCode(err error) int {
+
+	switch {
+	case err == nil:
+		return ExitOk
+	case errors.Is(err, context.Canceled):
+		return ExitCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitUnavailable
+	case errors.Is(err, api_errors.ErrValidation):
+		return ExitValidation
+	case errors.Is(err, api_errors.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, api_errors.ErrConflict):
+		return ExitConflict
+	case errors.Is(err, api_errors.ErrUnauthorized),
+		errors.Is(err, api_errors.ErrForbidden):
+		return ExitUnauthorized
+	case errors.Is(err, api_errors.ErrUnavailable),
+		errors.Is(err, api_errors.ErrTooManyRequests):
+		return ExitUnavailable
+	case errors.Is(err, api_errors.ErrServer):
+		return ExitServer
+	}
+
+	return ExitError
+}
+
+// exitCode returns exit code for value passed to error checks
+func exitCode(msg interface{}) int {
+	if err, ok := msg.(error); ok {
+		return ExitCode(err)
+	}
+	return ExitError
+}
@@ -0,0 +1,50 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOk},
+		{"plain error", fmt.Errorf("failed"), ExitError},
+		{"canceled", context.Canceled, ExitCanceled},
+		{"cluster not found", &response.ClusterNotFound{ErrMsg: "edge"}, ExitNotFound},
+		{"pool not found", &response.PoolNotFound{ErrMsg: "pool"}, ExitNotFound},
+		{"tenant cloud not found", &response.TenantCloudNotFound{}, ExitNotFound},
+		{"cnf not found", &response.CnfNotFound{}, ExitNotFound},
+		{"template not found", &response.TemplateNotFound{}, ExitNotFound},
+		{"cloud provider not found", &api.CloudProviderNotFound{}, ExitNotFound},
+		{"task not found", &api.TaskNotFound{ErrMsg: "task"}, ExitNotFound},
+		{"cluster task not found", &client.TaskNotFound{}, ExitNotFound},
+		{"network not found", &models.NetworkNotFound{}, ExitNotFound},
+		{"wrapped not found", fmt.Errorf("get: %w", &response.ClusterNotFound{}), ExitNotFound},
+		{"already exists", api_errors.NewAlreadyExists("pool default-pool01"), ExitConflict},
+		{"api not found", &api_errors.ApiError{StatusCode: http.StatusNotFound}, ExitNotFound},
+		{"api bad request", &api_errors.ApiError{StatusCode: http.StatusBadRequest}, ExitValidation},
+		{"api conflict", &api_errors.ApiError{StatusCode: http.StatusConflict}, ExitConflict},
+		{"api unauthorized", &api_errors.ApiError{StatusCode: http.StatusUnauthorized}, ExitUnauthorized},
+		{"api unavailable", &api_errors.ApiError{StatusCode: http.StatusServiceUnavailable}, ExitUnavailable},
+		{"api server error", &api_errors.ApiError{StatusCode: http.StatusInternalServerError}, ExitServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
@@ -30,8 +30,10 @@ import (
 	"github.com/spyroot/tcactl/lib/client/specs"
 	"github.com/spyroot/tcactl/lib/models"
 	"github.com/spyroot/tcactl/pkg/io"
+	"github.com/spyroot/tcactl/pkg/netutils"
 	"github.com/spyroot/tcactl/pkg/vmware/vc"
 	"os"
+	"time"
 )
 
 const (
@@ -44,6 +46,9 @@ const (
 	// ConfigYamlPinter yaml printers
 	ConfigYamlPinter = "yaml"
 
+	// ConfigXmlPinter xml printers
+	ConfigXmlPinter = "xml"
+
 	//FilteredOutFilter - Filtered output printer
 	FilteredOutFilter = "filtered"
 
@@ -98,6 +103,69 @@ const (
 	// ConfigTrace dump server respond
 	ConfigTrace = "trace"
 
+	// ConfigSessionCache cache TCA session key in ~/.tcactl
+	ConfigSessionCache = "session-cache"
+
+	// ConfigSessionTTL age of TCA session after which tcactl authenticates again
+	ConfigSessionTTL = "session-ttl"
+
+	// ConfigCacheTTL time responds used to resolve names cached, 0 disables cache
+	ConfigCacheTTL = "cache-ttl"
+
+	// ConfigCachePersist persist cached responds to ~/.tcactl/cache
+	ConfigCachePersist = "cache-persist"
+
+	// ConfigRetryMax max number of attempts for idempotent requests
+	ConfigRetryMax = "retry-max"
+
+	// ConfigRetryWait initial backoff between attempts
+	ConfigRetryWait = "retry-wait"
+
+	// ConfigRetryMaxWait max backoff between attempts
+	ConfigRetryMaxWait = "retry-max-wait"
+
+	// ConfigRetryCodes http status codes request retried on
+	ConfigRetryCodes = "retry-codes"
+
+	// ConfigProxy http, https or socks5 proxy url
+	ConfigProxy = "proxy"
+
+	// ConfigCaFile PEM bundle of trusted CA certificates
+	ConfigCaFile = "ca-file"
+
+	// ConfigCertFile PEM client certificate for mutual TLS
+	ConfigCertFile = "cert-file"
+
+	// ConfigKeyFile PEM client certificate key for mutual TLS
+	ConfigKeyFile = "key-file"
+
+	// ConfigPinSha256 SHA-256 fingerprints of pinned server certificates
+	ConfigPinSha256 = "pin-sha256"
+
+	// ConfigTlsMinVersion min TLS version
+	ConfigTlsMinVersion = "tls-min-version"
+
+	// ConfigInsecure skip server certificate verification
+	ConfigInsecure = "insecure"
+
+	// ConfigRateLimit max number of TCA requests per second, 0 unlimited
+	ConfigRateLimit = "rate-limit"
+
+	// ConfigRateBurst max number of TCA requests sent at once
+	ConfigRateBurst = "rate-burst"
+
+	// ConfigMaxInFlight max number of concurrent TCA requests, 0 unlimited
+	ConfigMaxInFlight = "max-in-flight"
+
+	// ConfigRateLimitFamilies rate limit per endpoint family infra, vnflcm, vnfpkgm
+	ConfigRateLimitFamilies = "rate-limit-families"
+
+	// FlagRecord records sanitized TCA requests and responds to a cassette file
+	FlagRecord = "record"
+
+	// FlagReplay serves TCA responds from a cassette file
+	FlagReplay = "replay"
+
 	// FlagOutput - default logging level
 	FlagOutput = "output"
 
@@ -109,6 +177,21 @@ const (
 
 	//FlagCliTerm normal terminal mode no color.
 	FlagCliTerm = "term"
+
+	// FlagSpecSet spec template value key=value
+	FlagSpecSet = "set"
+
+	// FlagSpecValues spec template value file
+	FlagSpecValues = "values"
+
+	// FlagSpecStrict fail if spec template value is missing
+	FlagSpecStrict = "strict"
+
+	// FlagFilter SOL013 attribute filter i.e. (eq,vnfInstanceName,test)
+	FlagFilter = "filter"
+
+	// FlagLimit max number of entities get command returns
+	FlagLimit = "limit"
 )
 
 // VSphereAuthSpec credential and endpoint
@@ -122,6 +205,9 @@ type VSphereAuthSpec struct {
 	// Default used in case caller didn't provide actual vc
 	// config contains a list of vc not just single entry
 	Default bool `json:"default" yaml:"default"`
+	// Transport optional TLS and proxy settings of vc,
+	// overwrites settings of tcactl config.
+	Transport *netutils.TransportSpec `json:"transport,omitempty" yaml:"transport,omitempty" mapstructure:"transport"`
 }
 
 // VMwareVcSpecs map hold all VCs
@@ -141,6 +227,9 @@ type TcaCtl struct {
 	//
 	vcRest *vc.VSphereRest
 
+	// ctx root context canceled on interrupt
+	ctx context.Context
+
 	// CnfInstancePrinters cnf instance printer
 	CnfInstancePrinters map[string]func(*response.Cnfs, ui.PrinterStyle)
 
@@ -206,6 +295,24 @@ type TcaCtl struct {
 	// already executed.
 	TaskClusterPrinter map[string]func(*models.ClusterTask, ui.PrinterStyle)
 
+	// spec diff printer, output difference between a spec and TCA object
+	SpecDiffPrinter map[string]func(*api.SpecDiff, ui.PrinterStyle)
+
+	// upgrade plan printer, output upgrade plan, pre-flight checks and steps
+	UpgradePlanPrinter map[string]func(*api.UpgradePlan, ui.PrinterStyle)
+
+	// spec schema printer, output fields of a spec kind
+	SpecSchemaPrinter map[string]func(*specs.JsonSchema, ui.PrinterStyle)
+
+	// spec printer, output a spec exported from TCA, default format yaml
+	SpecPrinter map[string]func(specs.RequestSpec, ui.PrinterStyle)
+
+	// backup manifest printer, output objects stored in backup archive
+	BackupManifestPrinter map[string]func(*api.BackupManifest, ui.PrinterStyle)
+
+	// site step printer, output result of each restore step
+	SiteStepPrinter map[string]func([]*api.SiteStepResult, ui.PrinterStyle)
+
 	// global flag what output printer to use
 	Printer string
 
@@ -253,6 +360,18 @@ type TcaCtl struct {
 
 	// VsphereAuthSpecs VMware VC Authentication specs
 	VsphereAuthSpecs VMwareVcSpecs
+
+	// Transport TLS and proxy settings shared by TCA, Harbor and vCenter
+	Transport *netutils.TransportSpec
+
+	// SpecValues spec template values in key=value format
+	SpecValues []string
+
+	// SpecValueFiles spec template value files
+	SpecValueFiles []string
+
+	// IsStrictSpec fails spec rendering if template value is missing
+	IsStrictSpec bool
 }
 
 // NewTcaCtl - main abstraction for a tool
@@ -264,59 +383,70 @@ func NewTcaCtl() *TcaCtl {
 			ConfigDefaultPinter: printer.CnfInstanceTablePrinter,
 			ConfigJsonPinter:    printer.CnfInstanceJsonPrinter,
 			ConfigYamlPinter:    printer.CnfInstanceYamlPrinter,
+			ConfigXmlPinter:     printer.CnfInstanceXmlPrinter,
 		},
 		CnfInstanceExtendedPrinters: map[string]func(*response.CnfsExtended, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.CnfInstanceExtendedTablePrinter,
 			ConfigJsonPinter:    printer.CnfInstanceExtendedJsonPrinter,
 			ConfigYamlPinter:    printer.CnfInstanceExtendedYamlPrinter,
+			ConfigXmlPinter:     printer.CnfInstanceExtendedXmlPrinter,
 			FilteredOutFilter:   printer.CnfsExtendedFilteredOutput,
 		},
 		CnfPackagePrinters: map[string]func(*response.VnfPackages, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.CnfPackageTablePrinter,
 			ConfigJsonPinter:    printer.CnfPackageJsonPrinter,
 			ConfigYamlPinter:    printer.CnfPackageYamlPrinter,
+			ConfigXmlPinter:     printer.CnfPackageXmlPrinter,
 			FilteredOutFilter:   printer.VnfPackageFilteredOutput,
 		},
 		RepoPrinter: map[string]func(*response.ReposList, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.RepoTablePrinter,
 			ConfigJsonPinter:    printer.RepoJsonPrinter,
 			ConfigYamlPinter:    printer.RepoYamlPrinter,
+			ConfigXmlPinter:     printer.RepoXmlPrinter,
 		},
 		TenantsPrinter: map[string]func(*response.Tenants, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.TenantsTablePrinter,
 			ConfigJsonPinter:    printer.TenantsJsonPrinter,
 			ConfigYamlPinter:    printer.TenantsYamlPrinter,
+			ConfigXmlPinter:     printer.TenantsXmlPrinter,
 			FilteredOutFilter:   printer.TenantsFilteredOutput,
 		},
 		NodePoolPrinter: map[string]func(*response.NodePool, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.NodePoolTablePrinter,
 			ConfigJsonPinter:    printer.NodePoolJsonPrinter,
 			ConfigYamlPinter:    printer.NodePoolYamlPrinter,
+			ConfigXmlPinter:     printer.NodePoolXmlPrinter,
 		},
 		ClustersPrinter: map[string]func(*response.Clusters, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.ClusterTablePrinter,
 			ConfigJsonPinter:    printer.ClusterJsonPrinter,
 			ConfigYamlPinter:    printer.ClusterYamlPrinter,
+			ConfigXmlPinter:     printer.ClusterXmlPrinter,
 		},
 		ClusterPrinter: map[string]func(*response.ClusterSpec, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.ClusterSpecTablePrinter,
 			ConfigJsonPinter:    printer.ClusterSpecJsonPrinter,
 			ConfigYamlPinter:    printer.ClusterSpecYamlPrinter,
+			ConfigXmlPinter:     printer.ClusterSpecXmlPrinter,
 		},
 		VduPrinter: map[string]func(*response.VduPackage, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VduTablePrinter,
 			ConfigJsonPinter:    printer.VduJsonPrinter,
 			ConfigYamlPinter:    printer.VduYamlPrinter,
+			ConfigXmlPinter:     printer.VduXmlPrinter,
 		},
 		TenantQueryPrinter: map[string]func(*response.Tenants, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.TenantTabularPinter,
 			ConfigJsonPinter:    printer.TenantJsonPrinter,
 			ConfigYamlPinter:    printer.TenantYamlPrinter,
+			ConfigXmlPinter:     printer.TenantXmlPrinter,
 		},
 		NodesPrinter: map[string]func(*response.NodePool, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.NodesTablePrinter,
 			ConfigJsonPinter:    printer.NodesJsonPrinter,
 			ConfigYamlPinter:    printer.NodesYamlPrinter,
+			ConfigXmlPinter:     printer.NodesXmlPrinter,
 		},
 		PoolSpecPrinter: map[string]func(*response.NodesSpecs, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.PoolSpecTablePrinter,
@@ -327,66 +457,119 @@ func NewTcaCtl() *TcaCtl {
 			ConfigDefaultPinter: printer.TemplateSpecTablePrinter,
 			ConfigJsonPinter:    printer.TemplateSpecJsonPrinter,
 			ConfigYamlPinter:    printer.TemplateSpecYamlPrinter,
+			ConfigXmlPinter:     printer.TemplateSpecXmlPrinter,
 		},
 		// printer for array of templates
 		TemplatesPrinter: map[string]func([]response.ClusterTemplateSpec, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.TemplatesSpecTablePrinter,
 			ConfigJsonPinter:    printer.TemplatesJsonPrinter,
 			ConfigYamlPinter:    printer.TemplatesYamlPrinter,
+			ConfigXmlPinter:     printer.TemplatesXmlPrinter,
 		},
 
 		ClusterRequestPrinter: map[string]func(*specs.SpecCluster, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.ClusterRequestJsonPrinter,
 			ConfigJsonPinter:    printer.ClusterRequestJsonPrinter,
 			ConfigYamlPinter:    printer.ClusterRequestYamlPrinter,
+			ConfigXmlPinter:     printer.ClusterRequestXmlPrinter,
 		},
 
 		TenantsResponsePrinter: map[string]func(*response.TenantSpecs, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VimTablePrinter,
 			ConfigJsonPinter:    printer.TenantsResponseYamlPrinter,
 			ConfigYamlPinter:    printer.TenantsResponseYamlPrinter,
+			ConfigXmlPinter:     printer.TenantsResponseXmlPrinter,
 		},
 
 		TaskClusterPrinter: map[string]func(*models.ClusterTask, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.ClusterTaskTablePrinter,
 			ConfigJsonPinter:    printer.ClusterTaskJsonPrinter,
 			ConfigYamlPinter:    printer.ClusterTaskYamlPrinter,
+			ConfigXmlPinter:     printer.ClusterTaskXmlPrinter,
+		},
+
+		SpecDiffPrinter: map[string]func(*api.SpecDiff, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.SpecDiffPrinter,
+			ConfigJsonPinter:    printer.SpecDiffJsonPrinter,
+			ConfigYamlPinter:    printer.SpecDiffYamlPrinter,
+			ConfigXmlPinter:     printer.SpecDiffXmlPrinter,
+		},
+
+		UpgradePlanPrinter: map[string]func(*api.UpgradePlan, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.UpgradePlanTablePrinter,
+			ConfigJsonPinter:    printer.UpgradePlanJsonPrinter,
+			ConfigYamlPinter:    printer.UpgradePlanYamlPrinter,
+			ConfigXmlPinter:     printer.UpgradePlanXmlPrinter,
+		},
+
+		SpecSchemaPrinter: map[string]func(*specs.JsonSchema, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.SchemaTablePrinter,
+			ConfigJsonPinter:    printer.SchemaJsonPrinter,
+			ConfigYamlPinter:    printer.SchemaYamlPrinter,
+			ConfigXmlPinter:     printer.SchemaXmlPrinter,
+		},
+
+		SpecPrinter: map[string]func(specs.RequestSpec, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.SpecYamlPrinter,
+			ConfigJsonPinter:    printer.SpecJsonPrinter,
+			ConfigYamlPinter:    printer.SpecYamlPrinter,
+			ConfigXmlPinter:     printer.SpecXmlPrinter,
+		},
+
+		BackupManifestPrinter: map[string]func(*api.BackupManifest, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.BackupManifestTablePrinter,
+			ConfigJsonPinter:    printer.BackupManifestJsonPrinter,
+			ConfigYamlPinter:    printer.BackupManifestYamlPrinter,
+			ConfigXmlPinter:     printer.BackupManifestXmlPrinter,
+		},
+
+		SiteStepPrinter: map[string]func([]*api.SiteStepResult, ui.PrinterStyle){
+			ConfigDefaultPinter: printer.SiteStepTablePrinter,
+			ConfigJsonPinter:    printer.SiteStepJsonPrinter,
+			ConfigYamlPinter:    printer.SiteStepYamlPrinter,
+			ConfigXmlPinter:     printer.SiteStepXmlPrinter,
 		},
 
 		TcaConsumptionPrinter: map[string]func(*models.ConsumptionResp, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.ConsumptionTablePrinter,
 			ConfigJsonPinter:    printer.ConsumptionJsonPrinter,
 			ConfigYamlPinter:    printer.ConsumptionSpecYamlPrinter,
+			ConfigXmlPinter:     printer.ConsumptionSpecXmlPrinter,
 		},
 
 		VMwareClusterPrinter: map[string]func(*models.VMwareClusters, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VmwareInventoryTablePrinter,
 			ConfigJsonPinter:    printer.VmwareInventoryJsonPrinter,
 			ConfigYamlPinter:    printer.VmwareInventoryYamlPrinter,
+			ConfigXmlPinter:     printer.VmwareInventoryXmlPrinter,
 		},
 
 		VMwareDatastorePrinter: map[string]func(*models.VMwareClusters, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VmwareDatastoreTablePrinter,
 			ConfigJsonPinter:    printer.VmwareInventoryJsonPrinter,
 			ConfigYamlPinter:    printer.VmwareInventoryYamlPrinter,
+			ConfigXmlPinter:     printer.VmwareInventoryXmlPrinter,
 		},
 
 		VmwareNetworkPrinter: map[string]func(*models.CloudNetworks, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VmwareNetworkTablePrinter,
 			ConfigJsonPinter:    printer.VmwareNetworkJsonPrinter,
 			ConfigYamlPinter:    printer.VmwareNetworkYamlPrinter,
+			ConfigXmlPinter:     printer.VmwareNetworkXmlPrinter,
 		},
 
 		VmwareVmTemplatePrinter: map[string]func(*models.VcInventory, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VmwareTemplateTablePrinter,
 			ConfigJsonPinter:    printer.VmwareTemplateJsonPrinter,
 			ConfigYamlPinter:    printer.VmwareTemplateYamlPrinter,
+			ConfigXmlPinter:     printer.VmwareTemplateXmlPrinter,
 		},
 
 		VmwareResourcePrinter: map[string]func(*models.ResourcePool, ui.PrinterStyle){
 			ConfigDefaultPinter: printer.VmwareResourcePoolTablePrinter,
 			ConfigJsonPinter:    printer.VmwareResourcePoolJsonPrinter,
 			ConfigYamlPinter:    printer.VmwareResourcePoolYamlPrinter,
+			ConfigXmlPinter:     printer.VmwareResourcePoolXmlPrinter,
 		},
 
 		VsphereDatastores: map[string]func(*vc.VsphereDatastores, ui.PrinterStyle){
@@ -438,7 +621,7 @@ func NewTcaCtl() *TcaCtl {
 // Authorize authenticate and obtain a session.
 // TODO this method will go away
 func (ctl *TcaCtl) Authorize() error {
-	ok, err := ctl.tca.GetAuthorization()
+	ok, err := ctl.tca.GetAuthorization(ctl.Context())
 	if err != nil {
 		return err
 	}
@@ -449,8 +632,9 @@ func (ctl *TcaCtl) Authorize() error {
 }
 
 // vcClient returns instance of VsphereRest API.
-func vcClient(ctx context.Context, url string, username string, password string) (*vc.VSphereRest, error) {
-	c, err := vc.Connect(ctx, url, username, password)
+func vcClient(ctx context.Context, url string, username string,
+	password string, spec *netutils.TransportSpec) (*vc.VSphereRest, error) {
+	c, err := vc.ConnectWithTransport(ctx, url, username, password, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -478,7 +662,12 @@ func (ctl *TcaCtl) VcConnect(ctx context.Context, alias string) error {
 		}
 	}
 
-	c, err := vcClient(ctx, activeSpec.Url, activeSpec.Username, activeSpec.Password)
+	transport := ctl.Transport
+	if activeSpec.Transport != nil {
+		transport = activeSpec.Transport
+	}
+
+	c, err := vcClient(ctx, activeSpec.Url, activeSpec.Username, activeSpec.Password, transport)
 	if err != nil {
 		return err
 	}
@@ -489,7 +678,7 @@ func (ctl *TcaCtl) VcConnect(ctx context.Context, alias string) error {
 
 // BasicAuthentication TODO this method will go away
 func (ctl *TcaCtl) BasicAuthentication() {
-	ok, err := ctl.tca.GetAuthorization()
+	ok, err := ctl.tca.GetAuthorization(ctl.Context())
 	io.CheckErr(err)
 	if ok {
 		glog.Infof("Received TCA Authorization Key %v", ctl.tca.GetApiKey())
@@ -499,13 +688,27 @@ func (ctl *TcaCtl) BasicAuthentication() {
 // ResolvePoolName - resolve pool name to id in given cluster
 // TODO this method will go away
 func (ctl *TcaCtl) ResolvePoolName(poolName string, clusterName string) (string, string, error) {
-	return ctl.tca.ResolvePoolName(context.Background(), poolName, clusterName)
+	return ctl.tca.ResolvePoolName(ctl.Context(), poolName, clusterName)
 }
 
 // ResolveClusterName - resolve cluster name to cluster id
 // TODO this method will go away
 func (ctl *TcaCtl) ResolveClusterName(q string) (string, error) {
-	return ctl.tca.ResolveClusterName(context.Background(), q)
+	return ctl.tca.ResolveClusterName(ctl.Context(), q)
+}
+
+// SetContext sets root context for all commands,
+// canceling context aborts TCA requests.
+func (ctl *TcaCtl) SetContext(ctx context.Context) {
+	ctl.ctx = ctx
+}
+
+// Context returns root context for all commands
+func (ctl *TcaCtl) Context() context.Context {
+	if ctl.ctx == nil {
+		return context.Background()
+	}
+	return ctl.ctx
 }
 
 // SetTcaBase sets tca base url
@@ -529,12 +732,219 @@ func (ctl *TcaCtl) SetPassword(password string) {
 	}
 }
 
+// SetSessionCache enables or disables on disk cache of TCA session key
+func (ctl *TcaCtl) SetSessionCache(enabled bool) {
+
+	if ctl.tca == nil {
+		return
+	}
+
+	if !enabled {
+		ctl.tca.SetSessionCache(nil)
+		return
+	}
+
+	cache, err := client.DefaultSessionCache()
+	if err != nil {
+		glog.Warningf("Session cache disabled %v", err)
+		return
+	}
+
+	ctl.tca.SetSessionCache(cache)
+}
+
+// SetResponseCache enables cache of TCA responds used to resolve names,
+// zero ttl disables cache. If persist is true cache stored in ~/.tcactl/cache
+func (ctl *TcaCtl) SetResponseCache(ttl time.Duration, persist bool) {
+
+	if ctl.tca == nil {
+		return
+	}
+
+	if ttl <= 0 {
+		ctl.tca.SetResponseCache(nil)
+		return
+	}
+
+	if !persist {
+		ctl.tca.SetResponseCache(client.NewResponseCache(ttl, ""))
+		return
+	}
+
+	cache, err := client.DefaultResponseCache(ttl)
+	if err != nil {
+		glog.Warningf("Response cache disabled %v", err)
+		return
+	}
+
+	ctl.tca.SetResponseCache(cache)
+}
+
+// SetTransport sets TLS and proxy settings of TCA, Harbor and
+// vCenter clients, vCenter entry in config might overwrite it.
+func (ctl *TcaCtl) SetTransport(spec *netutils.TransportSpec) error {
+
+	if spec != nil {
+		// fail early rather than on first request
+		if _, err := netutils.NewTransport(spec); err != nil {
+			return err
+		}
+	}
+
+	ctl.Transport = spec
+	if ctl.tca != nil {
+		ctl.tca.SetTransport(spec)
+	}
+	if ctl.HarborClient != nil {
+		ctl.HarborClient.Transport = spec
+		ctl.HarborClient.Client = nil
+	}
+
+	return nil
+}
+
+// SetRateLimiter limits rate and number of concurrent TCA requests,
+// globally and per endpoint family. Unlimited settings disable limiter.
+func (ctl *TcaCtl) SetRateLimiter(global client.RateLimit, families map[string]client.RateLimit) error {
+
+	if ctl.tca == nil {
+		return nil
+	}
+
+	unlimited := global.IsUnlimited()
+	for family, limit := range families {
+		if !client.IsEndpointFamily(family) {
+			return fmt.Errorf("unknown endpoint family %s, supported %s, %s, %s", family,
+				client.EndpointInfra, client.EndpointVnfLcm, client.EndpointVnfPkgm)
+		}
+		unlimited = unlimited && limit.IsUnlimited()
+	}
+
+	if unlimited {
+		ctl.tca.SetRateLimiter(nil)
+		return nil
+	}
+
+	ctl.tca.SetRateLimiter(client.NewRateLimiter(global, families))
+	return nil
+}
+
+// SetSessionTTL sets age of TCA session after which tcactl authenticates again
+func (ctl *TcaCtl) SetSessionTTL(ttl time.Duration) {
+	if ctl.tca != nil {
+		ctl.tca.SetSessionTTL(ttl)
+	}
+}
+
+// SetRetryPolicy sets retry policy for idempotent TCA requests,
+// max attempts 1 or less disables retry.
+func (ctl *TcaCtl) SetRetryPolicy(maxAttempts int, wait time.Duration, maxWait time.Duration, codes []int) {
+
+	if ctl.tca == nil {
+		return
+	}
+
+	if maxAttempts <= 1 {
+		ctl.tca.SetRetryPolicy(nil)
+		return
+	}
+
+	policy := client.DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	if wait > 0 {
+		policy.WaitTime = wait
+	}
+	if maxWait > 0 {
+		policy.MaxWaitTime = maxWait
+	}
+	if len(codes) > 0 {
+		policy.RetryableCodes = codes
+	}
+
+	ctl.tca.SetRetryPolicy(policy)
+}
+
+// SetRecorder records TCA requests and responds to a cassette file
+// or replays them from a cassette file. Session and response caches
+// disabled, so cassette always holds authentication and every request.
+func (ctl *TcaCtl) SetRecorder(fileName string, mode client.RecorderMode) error {
+
+	if ctl.tca == nil || len(fileName) == 0 {
+		return nil
+	}
+
+	recorder, err := client.NewRecorder(fileName, mode)
+	if err != nil {
+		return err
+	}
+
+	ctl.tca.SetSessionCache(nil)
+	ctl.tca.SetResponseCache(nil)
+	ctl.tca.SetTransportWrapper(recorder.Wrap)
+
+	return nil
+}
+
+// SetSpecRenderer sets values of default spec renderer,
+// value files merged in order, key=value pairs overwrite values from files.
+func (ctl *TcaCtl) SetSpecRenderer() error {
+
+	r := specs.NewSpecRenderer()
+	r.Strict = ctl.IsStrictSpec
+
+	for _, f := range ctl.SpecValueFiles {
+		if err := r.ReadValuesFile(f); err != nil {
+			return err
+		}
+	}
+
+	for _, kv := range ctl.SpecValues {
+		if err := r.SetValue(kv); err != nil {
+			return err
+		}
+	}
+
+	specs.DefaultRenderer = r
+
+	return nil
+}
+
 // GetApi returns TcaApi api.TcaApi
 func (ctl *TcaCtl) GetApi() *api.TcaApi {
 	return ctl.tca
 }
 
-// CheckErrLogError , print error and log error
+// progressApi returns api that reports progress of blocking calls
+// to a live task view, caller must call done once call returned.
+// If show is false api returned as is.
+func (ctl *TcaCtl) progressApi(show bool) (a *api.TcaApi, done func()) {
+
+	if !show || ctl.tca == nil {
+		return ctl.tca, func() {}
+	}
+
+	var (
+		events  = make(chan api.WaitEvent)
+		stopped = make(chan struct{})
+		view    = printer.NewTaskProgress(os.Stdout, printer.IsTerminal(os.Stdout), ctl.DefaultStyle)
+	)
+
+	go func() {
+		defer close(stopped)
+		view.Run(events)
+	}()
+
+	w := *ctl.tca.Waiter()
+	w.Progress = events
+
+	return ctl.tca.WithWaiter(&w), func() {
+		close(events)
+		<-stopped
+	}
+}
+
+// CheckErrLogError , print error and log error,
+// exit code reflects kind of error.
 func CheckErrLogError(msg interface{}) {
 	if msg != nil {
 		glog.Error(msg)
@@ -543,7 +953,7 @@ func CheckErrLogError(msg interface{}) {
 			fmt.Printf("Failed to write %v", err)
 			return
 		}
-		os.Exit(1)
+		os.Exit(exitCode(msg))
 	}
 }
 
@@ -577,7 +987,7 @@ func CheckErrLogInfoMsg(msg interface{}) {
 			fmt.Printf("Failed to write to stderr %v", err)
 			return
 		}
-		os.Exit(1)
+		os.Exit(exitCode(msg))
 	}
 }
 
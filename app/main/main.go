@@ -17,15 +17,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spyroot/tcactl/app/main/cmds"
+	"github.com/spyroot/tcactl/lib/client"
 
 	"github.com/spyroot/tcactl/pkg/io"
 	_ "github.com/spyroot/tcactl/pkg/io"
+	"github.com/spyroot/tcactl/pkg/netutils"
 	"net/url"
 	"strings"
 
@@ -34,6 +37,8 @@ import (
 	"github.com/spf13/viper"
 	_ "github.com/spyroot/tcactl/lib/client/specs"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 var (
@@ -64,6 +69,8 @@ func init() {
 	viper.SetDefault(cmds.ConfigVcUrl, "https://default")
 	viper.SetDefault(cmds.ConfigVcUsername, "Administrator@vsphere.local")
 	viper.SetDefault(cmds.ConfigVcPassword, "default")
+	viper.SetDefault(cmds.ConfigSessionCache, true)
+	viper.SetDefault(cmds.ConfigSessionTTL, client.DefaultSessionTTL)
 
 	cobra.OnInitialize(initConfig)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -73,7 +80,7 @@ func init() {
 
 	tcaCtl.RootCmd.PersistentFlags().StringVarP(&tcaCtl.Printer,
 		cmds.FlagOutput, "o", "default",
-		"output format json, yaml. (default console)")
+		"output format json, yaml, xml. (default console)")
 
 	tcaCtl.RootCmd.PersistentFlags().StringVarP(&tcaCtl.CfgFile,
 		cmds.FlagConfig, "c", "",
@@ -103,6 +110,18 @@ func init() {
 		cmds.ConfigHarborUsername, "",
 		"Overwrites harbor username.")
 
+	tcaCtl.RootCmd.PersistentFlags().StringArrayVar(&tcaCtl.SpecValues,
+		cmds.FlagSpecSet, nil,
+		"Sets spec template value, key=value, dotted key sets nested value.")
+
+	tcaCtl.RootCmd.PersistentFlags().StringArrayVar(&tcaCtl.SpecValueFiles,
+		cmds.FlagSpecValues, nil,
+		"Spec template values yaml file.")
+
+	tcaCtl.RootCmd.PersistentFlags().BoolVar(&tcaCtl.IsStrictSpec,
+		cmds.FlagSpecStrict, false,
+		"Flag fails spec rendering if template value is missing.")
+
 	// TODO enable for all command
 	tcaCtl.RootCmd.PersistentFlags().BoolVarP(&tcaCtl.IsTrace,
 		cmds.ConfigTrace, "x", false,
@@ -166,6 +185,85 @@ func init() {
 		tcaCtl.RootCmd.PersistentFlags().Lookup(cmds.ConfigHarborPassword))
 	io.CheckErr(err)
 
+	tcaCtl.RootCmd.PersistentFlags().Int(
+		cmds.ConfigRetryMax, client.DefaultRetryMaxAttempts,
+		"Max number of attempts for idempotent TCA requests, 1 disables retry.")
+
+	tcaCtl.RootCmd.PersistentFlags().Duration(
+		cmds.ConfigRetryWait, client.DefaultRetryWaitTime,
+		"Initial backoff between attempts.")
+
+	tcaCtl.RootCmd.PersistentFlags().Duration(
+		cmds.ConfigRetryMaxWait, client.DefaultRetryMaxWaitTime,
+		"Max backoff between attempts.")
+
+	tcaCtl.RootCmd.PersistentFlags().IntSlice(
+		cmds.ConfigRetryCodes, client.DefaultRetryableCodes,
+		"HTTP status codes request retried on.")
+
+	tcaCtl.RootCmd.PersistentFlags().Duration(
+		cmds.ConfigCacheTTL, 0,
+		"Time TCA responds used to resolve names cached, 0 disables cache.")
+
+	tcaCtl.RootCmd.PersistentFlags().Bool(
+		cmds.ConfigCachePersist, false,
+		"Persist cached TCA responds to ~/.tcactl/cache.")
+
+	for _, f := range []string{cmds.ConfigRetryMax, cmds.ConfigRetryWait,
+		cmds.ConfigRetryMaxWait, cmds.ConfigRetryCodes,
+		cmds.ConfigCacheTTL, cmds.ConfigCachePersist} {
+		err = viper.BindPFlag(f, tcaCtl.RootCmd.PersistentFlags().Lookup(f))
+		io.CheckErr(err)
+	}
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.ConfigProxy, "",
+		"http, https or socks5 proxy url, by default HTTPS_PROXY environment used.")
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.ConfigCaFile, "",
+		"PEM bundle of CA certificates trusted in addition to system pool.")
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.ConfigCertFile, "",
+		"PEM client certificate for mutual TLS.")
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.ConfigKeyFile, "",
+		"PEM client certificate key for mutual TLS.")
+
+	tcaCtl.RootCmd.PersistentFlags().StringSlice(cmds.ConfigPinSha256, nil,
+		"SHA-256 fingerprints of pinned server certificates.")
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.ConfigTlsMinVersion, "1.2",
+		"Min TLS version 1.0, 1.1, 1.2 or 1.3.")
+
+	tcaCtl.RootCmd.PersistentFlags().Bool(cmds.ConfigInsecure, true,
+		"Skip server certificate verification, ignored if ca-file or pin-sha256 set.")
+
+	for _, f := range []string{cmds.ConfigProxy, cmds.ConfigCaFile,
+		cmds.ConfigCertFile, cmds.ConfigKeyFile, cmds.ConfigPinSha256,
+		cmds.ConfigTlsMinVersion, cmds.ConfigInsecure} {
+		err = viper.BindPFlag(f, tcaCtl.RootCmd.PersistentFlags().Lookup(f))
+		io.CheckErr(err)
+	}
+
+	tcaCtl.RootCmd.PersistentFlags().Float64(cmds.ConfigRateLimit, 0,
+		"Max number of TCA requests per second, 0 unlimited.")
+
+	tcaCtl.RootCmd.PersistentFlags().Int(cmds.ConfigRateBurst, 0,
+		"Max number of TCA requests sent at once, by default rate-limit.")
+
+	tcaCtl.RootCmd.PersistentFlags().Int(cmds.ConfigMaxInFlight, 0,
+		"Max number of concurrent TCA requests, 0 unlimited.")
+
+	for _, f := range []string{cmds.ConfigRateLimit, cmds.ConfigRateBurst, cmds.ConfigMaxInFlight} {
+		err = viper.BindPFlag(f, tcaCtl.RootCmd.PersistentFlags().Lookup(f))
+		io.CheckErr(err)
+	}
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.FlagRecord, "",
+		"Records sanitized TCA requests and responds to a cassette file, attach it to a bug report.")
+
+	tcaCtl.RootCmd.PersistentFlags().String(cmds.FlagReplay, "",
+		"Serves TCA responds from a cassette file instead of TCA.")
+
 	viper.SetDefault("author", "spyroot@gmail.com")
 	viper.SetDefault("license", "apache")
 }
@@ -223,6 +321,38 @@ func initConfig() {
 	tcaCtl.SetTcaBase(viper.GetString(cmds.ConfigTcaEndpoint))
 	tcaCtl.SetTcaUsername(viper.GetString(cmds.ConfigTcaUsername))
 	tcaCtl.SetPassword(viper.GetString(cmds.ConfigTcaPassword))
+	tcaCtl.SetSessionTTL(viper.GetDuration(cmds.ConfigSessionTTL))
+	tcaCtl.SetSessionCache(viper.GetBool(cmds.ConfigSessionCache))
+	tcaCtl.SetRetryPolicy(viper.GetInt(cmds.ConfigRetryMax),
+		viper.GetDuration(cmds.ConfigRetryWait),
+		viper.GetDuration(cmds.ConfigRetryMaxWait),
+		viper.GetIntSlice(cmds.ConfigRetryCodes))
+	tcaCtl.SetResponseCache(viper.GetDuration(cmds.ConfigCacheTTL),
+		viper.GetBool(cmds.ConfigCachePersist))
+	io.CheckErr(tcaCtl.SetTransport(&netutils.TransportSpec{
+		Proxy:         viper.GetString(cmds.ConfigProxy),
+		CaFile:        viper.GetString(cmds.ConfigCaFile),
+		CertFile:      viper.GetString(cmds.ConfigCertFile),
+		KeyFile:       viper.GetString(cmds.ConfigKeyFile),
+		PinSha256:     viper.GetStringSlice(cmds.ConfigPinSha256),
+		TlsMinVersion: viper.GetString(cmds.ConfigTlsMinVersion),
+		Insecure:      viper.GetBool(cmds.ConfigInsecure),
+	}))
+
+	var families map[string]client.RateLimit
+	io.CheckErr(viper.UnmarshalKey(cmds.ConfigRateLimitFamilies, &families))
+	io.CheckErr(tcaCtl.SetRateLimiter(client.RateLimit{
+		Rate:        viper.GetFloat64(cmds.ConfigRateLimit),
+		Burst:       viper.GetInt(cmds.ConfigRateBurst),
+		MaxInFlight: viper.GetInt(cmds.ConfigMaxInFlight),
+	}, families))
+
+	if f, _ := tcaCtl.RootCmd.PersistentFlags().GetString(cmds.FlagRecord); len(f) > 0 {
+		io.CheckErr(tcaCtl.SetRecorder(f, client.RecorderModeRecord))
+	}
+	if f, _ := tcaCtl.RootCmd.PersistentFlags().GetString(cmds.FlagReplay); len(f) > 0 {
+		io.CheckErr(tcaCtl.SetRecorder(f, client.RecorderModeReplay))
+	}
 
 	// default Cloud in TCA,  SpecCluster and node pool
 	tcaCtl.DefaultCloudName = viper.GetString(cmds.ConfigDefaultCloud)
@@ -241,6 +371,9 @@ func initConfig() {
 	}
 	tcaCtl.VsphereAuthSpecs = vmwareAuthSPecs
 
+	err := tcaCtl.SetSpecRenderer()
+	io.CheckErr(err)
+
 	tcaCtl.Printer = viper.GetString("output")
 	glog.Infof("TCA Base set to %v", viper.GetString(cmds.ConfigTcaEndpoint))
 }
@@ -252,13 +385,20 @@ func initConfig() {
 func main() {
 
 	glog.Infof("Using config file %v", tcaCtl.CfgFile)
+
+	// interrupt cancels all in flight TCA requests
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	tcaCtl.SetContext(ctx)
+
 	if err := tcaCtl.RootCmd.Execute(); err != nil {
-		_, err := fmt.Fprintln(os.Stderr, err)
-		if err != nil {
-			fmt.Printf("Failed to write %v", err)
+		_, writeErr := fmt.Fprintln(os.Stderr, err)
+		if writeErr != nil {
+			fmt.Printf("Failed to write %v", writeErr)
 			return
 		}
 		glog.Error(err)
-		os.Exit(1)
+		cancel()
+		os.Exit(cmds.ExitCode(err))
 	}
 }
@@ -0,0 +1,127 @@
+// Package main
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"flag"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// selfSignedCert generates in-memory self-signed certificate for localhost
+func selfSignedCert() (tls.Certificate, error) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "tcasim", Organization: []string{"tcactl"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// tcasim runs in-memory TCA simulator
+func main() {
+
+	var (
+		listen   = flag.String("listen", "127.0.0.1:8443", "address simulator listens on")
+		certFile = flag.String("cert", "", "TLS certificate file, self-signed certificate generated if empty")
+		keyFile  = flag.String("key", "", "TLS private key file")
+		plain    = flag.Bool("plain", false, "serve plain http instead of https")
+		state    = flag.String("state", "", "state file, loaded on start if exists and saved after each change")
+		step     = flag.Duration("step", tcasim.DefaultStepDuration, "time each task step takes")
+		username = flag.String("username", tcasim.DefaultUsername, "username simulator accepts")
+		password = flag.String("password", tcasim.DefaultPassword, "password simulator accepts")
+		pageSize = flag.Int("page", 0, "vnflcm and vnfpkgm entities per page, 0 disables paging")
+	)
+	flag.Parse()
+
+	var st *tcasim.State
+	if len(*state) > 0 {
+		if _, err := os.Stat(*state); err == nil {
+			loaded, err := tcasim.LoadState(*state)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed load state %s: %v\n", *state, err)
+				os.Exit(1)
+			}
+			st = loaded
+		}
+	}
+
+	sim := tcasim.NewServer(st, tcasim.Options{
+		Username:     *username,
+		Password:     *password,
+		StepDuration: *step,
+		StateFile:    *state,
+		PageSize:     *pageSize,
+	})
+
+	server := &http.Server{Addr: *listen, Handler: sim}
+
+	var err error
+	switch {
+	case *plain:
+		fmt.Printf("tcasim listening on http://%s\n", *listen)
+		err = server.ListenAndServe()
+	case len(*certFile) > 0:
+		fmt.Printf("tcasim listening on https://%s\n", *listen)
+		err = server.ListenAndServeTLS(*certFile, *keyFile)
+	default:
+		cert, certErr := selfSignedCert()
+		if certErr != nil {
+			fmt.Fprintf(os.Stderr, "failed generate certificate: %v\n", certErr)
+			os.Exit(1)
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		fmt.Printf("tcasim listening on https://%s\n", *listen)
+		err = server.ListenAndServeTLS("", "")
+	}
+
+	glog.Flush()
+	if err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
 	"github.com/spyroot/tcactl/lib/models"
+	"github.com/spyroot/tcactl/pkg/netutils"
 	"strings"
 	"time"
 )
@@ -51,6 +52,9 @@ const (
 type TcaApi struct {
 	// rest client used to interact with tca
 	rest *client.RestClient
+
+	// waiter used by blocking calls, nil uses default
+	waiter *Waiter
 }
 
 // NewTcaApi - return instance for API.
@@ -77,6 +81,11 @@ func (m *CloudProviderNotFound) Error() string {
 	return m.errMsg + " cloud provider not found"
 }
 
+// Unwrap - CloudProviderNotFound matches api_errors.ErrNotFound
+func (m *CloudProviderNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // UnsupportedCloudProvider error raised if tenant cloud not found
 type UnsupportedCloudProvider struct {
 	errMsg string
@@ -109,7 +118,7 @@ func (a *TcaApi) GetAllNodePool(ctx context.Context) (*response.NodePool, error)
 			continue
 		}
 
-		clusterPool, poolErr := a.rest.GetClusterNodePools(cluster.Id)
+		clusterPool, poolErr := a.rest.GetClusterNodePools(ctx, cluster.Id)
 		if poolErr != nil {
 			glog.Error(err)
 			return nil, err
@@ -138,14 +147,43 @@ func (a *TcaApi) GetVimTenants(ctx context.Context) (*response.Tenants, error) {
 	return a.rest.GetVimTenants(ctx)
 }
 
-// GetCurrentClusterTask get current cluster task
-func (a *TcaApi) GetCurrentClusterTask(ctx context.Context, clusterId string) (*models.ClusterTask, error) {
+// FilterVimTenants returns cloud providers that match SOL013 filter,
+// up to limit entries, zero limit returns all.  Tenant api doesn't
+// support SOL013 query, filter applied on client side.
+func (a *TcaApi) FilterVimTenants(ctx context.Context, filter string, limit int) (*response.Tenants, error) {
 
-	if len(clusterId) == 0 {
-		return nil, api_errors.NewInvalidTaskId(clusterId)
+	f, err := client.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := a.GetVimTenants(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if !IsValidUUID(clusterId) == false {
+	var r response.Tenants
+	for _, t := range tenants.TenantsList {
+		if limit > 0 && len(r.TenantsList) == limit {
+			break
+		}
+		ok, err := f.Match(t)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			r.TenantsList = append(r.TenantsList, t)
+		}
+	}
+
+	return &r, nil
+}
+
+// GetCurrentClusterTask get current cluster task,
+// clusterId is cluster name or cluster id.
+func (a *TcaApi) GetCurrentClusterTask(ctx context.Context, clusterId string) (*models.ClusterTask, error) {
+
+	if len(clusterId) == 0 {
 		return nil, api_errors.NewInvalidTaskId(clusterId)
 	}
 
@@ -160,7 +198,7 @@ func (a *TcaApi) GetCurrentClusterTask(ctx context.Context, clusterId string) (*
 	}
 
 	glog.Infof("Retrieving current task task list for cluster '%v'", cid)
-	task, err := a.rest.GetClustersTask(ctx, specs.NewClusterTaskQuery(clusterId))
+	task, err := a.rest.GetClustersTask(ctx, specs.NewClusterTaskQuery(cid))
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +284,7 @@ func (a *TcaApi) GetVimFolders(ctx context.Context, cloudName string) (*models.F
 		return nil, fmt.Errorf("failed create folder filter")
 	}
 
-	t, err := a.rest.GetVMwareFolders(f)
+	t, err := a.rest.GetVMwareFolders(ctx, f)
 	if err != nil {
 		return nil, err
 	}
@@ -379,7 +417,7 @@ func validateClusterSpec(spec *specs.SpecCluster) error {
 // NormalizeTemplateId - resolve template name to id
 // for a give template type. Both must match in order
 // method return ture
-func (a *TcaApi) NormalizeTemplateId(IdOrName string, templateType string) (string, error) {
+func (a *TcaApi) NormalizeTemplateId(ctx context.Context, IdOrName string, templateType string) (string, error) {
 
 	if len(IdOrName) == 0 {
 		return "", api_errors.NewTemplateNotFound(IdOrName)
@@ -392,7 +430,7 @@ func (a *TcaApi) NormalizeTemplateId(IdOrName string, templateType string) (stri
 	glog.Infof("Resolving cluster template '%s' to id", IdOrName)
 
 	// resolve template id, in case client used name instead id
-	clusterTemplates, err := a.rest.GetClusterTemplates()
+	clusterTemplates, err := a.rest.GetClusterTemplates(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -747,7 +785,7 @@ func (a *TcaApi) DeleteTenantCluster(ctx context.Context, tenantCluster string)
 		return nil, err
 	}
 
-	return a.rest.DeleteTenant(clouds.TenantID)
+	return a.rest.DeleteTenant(ctx, clouds.TenantID)
 }
 
 // ResolveVim resolve vim name to id
@@ -853,15 +891,47 @@ func (a *TcaApi) GetClusters(ctx context.Context) (*response.Clusters, error) {
 	return a.rest.GetClusters(ctx)
 }
 
+// FilterClusters returns clusters that match SOL013 filter,
+// up to limit entries, zero limit returns all. Cluster api doesn't
+// support SOL013 query, filter applied on client side.
+func (a *TcaApi) FilterClusters(ctx context.Context, filter string, limit int) (*response.Clusters, error) {
+
+	f, err := client.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := a.GetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var r response.Clusters
+	for _, c := range clusters.Clusters {
+		if limit > 0 && len(r.Clusters) == limit {
+			break
+		}
+		ok, err := f.Match(c)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			r.Clusters = append(r.Clusters, c)
+		}
+	}
+
+	return &r, nil
+}
+
 // GetClusterNodePools - return node pool for a given id.
-func (a *TcaApi) GetClusterNodePools(Id string) (*response.NodePool, error) {
+func (a *TcaApi) GetClusterNodePools(ctx context.Context, Id string) (*response.NodePool, error) {
 
 	if a.rest == nil {
 		return nil, fmt.Errorf("rest interface is nil")
 	}
 
 	glog.Infof("Retrieving cluster list.")
-	return a.rest.GetClusterNodePools(Id)
+	return a.rest.GetClusterNodePools(ctx, Id)
 }
 
 // GetAllNodePools - return all node pool for clusterId
@@ -880,12 +950,12 @@ func (a *TcaApi) GetAllNodePools(ctx context.Context) ([]response.NodesSpecs, er
 	}
 
 	for _, c := range clusters.Clusters {
-		pools, err := a.GetClusterNodePools(c.Id)
+		pools, err := a.GetClusterNodePools(ctx, c.Id)
 		if err != nil {
 			return allSpecs, err
 		}
 		for _, p := range pools.Pools {
-			r, err := a.rest.GetClusterNodePool(c.Id, p.Id)
+			r, err := a.rest.GetClusterNodePool(ctx, c.Id, p.Id)
 			if err != nil {
 				return allSpecs, err
 			}
@@ -936,7 +1006,7 @@ func (a *TcaApi) GetTenant(ctx context.Context, tenant string) (*response.Tenant
 
 // GetTenantsQuery query tenant information based on
 // tenant id and package id
-func (a *TcaApi) GetTenantsQuery(tenantId string, nfType string) (*response.Tenants, error) {
+func (a *TcaApi) GetTenantsQuery(ctx context.Context, tenantId string, nfType string) (*response.Tenants, error) {
 
 	glog.Infof("Retrieving tenants.")
 
@@ -955,12 +1025,12 @@ func (a *TcaApi) GetTenantsQuery(tenantId string, nfType string) (*response.Tena
 		reqFilter.Filter.NfdId = tenantId
 	}
 
-	return a.rest.GetTenantsQuery(&reqFilter)
+	return a.rest.GetTenantsQuery(ctx, &reqFilter)
 }
 
 // GetNamedClusterTemplate - return template
 // if name is string first resolve template
-func (a *TcaApi) GetNamedClusterTemplate(name string) (*response.ClusterTemplateSpec, error) {
+func (a *TcaApi) GetNamedClusterTemplate(ctx context.Context, name string) (*response.ClusterTemplateSpec, error) {
 
 	glog.Infof("Retrieving vnf packages.")
 
@@ -971,7 +1041,7 @@ func (a *TcaApi) GetNamedClusterTemplate(name string) (*response.ClusterTemplate
 	var templateId = name
 	// resolve name first
 	if IsValidUUID(name) == false {
-		templates_, err := a.GetClusterTemplates()
+		templates_, err := a.GetClusterTemplates(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -981,7 +1051,7 @@ func (a *TcaApi) GetNamedClusterTemplate(name string) (*response.ClusterTemplate
 		}
 	}
 
-	return a.rest.GetClusterTemplate(templateId)
+	return a.rest.GetClusterTemplate(ctx, templateId)
 }
 
 // CreateCnfNewInstance create a new instance of VNF or CNF.
@@ -1024,7 +1094,7 @@ func (a *TcaApi) CreateCnfNewInstance(ctx context.Context, n *specs.InstanceRequ
 		return nil, err
 	}
 
-	pkg, vnfd, err := a.GetCatalogAndVdu(n.NfdName)
+	pkg, vnfd, err := a.GetCatalogAndVdu(ctx, n.NfdName)
 	if err != nil || vnfd == nil {
 		glog.Errorf("Failed acquire VDU information for %v", n.NfdName)
 		return nil, err
@@ -1032,7 +1102,7 @@ func (a *TcaApi) CreateCnfNewInstance(ctx context.Context, n *specs.InstanceRequ
 
 	// get linked Repo, if caller provide Repo that is not
 	// linked nothing to do.
-	reposUuid, err := a.rest.LinkedRepositories(cloud.TenantID, n.Repo)
+	reposUuid, err := a.rest.LinkedRepositories(ctx, cloud.TenantID, n.Repo)
 	if err != nil {
 		glog.Errorf("Failed acquire linked %v "+
 			"repository to cloud provider %v. Indicate a Repo "+
@@ -1133,14 +1203,14 @@ func (a *TcaApi) CreateCnfNewInstance(ctx context.Context, n *specs.InstanceRequ
 
 	}
 
-	instance, err := a.rest.GetRunningVnflcm(vnfLcm.Id)
+	instance, err := a.rest.GetRunningVnflcm(ctx, vnfLcm.Id)
 	if err != nil {
 		glog.Errorf("Failed create cnf instance information %v", err)
 		return nil, err
 	}
 
 	if isBlocked {
-		err := a.BlockWaitStateChange(ctx, vnfLcm.Id, StateInstantiate, DefaultMaxRetry, true)
+		err := a.WaitInstanceState(ctx, vnfLcm.Id, StateInstantiate)
 		if err != nil {
 			return instance, err
 		}
@@ -1148,52 +1218,28 @@ func (a *TcaApi) CreateCnfNewInstance(ctx context.Context, n *specs.InstanceRequ
 	return instance, nil
 }
 
-// BlockWaitStateChange - simple block and pull status
-// instanceId is instance that method will pull and check
-// waitFor is target status method waits.
-// maxRetry a limit.
-func (a *TcaApi) BlockWaitStateChange(ctx context.Context, instanceId string, waitFor string, maxRetry int, verbose bool) error {
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for i := 1; i < maxRetry; i++ {
-		{
-
-			select {
-			case <-ctx.Done():
-
-				return nil
-			default:
-
-				instance, err := a.rest.GetRunningVnflcm(instanceId)
-				if err != nil {
-					return err
-				}
-
-				if verbose {
-					fmt.Printf("Current state %s waiting for %s\n",
-						instance.InstantiationState, waitFor)
+// sleepContext pauses for duration d, returns ctx error if context canceled.
+func sleepContext(ctx context.Context, d time.Duration) error {
 
-					fmt.Printf("Current LCM Operation status %s target state %s\n\n",
-						instance.Metadata.LcmOperationState,
-						instance.Metadata.LcmOperation)
-				}
+	t := time.NewTimer(d)
+	defer t.Stop()
 
-				if instance.Metadata.LcmOperationState == "FAILED_TEMP" {
-					break
-				}
-
-				if strings.HasPrefix(instance.InstantiationState, waitFor) {
-					break
-				}
-
-				time.Sleep(TaskPoolSeconds * time.Second)
-			}
-		}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
+}
 
-	return nil
+// BlockWaitStateChange - block until instance reaches waitFor state,
+// at most maxRetry polls. If verbose, progress printed to stdout.
+//
+// Deprecated: use WaitInstanceState and Waiter.
+func (a *TcaApi) BlockWaitStateChange(ctx context.Context, instanceId string, waitFor string, maxRetry int, verbose bool) error {
+	return a.legacyWait(maxRetry, verbose, func(a *TcaApi) error {
+		return a.WaitInstanceState(ctx, instanceId, waitFor)
+	})
 }
 
 type TcaTaskFailed struct {
@@ -1220,75 +1266,20 @@ func (e *TaskNotFound) Error() string {
 	return e.ErrMsg
 }
 
-// BlockWaitTaskFinish - simple block and pull status
-// instanceId is instance that method will pull and check
-// waitFor is target status method waits.
-// maxRetry a limit.
-func (a *TcaApi) BlockWaitTaskFinish(ctx context.Context, task *models.TcaTask, waitFor string, maxRetry int, verbose bool) error {
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	req := specs.NewClusterTaskQuery(task.Id)
-
-	if verbose {
-		glog.Infof("Waiting task id=%s type=%s", task.Id, task.OperationId)
-	}
-
-	var (
-		allTaskDone = false
-		retry       = 0
-	)
-
-	for !allTaskDone || retry == maxRetry {
-		{
-
-			select {
-			case <-ctx.Done():
-
-				return nil
-			default:
-
-				task, err := a.rest.GetClustersTask(ctx, req)
-				if err != nil {
-					return err
-				}
-
-				if task == nil {
-					return &TaskNotFound{"task not found"}
-				}
-
-				var numTaskWaiting = 0
-				for _, item := range task.Items {
-					if verbose {
-						glog.Infof("Task id=%s type=%s progress=%d status=%s",
-							item.TaskId, item.Type, item.Progress, item.Status)
-					}
-
-					if item.Status != waitFor {
-						glog.Infof("Task still running id=%s type=%s progress=%d status=%s",
-							item.TaskId, item.Type, item.Progress, item.Status)
-						numTaskWaiting++
-					}
-
-					if item.Status == TaskStateFailed {
-						glog.Errorf("Task failed.")
-						return &TcaTaskFailed{item.Type}
-					}
-				}
-
-				if numTaskWaiting == 0 {
-					glog.Infof("All task finished.")
-					allTaskDone = true
-				}
-
-				time.Sleep(TaskPoolSeconds * time.Second)
-				retry++
-			}
-		}
-	}
+// Unwrap - TaskNotFound matches api_errors.ErrNotFound
+func (e *TaskNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
 
-	return nil
+// BlockWaitTaskFinish - block until every item of a task reaches
+// waitFor status, at most maxRetry polls. If verbose, progress
+// printed to stdout.
+//
+// Deprecated: use WaitTask and Waiter.
+func (a *TcaApi) BlockWaitTaskFinish(ctx context.Context, task *models.TcaTask, waitFor string, maxRetry int, verbose bool) error {
+	return a.legacyWait(maxRetry, verbose, func(a *TcaApi) error {
+		return a.WaitTask(ctx, task, waitFor)
+	})
 }
 
 // ResolvePoolId method resolves pool name to pool id
@@ -1297,7 +1288,7 @@ func (a *TcaApi) ResolvePoolId(ctx context.Context, poolName string, clusterScop
 	// get cluster id for a pool
 	if len(clusterScope) > 0 && len(clusterScope[0]) > 0 {
 		var clusterId = clusterScope[0]
-		if pool, restErr := a.rest.GetClusterNodePools(clusterId); restErr == nil && pool != nil {
+		if pool, restErr := a.rest.GetClusterNodePools(ctx, clusterId); restErr == nil && pool != nil {
 			if spec, pollErr := pool.GetPool(poolName); pollErr == nil && spec != nil {
 				return spec.Id, nil
 			}
@@ -1317,7 +1308,7 @@ func (a *TcaApi) ResolvePoolId(ctx context.Context, poolName string, clusterScop
 
 	// get all pools
 	for _, cid := range clusterIds {
-		if pool, restErr := a.rest.GetClusterNodePools(cid); restErr == nil && pool != nil {
+		if pool, restErr := a.rest.GetClusterNodePools(ctx, cid); restErr == nil && pool != nil {
 			if nodeSpec, pollErr := pool.GetPool(poolName); pollErr == nil && nodeSpec != nil {
 				return nodeSpec.Id, nil
 			}
@@ -1357,8 +1348,8 @@ func (a *TcaApi) ResolvePoolName(ctx context.Context, poolName string, clusterNa
 
 // GetAuthorization retrieve API key from TCA
 // and update internal state.
-func (a *TcaApi) GetAuthorization() (bool, error) {
-	return a.rest.GetAuthorization()
+func (a *TcaApi) GetAuthorization(ctx context.Context) (bool, error) {
+	return a.rest.GetAuthorization(ctx)
 }
 
 // ResolveClusterName - resolve cluster name to cluster id
@@ -1408,6 +1399,81 @@ func (a *TcaApi) SetPassword(password string) {
 
 }
 
+// SetSessionCache sets cache TCA session key stored in,
+// nil disables cache.
+func (a *TcaApi) SetSessionCache(cache *client.SessionCache) {
+
+	if a != nil && a.rest != nil {
+		a.rest.SessionCache = cache
+	}
+}
+
+// SetSessionTTL sets age of a session after which
+// rest client authenticates again.
+func (a *TcaApi) SetSessionTTL(ttl time.Duration) {
+
+	if a != nil && a.rest != nil {
+		a.rest.SessionTTL = ttl
+	}
+}
+
+// SetRetryPolicy sets retry policy for idempotent requests,
+// nil disables retry.
+func (a *TcaApi) SetRetryPolicy(policy *client.RetryPolicy) {
+
+	if a != nil && a.rest != nil {
+		a.rest.RetryPolicy = policy
+	}
+}
+
+// SetResponseCache sets read-through cache of responds api
+// uses to resolve names, nil disables cache.
+func (a *TcaApi) SetResponseCache(cache *client.ResponseCache) {
+
+	if a != nil && a.rest != nil {
+		a.rest.ResponseCache = cache
+		a.rest.Client = nil
+	}
+}
+
+// SetTransport sets TLS and proxy settings used to reach TCA,
+// nil spec resets to default settings.
+func (a *TcaApi) SetTransport(spec *netutils.TransportSpec) {
+
+	if a != nil && a.rest != nil {
+		a.rest.Transport = spec
+		a.rest.Client = nil
+	}
+}
+
+// SetRateLimiter sets limit of request rate and number of
+// concurrent requests to TCA, nil disables limit.
+func (a *TcaApi) SetRateLimiter(limiter *client.RateLimiter) {
+
+	if a != nil && a.rest != nil {
+		a.rest.RateLimiter = limiter
+		a.rest.Client = nil
+	}
+}
+
+// InvalidateCache drops cached responds, cache invalidated
+// automatically after each call that mutates TCA state.
+func (a *TcaApi) InvalidateCache() {
+
+	if a != nil && a.rest != nil {
+		a.rest.ResponseCache.Invalidate()
+	}
+}
+
+// SetTransportWrapper wraps http transport rest client uses,
+// for example client.Recorder to record or replay TCA responses.
+func (a *TcaApi) SetTransportWrapper(wrapper client.TransportWrapper) {
+
+	if a != nil && a.rest != nil {
+		a.rest.WrapTransport(wrapper)
+	}
+}
+
 // GetApiKey returns API key used to connect to rest interface
 func (a *TcaApi) GetApiKey() string {
 
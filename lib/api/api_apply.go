@@ -0,0 +1,369 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	errnos "github.com/spyroot/tcactl/pkg/errors"
+)
+
+// ApplyAction action apply took for a spec
+type ApplyAction string
+
+const (
+	// ApplyCreated object not found and created
+	ApplyCreated ApplyAction = "created"
+
+	// ApplyUpdated object found and updated
+	ApplyUpdated ApplyAction = "updated"
+
+	// ApplyUnchanged object found and matches the spec
+	ApplyUnchanged ApplyAction = "unchanged"
+
+	// ApplyDeleted object found and deleted
+	ApplyDeleted ApplyAction = "deleted"
+
+	// ApplyDrift object found and differs from the spec,
+	// but TCA can't update it, object left as is.
+	ApplyDrift ApplyAction = "drift"
+)
+
+// ApplyResult a result of apply for a single spec
+type ApplyResult struct {
+
+	// Kind spec kind
+	Kind specs.SpecType
+
+	// Name object name
+	Name string
+
+	// Action apply took
+	Action ApplyAction
+
+	// Task if create or update triggered a task in TCA
+	Task *models.TcaTask
+}
+
+// isNotFound returns true if error indicates that object not found
+func isNotFound(err error) bool {
+	return errors.Is(err, api_errors.ErrNotFound)
+}
+
+// Apply api call reconciles a spec against TCA. Method dispatches spec by kind,
+// looks up a live object and either creates, updates or leaves it unchanged.
+// In dry run, method only resolves action.
+func (a *TcaApi) Apply(ctx context.Context, req *ApplyApiReq) (*ApplyResult, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	if req.Spec == nil {
+		return nil, errnos.SpecNil
+	}
+
+	glog.Infof("Applying spec kind %s", req.Spec.Kind())
+
+	// resolve action first, create only request must not touch existing object
+	if req.IsCreateOnly {
+		dryReq := *req
+		dryReq.IsDryRun = true
+		dryReq.IsCreateOnly = false
+		r, err := a.Apply(ctx, &dryReq)
+		if err != nil {
+			return nil, err
+		}
+		if r.Action != ApplyCreated {
+			return nil, api_errors.NewAlreadyExists(fmt.Sprintf("%s %s", r.Kind, r.Name))
+		}
+		if req.IsDryRun {
+			return r, nil
+		}
+	}
+
+	switch spec := req.Spec.(type) {
+	case *specs.SpecCluster:
+		return a.applyCluster(ctx, spec, req)
+	case *specs.SpecNodePool:
+		return a.applyNodePool(ctx, spec, req)
+	case *specs.SpecClusterTemplate:
+		return a.applyTemplate(ctx, spec, req)
+	case *specs.SpecExtension:
+		return a.applyExtension(ctx, spec, req)
+	case *specs.SpecCloudProvider:
+		return a.applyCloudProvider(ctx, spec, req)
+	case *specs.InstanceRequestSpec:
+		return a.applyInstance(ctx, spec, req)
+	}
+
+	return nil, api_errors.NewInvalidSpec(fmt.Sprintf("unsupported spec kind '%s'", req.Spec.Kind()))
+}
+
+// applyCluster creates a cluster if it not found. TCA doesn't support
+// update for a cluster spec, node pools updated via node pool spec,
+// a cluster that differs from the spec reported as a drift.
+func (a *TcaApi) applyCluster(ctx context.Context, spec *specs.SpecCluster, req *ApplyApiReq) (*ApplyResult, error) {
+
+	r := &ApplyResult{Kind: specs.SpecKindCluster, Name: spec.Name}
+
+	if err := a.resolveDrift(ctx, spec, r); err != nil {
+		return nil, err
+	}
+	if r.Action != ApplyCreated || req.IsDryRun {
+		return r, nil
+	}
+
+	var err error
+	r.Task, err = a.CreateClusters(ctx, &ClusterCreateApiReq{
+		Spec:       spec,
+		IsBlocking: req.IsBlocking,
+		IsVerbose:  req.IsVerbose,
+	})
+
+	return r, err
+}
+
+// applyNodePool creates node pool if it not found in a cluster,
+// or updates it if live node pool differs from the spec.
+func (a *TcaApi) applyNodePool(ctx context.Context, spec *specs.SpecNodePool, req *ApplyApiReq) (*ApplyResult, error) {
+
+	// cluster in a spec takes precedence
+	cluster := req.Cluster
+	if len(spec.Cluster) > 0 {
+		cluster = spec.Cluster
+	}
+
+	if len(cluster) == 0 {
+		return nil, api_errors.NewInvalidArgument("cluster")
+	}
+
+	r := &ApplyResult{Kind: specs.SpecKindNodePool, Name: spec.Name, Action: ApplyUnchanged}
+
+	poolReq := &NodePoolCreateApiReq{
+		Spec:       spec,
+		Cluster:    cluster,
+		IsBlocking: req.IsBlocking,
+		IsVerbose:  req.IsVerbose,
+	}
+
+	pools, err := a.GetNodePool(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := pools.GetPoolByName(spec.Name)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		r.Action = ApplyCreated
+		if req.IsDryRun {
+			return r, nil
+		}
+		r.Task, err = a.CreateNewNodePool(ctx, poolReq)
+		return r, err
+	}
+
+	changed, err := isPoolChanged(spec, live)
+	if err != nil || !changed {
+		return r, err
+	}
+
+	r.Action = ApplyUpdated
+	if req.IsDryRun {
+		return r, nil
+	}
+
+	r.Task, err = a.UpdateNodePool(ctx, poolReq)
+	return r, err
+}
+
+// applyTemplate creates cluster template if it not found,
+// or updates it if live template differs from the spec.
+func (a *TcaApi) applyTemplate(ctx context.Context, spec *specs.SpecClusterTemplate, req *ApplyApiReq) (*ApplyResult, error) {
+
+	r := &ApplyResult{Kind: specs.SpecKindTemplate, Name: spec.Name, Action: ApplyUnchanged}
+
+	live, err := a.GetClusterTemplate(ctx, spec.Name)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		r.Action = ApplyCreated
+		if req.IsDryRun {
+			return r, nil
+		}
+		r.Name, err = a.CreateClusterTemplate(ctx, spec)
+		return r, err
+	}
+
+	changed, err := isTemplateChanged(spec, live)
+	if err != nil || !changed {
+		return r, err
+	}
+
+	r.Action = ApplyUpdated
+	if req.IsDryRun {
+		return r, nil
+	}
+
+	spec.Id = live.Id
+	return r, a.UpdateClusterTemplate(ctx, spec)
+}
+
+// applyExtension registers extension if it not found,
+// or updates it if live extension differs from the spec.
+func (a *TcaApi) applyExtension(ctx context.Context, spec *specs.SpecExtension, req *ApplyApiReq) (*ApplyResult, error) {
+
+	r := &ApplyResult{Kind: specs.SpecKindExtension, Name: spec.Name, Action: ApplyUnchanged}
+
+	extensions, err := a.GetExtension(ctx, spec.Name)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		r.Action = ApplyCreated
+		if req.IsDryRun {
+			return r, nil
+		}
+		_, err = a.CreateExtension(ctx, spec)
+		return r, err
+	}
+
+	live, err := extensions.FindExtension(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := isExtensionChanged(spec, live)
+	if err != nil || !changed {
+		return r, err
+	}
+
+	r.Action = ApplyUpdated
+	if req.IsDryRun {
+		return r, nil
+	}
+
+	_, err = a.UpdateExtension(ctx, spec)
+	return r, err
+}
+
+// applyCloudProvider registers cloud provider if it not found.
+// TCA doesn't update registered cloud provider, a provider that
+// differs from the spec reported as a drift.
+func (a *TcaApi) applyCloudProvider(ctx context.Context, spec *specs.SpecCloudProvider, req *ApplyApiReq) (*ApplyResult, error) {
+
+	r := &ApplyResult{Kind: specs.SpecKindProvider, Name: spec.VimName}
+
+	if err := a.resolveDrift(ctx, spec, r); err != nil {
+		return nil, err
+	}
+	if r.Action != ApplyCreated || req.IsDryRun {
+		return r, nil
+	}
+
+	_, err := a.CreateTenantProvider(ctx, spec)
+	return r, err
+}
+
+// applyInstance instantiates CNF if instance not found. Existing
+// instance not updated, an instance that differs from the spec
+// reported as a drift.
+func (a *TcaApi) applyInstance(ctx context.Context, spec *specs.InstanceRequestSpec, req *ApplyApiReq) (*ApplyResult, error) {
+
+	r := &ApplyResult{Kind: specs.SpecKindInstance, Name: spec.InstanceName}
+
+	if err := a.resolveDrift(ctx, spec, r); err != nil {
+		return nil, err
+	}
+	if r.Action != ApplyCreated || req.IsDryRun {
+		return r, nil
+	}
+
+	_, err := a.CreateCnfNewInstance(ctx, spec, false, req.IsBlocking)
+	return r, err
+}
+
+// resolveDrift resolves action for a kind that TCA can't update,
+// created if object not found, drift if it differs from the spec.
+func (a *TcaApi) resolveDrift(ctx context.Context, spec specs.RequestSpec, r *ApplyResult) error {
+
+	d, err := a.Diff(ctx, &DiffApiReq{Spec: spec})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case d.IsNew:
+		r.Action = ApplyCreated
+	case d.HasDrift():
+		glog.Warningf("%s %s differs from the spec in %d fields, TCA can't update it.",
+			r.Kind, r.Name, len(d.Fields))
+		r.Action = ApplyDrift
+	default:
+		r.Action = ApplyUnchanged
+	}
+
+	return nil
+}
+
+// isPoolChanged returns true if live node pool differs from a spec
+func isPoolChanged(spec *specs.SpecNodePool, live *response.NodesSpecs) (bool, error) {
+
+	specForm, err := NormalizeObject(spec, poolIgnoreFields...)
+	if err != nil {
+		return false, err
+	}
+
+	return isSpecChanged(specForm, live, poolIgnoreFields...)
+}
+
+// isTemplateChanged returns true if live cluster template differs from a spec
+func isTemplateChanged(spec *specs.SpecClusterTemplate, live *response.ClusterTemplateSpec) (bool, error) {
+
+	specForm, err := templateSpecForm(spec)
+	if err != nil {
+		return false, err
+	}
+
+	return isSpecChanged(specForm, live, templateIgnoreFields...)
+}
+
+// isExtensionChanged returns true if live extension differs from a spec
+func isExtensionChanged(spec *specs.SpecExtension, live *response.Extension) (bool, error) {
+
+	specForm, err := NormalizeObject(spec, extensionIgnoreFields...)
+	if err != nil {
+		return false, err
+	}
+
+	return isSpecChanged(specForm, live, extensionIgnoreFields...)
+}
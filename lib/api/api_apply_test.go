@@ -0,0 +1,227 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Test detection of not found errors
+func TestApply_isNotFound(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "cluster", err: &response.ClusterNotFound{ErrMsg: "test"}, want: true},
+		{name: "pool", err: &response.PoolNotFound{ErrMsg: "test"}, want: true},
+		{name: "template", err: api_errors.NewTemplateNotFound("test"), want: true},
+		{name: "extension", err: api_errors.NewExtensionsNotFound("test"), want: true},
+		{name: "cloud provider", err: &CloudProviderNotFound{errMsg: "test"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("apply: %w", &response.CnfNotFound{}), want: true},
+		{name: "api not found", err: &api_errors.ApiError{StatusCode: 404}, want: true},
+		{name: "other error", err: fmt.Errorf("test"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNotFound(tt.err))
+		})
+	}
+}
+
+// Test node pool drift detection
+func TestApply_isPoolChanged(t *testing.T) {
+
+	live := &response.NodesSpecs{
+		Name:      "pool01",
+		Cpu:       2,
+		Memory:    16384,
+		Storage:   50,
+		Replica:   1,
+		CloneMode: specs.LinkedClone,
+		Labels:    []string{"type=pool01", "zone=a"},
+	}
+
+	tests := []struct {
+		name string
+		spec *specs.SpecNodePool
+		want bool
+	}{
+		{
+			name: "unchanged, labels in different order",
+			spec: &specs.SpecNodePool{Name: "pool01", Cpu: 2, Memory: 16384, Storage: 50,
+				Replica: 1, CloneMode: specs.LinkedClone, Labels: []string{"zone=a", "type=pool01"}},
+			want: false,
+		},
+		{
+			name: "replica changed",
+			spec: &specs.SpecNodePool{Name: "pool01", Cpu: 2, Memory: 16384, Storage: 50,
+				Replica: 3, CloneMode: specs.LinkedClone, Labels: []string{"type=pool01", "zone=a"}},
+			want: true,
+		},
+		{
+			name: "label removed",
+			spec: &specs.SpecNodePool{Name: "pool01", Cpu: 2, Memory: 16384, Storage: 50,
+				Replica: 1, CloneMode: specs.LinkedClone, Labels: []string{"type=pool01"}},
+			want: true,
+		},
+		{
+			name: "network added",
+			spec: &specs.SpecNodePool{Name: "pool01", Cpu: 2, Memory: 16384, Storage: 50,
+				Replica: 1, CloneMode: specs.LinkedClone, Labels: []string{"type=pool01", "zone=a"},
+				Networks: []models.Network{{Label: "MANAGEMENT", NetworkName: "/Datacenter/network/mgmt"}}},
+			want: true,
+		},
+		{
+			name: "target cluster is spec only field",
+			spec: &specs.SpecNodePool{Name: "pool01", Cpu: 2, Memory: 16384, Storage: 50,
+				Replica: 1, CloneMode: specs.LinkedClone, Labels: []string{"type=pool01", "zone=a"},
+				Cluster: "edge"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := isPoolChanged(tt.spec, live)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+// Test that apply leaves a pool that matches live object unchanged
+func TestApply_poolUnchanged(t *testing.T) {
+
+	ctx := context.Background()
+	a, _ := getOperationSimApi(t, tcasim.Options{})
+
+	pools, err := a.GetNodePool(ctx, simWorkloadClusterId)
+	if !assert.NoError(t, err) {
+		return
+	}
+	live, err := pools.GetPool(simPoolId)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b, err := json.Marshal(live)
+	assert.NoError(t, err)
+	spec := &specs.SpecNodePool{}
+	assert.NoError(t, json.Unmarshal(b, spec))
+
+	req := &ApplyApiReq{Spec: spec, Cluster: simWorkloadClusterId, IsDryRun: true}
+	r, err := a.Apply(ctx, req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, ApplyUnchanged, r.Action)
+	}
+
+	spec.Replica++
+	r, err = a.Apply(ctx, req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, ApplyUpdated, r.Action)
+	}
+
+	// create only request conflicts with existing pool, in dry run as well
+	var exists *api_errors.AlreadyExists
+	req.IsCreateOnly = true
+	_, err = a.Apply(ctx, req)
+	assert.True(t, errors.As(err, &exists), "got %v", err)
+	assert.ErrorIs(t, err, api_errors.ErrConflict)
+
+	req.IsDryRun = false
+	_, err = a.Apply(ctx, req)
+	assert.True(t, errors.As(err, &exists), "got %v", err)
+}
+
+// Test that cluster, cloud provider and instance that differ from a spec reported as drift
+func TestApply_drift(t *testing.T) {
+
+	ctx := context.Background()
+	a, _ := getOperationSimApi(t, tcasim.Options{})
+
+	tests := []struct {
+		name   string
+		kind   specs.SpecType
+		object string
+		change func(spec specs.RequestSpec)
+	}{
+		{
+			name:   "cluster",
+			kind:   specs.SpecKindCluster,
+			object: "edge-test01",
+			change: func(spec specs.RequestSpec) {
+				spec.(*specs.SpecCluster).EndpointIP = "10.241.7.250"
+			},
+		},
+		{
+			name:   "cloud provider",
+			kind:   specs.SpecKindProvider,
+			object: "edge",
+			change: func(spec specs.RequestSpec) {
+				spec.(*specs.SpecCloudProvider).HcxCloudUrl = "https://tca-pod04-cp.cnfdemo.io"
+			},
+		},
+		{
+			name:   "instance",
+			kind:   specs.SpecKindInstance,
+			object: "unit_test_instance",
+			change: func(spec specs.RequestSpec) {
+				spec.(*specs.InstanceRequestSpec).NfdName = "other_catalog"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			spec, err := a.Export(ctx, &ExportApiReq{Kind: tt.kind, Name: tt.object})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			req := &ApplyApiReq{Spec: spec, IsDryRun: true}
+			r, err := a.Apply(ctx, req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ApplyUnchanged, r.Action)
+			}
+
+			tt.change(spec)
+			r, err = a.Apply(ctx, req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ApplyDrift, r.Action)
+			}
+
+			req.IsDryRun = false
+			r, err = a.Apply(ctx, req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ApplyDrift, r.Action, "object that TCA can't update left as is")
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	errnos "github.com/spyroot/tcactl/pkg/errors"
+	ioutils "github.com/spyroot/tcactl/pkg/io"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupFormatVersion version of backup archive format,
+	// restore rejects archive of a newer version.
+	BackupFormatVersion = 1
+
+	// BackupManifestFile manifest file inside backup archive
+	BackupManifestFile = "manifest.yaml"
+
+	// BackupKindCatalog catalog entity, CSAR stored as is
+	BackupKindCatalog specs.SpecType = "catalog"
+
+	// BackupKindRepos repositories linked to cloud providers,
+	// stored for a reference, restore doesn't replay them.
+	BackupKindRepos specs.SpecType = "repos"
+)
+
+// BackupEntry a single object stored in backup archive
+type BackupEntry struct {
+
+	// Kind spec kind, catalog or repos
+	Kind specs.SpecType `json:"kind" yaml:"kind"`
+
+	// Name object name
+	Name string `json:"name" yaml:"name"`
+
+	// Cluster a cluster node pool attached to
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+
+	// File a file inside archive
+	File string `json:"file" yaml:"file"`
+}
+
+// BackupManifest describes content of backup archive,
+// manifest stored in archive as manifest.yaml
+type BackupManifest struct {
+
+	// Version backup archive format version
+	Version int `json:"version" yaml:"version"`
+
+	// Created time backup created
+	Created time.Time `json:"created" yaml:"created"`
+
+	// Source TCA url backup taken from
+	Source string `json:"source" yaml:"source"`
+
+	// Entries all objects stored in archive
+	Entries []BackupEntry `json:"entries" yaml:"entries"`
+
+	// files content of archive
+	files map[string][]byte
+}
+
+// NewBackupManifest returns a new empty backup manifest
+func NewBackupManifest(source string) *BackupManifest {
+	return &BackupManifest{
+		Version: BackupFormatVersion,
+		Created: time.Now().UTC(),
+		Source:  source,
+		files:   map[string][]byte{},
+	}
+}
+
+// backupFileName returns a file name of object inside archive
+func backupFileName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+}
+
+// Add adds an object to a manifest
+func (m *BackupManifest) Add(kind specs.SpecType, name string, cluster string, file string, b []byte) {
+	m.Entries = append(m.Entries, BackupEntry{Kind: kind, Name: name, Cluster: cluster, File: file})
+	m.files[file] = b
+}
+
+// AddSpec adds a spec to a manifest, spec stored in yaml format.
+// Node pool stored under a cluster it attached to.
+func (m *BackupManifest) AddSpec(spec specs.RequestSpec) error {
+
+	if spec == nil {
+		return errnos.SpecNil
+	}
+
+	id, err := siteNodeId(spec, "")
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	var (
+		parts   = strings.SplitN(id, "/", 3)
+		name    = parts[len(parts)-1]
+		cluster string
+		file    = string(spec.Kind()) + "/" + backupFileName(name) + ".yaml"
+	)
+
+	if len(parts) == 3 {
+		cluster = parts[1]
+		file = string(spec.Kind()) + "/" + backupFileName(cluster) + "/" + backupFileName(name) + ".yaml"
+	}
+
+	m.Add(spec.Kind(), name, cluster, file, b)
+	return nil
+}
+
+// File returns content of a file stored in archive
+func (m *BackupManifest) File(file string) ([]byte, bool) {
+	b, ok := m.files[file]
+	return b, ok
+}
+
+// Specs returns all specs stored in archive in order they were added
+func (m *BackupManifest) Specs() ([]specs.RequestSpec, error) {
+
+	var site []specs.RequestSpec
+	for _, e := range m.Entries {
+		if e.Kind == BackupKindCatalog || e.Kind == BackupKindRepos {
+			continue
+		}
+
+		b, ok := m.files[e.File]
+		if !ok {
+			return nil, api_errors.NewFileNotFound(e.File)
+		}
+
+		spec, err := specs.NewRequestSpec(e.Kind)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := specs.ReadSpec(bytes.NewReader(b), spec, specs.Yaml)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", e.File, err)
+		}
+
+		site = append(site, *s)
+	}
+
+	return site, nil
+}
+
+// Write writes manifest and all objects to a zip archive
+func (m *BackupManifest) Write(fileName string) error {
+
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string][]byte, len(m.files)+1)
+	for f, content := range m.files {
+		files[f] = content
+	}
+	files[BackupManifestFile] = b
+
+	return ioutils.ZipFiles(files, fileName)
+}
+
+// ReadBackup reads backup archive, archive of newer format
+// version or archive without a manifest rejected.
+func ReadBackup(fileName string) (*BackupManifest, error) {
+
+	files, err := ioutils.UnzipFiles(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := files[BackupManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a backup archive, %s not found", fileName, BackupManifestFile)
+	}
+
+	var m BackupManifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	if m.Version < 1 || m.Version > BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d, supported version %d", m.Version, BackupFormatVersion)
+	}
+
+	delete(files, BackupManifestFile)
+	m.files = files
+
+	return &m, nil
+}
+
+// backupClusters adds clusters and node pools of workload clusters
+func (a *TcaApi) backupClusters(ctx context.Context, m *BackupManifest) error {
+
+	clusters, err := a.GetClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range clusters.Clusters {
+		spec, err := a.exportCluster(ctx, c.Id)
+		if err != nil {
+			return err
+		}
+		if err := m.AddSpec(spec); err != nil {
+			return err
+		}
+
+		// management cluster has no node pools
+		if !strings.EqualFold(c.ClusterType, string(specs.ClusterWorkload)) {
+			continue
+		}
+
+		pools, err := a.GetNodePool(ctx, c.Id)
+		if err != nil {
+			return err
+		}
+		for i := range pools.Pools {
+			pool, err := ExportNodePool(&pools.Pools[i], c.ClusterName)
+			if err != nil {
+				return err
+			}
+			if err := m.AddSpec(pool); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backupCatalog adds catalog entities and CSAR of each entity
+func (a *TcaApi) backupCatalog(ctx context.Context, m *BackupManifest) error {
+
+	catalog, err := a.GetEntireCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range catalog.Entity {
+		name := p.VnfProductName
+		if p.UserDefinedData != nil && len(p.UserDefinedData.Name) > 0 {
+			name = p.UserDefinedData.Name
+		}
+
+		glog.Infof("Backup catalog entity %s", name)
+
+		csar, err := a.rest.GetVnfPkgmContent(ctx, p.PID)
+		if err != nil {
+			return fmt.Errorf("catalog %s: %v", name, err)
+		}
+
+		m.Add(BackupKindCatalog, name, "", string(BackupKindCatalog)+"/"+backupFileName(name)+".csar", csar)
+	}
+
+	return nil
+}
+
+// Backup api call stores cloud providers, cluster templates, clusters,
+// node pools, extensions, repositories, catalog entities including CSAR
+// and CNF instance parameters in a single zip archive. Kubernetes clusters
+// registered as cloud providers by TCA skipped. Passwords that TCA doesn't
+// return are empty and must be provided to restore.
+func (a *TcaApi) Backup(ctx context.Context, req *BackupApiReq) (*BackupManifest, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	if len(req.FileName) == 0 {
+		return nil, api_errors.NewInvalidArgument("file name")
+	}
+
+	m := NewBackupManifest(a.GetBaseUrl())
+
+	vims, err := a.GetVims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vims.TenantsList {
+		vim := &vims.TenantsList[i]
+		if strings.EqualFold(vim.VimType, models.VimTypeKubernetes) {
+			continue
+		}
+		spec, err := ExportCloudProvider(vim)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.AddSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	templates, err := a.GetClusterTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates.ClusterTemplates {
+		spec, err := ExportClusterTemplate(&templates.ClusterTemplates[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := m.AddSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.backupClusters(ctx, m); err != nil {
+		return nil, err
+	}
+
+	extensions, err := a.GetExtensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range extensions.ExtensionsList {
+		spec, err := ExportExtension(&extensions.ExtensionsList[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := m.AddSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	repos, err := a.GetRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.Marshal(repos)
+	if err != nil {
+		return nil, err
+	}
+	m.Add(BackupKindRepos, string(BackupKindRepos), "", string(BackupKindRepos)+".yaml", b)
+
+	if !req.SkipCatalog {
+		if err := a.backupCatalog(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	cnfs, err := a.GetAllInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cnfs.CnfLcms {
+		cnf := &cnfs.CnfLcms[i]
+		if cnf.InstantiationState != StateInstantiated {
+			continue
+		}
+		spec, err := ExportInstance(cnf)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.AddSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.Write(req.FileName); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// restoreCatalog uploads catalog entities that TCA doesn't have
+func (a *TcaApi) restoreCatalog(ctx context.Context, m *BackupManifest, req *RestoreApiReq) []*SiteStepResult {
+
+	var results []*SiteStepResult
+	for _, e := range m.Entries {
+		if e.Kind != BackupKindCatalog {
+			continue
+		}
+
+		r := &SiteStepResult{Id: string(e.Kind) + "/" + e.Name, Kind: e.Kind}
+		results = append(results, r)
+
+		if _, _, err := a.GetCatalogId(ctx, e.Name); err == nil {
+			r.Action = ApplyUnchanged
+			continue
+		}
+
+		if req.IsDryRun {
+			r.Action = ApplyCreated
+			continue
+		}
+
+		glog.Infof("Restore catalog entity %s", e.Name)
+
+		csar, ok := m.File(e.File)
+		if !ok {
+			r.Err = api_errors.NewFileNotFound(e.File)
+			continue
+		}
+
+		pkg, err := a.rest.CreateVnfPkgmVnfd(ctx, client.NewPackageUpload(e.Name))
+		if err != nil {
+			r.Err = err
+			continue
+		}
+
+		if _, err := a.rest.UploadVnfPkgmVnfd(ctx, pkg.Id, csar, backupFileName(e.Name)+".csar"); err != nil {
+			r.Err = err
+			continue
+		}
+
+		r.Action = ApplyCreated
+	}
+
+	return results
+}
+
+// Restore api call replays a backup archive. Catalog entities uploaded
+// first, all other objects applied in order of dependencies same
+// as BringUpSite. Patches applied to specs before restore, i.e.
+// to provide passwords or to restore to a different cloud.
+func (a *TcaApi) Restore(ctx context.Context, req *RestoreApiReq) ([]*SiteStepResult, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	m, err := ReadBackup(req.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	site, err := m.Specs()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Patches) > 0 {
+		if site, err = specs.PatchSpecs(site, req.Patches...); err != nil {
+			return nil, err
+		}
+	}
+
+	results := a.restoreCatalog(ctx, m, req)
+	siteResults, err := a.BringUpSite(ctx, &SiteApiReq{
+		Specs:     site,
+		IsDryRun:  req.IsDryRun,
+		IsVerbose: req.IsVerbose,
+	})
+	if err != nil && siteResults == nil {
+		return results, err
+	}
+
+	results = append(results, siteResults...)
+	return results, siteErr(results)
+}
@@ -0,0 +1,115 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"archive/zip"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test backup archive written and read back
+func TestBackupManifest_Write(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl-backup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := NewBackupManifest("https://tca.cnfdemo.io")
+	assert.NoError(t, m.AddSpec(&specs.SpecCloudProvider{
+		SpecType:    specs.SpecKindProvider,
+		VimName:     "edge",
+		HcxCloudUrl: "https://tca-cp03.cnfdemo.io",
+		Username:    "administrator@vsphere.local",
+	}))
+	assert.NoError(t, m.AddSpec(&specs.SpecNodePool{
+		SpecType: specs.SpecKindNodePool,
+		Name:     "pool01",
+		Cluster:  "edge-workload",
+		Cpu:      2,
+		Networks: []models.Network{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}},
+	}))
+	m.Add(BackupKindCatalog, "unit-test", "", "catalog/unit-test.csar", []byte("csar"))
+
+	fileName := filepath.Join(dir, "backup.zip")
+	assert.NoError(t, m.Write(fileName))
+
+	r, err := ReadBackup(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupFormatVersion, r.Version)
+	assert.Equal(t, "https://tca.cnfdemo.io", r.Source)
+	assert.Equal(t, 3, len(r.Entries))
+	assert.Equal(t, "node_pool/edge-workload/pool01.yaml", r.Entries[1].File)
+	assert.Equal(t, "edge-workload", r.Entries[1].Cluster)
+
+	csar, ok := r.File("catalog/unit-test.csar")
+	assert.True(t, ok)
+	assert.Equal(t, "csar", string(csar))
+
+	site, err := r.Specs()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(site))
+
+	provider, ok := site[0].(*specs.SpecCloudProvider)
+	assert.True(t, ok)
+	assert.Equal(t, "edge", provider.VimName)
+
+	pool, ok := site[1].(*specs.SpecNodePool)
+	assert.True(t, ok)
+	assert.Equal(t, "edge-workload", pool.Cluster)
+	assert.Equal(t, 2, pool.Cpu)
+}
+
+// Test archive of newer version or without manifest rejected
+func TestReadBackup_Invalid(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl-backup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeZip := func(name string, files map[string]string) string {
+		fileName := filepath.Join(dir, name)
+		f, err := os.Create(fileName)
+		assert.NoError(t, err)
+		w := zip.NewWriter(f)
+		for n, content := range files {
+			fw, err := w.Create(n)
+			assert.NoError(t, err)
+			_, err = fw.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		assert.NoError(t, f.Close())
+		return fileName
+	}
+
+	_, err = ReadBackup(writeZip("newer.zip", map[string]string{BackupManifestFile: "version: 99\n"}))
+	assert.Error(t, err)
+
+	_, err = ReadBackup(writeZip("empty.zip", map[string]string{"cluster/edge.yaml": "kind: cluster\n"}))
+	assert.Error(t, err)
+
+	_, err = ReadBackup(writeZip("escape.zip", map[string]string{"../escape.yaml": "kind: cluster\n"}))
+	assert.Error(t, err)
+}
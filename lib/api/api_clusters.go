@@ -94,7 +94,7 @@ func (a *TcaApi) GetClusterNodePool(ctx context.Context, clusterId string, nodeP
 		}
 	}
 
-	return a.rest.GetClusterNodePool(_clusterid, _nodePoolId)
+	return a.rest.GetClusterNodePool(ctx, _clusterid, _nodePoolId)
 }
 
 // GetClusterTask method return list task models.ClusterTask
@@ -273,18 +273,19 @@ func (a *TcaApi) CreateClusters(ctx context.Context, req *ClusterCreateApiReq) (
 		glog.Infof("Duplicate name regenerated new name '%v'", req.Spec.Name)
 	}
 
-	if ok, _ := a.checkClusterAddrConflict(ctx, req.Spec); ok {
+	// conflict resolved after spec validated, if requested
+	if ok, _ := a.checkClusterAddrConflict(ctx, req.Spec); ok && !req.IsFixConflict {
 		return nil, fmt.Errorf("cluster IP %s already in use", req.Spec.EndpointIP)
 	}
 
 	// resolve template id, and cluster type
-	req.Spec.ClusterTemplateId, err = a.NormalizeTemplateId(req.Spec.ClusterTemplateId, req.Spec.ClusterType)
+	req.Spec.ClusterTemplateId, err = a.NormalizeTemplateId(ctx, req.Spec.ClusterTemplateId, req.Spec.ClusterType)
 	if err != nil {
 		return nil, err
 	}
 
 	// get template and validate specs
-	t, err := a.rest.GetClusterTemplate(req.Spec.ClusterTemplateId)
+	t, err := a.rest.GetClusterTemplate(ctx, req.Spec.ClusterTemplateId)
 	if err != nil {
 		return nil, err
 	}
@@ -355,13 +356,13 @@ func (a *TcaApi) CreateClusters(ctx context.Context, req *ClusterCreateApiReq) (
 		return &models.TcaTask{}, nil
 	}
 
-	task, err := a.rest.CreateCluster(req.Spec)
+	task, err := a.rest.CreateCluster(ctx, req.Spec)
 	if err != nil {
 		return nil, err
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitTaskFinish(context.Background(), task, TaskStateSuccess, BlockMaxRetryTimer, req.IsBlocking)
+		err := a.WaitTask(ctx, task, TaskStateSuccess)
 		if err != nil {
 			return task, err
 		}
@@ -407,7 +408,7 @@ func (a *TcaApi) DeleteCluster(ctx context.Context, req *ClusterDeleteApiReq) (*
 
 	// block and wait task to finish
 	if req.IsBlocking {
-		err := a.BlockWaitTaskFinish(ctx, task, TaskStateSuccess, BlockMaxRetryTimer, req.IsVerbose)
+		err := a.WaitTask(ctx, task, TaskStateSuccess)
 		if err != nil {
 			return task, err
 		}
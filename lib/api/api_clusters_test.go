@@ -847,6 +847,7 @@ func TestCreateBlockDeleteCluster(t *testing.T) {
 }
 
 var newManagementCluster = `---
+kind: cluster
 name: unittest
 clusterPassword: VMware1!
 clusterTemplateId: "55e69a3c-d92b-40ca-be51-9c6585b89ad7"
@@ -898,6 +899,7 @@ placementParams:
 
 var WorkloadCluster = `
 ---
+kind: cluster
 name: edge-workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -966,6 +968,7 @@ placementParams:
 
 var YamlBrokenWorkload = `
 ---
+kind: cluster
 name: edge-workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1033,6 +1036,7 @@ placementParams:
 `
 var CreateDeleteTest01 = `
 ---
+kind: cluster
 name: edge-workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1101,6 +1105,7 @@ placementParams:
 
 // no template id
 var NewManagementClusterFailCase01 = `---
+kind: cluster
 name: edge-mgmt-test01
 clusterPassword: VMware1!
 clusterType: MANAGEMENT
@@ -1150,6 +1155,7 @@ placementParams:
       type: ClusterComputeResource`
 
 var NewManagementClusterFailCase02 = `---
+kind: cluster
 name: unit_test
 clusterPassword: VMware1!
 clusterTemplateId: "55e69a3c-d92b-40ca-be51-9c6585b89ad7"
@@ -1200,6 +1206,7 @@ placementParams:
 
 var InvalidMgmtCluster = `
 ---
+kind: cluster
 name: edge-workload-test01
 managementClusterId: wrong
 clusterPassword: VMware1!
@@ -1268,6 +1275,7 @@ placementParams:
 
 var WrongMgmtTemplateId = `
 ---
+kind: cluster
 name: edge-workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1335,6 +1343,7 @@ placementParams:
 
 var InvalidDatastoreGlobal = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1403,6 +1412,7 @@ placementParams:
 
 var InvalidDatastoreWorker = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1471,6 +1481,7 @@ placementParams:
 
 var InvalidDatastoreMasterNode = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1539,6 +1550,7 @@ placementParams:
 
 var InvalidFolderGlobal = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1607,6 +1619,7 @@ placementParams:
 
 var InvalidMasterNodeFolder = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1675,6 +1688,7 @@ placementParams:
 
 var InvalidWorkerNodeFolder = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1743,6 +1757,7 @@ placementParams:
 
 var InvalidDatastoreUrl = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1811,6 +1826,7 @@ placementParams:
 
 var InvalidNetworkPath = `
 ---
+kind: cluster
 name: workload-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -1878,6 +1894,7 @@ placementParams:
 `
 
 var newTestWorkloadCluster = `
+kind: cluster
 name: edge-test01
 managementClusterId: edge-mgmt-test01
 clusterPassword: VMware1!
@@ -0,0 +1,516 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	errnos "github.com/spyroot/tcactl/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// elementMarker value of a synthetic field that indicates
+	// a named list element present in an object.
+	elementMarker = "{}"
+)
+
+// elementKeys fields used to identify list elements,
+// in order of preference.
+var elementKeys = []string{"name", "label", "vimName"}
+
+var (
+	// poolIgnoreFields node pool fields that TCA manages, cluster is spec only field
+	poolIgnoreFields = []string{"kind", "id", "cluster", "status", "nodes", "activeTasksCount", "isNodeCustomizationDeprecated"}
+
+	// templateIgnoreFields cluster template fields that TCA manages
+	templateIgnoreFields = []string{"kind", "id", "tags"}
+
+	// clusterIgnoreFields cluster spec fields that TCA doesn't return
+	clusterIgnoreFields = []string{"kind", "clusterPassword", "hcxCloudUrl", "vmTemplate",
+		"location", "clusterConfig", "placementParams"}
+
+	// extensionIgnoreFields extension fields that TCA manages or encodes
+	extensionIgnoreFields = []string{"kind", "accessInfo", "extensionId", "state"}
+
+	// providerIgnoreFields cloud provider fields that TCA doesn't return
+	providerIgnoreFields = []string{"kind", "password", "tenantName"}
+
+	// instanceIgnoreFields instance spec fields that TCA doesn't return,
+	// cloud name and vim type derived from a target cluster.
+	instanceIgnoreFields = []string{"kind", "cloud_name", "vim_type", "password", "repo_password",
+		"flavor_name", "default_repo", "use_linked_repo", "auto_name",
+		"disableGrant", "ignoreGrantFailure", "disableAutoRollback"}
+)
+
+// NormalizedSpec is a common form of a spec or TCA object,
+// field path mapped to a value. Named list elements addressed
+// by name, i.e. workerNodes[default-pool01].cpu
+type NormalizedSpec map[string]string
+
+// FieldDiff a single field level difference
+type FieldDiff struct {
+
+	// Field a field path
+	Field string
+
+	// Spec value in local spec
+	Spec string
+
+	// Live value in TCA
+	Live string
+
+	// InSpec field present in local spec
+	InSpec bool
+
+	// InLive field present in TCA
+	InLive bool
+}
+
+// SpecDiff result of diff between a local spec and TCA object
+type SpecDiff struct {
+
+	// Kind spec kind
+	Kind specs.SpecType
+
+	// Name object name
+	Name string
+
+	// IsNew true if object not found in TCA
+	IsNew bool
+
+	// Fields a list of fields that differ
+	Fields []FieldDiff
+}
+
+// HasDrift returns true if live object differs from a spec
+func (d *SpecDiff) HasDrift() bool {
+	return d != nil && (d.IsNew || len(d.Fields) > 0)
+}
+
+// toGenericMap converts object to generic map via json encoder,
+// json used since spec and response share a field names.
+func toGenericMap(obj interface{}) (map[string]interface{}, error) {
+
+	m := make(map[string]interface{})
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// isEmptyValue returns true for nil, zero, empty string, empty list and map.
+// Spec doesn't distinguish unset numeric field from zero.
+func isEmptyValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case float64:
+		return t == 0
+	case string:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// scalarString formats scalar value
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// elementKey returns a key that identifies list element,
+// empty string if element doesn't have identity
+func elementKey(v interface{}) string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, k := range elementKeys {
+		if s, ok := m[k].(string); ok && len(s) > 0 {
+			return s
+		}
+	}
+	return ""
+}
+
+// flatten walks generic map and adds each scalar to NormalizedSpec.
+// Scalar lists compared as a sorted set, lists of objects keyed by name
+// if all elements have unique name, otherwise by index.
+func flatten(prefix string, v interface{}, ignore map[string]bool, out NormalizedSpec) {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			if ignore[k] || isEmptyValue(e) {
+				continue
+			}
+			p := k
+			if len(prefix) > 0 {
+				p = prefix + "." + k
+			}
+			flatten(p, e, ignore, out)
+		}
+	case []interface{}:
+		keys := make([]string, len(t))
+		seen := make(map[string]bool)
+		keyed := true
+		for i, e := range t {
+			keys[i] = elementKey(e)
+			if len(keys[i]) == 0 || seen[keys[i]] {
+				keyed = false
+			}
+			seen[keys[i]] = true
+		}
+
+		if _, ok := t[0].(map[string]interface{}); !ok {
+			var values []string
+			for _, e := range t {
+				values = append(values, scalarString(e))
+			}
+			sort.Strings(values)
+			out[prefix] = "[" + strings.Join(values, ", ") + "]"
+			return
+		}
+
+		for i, e := range t {
+			p := fmt.Sprintf("%s[%d]", prefix, i)
+			if keyed {
+				p = fmt.Sprintf("%s[%s]", prefix, keys[i])
+				out[p] = elementMarker
+			}
+			flatten(p, e, ignore, out)
+		}
+	default:
+		out[prefix] = scalarString(t)
+	}
+}
+
+// NormalizeObject converts a spec or a response object to a common form,
+// ignore is a list of field names skipped at any depth.
+func NormalizeObject(obj interface{}, ignore ...string) (NormalizedSpec, error) {
+
+	m, err := toGenericMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeMap(m, ignore...), nil
+}
+
+// normalizeMap flattens generic map
+func normalizeMap(m map[string]interface{}, ignore ...string) NormalizedSpec {
+
+	skip := make(map[string]bool)
+	for _, s := range ignore {
+		skip[s] = true
+	}
+
+	out := NormalizedSpec{}
+	flatten("", m, skip, out)
+
+	return out
+}
+
+// DiffNormalized compares normalized spec and live object. Only fields
+// present in spec compared, since TCA object holds many fields that spec
+// doesn't set. Named list elements present only in TCA reported as well.
+func DiffNormalized(spec NormalizedSpec, live NormalizedSpec) []FieldDiff {
+
+	var diffs []FieldDiff
+
+	fields := make(map[string]bool)
+	for k := range spec {
+		fields[k] = true
+	}
+	for k, v := range live {
+		if v == elementMarker {
+			fields[k] = true
+		}
+	}
+
+	var paths []string
+	for k := range fields {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		s, inSpec := spec[p]
+		l, inLive := live[p]
+		if inSpec && inLive && s == l {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{
+			Field:  p,
+			Spec:   s,
+			Live:   l,
+			InSpec: inSpec,
+			InLive: inLive,
+		})
+	}
+
+	return diffs
+}
+
+// templateSpecForm normalizes cluster template spec
+func templateSpecForm(spec *specs.SpecClusterTemplate) (NormalizedSpec, error) {
+
+	m, err := toGenericMap(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// top level kubernetes version is spec only field
+	delete(m, "kubernetesVersion")
+
+	return normalizeMap(m, templateIgnoreFields...), nil
+}
+
+// instanceSpecForm normalizes instance spec
+func instanceSpecForm(spec *specs.InstanceRequestSpec) (NormalizedSpec, error) {
+
+	m, err := toGenericMap(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// spec reader defaults node pool and namespace
+	delete(m, "node_pool")
+	delete(m, "namespace")
+
+	return normalizeMap(m, instanceIgnoreFields...), nil
+}
+
+// isSpecChanged returns true if live object differs from normalized spec,
+// i.e. diff reports a drift for it.
+func isSpecChanged(spec NormalizedSpec, live interface{}, ignore ...string) (bool, error) {
+
+	liveForm, err := NormalizeObject(live, ignore...)
+	if err != nil {
+		return false, err
+	}
+
+	return len(DiffNormalized(spec, liveForm)) > 0, nil
+}
+
+// Diff api call compares a local spec with a live object in TCA and returns
+// field level differences. If object not found, all spec fields reported
+// as a new. Node pool spec requires req.Cluster.
+func (a *TcaApi) Diff(ctx context.Context, req *DiffApiReq) (*SpecDiff, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	if req.Spec == nil {
+		return nil, errnos.SpecNil
+	}
+
+	var (
+		d         = &SpecDiff{Kind: req.Spec.Kind()}
+		specForm  NormalizedSpec
+		liveForm  = NormalizedSpec{}
+		err       error
+		liveFound = true
+	)
+
+	switch spec := req.Spec.(type) {
+	case *specs.SpecNodePool:
+		d.Name = spec.Name
+		cluster := req.Cluster
+		if len(spec.Cluster) > 0 {
+			cluster = spec.Cluster
+		}
+		if len(cluster) == 0 {
+			return nil, api_errors.NewInvalidArgument("cluster")
+		}
+		ignore := poolIgnoreFields
+		if specForm, err = NormalizeObject(spec, ignore...); err != nil {
+			return nil, err
+		}
+		pools, err := a.GetNodePool(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		live, err := pools.GetPoolByName(spec.Name)
+		if err == nil {
+			liveForm, err = NormalizeObject(live, ignore...)
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	case *specs.SpecClusterTemplate:
+		d.Name = spec.Name
+		ignore := templateIgnoreFields
+		if specForm, err = templateSpecForm(spec); err != nil {
+			return nil, err
+		}
+		live, err := a.GetClusterTemplate(ctx, spec.Name)
+		if err == nil {
+			liveForm, err = NormalizeObject(live, ignore...)
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	case *specs.SpecCluster:
+		d.Name = spec.Name
+		ignore := clusterIgnoreFields
+		m, err := toGenericMap(spec)
+		if err != nil {
+			return nil, err
+		}
+		// spec can hold a names, live object holds ids
+		if !IsValidUUID(spec.ClusterTemplateId) {
+			if id, err := a.ResolveTemplateId(ctx, spec.ClusterTemplateId); err == nil {
+				m["clusterTemplateId"] = id
+			}
+		}
+		if len(spec.ManagementClusterId) > 0 && !IsValidUUID(spec.ManagementClusterId) {
+			if id, err := a.ResolveClusterName(ctx, spec.ManagementClusterId); err == nil && len(id) > 0 {
+				m["managementClusterId"] = id
+			}
+		}
+		specForm = normalizeMap(m, ignore...)
+		live, err := a.GetCluster(ctx, spec.Name)
+		if err == nil {
+			var lm map[string]interface{}
+			if lm, err = toGenericMap(live); err == nil {
+				// live object uses different name for same fields
+				lm["name"] = live.ClusterName
+				if live.ClusterTemplate != nil {
+					lm["clusterTemplateId"] = live.ClusterTemplate.Id
+				}
+				liveForm = normalizeMap(lm, ignore...)
+			}
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	case *specs.SpecExtension:
+		d.Name = spec.Name
+		ignore := extensionIgnoreFields
+		if specForm, err = NormalizeObject(spec, ignore...); err != nil {
+			return nil, err
+		}
+		extensions, err := a.GetExtension(ctx, spec.Name)
+		if err == nil {
+			live, findErr := extensions.FindExtension(spec.Name)
+			if err = findErr; err == nil {
+				liveForm, err = NormalizeObject(live, ignore...)
+			}
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	case *specs.SpecCloudProvider:
+		d.Name = spec.VimName
+		ignore := providerIgnoreFields
+		if specForm, err = NormalizeObject(spec, ignore...); err != nil {
+			return nil, err
+		}
+		vim, err := a.ResolveVim(ctx, spec.VimName)
+		if err == nil {
+			live, exportErr := ExportCloudProvider(vim)
+			if err = exportErr; err == nil {
+				liveForm, err = NormalizeObject(live, ignore...)
+			}
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	case *specs.InstanceRequestSpec:
+		d.Name = spec.InstanceName
+		ignore := instanceIgnoreFields
+		if specForm, err = instanceSpecForm(spec); err != nil {
+			return nil, err
+		}
+		cnf, err := a.getInstance(ctx, spec.InstanceName)
+		if err == nil {
+			live, exportErr := ExportInstance(cnf)
+			if err = exportErr; err == nil {
+				liveForm, err = NormalizeObject(live, ignore...)
+			}
+		}
+		if err != nil {
+			liveFound = false
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+	default:
+		return nil, api_errors.NewInvalidSpec(fmt.Sprintf("diff not supported for spec kind '%s'", req.Spec.Kind()))
+	}
+
+	glog.Infof("Comparing %s %s, found in tca %v", d.Kind, d.Name, liveFound)
+
+	d.IsNew = !liveFound
+	d.Fields = DiffNormalized(specForm, liveForm)
+
+	return d, nil
+}
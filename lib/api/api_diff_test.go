@@ -0,0 +1,93 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Test normalized form of node pool spec
+func TestNormalizeObject(t *testing.T) {
+
+	spec := &specs.SpecNodePool{
+		SpecType: specs.SpecKindNodePool,
+		Name:     "pool01",
+		Cpu:      2,
+		Labels:   []string{"zone=a", "type=pool01"},
+		Networks: []models.Network{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}},
+	}
+
+	n, err := NormalizeObject(spec, "kind")
+	assert.NoError(t, err)
+	assert.Equal(t, "pool01", n["name"])
+	assert.Equal(t, "2", n["cpu"])
+	assert.Equal(t, "[type=pool01, zone=a]", n["labels"])
+	assert.Equal(t, elementMarker, n["networks[MANAGEMENT]"])
+	assert.Equal(t, "tkg-dhcp", n["networks[MANAGEMENT].networkName"])
+	_, ok := n["kind"]
+	assert.False(t, ok)
+}
+
+// Test diff between node pool spec and live node pool
+func TestDiffNormalized(t *testing.T) {
+
+	spec := &specs.SpecNodePool{
+		Name:     "pool01",
+		Cpu:      4,
+		Replica:  1,
+		Labels:   []string{"type=pool01"},
+		Networks: []models.Network{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}},
+	}
+
+	live := &response.NodesSpecs{
+		Id:      "9411f70f-d24d-4842-ab56-b7214d",
+		Name:    "pool01",
+		Cpu:     2,
+		Replica: 1,
+		Labels:  []string{"type=pool01"},
+		Status:  "ACTIVE",
+		Networks: []models.Network{
+			{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"},
+			{Label: "WORKLOAD", NetworkName: "sriov"},
+		},
+	}
+
+	s, err := NormalizeObject(spec, poolIgnoreFields...)
+	assert.NoError(t, err)
+	l, err := NormalizeObject(live, poolIgnoreFields...)
+	assert.NoError(t, err)
+
+	diffs := DiffNormalized(s, l)
+	assert.Equal(t, 2, len(diffs))
+
+	assert.Equal(t, "cpu", diffs[0].Field)
+	assert.Equal(t, "4", diffs[0].Spec)
+	assert.Equal(t, "2", diffs[0].Live)
+
+	assert.Equal(t, "networks[WORKLOAD]", diffs[1].Field)
+	assert.False(t, diffs[1].InSpec)
+	assert.True(t, diffs[1].InLive)
+
+	d := SpecDiff{Fields: diffs}
+	assert.True(t, d.HasDrift())
+	assert.Equal(t, 0, len(DiffNormalized(s, s)))
+}
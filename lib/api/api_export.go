@@ -0,0 +1,452 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	errnos "github.com/spyroot/tcactl/pkg/errors"
+	"sort"
+)
+
+// convertObject converts TCA object to a spec via json encoder,
+// spec and TCA object share json field names.
+func convertObject(obj interface{}, spec interface{}) error {
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, spec)
+}
+
+// ExportNodePool converts live node pool to a node pool spec,
+// fields managed by TCA removed. cluster is a cluster name
+// stored in spec as a target cluster.
+func ExportNodePool(pool *response.NodesSpecs, cluster string) (*specs.SpecNodePool, error) {
+
+	if pool == nil {
+		return nil, errnos.SpecNil
+	}
+
+	spec := &specs.SpecNodePool{}
+	if err := convertObject(pool, spec); err != nil {
+		return nil, err
+	}
+
+	spec.SpecType = specs.SpecKindNodePool
+	spec.Cluster = cluster
+	spec.Id = ""
+	spec.Status = ""
+	spec.Nodes = nil
+	spec.ActiveTasksCount = 0
+	spec.IsNodeCustomizationDeprecated = false
+
+	return spec, nil
+}
+
+// ExportClusterTemplate converts live cluster template to a template spec,
+// template id and tags that TCA created removed.
+func ExportClusterTemplate(t *response.ClusterTemplateSpec) (*specs.SpecClusterTemplate, error) {
+
+	if t == nil {
+		return nil, errnos.SpecNil
+	}
+
+	spec := &specs.SpecClusterTemplate{}
+	if err := convertObject(t, spec); err != nil {
+		return nil, err
+	}
+
+	spec.SpecType = specs.SpecKindTemplate
+	spec.Id = ""
+
+	tags := spec.Tags[:0]
+	for _, tag := range spec.Tags {
+		if !tag.AutoCreated {
+			tags = append(tags, tag)
+		}
+	}
+	spec.Tags = tags
+	if len(spec.Tags) == 0 {
+		spec.Tags = nil
+	}
+
+	return spec, nil
+}
+
+// ExportCluster converts live cluster to a cluster spec. TCA doesn't return
+// vm template, placement, cluster config and location of a cluster,
+// these fields are taken from a payload of cluster create task if provided.
+// Cluster template and management cluster stored as ids, caller
+// resolves them to names.  Cluster password never exported.
+func ExportCluster(cluster *response.ClusterSpec, payload *models.TaskPayload) (*specs.SpecCluster, error) {
+
+	if cluster == nil {
+		return nil, errnos.SpecNil
+	}
+
+	spec := &specs.SpecCluster{
+		SpecType:            specs.SpecKindCluster,
+		Name:                cluster.ClusterName,
+		ClusterType:         cluster.ClusterType,
+		EndpointIP:          cluster.EndpointIP,
+		ManagementClusterId: cluster.ManagementClusterId,
+	}
+
+	if cluster.ClusterTemplate != nil {
+		spec.ClusterTemplateId = cluster.ClusterTemplate.Id
+	}
+
+	// placement of each node pool by name, from a create request
+	placement := map[string][]models.PlacementParams{}
+
+	if payload != nil {
+		spec.VmTemplate = payload.VmTemplate
+		spec.HcxCloudUrl = payload.HcxCloudUrl
+		for _, p := range payload.PlacementParams {
+			spec.PlacementParams = append(spec.PlacementParams, models.PlacementParams{Name: p.Name, Type: p.Type})
+		}
+		for _, n := range payload.MasterNodes {
+			for _, p := range n.PlacementParams {
+				placement[n.Name] = append(placement[n.Name], models.PlacementParams{Name: p.Name, Type: p.Type})
+			}
+		}
+		for _, n := range payload.WorkerNodes {
+			for _, p := range n.PlacementParams {
+				placement[n.Name] = append(placement[n.Name], models.PlacementParams{Name: p.Name, Type: p.Type})
+			}
+		}
+		if len(payload.ClusterConfig.Csi) > 0 || len(payload.ClusterConfig.Tools) > 0 {
+			spec.ClusterConfig = &specs.ClusterConfig{}
+			if err := convertObject(payload.ClusterConfig, spec.ClusterConfig); err != nil {
+				return nil, err
+			}
+		}
+		if len(payload.Location.City) > 0 {
+			location := payload.Location
+			spec.Location = &location
+		}
+	}
+
+	for _, n := range cluster.MasterNodes {
+		spec.MasterNodes = append(spec.MasterNodes, models.TypeNode{
+			Name:            n.Name,
+			Networks:        n.Networks,
+			PlacementParams: placement[n.Name],
+		})
+	}
+	for _, n := range cluster.WorkerNodes {
+		spec.WorkerNodes = append(spec.WorkerNodes, models.TypeNode{
+			Name:            n.Name,
+			Networks:        n.Networks,
+			PlacementParams: placement[n.Name],
+		})
+	}
+
+	return spec, nil
+}
+
+// ExportCloudProvider converts registered cloud provider to a provider spec,
+// TCA never returns a password, caller must provide it before
+// spec used to register a provider.
+func ExportCloudProvider(vim *response.TenantsDetails) (*specs.SpecCloudProvider, error) {
+
+	if vim == nil {
+		return nil, errnos.SpecNil
+	}
+
+	return &specs.SpecCloudProvider{
+		SpecType:    specs.SpecKindProvider,
+		HcxCloudUrl: vim.HcxCloudURL,
+		VimName:     vim.VimName,
+		Username:    vim.Username,
+	}, nil
+}
+
+// ExportExtension converts live extension to an extension spec,
+// extension id, state and vim ids removed, extension attached
+// to a vim by name.
+func ExportExtension(ext *response.Extension) (*specs.SpecExtension, error) {
+
+	if ext == nil {
+		return nil, errnos.SpecNil
+	}
+
+	spec := &specs.SpecExtension{}
+	if err := convertObject(ext, spec); err != nil {
+		return nil, err
+	}
+
+	spec.SpecType = specs.SpecKindExtension
+	for i := range spec.VimInfo {
+		spec.VimInfo[i].VimId = ""
+		spec.VimInfo[i].VimSystemUUID = ""
+	}
+
+	return spec, nil
+}
+
+// ExportInstance converts CNF instance to an instance spec. Target
+// cloud, cluster and node pool taken from vim connection, repository
+// and namespace from instantiated charts.
+func ExportInstance(cnf *response.CnfLcmExtended) (*specs.InstanceRequestSpec, error) {
+
+	if cnf == nil {
+		return nil, errnos.SpecNil
+	}
+
+	spec := &specs.InstanceRequestSpec{
+		SpecType:     specs.SpecKindInstance,
+		InstanceName: cnf.VnfInstanceName,
+		NfdName:      cnf.VnfCatalogName,
+		Description:  cnf.VnfInstanceDescription,
+	}
+
+	for _, info := range cnf.VimConnectionInfo {
+		spec.VimType = info.VimType
+		if info.Extra != nil {
+			spec.CloudName = info.Extra.VimName
+			spec.ClusterName = info.Extra.VimName
+			spec.NodePoolName = info.Extra.NodePoolName
+		}
+		break
+	}
+
+	charts := cnf.InstantiatedVnfInfo
+	if len(charts) == 0 {
+		charts = cnf.InstantiatedNfInfo
+	}
+
+	vdus := make([]string, 0, len(charts))
+	for vdu := range charts {
+		vdus = append(vdus, vdu)
+	}
+	sort.Strings(vdus)
+
+	for _, vdu := range vdus {
+		chart := charts[vdu]
+		if len(spec.Repo) == 0 {
+			spec.Repo = chart.RepoURL
+			spec.RepoUsername = chart.Username
+			spec.RepoPassword = chart.Password
+			spec.Namespace = chart.Namespace
+		}
+		spec.AdditionalParams.VduParams = append(spec.AdditionalParams.VduParams, specs.VduParam{
+			Namespace: chart.Namespace,
+			RepoURL:   chart.RepoURL,
+			Username:  chart.Username,
+			Password:  chart.Password,
+			VduName:   vdu,
+		})
+	}
+
+	return spec, nil
+}
+
+// clusterCreatePayload returns a payload of a task that created a cluster,
+// or nil if TCA no longer holds a task.
+func (a *TcaApi) clusterCreatePayload(ctx context.Context, cluster *response.ClusterSpec) *models.TaskPayload {
+
+	task, err := a.GetClusterTask(ctx, cluster.Id, false)
+	if err != nil || task == nil {
+		glog.Infof("Cluster %s task not found %v", cluster.ClusterName, err)
+		return nil
+	}
+
+	for _, item := range task.Items {
+		if item.Request == nil || item.Request.Payload == nil {
+			continue
+		}
+		if item.EntityDetails.Id == cluster.Id || item.Request.Payload.Name == cluster.ClusterName {
+			return item.Request.Payload
+		}
+	}
+
+	return nil
+}
+
+// exportCluster exports a cluster and resolves ids to names,
+// fields that create task doesn't hold resolved from a cloud
+// provider and node pools.
+func (a *TcaApi) exportCluster(ctx context.Context, name string) (*specs.SpecCluster, error) {
+
+	live, err := a.GetCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := ExportCluster(live, a.clusterCreatePayload(ctx, live))
+	if err != nil {
+		return nil, err
+	}
+
+	if live.ClusterTemplate != nil && len(live.ClusterTemplate.Name) > 0 {
+		spec.ClusterTemplateId = live.ClusterTemplate.Name
+	} else if IsValidUUID(spec.ClusterTemplateId) {
+		if t, err := a.GetClusterTemplate(ctx, spec.ClusterTemplateId); err == nil {
+			spec.ClusterTemplateId = t.Name
+		}
+	}
+
+	if IsValidUUID(spec.ManagementClusterId) {
+		clusters, err := a.rest.GetClusters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if mgmt, err := clusters.GetClusterSpec(spec.ManagementClusterId); err == nil {
+			spec.ManagementClusterId = mgmt.ClusterName
+		}
+	}
+
+	if len(spec.HcxCloudUrl) == 0 && len(live.VimId) > 0 {
+		if vim, err := a.ResolveVim(ctx, live.VimId); err == nil {
+			spec.HcxCloudUrl = vim.HcxCloudURL
+		}
+	}
+
+	// worker node placement is a placement of a node pool
+	pools, err := a.GetNodePool(ctx, live.Id)
+	if err == nil {
+		for i, n := range spec.WorkerNodes {
+			if len(n.PlacementParams) > 0 {
+				continue
+			}
+			if pool, err := pools.GetPoolByName(n.Name); err == nil {
+				spec.WorkerNodes[i].PlacementParams = pool.PlacementParams
+			}
+		}
+	}
+
+	if len(spec.PlacementParams) == 0 && len(spec.MasterNodes) > 0 {
+		spec.PlacementParams = spec.MasterNodes[0].PlacementParams
+	}
+
+	return spec, nil
+}
+
+// Export api call converts a live cluster, node pool, cluster template,
+// cloud provider, extension or CNF instance to a spec, fields that TCA manages removed
+// and ids resolved to names. Exported spec can be used to
+// create a copy of object.  Node pool requires req.Cluster.
+func (a *TcaApi) Export(ctx context.Context, req *ExportApiReq) (specs.RequestSpec, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	if len(req.Name) == 0 {
+		return nil, api_errors.NewInvalidArgument("name")
+	}
+
+	switch req.Kind {
+	case specs.SpecKindCluster:
+		spec, err := a.exportCluster(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+
+	case specs.SpecKindTemplate:
+		t, err := a.GetClusterTemplate(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ExportClusterTemplate(t)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+
+	case specs.SpecKindNodePool:
+		if len(req.Cluster) == 0 {
+			return nil, api_errors.NewInvalidArgument("cluster")
+		}
+		cluster, err := a.GetCluster(ctx, req.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		pools, err := a.GetNodePool(ctx, cluster.Id)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := pools.GetPoolByName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ExportNodePool(pool, cluster.ClusterName)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+
+	case specs.SpecKindProvider:
+		vim, err := a.ResolveVim(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ExportCloudProvider(vim)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+
+	case specs.SpecKindExtension:
+		extensions, err := a.GetExtensions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ext, err := extensions.FindExtension(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ExportExtension(ext)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+
+	case specs.SpecKindInstance:
+		cnfs, err := a.GetAllInstances(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cnf, err := cnfs.ResolveFromName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ExportInstance(cnf)
+		if err != nil {
+			return nil, err
+		}
+		return spec, nil
+	}
+
+	return nil, api_errors.NewInvalidSpec("export of kind '" + string(req.Kind) + "' not supported")
+}
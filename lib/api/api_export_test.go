@@ -0,0 +1,195 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"encoding/json"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Test node pool export removes fields that TCA manages
+func TestExportNodePool(t *testing.T) {
+
+	live := &response.NodesSpecs{
+		Id:               "9411f70f-d24d-4842-ab56-b7214d",
+		Name:             "pool01",
+		Cpu:              2,
+		Memory:           16384,
+		Replica:          1,
+		Storage:          50,
+		CloneMode:        "linkedClone",
+		Labels:           []string{"type=pool01"},
+		Status:           "ACTIVE",
+		ActiveTasksCount: 1,
+		Networks:         []models.Network{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}},
+		PlacementParams:  []models.PlacementParams{{Name: "core", Type: "ClusterComputeResource"}},
+		Nodes:            []models.Nodes{{}},
+	}
+
+	spec, err := ExportNodePool(live, "edge")
+	assert.NoError(t, err)
+	assert.Equal(t, specs.SpecKindNodePool, spec.Kind())
+	assert.Equal(t, "pool01", spec.Name)
+	assert.Equal(t, "edge", spec.Cluster)
+	assert.Equal(t, 16384, spec.Memory)
+	assert.Equal(t, live.PlacementParams, spec.PlacementParams)
+	assert.Empty(t, spec.Id)
+	assert.Empty(t, spec.Status)
+	assert.Empty(t, spec.Nodes)
+	assert.Zero(t, spec.ActiveTasksCount)
+
+	_, err = ExportNodePool(nil, "edge")
+	assert.Error(t, err)
+}
+
+// Test template export removes id and tags that TCA created
+func TestExportClusterTemplate(t *testing.T) {
+
+	live := &response.ClusterTemplateSpec{
+		Id:          "c3e006c1-e6aa-4591-950b-6f3bedd944d3",
+		Name:        "myworkload",
+		ClusterType: "WORKLOAD",
+		Tags: []response.Tags{
+			{Name: "system", AutoCreated: true},
+			{Name: "edge"},
+		},
+	}
+
+	spec, err := ExportClusterTemplate(live)
+	assert.NoError(t, err)
+	assert.Equal(t, specs.SpecKindTemplate, spec.Kind())
+	assert.Equal(t, "myworkload", spec.Name)
+	assert.Equal(t, "WORKLOAD", spec.ClusterType)
+	assert.Empty(t, spec.Id)
+	assert.Equal(t, 1, len(spec.Tags))
+	assert.Equal(t, "edge", spec.Tags[0].Name)
+}
+
+// Test cluster export takes placement and vm template from create payload
+func TestExportCluster(t *testing.T) {
+
+	live := &response.ClusterSpec{
+		Id:                  "794a675c-777a-47f4-8edb-36a686ef4065",
+		ClusterName:         "edge",
+		ClusterType:         "WORKLOAD",
+		ManagementClusterId: "d1b9e6a5-83b1-4b6f-9b51-5d4e1e31c1a5",
+		Status:              "ACTIVE",
+		EndpointIP:          "10.241.7.222",
+		ClusterTemplate:     &response.ClusterSpecTemplate{Name: "myworkload", Id: "c3e006c1-e6aa-4591-950b-6f3bedd944d3"},
+		MasterNodes: []response.ClusterNodeSpec{
+			{Name: "master", Networks: []models.Networks{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}}},
+		},
+		WorkerNodes: []response.ClusterNodeSpec{
+			{Name: "default-pool01", Networks: []models.Networks{{Label: "MANAGEMENT", NetworkName: "tkg-dhcp"}}},
+		},
+	}
+
+	payload := &models.TaskPayload{}
+	err := json.Unmarshal([]byte(`{
+		"name": "edge",
+		"clusterPassword": "VMware1!",
+		"vmTemplate": "photon-3-kube-v1.20.4+vmware.1",
+		"hcxCloudUrl": "https://tca-cp03.cnfdemo.io",
+		"placementParams": [{"name": "core", "type": "ClusterComputeResource"}],
+		"masterNodes": [{"name": "master", "placementParams": [{"name": "core", "type": "ClusterComputeResource"}]}],
+		"workerNodes": [{"name": "default-pool01", "placementParams": [{"name": "edge", "type": "ClusterComputeResource"}]}]
+	}`), payload)
+	assert.NoError(t, err)
+
+	spec, err := ExportCluster(live, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, specs.SpecKindCluster, spec.Kind())
+	assert.Equal(t, "edge", spec.Name)
+	assert.Equal(t, "10.241.7.222", spec.EndpointIP)
+	assert.Equal(t, live.ClusterTemplate.Id, spec.ClusterTemplateId)
+	assert.Equal(t, payload.VmTemplate, spec.VmTemplate)
+	assert.Equal(t, payload.HcxCloudUrl, spec.HcxCloudUrl)
+	assert.Equal(t, []models.PlacementParams{{Name: "core", Type: "ClusterComputeResource"}}, spec.PlacementParams)
+	assert.Empty(t, spec.ClusterPassword)
+	assert.Equal(t, 1, len(spec.MasterNodes))
+	assert.Equal(t, "default-pool01", spec.WorkerNodes[0].Name)
+	assert.Equal(t, "tkg-dhcp", spec.WorkerNodes[0].Networks[0].NetworkName)
+	assert.Equal(t, "edge", spec.WorkerNodes[0].PlacementParams[0].Name)
+
+	// without create payload only live fields exported
+	spec, err = ExportCluster(live, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, spec.VmTemplate)
+	assert.Empty(t, spec.PlacementParams)
+}
+
+// Test extension export attaches extension to vim by name
+func TestExportExtension(t *testing.T) {
+
+	live := &response.Extension{
+		ExtensionId: "9d0d4ff4-1963-4d89-ac15-2d856768deeb",
+		Name:        "repo",
+		Type:        "Repository",
+		Version:     "v2.x",
+		State:       "ENABLED",
+		InterfaceInfo: &response.ExtensionInterfaceInfo{
+			Url: "https://repo.cnfdemo.io/chartrepo/library",
+		},
+		VimInfo: []response.ExtensionVimInfo{
+			{VimName: "edge", VimId: "vmware_FB40D3DE2967483FBF9033B451DC7571", VimSystemUUID: "b4b4c4c4"},
+		},
+	}
+
+	spec, err := ExportExtension(live)
+	assert.NoError(t, err)
+	assert.Equal(t, specs.SpecKindExtension, spec.Kind())
+	assert.Equal(t, "repo", spec.Name)
+	assert.Equal(t, live.InterfaceInfo.Url, spec.InterfaceInfo.Url)
+	assert.Equal(t, 1, len(spec.VimInfo))
+	assert.Equal(t, "edge", spec.VimInfo[0].VimName)
+	assert.Empty(t, spec.VimInfo[0].VimId)
+}
+
+// Test instance export takes target and repository from live instance
+func TestExportInstance(t *testing.T) {
+
+	live := &response.CnfLcmExtended{
+		CID:                "a0b2e8e5-4d3b-4b8a-8e0f-1c9c2d0e6f11",
+		VnfInstanceName:    "unit-test",
+		VnfCatalogName:     "unit-test-catalog",
+		InstantiationState: StateInstantiated,
+		VimConnectionInfo: []models.VimConnectionInfo{
+			{VimType: "kubernetes", Extra: &models.VimExtra{VimName: "edge", NodePoolName: "default-pool01"}},
+		},
+		InstantiatedVnfInfo: map[string]response.CnfInstantiateEntry{
+			"vdu2": {Namespace: "default", RepoURL: "https://repo.cnfdemo.io/chartrepo/library"},
+			"vdu1": {Namespace: "default", RepoURL: "https://repo.cnfdemo.io/chartrepo/library"},
+		},
+	}
+
+	spec, err := ExportInstance(live)
+	assert.NoError(t, err)
+	assert.Equal(t, specs.SpecKindInstance, spec.Kind())
+	assert.Equal(t, "unit-test", spec.InstanceName)
+	assert.Equal(t, "unit-test-catalog", spec.NfdName)
+	assert.Equal(t, "edge", spec.ClusterName)
+	assert.Equal(t, "default-pool01", spec.NodePoolName)
+	assert.Equal(t, "default", spec.Namespace)
+	assert.Equal(t, 2, len(spec.AdditionalParams.VduParams))
+	assert.Equal(t, "vdu1", spec.AdditionalParams.VduParams[0].VduName)
+}
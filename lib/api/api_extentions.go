@@ -43,7 +43,7 @@ func (a *TcaApi) GetRepos(ctx context.Context) (*response.ReposList, error) {
 
 	var allRepos response.ReposList
 	for _, r := range tenants.TenantsList {
-		repos, err := a.rest.RepositoriesQuery(&specs.RepoQuery{
+		repos, err := a.rest.RepositoriesQuery(ctx, &specs.RepoQuery{
 			QueryFilter: specs.Filter{
 				ExtraFilter: specs.AdditionalFilters{
 					VimID: r.TenantID,
@@ -270,7 +270,7 @@ func (a *TcaApi) GetFilteredExtension(ctx context.Context) (*response.ReposList,
 
 	var allRepos response.ReposList
 	for _, r := range tenants.TenantsList {
-		repos, err := a.rest.RepositoriesQuery(&specs.RepoQuery{
+		repos, err := a.rest.RepositoriesQuery(ctx, &specs.RepoQuery{
 			QueryFilter: specs.Filter{
 				ExtraFilter: specs.AdditionalFilters{
 					VimID: r.TenantID,
@@ -0,0 +1,223 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"net/http"
+)
+
+// pager tracks SOL013 page marker, next page requested
+// only when caller consumed current page.
+type pager struct {
+	ctx    context.Context
+	filter string
+	marker string
+	pos    int
+	size   int
+	done   bool
+	err    error
+
+	// fetch requests page for a marker, returns page size and next marker
+	fetch func(marker string) (int, string, error)
+}
+
+// next advance pager, return false if no more entities left or error
+func (p *pager) next() bool {
+
+	for p.pos+1 >= p.size {
+		if p.done || p.err != nil {
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		n, next, err := p.fetch(p.marker)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		p.done = len(next) == 0 || next == p.marker
+		p.marker = next
+		p.size = n
+		p.pos = -1
+	}
+
+	p.pos++
+	return true
+}
+
+// InstanceIterator iterates over CNF/VNF instances, instances
+// fetched page by page from TCA.
+//
+//	it := a.Instances(ctx, "(eq,vnfdId,nfd_1b6bed2e)")
+//	for it.Next() {
+//		fmt.Println(it.Instance().VnfInstanceName)
+//	}
+//	if it.Err() != nil {
+//		...
+//	}
+type InstanceIterator struct {
+	pager
+	page *response.CnfsExtended
+}
+
+// Instances returns iterator over CNF/VNF instances that match
+// optional SOL013 filter.
+func (a *TcaApi) Instances(ctx context.Context, filter string) *InstanceIterator {
+
+	it := &InstanceIterator{pager: pager{ctx: ctx, filter: filter}}
+	it.fetch = func(marker string) (int, string, error) {
+		if a.rest == nil {
+			return 0, "", fmt.Errorf("rest interface is nil")
+		}
+		page, next, err := a.rest.GetVnflcmPage(ctx, it.filter, marker)
+		if err != nil {
+			return 0, "", err
+		}
+		it.page = page
+		return len(page.CnfLcms), next, nil
+	}
+
+	return it
+}
+
+// Next advance iterator to a next instance
+func (it *InstanceIterator) Next() bool {
+	return it.next()
+}
+
+// Instance returns current instance
+func (it *InstanceIterator) Instance() *response.CnfLcmExtended {
+	if it.page == nil || it.pos < 0 || it.pos >= len(it.page.CnfLcms) {
+		return nil
+	}
+	return &it.page.CnfLcms[it.pos]
+}
+
+// Err returns error that stopped iteration
+func (it *InstanceIterator) Err() error {
+	return it.err
+}
+
+// Collect returns up to limit instances, zero limit returns all.
+func (it *InstanceIterator) Collect(limit int) (*response.CnfsExtended, error) {
+
+	var cnfs response.CnfsExtended
+	for (limit <= 0 || len(cnfs.CnfLcms) < limit) && it.Next() {
+		cnfs.CnfLcms = append(cnfs.CnfLcms, *it.Instance())
+	}
+
+	return &cnfs, it.Err()
+}
+
+// PackageIterator iterates over catalog entities, entities
+// fetched page by page from TCA.
+type PackageIterator struct {
+	pager
+	page *response.VnfPackages
+}
+
+// Packages returns iterator over catalog entities that match
+// optional SOL013 filter.
+func (a *TcaApi) Packages(ctx context.Context, filter string) *PackageIterator {
+
+	it := &PackageIterator{pager: pager{ctx: ctx, filter: filter}}
+	it.fetch = func(marker string) (int, string, error) {
+		if a.rest == nil {
+			return 0, "", fmt.Errorf("rest interface is nil")
+		}
+		page, next, err := a.rest.GetVnfPkgmPage(ctx, it.filter, marker)
+		if err != nil {
+			return 0, "", err
+		}
+		it.page = page
+		return len(page.Entity), next, nil
+	}
+
+	return it
+}
+
+// Next advance iterator to a next catalog entity
+func (it *PackageIterator) Next() bool {
+	return it.next()
+}
+
+// Package returns current catalog entity
+func (it *PackageIterator) Package() *response.VnfPackage {
+	if it.page == nil || it.pos < 0 || it.pos >= len(it.page.Entity) {
+		return nil
+	}
+	return &it.page.Entity[it.pos]
+}
+
+// Err returns error that stopped iteration
+func (it *PackageIterator) Err() error {
+	return it.err
+}
+
+// Collect returns up to limit catalog entities, zero limit returns all.
+func (it *PackageIterator) Collect(limit int) (*response.VnfPackages, error) {
+
+	var pkgs response.VnfPackages
+	for (limit <= 0 || len(pkgs.Entity) < limit) && it.Next() {
+		pkgs.Entity = append(pkgs.Entity, *it.Package())
+	}
+
+	return &pkgs, it.Err()
+}
+
+// isFilterRejected returns true if TCA rejected SOL013 filter
+func isFilterRejected(err error) bool {
+	var apiErr *api_errors.ApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest
+}
+
+// getInstance resolves instance by name or id. Method asks TCA to
+// filter by name or id, if TCA rejects the filter or filter found
+// nothing method falls back to scan of all instances, so name
+// lookup stays case-insensitive.
+func (a *TcaApi) getInstance(ctx context.Context, nameOrId string) (*response.CnfLcmExtended, error) {
+
+	filter := client.FilterEq("vnfInstanceName", nameOrId)
+	if IsValidUUID(nameOrId) {
+		filter = client.FilterEq("id", nameOrId)
+	}
+
+	cnfs, err := a.Instances(ctx, filter).Collect(0)
+	if isFilterRejected(err) {
+		glog.Warningf("TCA rejected filter %s, scanning all instances", filter)
+		cnfs, err = a.Instances(ctx, "").Collect(0)
+	} else if err == nil && len(cnfs.CnfLcms) == 0 {
+		glog.Infof("filter %s found no instances, scanning all instances", filter)
+		cnfs, err = a.Instances(ctx, "").Collect(0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cnfs.ResolveFromName(nameOrId)
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// getPagedSimApi returns api for a dedicated simulator that
+// returns a single entity per page.
+func getPagedSimApi(t *testing.T) *TcaApi {
+
+	server := httptest.NewTLSServer(tcasim.NewServer(nil, tcasim.Options{PageSize: 1}))
+	t.Cleanup(server.Close)
+
+	rest, err := client.NewRestClient(server.URL, true, tcasim.DefaultUsername, tcasim.DefaultPassword)
+	assert.NoError(t, err)
+
+	return getTcaApi(t, rest, false)
+}
+
+func TestInstanceIterator(t *testing.T) {
+
+	ctx := context.Background()
+	a := getPagedSimApi(t)
+
+	pkgs, err := a.Packages(ctx, "(eq,userDefinedData/name,unit_test)").Collect(0)
+	assert.NoError(t, err)
+	if !assert.Len(t, pkgs.Entity, 1) {
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := a.rest.CreateInstance(ctx, &specs.LcmCreateRequest{
+			VnfdId:          pkgs.Entity[0].VnfdID,
+			VnfInstanceName: fmt.Sprintf("iterator_test%d", i),
+		})
+		assert.NoError(t, err)
+	}
+
+	cnfs, err := a.Instances(ctx, "").Collect(0)
+	assert.NoError(t, err)
+	assert.Len(t, cnfs.CnfLcms, 4)
+
+	cnfs, err = a.Instances(ctx, "").Collect(2)
+	assert.NoError(t, err)
+	assert.Len(t, cnfs.CnfLcms, 2)
+
+	cnfs, err = a.Instances(ctx, "(cont,vnfInstanceName,iterator_test)").Collect(0)
+	assert.NoError(t, err)
+	assert.Len(t, cnfs.CnfLcms, 3)
+
+	it := a.Instances(ctx, "(eq,vnfInstanceName,iterator_test1)")
+	assert.True(t, it.Next())
+	assert.Equal(t, "iterator_test1", it.Instance().VnfInstanceName)
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+
+	id, err := a.ResolveInstanceName(ctx, "ITERATOR_TEST2")
+	assert.NoError(t, err, "name lookup must be case insensitive")
+	assert.Equal(t, cnfs.CnfLcms[2].CID, id)
+
+	_, err = a.ResolveInstanceName(ctx, "not_found")
+	assert.ErrorIs(t, err, api_errors.ErrNotFound)
+
+	_, err = a.Instances(ctx, "(like,id,1)").Collect(0)
+	assert.Error(t, err, "invalid filter must fail")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = a.Instances(cancelled, "").Collect(0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTcaApi_getInstance(t *testing.T) {
+
+	var (
+		ctx          = context.Background()
+		calls        int32
+		rejectFilter int32
+		sim          = tcasim.NewServer(nil, tcasim.Options{})
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == client.TcaApiVnfLcmExtensionVnfInstance {
+			atomic.AddInt32(&calls, 1)
+			if atomic.LoadInt32(&rejectFilter) == 1 && len(r.URL.Query().Get("filter")) > 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"status":400,"detail":"filter not supported"}`))
+				return
+			}
+		}
+		sim.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	rest, err := client.NewRestClient(server.URL, true, tcasim.DefaultUsername, tcasim.DefaultPassword)
+	assert.NoError(t, err)
+	_, err = rest.GetAuthorization(ctx)
+	assert.NoError(t, err)
+	a := getTcaApi(t, rest, false)
+
+	instance, err := a.getInstance(ctx, "unit_test_instance")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "unit_test_instance", instance.VnfInstanceName)
+	}
+	assert.Equal(t, int32(1), atomic.SwapInt32(&calls, 0))
+
+	_, err = a.getInstance(ctx, "not_found")
+	assert.ErrorIs(t, err, api_errors.ErrNotFound)
+	assert.Equal(t, int32(2), atomic.SwapInt32(&calls, 0), "filter found nothing, instances scanned")
+
+	instance, err = a.getInstance(ctx, "UNIT_TEST_INSTANCE")
+	if assert.NoError(t, err, "name lookup must be case insensitive") {
+		assert.Equal(t, "unit_test_instance", instance.VnfInstanceName)
+	}
+	assert.Equal(t, int32(2), atomic.SwapInt32(&calls, 0), "filter found nothing, instances scanned")
+
+	atomic.StoreInt32(&rejectFilter, 1)
+	instance, err = a.getInstance(ctx, "unit_test_instance")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "unit_test_instance", instance.VnfInstanceName)
+	}
+	assert.Equal(t, int32(2), atomic.SwapInt32(&calls, 0), "filter rejected, instances scanned")
+}
+
+func TestFilterClusters(t *testing.T) {
+
+	ctx := context.Background()
+	a := getPagedSimApi(t)
+
+	clusters, err := a.FilterClusters(ctx, "(eq,clusterType,WORKLOAD)", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, clusters.Clusters, 1) {
+		assert.Equal(t, "edge-test01", clusters.Clusters[0].ClusterName)
+	}
+
+	clusters, err = a.FilterClusters(ctx, "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, clusters.Clusters, 1)
+
+	tenants, err := a.FilterVimTenants(ctx, "(eq,vimName,edge)", 0)
+	assert.NoError(t, err)
+	assert.Len(t, tenants.TenantsList, 1)
+}
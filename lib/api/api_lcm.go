@@ -49,34 +49,24 @@ func (a *TcaApi) GetInstance(ctx context.Context, NameOrId string) (*response.Lc
 	)
 
 	if !IsValidUUID(NameOrId) {
-		iid, err = a.ResolveInstanceName(NameOrId)
+		iid, err = a.ResolveInstanceName(ctx, NameOrId)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return a.rest.GetRunningVnflcm(iid)
+	return a.rest.GetRunningVnflcm(ctx, iid)
 }
 
 //ResolveInstanceName resolve instance name to id
-func (a *TcaApi) ResolveInstanceName(name string) (string, error) {
+func (a *TcaApi) ResolveInstanceName(ctx context.Context, name string) (string, error) {
 
-	_instances, err := a.rest.GetVnflcm()
+	instance, err := a.getInstance(ctx, name)
 	if err != nil {
 		return "", err
 	}
 
-	instances, ok := _instances.(*response.CnfsExtended)
-	if ok {
-		instance, err := instances.ResolveFromName(name)
-		if err != nil {
-			return "", err
-		}
-
-		return instance.CID, nil
-	}
-
-	return "", fmt.Errorf("not found %s", name)
+	return instance.CID, nil
 }
 
 // CreateCnfInstance - create cnf instance that already in running, not instantiated state
@@ -98,17 +88,7 @@ func (a *TcaApi) CreateCnfInstance(ctx context.Context, req *CreateInstanceApiRe
 		}
 	}
 
-	_instances, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	instances, ok := _instances.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("wrong instance type")
-	}
-
-	instance, err := instances.ResolveFromName(req.InstanceName)
+	instance, err := a.getInstance(ctx, req.InstanceName)
 	if err != nil {
 		return err
 	}
@@ -197,13 +177,21 @@ func (a *TcaApi) CreateCnfInstance(ctx context.Context, req *CreateInstanceApiRe
 		},
 	}
 
+	// LCM state before the call, a failure it left isn't a failure of the call
+	var before *LcmState
+	if req.IsBlocking {
+		if before, err = a.InstanceLcmState(ctx, instance.CID); err != nil {
+			return err
+		}
+	}
+
 	err = a.rest.InstanceInstantiate(ctx, instance.CID, instantiateReq)
 	if err != nil {
 		return err
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitStateChange(ctx, instance.CID, StateInstantiate, DefaultMaxRetry, req.IsVerbose)
+		err := a.WaitInstanceStateSince(ctx, instance.CID, StateInstantiate, before)
 		if err != nil {
 			return err
 		}
@@ -215,18 +203,7 @@ func (a *TcaApi) CreateCnfInstance(ctx context.Context, req *CreateInstanceApiRe
 // DeleteCnfInstance - deletes instance
 func (a *TcaApi) DeleteCnfInstance(ctx context.Context, instanceName string, vimName string, isForce bool) error {
 
-	_instances, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	// for extension request we route to correct printer
-	instances, ok := _instances.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("wrong instance type")
-	}
-
-	instance, err := instances.ResolveFromName(instanceName)
+	instance, err := a.getInstance(ctx, instanceName)
 	if err != nil {
 		return err
 	}
@@ -256,7 +233,7 @@ func (a *TcaApi) DeleteCnfInstance(ctx context.Context, instanceName string, vim
 			return err
 		}
 
-		terminated, err := a.rest.GetRunningVnflcm(instance.CID)
+		terminated, err := a.rest.GetRunningVnflcm(ctx, instance.CID)
 		if err != nil {
 			return err
 		}
@@ -302,18 +279,7 @@ func (a *TcaApi) CnfReconfigure(ctx context.Context, instanceName string, valueF
 		return fmt.Errorf("specify valid path to value file")
 	}
 
-	_instances, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	// for extension request we route to correct printer
-	instances, ok := _instances.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("wrong instance type")
-	}
-
-	instance, err := instances.ResolveFromName(instanceName)
+	instance, err := a.getInstance(ctx, instanceName)
 	if err != nil {
 		return err
 	}
@@ -371,18 +337,7 @@ func (a *TcaApi) CnfMove(ctx context.Context, instanceName string, moveReq *CnfM
 		return fmt.Errorf("instance name empty string")
 	}
 
-	_instances, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	// for extension request we route to correct printer
-	instances, ok := _instances.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("wrong instance type")
-	}
-
-	instance, err := instances.ResolveFromName(instanceName)
+	instance, err := a.getInstance(ctx, instanceName)
 	if err != nil {
 		return err
 	}
@@ -435,17 +390,7 @@ func (a *TcaApi) CnfMove(ctx context.Context, instanceName string, moveReq *CnfM
 // verbose output status on screen after each pool timer.
 func (a *TcaApi) TerminateCnfInstance(ctx context.Context, req *TerminateInstanceApiReq) error {
 
-	respond, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	cnfs, ok := respond.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("Received wrong object type")
-	}
-
-	instance, err := cnfs.ResolveFromName(req.InstanceName)
+	instance, err := a.getInstance(ctx, req.InstanceName)
 	if err != nil {
 		return err
 	}
@@ -467,7 +412,15 @@ func (a *TcaApi) TerminateCnfInstance(ctx context.Context, req *TerminateInstanc
 			"already terminated", req.InstanceName, instance.CID)
 	}
 
-	if err = a.rest.TerminateInstance(
+	// LCM state before the call, a failure it left isn't a failure of the call
+	var before *LcmState
+	if req.IsBlocking {
+		if before, err = a.InstanceLcmState(ctx, instance.CID); err != nil {
+			return err
+		}
+	}
+
+	if err = a.rest.TerminateInstance(ctx,
 		instance.Links.Terminate.Href,
 		&specs.LcmTerminateRequest{
 			TerminationType:            "GRACEFUL",
@@ -477,7 +430,7 @@ func (a *TcaApi) TerminateCnfInstance(ctx context.Context, req *TerminateInstanc
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitStateChange(ctx, instance.CID, "NOT_INSTANTIATED", DefaultMaxRetry, req.IsVerbose)
+		err := a.WaitInstanceStateSince(ctx, instance.CID, StateNotInstantiated, before)
 		if err != nil {
 			return err
 		}
@@ -490,17 +443,7 @@ func (a *TcaApi) TerminateCnfInstance(ctx context.Context, req *TerminateInstanc
 // in current state
 func (a *TcaApi) GetLcmActions(ctx context.Context, instanceName string) (*models.PolicyLinks, error) {
 
-	lcmState, err := a.rest.GetVnflcm()
-	if err != nil {
-		return nil, err
-	}
-
-	cnfs, ok := lcmState.(*response.CnfsExtended)
-	if !ok {
-		return nil, errors.New("Received wrong object type")
-	}
-
-	instance, err := cnfs.ResolveFromName(instanceName)
+	instance, err := a.getInstance(ctx, instanceName)
 	if err != nil {
 		return nil, err
 	}
@@ -512,17 +455,7 @@ func (a *TcaApi) GetLcmActions(ctx context.Context, instanceName string) (*model
 // if flag delete provide will also delete.
 func (a *TcaApi) RollbackCnf(ctx context.Context, instanceName string, doBlock bool, verbose bool) error {
 
-	lcmState, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	cnfs, ok := lcmState.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("Received wrong object type")
-	}
-
-	instance, err := cnfs.ResolveFromName(instanceName)
+	instance, err := a.getInstance(ctx, instanceName)
 	if err != nil {
 		return err
 	}
@@ -531,6 +464,14 @@ func (a *TcaApi) RollbackCnf(ctx context.Context, instanceName string, doBlock b
 		return fmt.Errorf("rollback action not avaliable in current state")
 	}
 
+	// LCM state before the call, a failure it left isn't a failure of the call
+	var before *LcmState
+	if doBlock {
+		if before, err = a.InstanceLcmState(ctx, instance.CID); err != nil {
+			return err
+		}
+	}
+
 	err = a.rest.CnfRollback(ctx, instance.Links.Rollback.Href)
 	if err != nil {
 		glog.Error(err)
@@ -538,7 +479,7 @@ func (a *TcaApi) RollbackCnf(ctx context.Context, instanceName string, doBlock b
 	}
 
 	if doBlock {
-		err := a.BlockWaitStateChange(ctx, instance.CID, StateInstantiate, DefaultMaxRetry, verbose)
+		err := a.WaitInstanceStateSince(ctx, instance.CID, StateInstantiate, before)
 		if err != nil {
 			return err
 		}
@@ -550,17 +491,7 @@ func (a *TcaApi) RollbackCnf(ctx context.Context, instanceName string, doBlock b
 // ResetState reset instance state.
 func (a *TcaApi) ResetState(ctx context.Context, req *ResetInstanceApiReq) error {
 
-	lcmState, err := a.rest.GetVnflcm()
-	if err != nil {
-		return err
-	}
-
-	cnfs, ok := lcmState.(*response.CnfsExtended)
-	if !ok {
-		return errors.New("Received wrong object type")
-	}
-
-	instance, err := cnfs.ResolveFromName(req.InstanceName)
+	instance, err := a.getInstance(ctx, req.InstanceName)
 	if err != nil {
 		return err
 	}
@@ -569,6 +500,14 @@ func (a *TcaApi) ResetState(ctx context.Context, req *ResetInstanceApiReq) error
 		return fmt.Errorf("update action not avaliable in current state")
 	}
 
+	// LCM state before the call, a failure it left isn't a failure of the call
+	var before *LcmState
+	if req.IsBlocking {
+		if before, err = a.InstanceLcmState(ctx, instance.CID); err != nil {
+			return err
+		}
+	}
+
 	err = a.rest.CnfResetState(ctx, instance.Links.UpdateState.Href)
 	if err != nil {
 		glog.Error(err)
@@ -576,7 +515,7 @@ func (a *TcaApi) ResetState(ctx context.Context, req *ResetInstanceApiReq) error
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitStateChange(ctx, instance.CID, StateInstantiate, DefaultMaxRetry, req.IsVerbose)
+		err := a.WaitInstanceStateSince(ctx, instance.CID, StateInstantiate, before)
 		if err != nil {
 			return err
 		}
@@ -593,12 +532,20 @@ func (a *TcaApi) UpdateCnfState(ctx context.Context, req *UpdateInstanceApiReq)
 	)
 
 	if !IsValidUUID(req.InstanceName) {
-		instanceId, err = a.ResolveInstanceName(req.InstanceName)
+		instanceId, err = a.ResolveInstanceName(ctx, req.InstanceName)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// LCM state before the call, a failure it left isn't a failure of the call
+	var before *LcmState
+	if req.IsBlocking {
+		if before, err = a.InstanceLcmState(ctx, instanceId); err != nil {
+			return nil, err
+		}
+	}
+
 	rep, err := a.rest.InstanceUpdateState(ctx, instanceId, req.UpdateReq)
 	if err != nil {
 		glog.Error(err)
@@ -606,7 +553,7 @@ func (a *TcaApi) UpdateCnfState(ctx context.Context, req *UpdateInstanceApiReq)
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitStateChange(ctx, instanceId, StateInstantiate, DefaultMaxRetry, req.IsVerbose)
+		err := a.WaitInstanceStateSince(ctx, instanceId, StateInstantiate, before)
 		if err != nil {
 			glog.Error(err)
 			return nil, err
@@ -619,7 +566,7 @@ func (a *TcaApi) UpdateCnfState(ctx context.Context, req *UpdateInstanceApiReq)
 // DeleteCnf delete CNF or VNF instance
 func (a *TcaApi) DeleteCnf(ctx context.Context, instanceName string) error {
 
-	cnfs, err := a.GetAllPackages()
+	cnfs, err := a.GetAllPackages(ctx)
 	if err != nil {
 		return err
 	}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"context"
+	"errors"
 	"github.com/spyroot/tcactl/lib/client"
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // Fetch all instance
@@ -100,8 +104,8 @@ func TestCreateCnfInstance(t *testing.T) {
 			wantOnCreateErr: true,
 			instanceName:    "unit_test_instance",
 			poolName:        getTestNodePoolName(),
-			vimName:         "wrong cluster",
-			clusterName:     getTestWorkloadClusterName(),
+			vimName:         getTestCloudProvider(),
+			clusterName:     "wrong cluster",
 		},
 		{
 			name:            "Create unit_test instance must pass",
@@ -114,6 +118,22 @@ func TestCreateCnfInstance(t *testing.T) {
 			clusterName:     getTestWorkloadClusterName(),
 		},
 	}
+
+	// instantiate requires terminated instance, terminate one left by earlier run
+	ctx := context.Background()
+	a := getTcaApi(t, getAuthenticatedClient(), false)
+	a.SetWaiter(fastWaiter())
+	if instance, err := a.GetInstance(ctx, getTestInstanceName()); err == nil && instance.IsInstantiated() {
+		if err := a.TerminateCnfInstance(ctx, &TerminateInstanceApiReq{
+			InstanceName: getTestInstanceName(),
+			ClusterName:  getTestWorkloadClusterName(),
+			IsBlocking:   true,
+		}); err != nil {
+			t.Errorf("TerminateCnfInstance() error = %v", err)
+			return
+		}
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
@@ -316,10 +336,36 @@ func TestRollbackCnf(t *testing.T) {
 			wantErr:      false,
 		},
 	}
+
+	// rollback requires failed operation, simulator fails next terminate
+	ctx := context.Background()
+	a := getTcaApi(t, getAuthenticatedClient(), false)
+	a.SetWaiter(fastWaiter())
+	instance, err := a.GetInstance(ctx, getTestInstanceName())
+	if err != nil {
+		t.Errorf("GetInstance() error = %v", err)
+		return
+	}
+	if !instance.IsFailed() {
+		if testSimServer == nil {
+			t.Skipf("rollback requires %s in %s state", getTestInstanceName(), StateFailedTemp)
+		}
+		testSimServer.InjectFailure(instance.Id)
+		if err := a.TerminateCnfInstance(ctx, &TerminateInstanceApiReq{
+			InstanceName: getTestInstanceName(),
+			ClusterName:  getTestWorkloadClusterName(),
+			IsBlocking:   true,
+		}); err == nil {
+			t.Errorf("TerminateCnfInstance() must fail")
+			return
+		}
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 			a := getTcaApi(t, tt.rest, false)
+			a.SetWaiter(fastWaiter())
 			if err := a.RollbackCnf(ctx, tt.instanceName, tt.doBlock, tt.doVerbose); (err != nil) != tt.wantErr {
 				t.Errorf("RollbackCnf() error = %v, wantOnGetErr %v", err, tt.wantErr)
 			}
@@ -402,3 +448,41 @@ func TestTerminateCnfInstance(t *testing.T) {
 		})
 	}
 }
+
+// BlockWaitStateChange must return context error once context canceled
+func TestBlockWaitStateChange_Canceled(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","instantiationState":"NOT_INSTANTIATED",` +
+			`"metadata":{"lcmOperation":"INSTANTIATE","lcmOperationState":"PROCESSING"}}`))
+	}))
+	defer server.Close()
+
+	rest, err := client.NewRestClient(server.URL, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewTcaApi(rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = a.BlockWaitStateChange(ctx, "1", StateInstantiate, DefaultMaxRetry, false)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BlockWaitStateChange() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("BlockWaitStateChange() returned after %v, expected on cancel", time.Since(start))
+	}
+
+	// canceled request returns context error
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := rest.GetRunningVnflcm(ctx, "1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRunningVnflcm() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -19,7 +19,6 @@ package api
 
 import (
 	"context"
-	"github.com/go-playground/validator/v10"
 	"github.com/golang/glog"
 	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/client/response"
@@ -44,7 +43,7 @@ func (a *TcaApi) GetNodePool(ctx context.Context, NameOrId string) (*response.No
 		return nil, err
 	}
 
-	pool, err := a.rest.GetClusterNodePools(resolvedId)
+	pool, err := a.rest.GetClusterNodePools(ctx, resolvedId)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +76,7 @@ func (a *TcaApi) ResolvePoolAndCluster(ctx context.Context,
 	}
 
 	if IsValidUUID(_nodepoolId) {
-		pool, err := a.rest.GetClusterNodePool(_clusterId, _nodepoolId)
+		pool, err := a.rest.GetClusterNodePool(ctx, _clusterId, _nodepoolId)
 		if err != nil {
 			glog.Error(err)
 			return "", "", err
@@ -119,7 +118,7 @@ func (a *TcaApi) DeleteNodePool(ctx context.Context, cluster string, nodePool st
 	if err != nil {
 		return nil, err
 	}
-	task, err := a.rest.DeleteNodePool(_clusterId, _nodepoolId)
+	task, err := a.rest.DeleteNodePool(ctx, _clusterId, _nodepoolId)
 	if err != nil {
 		return nil, err
 	}
@@ -205,13 +204,13 @@ func (a *TcaApi) CreateNewNodePool(ctx context.Context, req *NodePoolCreateApiRe
 	specCopy := req.Spec
 	specCopy.SpecType = ""
 
-	task, err := a.rest.CreateNewNodePool(req.Spec, _clusterId)
+	task, err := a.rest.CreateNewNodePool(ctx, req.Spec, _clusterId)
 	if err != nil {
 		return nil, err
 	}
 
 	if req.IsBlocking {
-		err := a.BlockWaitTaskFinish(context.Background(), task, TaskStateSuccess, BlockMaxRetryTimer, req.IsVerbose)
+		err := a.WaitTask(ctx, task, TaskStateSuccess)
 		if err != nil {
 			return task, err
 		}
@@ -243,8 +242,7 @@ func (a *TcaApi) UpdateNodePool(ctx context.Context, req *NodePoolCreateApiReq)
 	}
 
 	if err := a.nodePoolReqValidator(req.Spec); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		return &models.TcaTask{}, validationErrors
+		return &models.TcaTask{}, err
 	}
 
 	_clusterId, _nodePoolId, err := a.ResolvePoolAndCluster(ctx, req.Cluster, req.Spec.Name)
@@ -265,13 +263,13 @@ func (a *TcaApi) UpdateNodePool(ctx context.Context, req *NodePoolCreateApiReq)
 	specCopy := req.Spec
 	specCopy.SpecType = ""
 
-	task, err := a.rest.UpdateNodePool(req.Spec, _clusterId, _nodePoolId)
+	task, err := a.rest.UpdateNodePool(ctx, req.Spec, _clusterId, _nodePoolId)
 	if err != nil {
 		return nil, err
 	}
 
 	if req.IsBlocking {
-		err = a.BlockWaitTaskFinish(context.Background(), task, TaskStateSuccess, BlockMaxRetryTimer, req.IsVerbose)
+		err = a.WaitTask(ctx, task, TaskStateSuccess)
 		if err != nil {
 			return task, err
 		}
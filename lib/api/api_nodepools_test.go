@@ -109,6 +109,7 @@ func TestTcaCreateNewNodePool(t *testing.T) {
 		wantErr      bool
 		reset        bool
 		withoutLabel bool
+		noReplica    bool
 	}{
 		{
 			name:    "Create node pool from json spec",
@@ -129,10 +130,11 @@ func TestTcaCreateNewNodePool(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "Wrong specString no replica",
-			rest:    getAuthenticatedClient(),
-			spec:    specNodePoolStringReaderHelper(newNodePoolYamlNoReplica),
-			wantErr: true,
+			name:      "Wrong specString no replica",
+			rest:      getAuthenticatedClient(),
+			spec:      specNodePoolStringReaderHelper(newNodePoolYamlNoReplica),
+			wantErr:   true,
+			noReplica: true,
 		},
 		{
 			name:    "Wrong specString no network",
@@ -169,6 +171,11 @@ func TestTcaCreateNewNodePool(t *testing.T) {
 
 			tt.spec.CloneMode = specs.LinkedClone
 
+			// reader defaults replica to 1, clear it so validator sees no replica
+			if tt.noReplica {
+				tt.spec.Replica = 0
+			}
+
 			if tt.spec != nil && tt.withoutLabel == false {
 				tt.spec.Name = generateName()
 				tt.spec.Labels[0] = "type=" + tt.spec.Name
@@ -326,12 +333,12 @@ func TestTcaUpdateNodePool(t *testing.T) {
 var updateYamlPoolSpec = `
 kind: node_pool
 # in this example we just update replica for existing pool
-name: test-cluster01
-cpu: 2
-id: 3acf9b79-f8e5-41d6-997b-58792d3955bb
+name: default-pool01
+cpu: 4
+id: f532cde9-e574-40b6-856d-78fdfc8be3b9
 labels:
   - type=test_cluster01
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -345,7 +352,7 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 activeTasksCount: 0
 isNodeCustomizationDeprecated: false
@@ -356,12 +363,12 @@ isNodeCustomizationDeprecated: false
 var updateMinYamlPoolSpec = `
 kind: node_pool
 # in this example we just update replica for existing pool
-name: test-cluster01
-cpu: 2
-id: 3acf9b79-f8e5-41d6-997b-58792d3955bb
+name: default-pool01
+cpu: 4
+id: f532cde9-e574-40b6-856d-78fdfc8be3b9
 labels:
   - type=test_cluster01
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -375,7 +382,7 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 isNodeCustomizationDeprecated: false
 `
@@ -385,11 +392,11 @@ isNodeCustomizationDeprecated: false
 var updateMinYamlNoID = `
 kind: node_pool
 # in this example we just update replica for existing pool
-name: test-cluster01
-cpu: 2
+name: default-pool01
+cpu: 4
 labels:
   - type=test_cluster01
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -403,7 +410,7 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 isNodeCustomizationDeprecated: false
 `
@@ -413,12 +420,12 @@ isNodeCustomizationDeprecated: false
 var updatePoolAddLabel = `
 kind: node_pool
 # in this example we just update replica for existing pool
-name: test-cluster01
-cpu: 2
+name: default-pool01
+cpu: 4
 labels:
   - type=test_cluster01
   - loc=paloalot
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -432,7 +439,7 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 isNodeCustomizationDeprecated: false
 `
@@ -440,12 +447,12 @@ isNodeCustomizationDeprecated: false
 //  "Only Update of labels, replicas, and machine health check for node pools supported."
 var updateMustWrongCPUCount = `
 kind: node_pool
-name: test-cluster01
+name: default-pool01
 cpu: 3
-id: 3acf9b79-f8e5-41d6-997b-58792d3955bb
+id: f532cde9-e574-40b6-856d-78fdfc8be3b9
 labels:
   - type=test_cluster01
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -459,7 +466,7 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 isNodeCustomizationDeprecated: false
 `
@@ -469,11 +476,11 @@ isNodeCustomizationDeprecated: false
 var updateMustFailNoCPU = `
 # in this example we just update replica for existing pool
 kind: node_pool
-name: test-cluster01
-id: 3acf9b79-f8e5-41d6-997b-58792d3955bb
+name: default-pool01
+id: f532cde9-e574-40b6-856d-78fdfc8be3b9
 labels:
   - type=test_cluster01
-memory: 16384
+memory: 131072
 networks:
   - label: MANAGEMENT
     networkName: /Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0
@@ -487,18 +494,18 @@ placementParams:
   - name: k8s
     type: ResourcePool
 replica: 1
-storage: 50
+storage: 80
 status: ACTIVE,
 isNodeCustomizationDeprecated: false
 `
 
 var updateJsonPoolSpec = `
 {
-	"kind": "node_pool"
-    "name": "test-cluster01",
-    "id": "3acf9b79-f8e5-41d6-997b-58792d3955bb",
-    "cpu": 2,
-    "memory": 16384,
+	"kind": "node_pool",
+    "name": "default-pool01",
+    "id": "f532cde9-e574-40b6-856d-78fdfc8be3b9",
+    "cpu": 4,
+    "memory": 131072,
     "labels": [
         "type=test_cluster"
     ],
@@ -526,7 +533,7 @@ var updateJsonPoolSpec = `
         }
     ],
     "replica": 2,
-    "storage": 50,
+    "storage": 80,
     "config": {},
     "status": "ACTIVE",
     "activeTasksCount": 0,
@@ -0,0 +1,189 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+)
+
+// isTaskFinished returns true if task item succeed or failed
+func isTaskFinished(item *models.TaskItems) bool {
+	return item.Status == TaskStateSuccess || item.Status == TaskStateFailed
+}
+
+// resolveOperation resolves cluster or node pool to entity id and its
+// last operation that matches predicate, i.e. last failed operation.
+func (a *TcaApi) resolveOperation(ctx context.Context, req *OperationApiReq,
+	what string, match func(item *models.TaskItems) bool) (string, string, error) {
+
+	if len(req.Cluster) == 0 {
+		return "", "", api_errors.NewInvalidArgument("cluster name or id is empty")
+	}
+
+	var (
+		entityId string
+		entity   = "cluster " + req.Cluster
+		err      error
+	)
+
+	entityId, err = a.ResolveClusterName(ctx, req.Cluster)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(req.NodePool) > 0 {
+		entity = "node pool " + req.NodePool
+		pool, err := a.GetClusterNodePool(ctx, entityId, req.NodePool)
+		if err != nil {
+			return "", "", err
+		}
+		entityId = pool.Id
+	}
+
+	if len(req.OperationId) > 0 {
+		return entityId, req.OperationId, nil
+	}
+
+	tasks, err := a.rest.GetClustersTask(ctx, specs.NewClusterTaskQuery(entityId))
+	if err != nil {
+		return "", "", err
+	}
+
+	var last *models.TaskItems
+	if tasks != nil {
+		for i := range tasks.Items {
+			item := &tasks.Items[i]
+			if item.EntityDetails.Id != entityId || !match(item) {
+				continue
+			}
+			if last == nil || item.StartTime > last.StartTime {
+				last = item
+			}
+		}
+	}
+
+	if last == nil {
+		return "", "", &TaskNotFound{"no " + what + " operation found for " + entity}
+	}
+
+	glog.Infof("Resolved %s operation id=%s type=%s", what, last.TaskId, last.Type)
+	return entityId, last.TaskId, nil
+}
+
+// RetryOperation retries failed cluster or node pool operation.
+// If operation id not indicated, last failed operation retried.
+// isBlocking will block and wait until retried operation finish.
+func (a *TcaApi) RetryOperation(ctx context.Context, req *OperationApiReq) (*models.TcaTask, error) {
+
+	if req == nil {
+		return nil, api_errors.NewInvalidArgument("nil request")
+	}
+
+	entityId, operationId, err := a.resolveOperation(ctx, req, "failed",
+		func(item *models.TaskItems) bool {
+			return item.Status == TaskStateFailed
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := a.rest.NodePoolRetryTask(ctx, operationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(task.Id) == 0 {
+		task.Id = entityId
+	}
+	if len(task.OperationId) == 0 {
+		task.OperationId = operationId
+	}
+
+	if req.IsBlocking {
+		err := a.Waiter().Wait(ctx, "task "+task.Id, a.retriedTaskCondition(task, operationId))
+		if err != nil {
+			return task, err
+		}
+	}
+
+	return task, nil
+}
+
+// retriedTaskCondition returns TaskCondition for a retried operation.
+// If retry reuses failed operation id, FAILED status ignored until
+// operation left FAILED, i.e. TCA picked retry up.
+func (a *TcaApi) retriedTaskCondition(task *models.TcaTask, failedId string) WaitCondition {
+
+	cond := a.TaskCondition(task, TaskStateSuccess)
+	if task.OperationId != failedId {
+		return cond
+	}
+
+	restarted := false
+	return func(ctx context.Context) (bool, []WaitEvent, error) {
+
+		done, events, err := cond(ctx)
+		if restarted {
+			return done, events, err
+		}
+
+		var failed *TcaTaskFailed
+		if errors.As(err, &failed) {
+			glog.Infof("Operation %s not restarted yet", failedId)
+			return false, events, nil
+		}
+
+		restarted = err == nil
+		return done, events, err
+	}
+}
+
+// AbortOperation aborts running cluster or node pool operation.
+// If operation id not indicated, last running operation aborted.
+func (a *TcaApi) AbortOperation(ctx context.Context, req *OperationApiReq) (*models.TcaTask, error) {
+
+	if req == nil {
+		return nil, api_errors.NewInvalidArgument("nil request")
+	}
+
+	entityId, operationId, err := a.resolveOperation(ctx, req, "running",
+		func(item *models.TaskItems) bool {
+			return !isTaskFinished(item)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := a.rest.NodePoolAbortTask(ctx, operationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(task.Id) == 0 {
+		task.Id = entityId
+	}
+	if len(task.OperationId) == 0 {
+		task.OperationId = operationId
+	}
+
+	return task, nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	simWorkloadClusterId = "6df10113-3c76-48ce-a742-0869fadd60b4"
+	simPoolId            = "f532cde9-e574-40b6-856d-78fdfc8be3b9"
+)
+
+// getOperationSimApi returns api and a dedicated simulator
+func getOperationSimApi(t *testing.T, opts tcasim.Options) (*TcaApi, *tcasim.Server) {
+
+	sim := tcasim.NewServer(nil, opts)
+	server := httptest.NewTLSServer(sim)
+	t.Cleanup(server.Close)
+
+	rest, err := client.NewRestClient(server.URL, true, tcasim.DefaultUsername, tcasim.DefaultPassword)
+	assert.NoError(t, err)
+
+	a := getTcaApi(t, rest, false)
+	a.SetWaiter(fastWaiter())
+
+	return a, sim
+}
+
+func TestTcaApi_RetryOperation(t *testing.T) {
+
+	ctx := context.Background()
+	a, sim := getOperationSimApi(t, tcasim.Options{})
+
+	sim.InjectFailure(simPoolId)
+	failed, err := a.rest.UpgradeNodePool(ctx, simWorkloadClusterId, simPoolId)
+	assert.NoError(t, err)
+
+	req := &OperationApiReq{Cluster: simWorkloadClusterId, NodePool: simPoolId, IsBlocking: true}
+	task, err := a.RetryOperation(ctx, req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, failed.OperationId, task.OperationId, "last failed operation retried")
+	}
+
+	pool, err := a.rest.GetClusterNodePool(ctx, simWorkloadClusterId, simPoolId)
+	assert.NoError(t, err)
+	assert.Equal(t, StateActive, pool.Status)
+
+	var notFound *TaskNotFound
+	_, err = a.RetryOperation(ctx, req)
+	assert.True(t, errors.As(err, &notFound), "no failed operation left, got %v", err)
+}
+
+// retried operation reported FAILED until TCA picks retry up
+func TestTcaApi_RetryOperationLag(t *testing.T) {
+
+	ctx := context.Background()
+	a, sim := getOperationSimApi(t, tcasim.Options{RetryLag: 2})
+
+	sim.InjectFailure(simPoolId)
+	_, err := a.rest.UpgradeNodePool(ctx, simWorkloadClusterId, simPoolId)
+	assert.NoError(t, err)
+
+	req := &OperationApiReq{Cluster: simWorkloadClusterId, NodePool: simPoolId, IsBlocking: true}
+	_, err = a.RetryOperation(ctx, req)
+	assert.NoError(t, err, "FAILED before retry picked up must not fail wait")
+
+	pool, err := a.rest.GetClusterNodePool(ctx, simWorkloadClusterId, simPoolId)
+	assert.NoError(t, err)
+	assert.Equal(t, StateActive, pool.Status)
+}
+
+func TestTcaApi_AbortOperation(t *testing.T) {
+
+	ctx := context.Background()
+	a, _ := getOperationSimApi(t, tcasim.Options{StepDuration: time.Hour})
+
+	req := &OperationApiReq{Cluster: simWorkloadClusterId, NodePool: simPoolId}
+
+	var notFound *TaskNotFound
+	_, err := a.AbortOperation(ctx, req)
+	assert.True(t, errors.As(err, &notFound), "nothing to abort, got %v", err)
+
+	running, err := a.rest.UpgradeNodePool(ctx, simWorkloadClusterId, simPoolId)
+	assert.NoError(t, err)
+
+	task, err := a.AbortOperation(ctx, req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, running.OperationId, task.OperationId)
+	}
+
+	tasks, err := a.rest.GetClustersTask(ctx, specs.NewClusterTaskQuery(simPoolId))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks.Items, 1) {
+		assert.Equal(t, TaskStateFailed, tasks.Items[0].Status)
+	}
+
+	// aborted operation can be retried by id
+	req.OperationId = running.OperationId
+	_, err = a.RetryOperation(ctx, req)
+	assert.NoError(t, err)
+}
+
+func TestOperationItems(t *testing.T) {
+
+	items := []models.TaskItems{
+		{TaskId: "old", Status: TaskStateFailed},
+		{TaskId: "op", Status: "RUNNING"},
+	}
+
+	filtered := operationItems(items, "op")
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "op", filtered[0].TaskId)
+	}
+
+	assert.Len(t, operationItems(items, "CreateNodePool"), 2, "unknown operation keeps all items")
+	assert.Len(t, operationItems(items, ""), 2)
+}
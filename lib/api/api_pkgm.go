@@ -18,37 +18,38 @@
 package api
 
 import (
+	"context"
 	"github.com/golang/glog"
 	"github.com/spyroot/tcactl/lib/client/response"
 )
 
 // GetEntireCatalog - api call return entire TCA catalog.
-func (a *TcaApi) GetEntireCatalog() (*response.VnfPackages, error) {
+func (a *TcaApi) GetEntireCatalog(ctx context.Context) (*response.VnfPackages, error) {
 	glog.Infof("Fetching entire tca catalog")
-	return a.rest.GetAllCatalog()
+	return a.rest.GetAllCatalog(ctx)
 }
 
 // GetVnfPkgm - return catalog entity
 // pkgId is id of package in TCA
 // filter
-func (a *TcaApi) GetVnfPkgm(filter string, pkgId string) (*response.VnfPackages, error) {
+func (a *TcaApi) GetVnfPkgm(ctx context.Context, filter string, pkgId string) (*response.VnfPackages, error) {
 	glog.Infof("Fetching catalog entity id %s", pkgId)
-	return a.rest.GetVnfPkgm(filter, pkgId)
+	return a.rest.GetVnfPkgm(ctx, filter, pkgId)
 }
 
 // GetCatalogId return vnf Package ID and VNFD ID
-func (a *TcaApi) GetCatalogId(catalogId string) (string, string, error) {
+func (a *TcaApi) GetCatalogId(ctx context.Context, catalogId string) (string, string, error) {
 	glog.Infof("Retrieving vnf packages for catalog entity %s.", catalogId)
-	return a.rest.GetPackageCatalogId(catalogId)
+	return a.rest.GetPackageCatalogId(ctx, catalogId)
 }
 
 // GetCatalogAndVdu API method returns
 // catalog entity and vdu package.
-func (a *TcaApi) GetCatalogAndVdu(nfdName string) (*response.VnfPackage, *response.VduPackage, error) {
+func (a *TcaApi) GetCatalogAndVdu(ctx context.Context, nfdName string) (*response.VnfPackage, *response.VduPackage, error) {
 
 	glog.Infof("Fetching catalog entity %s", nfdName)
 
-	vnfCatalog, err := a.rest.GetVnfPkgm("", "")
+	vnfCatalog, err := a.rest.GetVnfPkgm(ctx, "", "")
 	if err != nil || vnfCatalog == nil {
 		glog.Errorf("Failed acquire vnf package information. Error %v", err)
 		return nil, nil, err
@@ -60,6 +61,6 @@ func (a *TcaApi) GetCatalogAndVdu(nfdName string) (*response.VnfPackage, *respon
 		return nil, nil, err
 	}
 
-	v, err := a.rest.GetVnfPkgmVnfd(catalogEntity.PID)
+	v, err := a.rest.GetVnfPkgmVnfd(ctx, catalogEntity.PID)
 	return catalogEntity, v, err
 }
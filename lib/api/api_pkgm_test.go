@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"github.com/spyroot/tcactl/lib/client"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -27,7 +28,7 @@ func TestTcaApiGetEntireCatalog(t *testing.T) {
 			api, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			got, err := api.GetEntireCatalog()
+			got, err := api.GetEntireCatalog(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetVnfPkgm() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -60,7 +61,7 @@ func TestTcaApiGetVnfPkgm(t *testing.T) {
 			api, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			catalog, err := api.GetEntireCatalog()
+			catalog, err := api.GetEntireCatalog(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetVnfPkgm() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -72,7 +73,7 @@ func TestTcaApiGetVnfPkgm(t *testing.T) {
 			}
 
 			for _, p := range catalog.Entity {
-				pkgm, err := api.GetVnfPkgm("", p.PID)
+				pkgm, err := api.GetVnfPkgm(context.Background(), "", p.PID)
 				if err != nil {
 					return
 				}
@@ -116,12 +117,12 @@ func TestTcaApiGetCatalogAndVdu(t *testing.T) {
 			api, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			catalog, err := api.GetEntireCatalog()
+			catalog, err := api.GetEntireCatalog(context.Background())
 			assert.NoError(t, err)
 			assert.NotNil(t, catalog)
 
 			if tt.wantErr {
-				_, _, err := api.GetCatalogAndVdu(tt.CatalogName)
+				_, _, err := api.GetCatalogAndVdu(context.Background(), tt.CatalogName)
 				if err == nil {
 					t.Errorf("GetCatalogAndVdu() must return error")
 					return
@@ -133,7 +134,7 @@ func TestTcaApiGetCatalogAndVdu(t *testing.T) {
 			for _, p := range catalog.Entity {
 				// search by catalog user defined name
 				if tt.useUserDefineField {
-					pkg, vdu, err2 := api.GetCatalogAndVdu(p.UserDefinedData.Name)
+					pkg, vdu, err2 := api.GetCatalogAndVdu(context.Background(), p.UserDefinedData.Name)
 					if !tt.wantErr {
 						assert.NoError(t, err2)
 						assert.NotNil(t, pkg)
@@ -68,6 +68,101 @@ type ClusterCreateApiReq struct {
 	IsFixConflict bool
 }
 
+// ApplyApiReq api request issued to reconcile a spec
+// of any kind against TCA.
+type ApplyApiReq struct {
+
+	// Spec is any specs.RequestSpec, dispatched by spec kind.
+	Spec specs.RequestSpec
+
+	// Cluster is cluster name or cluster id, required only for node pool spec
+	// that doesn't indicate a cluster.
+	Cluster string
+
+	// dry run, resolves action without applying any changes.
+	IsDryRun bool
+
+	// if create or update needs to block
+	IsBlocking bool
+
+	// if blocking request require output progress
+	IsVerbose bool
+
+	// IsCreateOnly if true, an existing object is an error.
+	IsCreateOnly bool
+}
+
+// SiteApiReq api request issued to bring up or tear down
+// a site described by a set of specs.
+type SiteApiReq struct {
+
+	// Specs a site specs of any kind, order doesn't matter.
+	Specs []specs.RequestSpec
+
+	// Cluster is default cluster name or cluster id for node pool spec
+	// that doesn't indicate a cluster.
+	Cluster string
+
+	// dry run, resolves action for each step without applying any changes.
+	IsDryRun bool
+
+	// if steps require output progress
+	IsVerbose bool
+}
+
+// DiffApiReq api request issued to compare
+// a local spec with a live object in TCA.
+type DiffApiReq struct {
+
+	// Spec is a cluster, node pool, template, extension,
+	// cloud provider or instance spec.
+	Spec specs.RequestSpec
+
+	// Cluster is cluster name or cluster id, required only for node pool spec.
+	Cluster string
+}
+
+// ExportApiReq api request issued to export
+// a live TCA object as a spec.
+type ExportApiReq struct {
+
+	// Kind is a cluster, node pool or template.
+	Kind specs.SpecType
+
+	// Name is object name or id.
+	Name string
+
+	// Cluster is cluster name or cluster id, required only for node pool.
+	Cluster string
+}
+
+// BackupApiReq api request issued to backup TCA configuration.
+type BackupApiReq struct {
+
+	// FileName a backup archive file.
+	FileName string
+
+	// SkipCatalog skips catalog entities and CSAR files.
+	SkipCatalog bool
+}
+
+// RestoreApiReq api request issued to restore TCA configuration
+// from a backup archive.
+type RestoreApiReq struct {
+
+	// FileName a backup archive file.
+	FileName string
+
+	// Patches applied to specs stored in archive before restore.
+	Patches []*specs.SpecPatch
+
+	// dry run, resolves action for each step without applying any changes.
+	IsDryRun bool
+
+	// if steps require output progress
+	IsVerbose bool
+}
+
 // CreateInstanceApiReq - api request to create new cnf or vnf instance
 type CreateInstanceApiReq struct {
 
@@ -195,3 +290,67 @@ func NewInstanceRequestSpec(cloudName string, clusterName string, vimType string
 
 	return i, nil
 }
+
+// WaitApiReq api request issued to wait until object reaches a state
+type WaitApiReq struct {
+
+	// Kind is kind of object cluster, nodepool, cnf or task
+	Kind string
+
+	// Name is object name or id, node pool might be
+	// indicated as cluster/pool
+	Name string
+
+	// Cluster is cluster name or id of a node pool
+	Cluster string
+
+	// For is state to wait for, empty waits for default state of a kind
+	For string
+}
+
+// OperationApiReq api request to retry or abort cluster or node pool operation.
+type OperationApiReq struct {
+
+	// Cluster is cluster name or id
+	Cluster string
+
+	// NodePool is node pool name or id, empty if operation on cluster
+	NodePool string
+
+	// OperationId is operation id, resolved from cluster
+	// or node pool last operation if empty
+	OperationId string
+
+	//IsBlocking block until retried operation finish
+	IsBlocking bool
+
+	// if a request is blocking, and caller requires output progress
+	IsVerbose bool
+}
+
+// UpgradeApiReq api request to upgrade cluster or node pool kubernetes version.
+type UpgradeApiReq struct {
+
+	// Cluster is cluster name or id
+	Cluster string
+
+	// NodePool is node pool name or id, if set only node pool
+	// upgraded to control plane version
+	NodePool string
+
+	// Version is target kubernetes version, latest upgrade path if empty
+	Version string
+
+	// Template is cluster template name or id, resolved
+	// from target kubernetes version if empty
+	Template string
+
+	// VmTemplate is VM template used for upgraded nodes
+	VmTemplate string
+
+	// IsDryRun only plans upgrade and runs pre-flight checks
+	IsDryRun bool
+
+	// if caller requires output progress
+	IsVerbose bool
+}
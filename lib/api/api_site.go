@@ -0,0 +1,521 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	errnos "github.com/spyroot/tcactl/pkg/errors"
+	"strings"
+)
+
+// SiteStepSkipped error reported for a step that
+// never started because one of its dependencies failed.
+type SiteStepSkipped struct {
+	errMsg string
+}
+
+func (e *SiteStepSkipped) Error() string {
+	return "skipped, dependency " + e.errMsg + " failed"
+}
+
+// SiteNode a single spec in a site graph
+type SiteNode struct {
+
+	// Id node id in form kind/name, node pool id is node_pool/cluster/name
+	Id string
+
+	// Spec a spec node applies
+	Spec specs.RequestSpec
+
+	// DependsOn ids of nodes that must be applied before this node
+	DependsOn []string
+
+	// dependents ids of nodes that depend on this node
+	dependents []string
+}
+
+// SiteGraph a dependency graph of site specs.
+// Workload cluster depends on a management cluster, cluster template
+// and cloud provider, node pool on a cluster, extension on a cluster or
+// cloud provider it attached to and CNF instance on a cluster,
+// node pool and cloud provider. Dependency added only if both
+// specs are part of the site.
+type SiteGraph struct {
+	nodes map[string]*SiteNode
+
+	// ids in order specs provided
+	ids []string
+}
+
+// SiteStepResult a result of a single step
+type SiteStepResult struct {
+
+	// Id node id
+	Id string
+
+	// Kind spec kind
+	Kind specs.SpecType
+
+	// Action step took, empty if step failed or skipped
+	Action ApplyAction
+
+	// Err error if step failed or skipped
+	Err error
+}
+
+// siteStep applies a single node of the graph
+type siteStep func(ctx context.Context, n *SiteNode) (ApplyAction, error)
+
+// normalizeUrl normalizes url for comparison
+func normalizeUrl(u string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(u)), "/")
+}
+
+// poolCluster returns a cluster node pool spec targets
+func poolCluster(spec *specs.SpecNodePool, defaultCluster string) string {
+	if len(spec.Cluster) > 0 {
+		return spec.Cluster
+	}
+	return defaultCluster
+}
+
+// siteNodeId returns a graph node id for a spec
+func siteNodeId(spec specs.RequestSpec, defaultCluster string) (string, error) {
+
+	switch s := spec.(type) {
+	case *specs.SpecCloudProvider:
+		return fmt.Sprintf("%s/%s", specs.SpecKindProvider, s.VimName), nil
+	case *specs.SpecClusterTemplate:
+		return fmt.Sprintf("%s/%s", specs.SpecKindTemplate, s.Name), nil
+	case *specs.SpecExtension:
+		return fmt.Sprintf("%s/%s", specs.SpecKindExtension, s.Name), nil
+	case *specs.SpecCluster:
+		return fmt.Sprintf("%s/%s", specs.SpecKindCluster, s.Name), nil
+	case *specs.SpecNodePool:
+		return fmt.Sprintf("%s/%s/%s", specs.SpecKindNodePool, poolCluster(s, defaultCluster), s.Name), nil
+	case *specs.InstanceRequestSpec:
+		return fmt.Sprintf("%s/%s", specs.SpecKindInstance, s.InstanceName), nil
+	}
+
+	return "", api_errors.NewInvalidSpec(fmt.Sprintf("unsupported spec kind '%s'", spec.Kind()))
+}
+
+// NewSiteGraph builds a dependency graph from a set of specs.
+// defaultCluster is used for node pool spec that doesn't indicate a cluster.
+// Method returns error if two specs describe same object or graph has a cycle.
+func NewSiteGraph(site []specs.RequestSpec, defaultCluster string) (*SiteGraph, error) {
+
+	g := &SiteGraph{nodes: map[string]*SiteNode{}}
+
+	for _, spec := range site {
+		if spec == nil {
+			return nil, errnos.SpecNil
+		}
+		id, err := siteNodeId(spec, defaultCluster)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := g.nodes[id]; ok {
+			return nil, api_errors.NewInvalidSpec(fmt.Sprintf("duplicate spec %s", id))
+		}
+		g.nodes[id] = &SiteNode{Id: id, Spec: spec}
+		g.ids = append(g.ids, id)
+	}
+
+	for _, id := range g.ids {
+		g.resolveDependencies(g.nodes[id], defaultCluster)
+	}
+
+	if _, err := g.Order(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// addDependency adds edge from node to a dependency if dependency is in a graph
+func (g *SiteGraph) addDependency(n *SiteNode, kind specs.SpecType, name string) {
+
+	if len(name) == 0 {
+		return
+	}
+
+	dep, ok := g.nodes[fmt.Sprintf("%s/%s", kind, name)]
+	if !ok || dep == n {
+		return
+	}
+
+	for _, id := range n.DependsOn {
+		if id == dep.Id {
+			return
+		}
+	}
+
+	n.DependsOn = append(n.DependsOn, dep.Id)
+	dep.dependents = append(dep.dependents, n.Id)
+}
+
+// addProviderByUrl adds dependency on cloud provider registered with hcx url
+func (g *SiteGraph) addProviderByUrl(n *SiteNode, hcxUrl string) {
+
+	if len(hcxUrl) == 0 {
+		return
+	}
+
+	for _, id := range g.ids {
+		if p, ok := g.nodes[id].Spec.(*specs.SpecCloudProvider); ok {
+			if normalizeUrl(p.HcxCloudUrl) == normalizeUrl(hcxUrl) {
+				g.addDependency(n, specs.SpecKindProvider, p.VimName)
+			}
+		}
+	}
+}
+
+// resolveDependencies resolves node dependencies from a spec
+func (g *SiteGraph) resolveDependencies(n *SiteNode, defaultCluster string) {
+
+	switch s := n.Spec.(type) {
+	case *specs.SpecCluster:
+		g.addDependency(n, specs.SpecKindTemplate, s.ClusterTemplateId)
+		g.addDependency(n, specs.SpecKindCluster, s.ManagementClusterId)
+		g.addProviderByUrl(n, s.HcxCloudUrl)
+	case *specs.SpecNodePool:
+		g.addDependency(n, specs.SpecKindCluster, poolCluster(s, defaultCluster))
+	case *specs.SpecExtension:
+		// workload cluster registered as vim under cluster name
+		for _, vim := range s.VimInfo {
+			g.addDependency(n, specs.SpecKindCluster, vim.VimName)
+			g.addDependency(n, specs.SpecKindProvider, vim.VimName)
+		}
+	case *specs.InstanceRequestSpec:
+		g.addDependency(n, specs.SpecKindCluster, s.ClusterName)
+		g.addDependency(n, specs.SpecKindNodePool, s.ClusterName+"/"+s.NodePoolName)
+		g.addDependency(n, specs.SpecKindProvider, s.CloudName)
+	}
+}
+
+// Node returns a node by id
+func (g *SiteGraph) Node(id string) (*SiteNode, bool) {
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// Order returns node ids in topological order, among independent
+// nodes order of specs preserved.
+func (g *SiteGraph) Order() ([]string, error) {
+
+	pending := make(map[string]int, len(g.nodes))
+	for id, n := range g.nodes {
+		pending[id] = len(n.DependsOn)
+	}
+
+	var order []string
+	visited := make(map[string]bool, len(g.nodes))
+	for len(order) < len(g.ids) {
+		progress := false
+		for _, id := range g.ids {
+			if visited[id] || pending[id] > 0 {
+				continue
+			}
+			visited[id] = true
+			progress = true
+			order = append(order, id)
+			for _, d := range g.nodes[id].dependents {
+				pending[d]--
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, id := range g.ids {
+				if !visited[id] {
+					cycle = append(cycle, id)
+				}
+			}
+			return nil, api_errors.NewInvalidSpec(fmt.Sprintf("dependency cycle between %s", strings.Join(cycle, ", ")))
+		}
+	}
+
+	return order, nil
+}
+
+// ReverseOrder returns node ids in order of a teardown
+func (g *SiteGraph) ReverseOrder() ([]string, error) {
+
+	order, err := g.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+
+	return order, nil
+}
+
+// walk runs step for each node once all nodes it waits for finished,
+// independent nodes run in parallel. If reverse is true node waits for
+// its dependents, otherwise for its dependencies. If a step fails
+// all nodes that wait for it skipped. Results returned in
+// topological order or reverse topological order.
+func (g *SiteGraph) walk(ctx context.Context, reverse bool, step siteStep) []*SiteStepResult {
+
+	waitsFor := func(n *SiteNode) []string {
+		if reverse {
+			return n.dependents
+		}
+		return n.DependsOn
+	}
+	unblocks := func(n *SiteNode) []string {
+		if reverse {
+			return n.DependsOn
+		}
+		return n.dependents
+	}
+
+	var (
+		pending = make(map[string]int, len(g.nodes))
+		failed  = make(map[string]string, len(g.nodes))
+		results = make(map[string]*SiteStepResult, len(g.nodes))
+		done    = make(chan *SiteStepResult)
+		running = 0
+	)
+
+	start := func(n *SiteNode) {
+		running++
+		go func() {
+			r := &SiteStepResult{Id: n.Id, Kind: n.Spec.Kind()}
+			if err := ctx.Err(); err != nil {
+				r.Err = err
+			} else {
+				r.Action, r.Err = step(ctx, n)
+			}
+			done <- r
+		}()
+	}
+
+	var complete func(r *SiteStepResult)
+	complete = func(r *SiteStepResult) {
+		results[r.Id] = r
+		for _, id := range unblocks(g.nodes[r.Id]) {
+			if r.Err != nil {
+				if _, ok := failed[id]; !ok {
+					failed[id] = r.Id
+				}
+			}
+			pending[id]--
+			if pending[id] > 0 {
+				continue
+			}
+			n := g.nodes[id]
+			if dep, ok := failed[id]; ok {
+				complete(&SiteStepResult{Id: id, Kind: n.Spec.Kind(), Err: &SiteStepSkipped{errMsg: dep}})
+				continue
+			}
+			start(n)
+		}
+	}
+
+	for _, id := range g.ids {
+		pending[id] = len(waitsFor(g.nodes[id]))
+	}
+	for _, id := range g.ids {
+		if pending[id] == 0 {
+			start(g.nodes[id])
+		}
+	}
+
+	for running > 0 {
+		r := <-done
+		running--
+		complete(r)
+	}
+
+	var order []string
+	if reverse {
+		order, _ = g.ReverseOrder()
+	} else {
+		order, _ = g.Order()
+	}
+
+	var out []*SiteStepResult
+	for _, id := range order {
+		out = append(out, results[id])
+	}
+
+	return out
+}
+
+// siteErr returns first failed step as error
+func siteErr(results []*SiteStepResult) error {
+	for _, r := range results {
+		if r.Err != nil {
+			if _, ok := r.Err.(*SiteStepSkipped); ok {
+				continue
+			}
+			return fmt.Errorf("%s: %v", r.Id, r.Err)
+		}
+	}
+	return nil
+}
+
+// waitSiteTask blocks until task finished, if verbose
+// prints progress of a task.
+func (a *TcaApi) waitSiteTask(ctx context.Context, task *models.TcaTask, verbose bool) error {
+	if task == nil || len(task.Id) == 0 {
+		return nil
+	}
+	return a.legacyWait(a.Waiter().MaxAttempts, verbose, func(a *TcaApi) error {
+		return a.WaitTask(ctx, task, TaskStateSuccess)
+	})
+}
+
+// bringUpStep applies a spec and waits for a task to finish
+func (a *TcaApi) bringUpStep(req *SiteApiReq) siteStep {
+	return func(ctx context.Context, n *SiteNode) (ApplyAction, error) {
+
+		glog.Infof("Site bring up %s", n.Id)
+
+		// catalog must be uploaded before we can instantiate
+		if s, ok := n.Spec.(*specs.InstanceRequestSpec); ok {
+			if _, _, err := a.GetCatalogAndVdu(ctx, s.NfdName); err != nil {
+				return "", err
+			}
+		}
+
+		_, isInstance := n.Spec.(*specs.InstanceRequestSpec)
+		r, err := a.Apply(ctx, &ApplyApiReq{
+			Spec:     n.Spec,
+			Cluster:  req.Cluster,
+			IsDryRun: req.IsDryRun,
+			// instance has no task, it blocks on instantiation state
+			IsBlocking: isInstance,
+			IsVerbose:  req.IsVerbose,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if err := a.waitSiteTask(ctx, r.Task, req.IsVerbose); err != nil {
+			return "", err
+		}
+
+		return r.Action, nil
+	}
+}
+
+// tearDownStep deletes an object described by spec and waits for a task
+// to finish. Object that is not found is left unchanged.
+func (a *TcaApi) tearDownStep(req *SiteApiReq) siteStep {
+	return func(ctx context.Context, n *SiteNode) (ApplyAction, error) {
+
+		glog.Infof("Site tear down %s", n.Id)
+
+		// resolve if object exists
+		r, err := a.Apply(ctx, &ApplyApiReq{Spec: n.Spec, Cluster: req.Cluster, IsDryRun: true})
+		if err != nil {
+			if isNotFound(err) {
+				return ApplyUnchanged, nil
+			}
+			return "", err
+		}
+		if r.Action == ApplyCreated {
+			return ApplyUnchanged, nil
+		}
+		if req.IsDryRun {
+			return ApplyDeleted, nil
+		}
+
+		var task *models.TcaTask
+		switch s := n.Spec.(type) {
+		case *specs.InstanceRequestSpec:
+			err = a.DeleteCnfInstance(ctx, s.InstanceName, s.ClusterName, true)
+		case *specs.SpecNodePool:
+			task, err = a.DeleteNodePool(ctx, poolCluster(s, req.Cluster), s.Name)
+		case *specs.SpecCluster:
+			task, err = a.DeleteCluster(ctx, &ClusterDeleteApiReq{Cluster: s.Name, IsVerbose: req.IsVerbose})
+		case *specs.SpecExtension:
+			_, err = a.DeleteExtension(ctx, s.Name)
+		case *specs.SpecClusterTemplate:
+			err = a.DeleteTemplate(ctx, s.Name)
+		case *specs.SpecCloudProvider:
+			task, err = a.DeleteCloudProvider(ctx, s.VimName)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if err := a.waitSiteTask(ctx, task, req.IsVerbose); err != nil {
+			return "", err
+		}
+
+		return ApplyDeleted, nil
+	}
+}
+
+// BringUpSite api call applies a set of specs in order of dependencies.
+// Independent branches applied in parallel, each step waits for
+// TCA task to finish before dependent step starts. If a step fails,
+// all steps that depend on it skipped and the rest proceed.
+// Method returns result for each step and error for first failed step.
+func (a *TcaApi) BringUpSite(ctx context.Context, req *SiteApiReq) ([]*SiteStepResult, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	g, err := NewSiteGraph(req.Specs, req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	results := g.walk(ctx, false, a.bringUpStep(req))
+	return results, siteErr(results)
+}
+
+// TearDownSite api call deletes objects described by a set of specs
+// in reverse order of dependencies, i.e. instances first and cloud
+// providers last. Object that is not found is left unchanged.
+func (a *TcaApi) TearDownSite(ctx context.Context, req *SiteApiReq) ([]*SiteStepResult, error) {
+
+	if a == nil {
+		return nil, errnos.NilError
+	}
+
+	if req == nil {
+		return nil, errnos.ReqNil
+	}
+
+	g, err := NewSiteGraph(req.Specs, req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	results := g.walk(ctx, true, a.tearDownStep(req))
+	return results, siteErr(results)
+}
@@ -0,0 +1,244 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSite a provider, template, management and workload cluster,
+// node pool, extension and CNF instance, listed in reverse order.
+func testSite() []specs.RequestSpec {
+	return []specs.RequestSpec{
+		&specs.InstanceRequestSpec{InstanceName: "cnf01", ClusterName: "edge", NodePoolName: "pool01", CloudName: "edge"},
+		&specs.SpecExtension{Name: "repo", VimInfo: []specs.VimInfo{{VimName: "edge"}}},
+		&specs.SpecNodePool{Name: "pool01"},
+		&specs.SpecCluster{Name: "edge", ClusterTemplateId: "workload", ManagementClusterId: "mgmt",
+			HcxCloudUrl: "https://tca-cp03.cnfdemo.io/"},
+		&specs.SpecCluster{Name: "mgmt", ClusterTemplateId: "management", HcxCloudUrl: "https://tca-cp03.cnfdemo.io"},
+		&specs.SpecClusterTemplate{Name: "workload"},
+		&specs.SpecCloudProvider{VimName: "core", HcxCloudUrl: "https://TCA-CP03.cnfdemo.io"},
+	}
+}
+
+// indexOf returns position of id in order
+func indexOf(order []string, id string) int {
+	for i, s := range order {
+		if s == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Test site graph dependencies and order
+func TestSiteGraph_Order(t *testing.T) {
+
+	g, err := NewSiteGraph(testSite(), "edge")
+	assert.NoError(t, err)
+
+	edge, ok := g.Node("cluster/edge")
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []string{"template/workload", "cluster/mgmt", "provider/core"}, edge.DependsOn)
+
+	pool, ok := g.Node("node_pool/edge/pool01")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"cluster/edge"}, pool.DependsOn)
+
+	order, err := g.Order()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, len(order))
+	for _, id := range order {
+		n, _ := g.Node(id)
+		for _, dep := range n.DependsOn {
+			assert.Less(t, indexOf(order, dep), indexOf(order, id), "%s before %s", dep, id)
+		}
+	}
+
+	reverse, err := g.ReverseOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, "instance/cnf01", reverse[0])
+	assert.Equal(t, order[0], reverse[len(reverse)-1])
+}
+
+// Test invalid site graphs
+func TestSiteGraph_Invalid(t *testing.T) {
+
+	tests := []struct {
+		name string
+		site []specs.RequestSpec
+	}{
+		{
+			name: "duplicate spec",
+			site: []specs.RequestSpec{&specs.SpecNodePool{Name: "pool01"}, &specs.SpecNodePool{Name: "pool01"}},
+		},
+		{
+			name: "cycle",
+			site: []specs.RequestSpec{
+				&specs.SpecCluster{Name: "a", ManagementClusterId: "b"},
+				&specs.SpecCluster{Name: "b", ManagementClusterId: "a"},
+			},
+		},
+		{
+			name: "nil spec",
+			site: []specs.RequestSpec{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSiteGraph(tt.site, "edge")
+			assert.Error(t, err)
+		})
+	}
+}
+
+// Test that walk respects dependencies and skips dependents of a failed step
+func TestSiteGraph_walk(t *testing.T) {
+
+	g, err := NewSiteGraph(testSite(), "edge")
+	assert.NoError(t, err)
+
+	for _, reverse := range []bool{false, true} {
+
+		var (
+			mu       sync.Mutex
+			finished = map[string]bool{}
+		)
+
+		results := g.walk(context.Background(), reverse, func(ctx context.Context, n *SiteNode) (ApplyAction, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			wait := n.DependsOn
+			if reverse {
+				wait = n.dependents
+			}
+			for _, id := range wait {
+				assert.True(t, finished[id], "%s started before %s", n.Id, id)
+			}
+			finished[n.Id] = true
+			return ApplyCreated, nil
+		})
+
+		assert.Equal(t, 7, len(results))
+		assert.NoError(t, siteErr(results))
+	}
+
+	results := g.walk(context.Background(), false, func(ctx context.Context, n *SiteNode) (ApplyAction, error) {
+		if n.Id == "cluster/edge" {
+			return "", fmt.Errorf("failed")
+		}
+		return ApplyCreated, nil
+	})
+
+	for _, r := range results {
+		switch r.Id {
+		case "cluster/edge":
+			assert.Error(t, r.Err)
+		case "node_pool/edge/pool01", "extensions/repo", "instance/cnf01":
+			assert.IsType(t, &SiteStepSkipped{}, r.Err)
+		default:
+			assert.NoError(t, r.Err)
+		}
+	}
+	assert.Error(t, siteErr(results))
+}
+
+// Test parallel branches of a site graph share one api, run with -race
+func TestSiteGraph_walkParallel(t *testing.T) {
+
+	ctx := context.Background()
+	a, _ := getOperationSimApi(t, tcasim.Options{})
+
+	g, err := NewSiteGraph(testSite(), "edge")
+	assert.NoError(t, err)
+
+	// provider and template independent, each waits for other to start
+	started := map[string]chan struct{}{
+		"provider/core":     make(chan struct{}),
+		"template/workload": make(chan struct{}),
+	}
+
+	results := g.walk(ctx, false, func(ctx context.Context, n *SiteNode) (ApplyAction, error) {
+		if ch, ok := started[n.Id]; ok {
+			close(ch)
+			for id, other := range started {
+				if id == n.Id {
+					continue
+				}
+				select {
+				case <-other:
+				case <-time.After(5 * time.Second):
+					return "", fmt.Errorf("%s not started in parallel with %s", id, n.Id)
+				}
+			}
+		}
+		if _, err := a.GetClusters(ctx); err != nil {
+			return "", err
+		}
+		if _, err := a.rest.GetClusterNodePools(ctx, simWorkloadClusterId); err != nil {
+			return "", err
+		}
+		if _, err := a.rest.GetClusterTemplates(ctx); err != nil {
+			return "", err
+		}
+		return ApplyUnchanged, nil
+	})
+
+	assert.Equal(t, 7, len(results))
+	assert.NoError(t, siteErr(results))
+}
+
+func TestTcaApi_waitSiteTask(t *testing.T) {
+
+	ctx := context.Background()
+	task := &models.TcaTask{Id: "task", OperationId: "CreateNodePool"}
+	item := `{"items":[{"taskId":"task","type":"Node Pool Creation","status":"%s","progress":%d,` +
+		`"entityDetails":{"name":"pool"},"steps":[{"title":"Deploy","status":"%s"}]}]}`
+	respond := func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(item, "RUNNING", 50, "RUNNING")
+		}
+		return fmt.Sprintf(item, TaskStateSuccess, 100, TaskStateSuccess)
+	}
+
+	var out bytes.Buffer
+	verboseOutput = &out
+	defer func() { verboseOutput = os.Stdout }()
+
+	// silent unless verbose
+	assert.NoError(t, getWaiterApi(t, respond).waitSiteTask(ctx, task, false))
+	assert.Equal(t, 0, out.Len())
+
+	assert.NoError(t, getWaiterApi(t, respond).waitSiteTask(ctx, task, true))
+	assert.Equal(t, 2, strings.Count(out.String(), "\n"), out.String())
+	assert.Contains(t, out.String(), "status="+TaskStateSuccess)
+
+	// no task nothing to wait for
+	assert.NoError(t, getWaiterApi(t, respond).waitSiteTask(ctx, nil, true))
+}
@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"github.com/golang/glog"
 	"github.com/google/uuid"
 	"github.com/spyroot/tcactl/lib/api_errors"
@@ -81,7 +82,7 @@ func TemplateFields() []string {
 //}
 
 // GetClusterTemplates - return list of cluster templates
-func (a *TcaApi) GetClusterTemplates() (*response.ClusterTemplates, error) {
+func (a *TcaApi) GetClusterTemplates(ctx context.Context) (*response.ClusterTemplates, error) {
 
 	glog.Infof("Retrieving vnf packages.")
 
@@ -89,11 +90,11 @@ func (a *TcaApi) GetClusterTemplates() (*response.ClusterTemplates, error) {
 		return nil, errnos.RestNil
 	}
 
-	return a.rest.GetClusterTemplates()
+	return a.rest.GetClusterTemplates(ctx)
 }
 
 // CreateClusterTemplate - create cluster template from initialSpec
-func (a *TcaApi) CreateClusterTemplate(spec *specs.SpecClusterTemplate) (string, error) {
+func (a *TcaApi) CreateClusterTemplate(ctx context.Context, spec *specs.SpecClusterTemplate) (string, error) {
 
 	glog.Infof("Create %s cluster template.", spec.Name)
 
@@ -106,7 +107,7 @@ func (a *TcaApi) CreateClusterTemplate(spec *specs.SpecClusterTemplate) (string,
 		return "", err
 	}
 
-	_id, err := a.ResolveTemplateId(spec.Name)
+	_id, err := a.ResolveTemplateId(ctx, spec.Name)
 	if err == nil && len(_id) > 0 {
 		// generate initialSpec name
 		spec.Name = spec.Name + "-" + uuid.New().String()
@@ -123,11 +124,11 @@ func (a *TcaApi) CreateClusterTemplate(spec *specs.SpecClusterTemplate) (string,
 		return "", api_errors.NewInvalidSpec(" worker node section not present.")
 	}
 
-	return spec.Name, a.rest.CreateClusterTemplate(spec)
+	return spec.Name, a.rest.CreateClusterTemplate(ctx, spec)
 }
 
 // GetClusterTemplate return cluster template
-func (a *TcaApi) GetClusterTemplate(templateId string) (*response.ClusterTemplateSpec, error) {
+func (a *TcaApi) GetClusterTemplate(ctx context.Context, templateId string) (*response.ClusterTemplateSpec, error) {
 
 	glog.Infof("Retrieving vnf packages.")
 
@@ -141,18 +142,18 @@ func (a *TcaApi) GetClusterTemplate(templateId string) (*response.ClusterTemplat
 	)
 
 	if !IsValidUUID(templateId) {
-		tid, err = a.ResolveTemplateId(templateId)
+		tid, err = a.ResolveTemplateId(ctx, templateId)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return a.rest.GetClusterTemplate(tid)
+	return a.rest.GetClusterTemplate(ctx, tid)
 }
 
 // UpdateClusterTemplate - updates cluster template based
 // on input initialSpec
-func (a *TcaApi) UpdateClusterTemplate(spec *specs.SpecClusterTemplate) error {
+func (a *TcaApi) UpdateClusterTemplate(ctx context.Context, spec *specs.SpecClusterTemplate) error {
 
 	glog.Infof("Updating cluster template. %v", spec)
 
@@ -167,37 +168,45 @@ func (a *TcaApi) UpdateClusterTemplate(spec *specs.SpecClusterTemplate) error {
 
 	if IsValidUUID(spec.Id) {
 		glog.Infof("Validating template id %s", spec.Id)
-		_, err := a.rest.GetClusterTemplate(spec.Id)
+		_, err := a.rest.GetClusterTemplate(ctx, spec.Id)
 		if err != nil {
 			glog.Error(err)
 			return err
 		}
 	} else {
-		// if template name used , resolve id
-		id, err := a.ResolveTemplateId(spec.Id)
+		// if template name used , resolve id, spec without id resolved by name
+		name := spec.Id
+		if len(name) == 0 {
+			name = spec.Name
+		}
+		id, err := a.ResolveTemplateId(ctx, name)
 		if err != nil {
 			return err
 		}
 		spec.Id = id
 	}
 
-	return a.rest.UpdateClusterTemplate(spec)
+	return a.rest.UpdateClusterTemplate(ctx, spec)
 }
 
 // DeleteTemplate deletes cluster template from TCA
 // template argument can be name or ID.
-func (a *TcaApi) DeleteTemplate(template string) error {
+func (a *TcaApi) DeleteTemplate(ctx context.Context, template string) error {
+
+	if a.rest == nil {
+		return errnos.RestNil
+	}
 
 	var templateId = ""
 
 	if IsValidUUID(template) {
-		tmpl, err := a.rest.GetClusterTemplate(template)
+		tmpl, err := a.rest.GetClusterTemplate(ctx, template)
 		if err != nil {
 			return err
 		}
 		templateId = tmpl.Id
 	} else {
-		templates_, err := a.rest.GetClusterTemplates()
+		templates_, err := a.rest.GetClusterTemplates(ctx)
 		if err != nil {
 			return err
 		}
@@ -208,7 +217,7 @@ func (a *TcaApi) DeleteTemplate(template string) error {
 		glog.Infof("Resolved template id %s", templateId)
 	}
 
-	err := a.rest.DeleteClusterTemplate(templateId)
+	err := a.rest.DeleteClusterTemplate(ctx, templateId)
 	if err != nil {
 		return err
 	}
@@ -217,10 +226,10 @@ func (a *TcaApi) DeleteTemplate(template string) error {
 }
 
 // ResolveTemplateId - resolves template name to id
-func (a *TcaApi) ResolveTemplateId(templateId string) (string, error) {
+func (a *TcaApi) ResolveTemplateId(ctx context.Context, templateId string) (string, error) {
 
 	// resolve template id, in case client used name instead id
-	clusterTemplates, err := a.rest.GetClusterTemplates()
+	clusterTemplates, err := a.rest.GetClusterTemplates(ctx)
 	if err != nil {
 		return "", err
 	}
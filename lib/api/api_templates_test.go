@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/spyroot/tcactl/lib/client"
 	"github.com/spyroot/tcactl/lib/client/response"
@@ -113,7 +114,7 @@ func TestTcaApi_CreateClusterTemplate(t *testing.T) {
 				a.rest = nil
 			}
 
-			if name, err = a.CreateClusterTemplate(tt.spec); (err != nil) != tt.wantErr {
+			if name, err = a.CreateClusterTemplate(context.Background(), tt.spec); (err != nil) != tt.wantErr {
 				t.Errorf("CreateClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
 				return
 			}
@@ -130,7 +131,7 @@ func TestTcaApi_CreateClusterTemplate(t *testing.T) {
 
 			time.Sleep(3 * time.Second)
 
-			if template, err2 = a.GetClusterTemplate(name); (err2 != nil) != tt.wantErr {
+			if template, err2 = a.GetClusterTemplate(context.Background(), name); (err2 != nil) != tt.wantErr {
 				t.Errorf("CreateClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
 				return
 			}
@@ -144,7 +145,7 @@ func TestTcaApi_CreateClusterTemplate(t *testing.T) {
 				}
 			} else {
 				t.Logf("Template retrieved %s", template.Id)
-				if err = a.DeleteTemplate(name); (err != nil) != tt.wantErr {
+				if err = a.DeleteTemplate(context.Background(), name); (err != nil) != tt.wantErr {
 					t.Errorf("DeleteTemplate() error = %v, vimErr %v", err, tt.wantErr)
 					return
 				}
@@ -182,7 +183,7 @@ func TestTcaApi_GetClusterTemplate(t *testing.T) {
 				if tt.specs != nil {
 					u := uuid.New().String()
 					tt.specs.Name = u[0:8]
-					_, err := a.CreateClusterTemplate(tt.specs)
+					_, err := a.CreateClusterTemplate(context.Background(), tt.specs)
 					assert.NoError(t, err)
 					time.Sleep(1 * time.Second)
 					names = append(names, u[0:8])
@@ -190,7 +191,7 @@ func TestTcaApi_GetClusterTemplate(t *testing.T) {
 			}
 
 			for _, n := range names {
-				_, err := a.GetClusterTemplate(n)
+				_, err := a.GetClusterTemplate(context.Background(), n)
 				if tt.wantErr == false && err != nil {
 					t.Errorf("GetClusterTemplates() error = %v, vimErr %v", err, tt.wantErr)
 					return
@@ -199,7 +200,7 @@ func TestTcaApi_GetClusterTemplate(t *testing.T) {
 
 			// delete all
 			for _, n := range names {
-				err := a.DeleteTemplate(n)
+				err := a.DeleteTemplate(context.Background(), n)
 				if tt.wantErr == false && err != nil {
 					t.Errorf("GetClusterTemplates() error = %v, vimErr %v", err, tt.wantErr)
 				}
@@ -236,14 +237,14 @@ func TestTcaApi_GetClusterTemplates(t *testing.T) {
 				if tt.specs != nil {
 					u := uuid.New().String()
 					tt.specs.Name = u[0:8]
-					_, err := a.CreateClusterTemplate(tt.specs)
+					_, err := a.CreateClusterTemplate(context.Background(), tt.specs)
 					assert.NoError(t, err)
 					time.Sleep(1 * time.Second)
 					names = append(names, u[0:8])
 				}
 			}
 
-			got, err := a.GetClusterTemplates()
+			got, err := a.GetClusterTemplates(context.Background())
 			assert.NotNil(t, got)
 
 			if (err != nil) != tt.wantErr {
@@ -260,7 +261,7 @@ func TestTcaApi_GetClusterTemplates(t *testing.T) {
 
 			// delete all
 			for _, n := range names {
-				err = a.DeleteTemplate(n)
+				err = a.DeleteTemplate(context.Background(), n)
 				if tt.wantErr == false && err != nil {
 					t.Errorf("GetClusterTemplates() error = %v, vimErr %v", err, tt.wantErr)
 				}
@@ -363,12 +364,12 @@ func TestTcaApi_UpdateClusterTemplate(t *testing.T) {
 			)
 
 			if tt.initialSpec != nil {
-				name, err = a.CreateClusterTemplate(tt.initialSpec)
+				name, err = a.CreateClusterTemplate(context.Background(), tt.initialSpec)
 				assert.NoError(t, err)
 				time.Sleep(3 * time.Second)
 
 				//
-				template, err = a.GetClusterTemplate(name)
+				template, err = a.GetClusterTemplate(context.Background(), name)
 				assert.NoError(t, err)
 				t.Logf("Template created %s", name)
 			}
@@ -377,8 +378,8 @@ func TestTcaApi_UpdateClusterTemplate(t *testing.T) {
 			updatedSpec := tt.transformer(tt.initialSpec)
 
 			var errUpdate error
-			if errUpdate = a.UpdateClusterTemplate(updatedSpec); (errUpdate != nil) != tt.wantErr {
-				t.Errorf("UpdateClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
+			if errUpdate = a.UpdateClusterTemplate(context.Background(), updatedSpec); (errUpdate != nil) != tt.wantErr {
+				t.Errorf("UpdateClusterTemplate() error = %v, vimErr %v", errUpdate, tt.wantErr)
 				return
 			}
 
@@ -393,13 +394,13 @@ func TestTcaApi_UpdateClusterTemplate(t *testing.T) {
 
 			var updateTemplate *response.ClusterTemplateSpec
 			// get result and compare
-			if updateTemplate, errUpdate = a.GetClusterTemplate(name); (errUpdate != nil) != tt.wantErr {
+			if updateTemplate, errUpdate = a.GetClusterTemplate(context.Background(), name); (errUpdate != nil) != tt.wantErr {
 				t.Errorf("GetClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
 				return
 			}
 
 			if tt.checker(updateTemplate) == true && tt.wantErr == false {
-				if err = a.DeleteTemplate(name); (err != nil) != tt.wantErr {
+				if err = a.DeleteTemplate(context.Background(), name); (err != nil) != tt.wantErr {
 					t.Errorf("DeleteTemplate() error = %v, vimErr %v", err, tt.wantErr)
 					return
 				}
@@ -419,6 +420,7 @@ func TestTcaApi_DeleteClusterTemplate(t *testing.T) {
 		spec    *specs.SpecClusterTemplate
 		tid     string
 		wantErr bool
+		reset   bool
 	}{
 		{
 			name:    "Should produce error.",
@@ -436,16 +438,20 @@ func TestTcaApi_DeleteClusterTemplate(t *testing.T) {
 		},
 		{
 			name:    "Should produce error nil rest.",
-			rest:    nil,
+			rest:    getAuthenticatedClient(),
 			spec:    nil,
 			tid:     "abc",
 			wantErr: true,
+			reset:   true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			api := getTcaApi(t, tt.rest, false)
+			if tt.reset {
+				api.rest = nil
+			}
 
 			var (
 				err  error
@@ -455,7 +461,7 @@ func TestTcaApi_DeleteClusterTemplate(t *testing.T) {
 
 			// if need create initialSpec
 			if tt.spec != nil {
-				if name, err = api.CreateClusterTemplate(tt.spec); (err != nil) != tt.wantErr {
+				if name, err = api.CreateClusterTemplate(context.Background(), tt.spec); (err != nil) != tt.wantErr {
 					t.Errorf("CreateClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
 					return
 				}
@@ -472,7 +478,7 @@ func TestTcaApi_DeleteClusterTemplate(t *testing.T) {
 
 				time.Sleep(3 * time.Second)
 
-				if _, err2 = api.GetClusterTemplate(name); (err2 != nil) != tt.wantErr {
+				if _, err2 = api.GetClusterTemplate(context.Background(), name); (err2 != nil) != tt.wantErr {
 					t.Errorf("CreateClusterTemplate() error = %v, vimErr %v", err, tt.wantErr)
 					return
 				}
@@ -480,7 +486,7 @@ func TestTcaApi_DeleteClusterTemplate(t *testing.T) {
 				t.Logf("Template created %s", name)
 			}
 
-			if err = api.DeleteTemplate(name); (err != nil) != tt.wantErr {
+			if err = api.DeleteTemplate(context.Background(), name); (err != nil) != tt.wantErr {
 				t.Errorf("DeleteTemplate() error = %v, vimErr %v", err, tt.wantErr)
 				return
 			}
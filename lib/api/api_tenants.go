@@ -77,12 +77,12 @@ func (a *TcaApi) DeleteTenantsProvider(ctx context.Context, tenantCluster string
 		return nil, err
 	}
 
-	return a.rest.DeleteTenant(r.TenantID)
+	return a.rest.DeleteTenant(ctx, r.TenantID)
 }
 
 // CreateTenantProvider method create, registers new target cloud provider
 // as tenant infrastructure in TCA.
-func (a *TcaApi) CreateTenantProvider(spec *specs.SpecCloudProvider) (*models.RegistrationRespond, error) {
+func (a *TcaApi) CreateTenantProvider(ctx context.Context, spec *specs.SpecCloudProvider) (*models.RegistrationRespond, error) {
 
 	if spec == nil {
 		return nil, errnos.SpecNil
@@ -98,7 +98,7 @@ func (a *TcaApi) CreateTenantProvider(spec *specs.SpecCloudProvider) (*models.Re
 	specCopy.SpecType = ""
 	//specCopy.Password = b64.StdEncoding.EncodeToString([]byte(spec.Password))
 
-	return a.rest.RegisterCloudProvider(specCopy)
+	return a.rest.RegisterCloudProvider(ctx, specCopy)
 }
 
 // DeleteCloudProvider method delete cloud provider
@@ -116,7 +116,7 @@ func (a *TcaApi) DeleteCloudProvider(ctx context.Context, s string) (*models.Tca
 		return nil, err
 	}
 
-	task, err := a.rest.DeleteTenant(provider.ID)
+	task, err := a.rest.DeleteTenant(ctx, provider.ID)
 	if err != nil {
 		return nil, err
 	}
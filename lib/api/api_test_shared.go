@@ -1,13 +1,17 @@
 package api
 
 import (
+	"context"
 	"flag"
 	"github.com/golang/glog"
 	"github.com/google/uuid"
 	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/tcasim"
 	"github.com/spyroot/tcactl/lib/testutil"
 	"github.com/spyroot/tcactl/pkg/io"
+	"net/http/httptest"
 	"os"
+	"sync"
 )
 
 const (
@@ -57,11 +61,24 @@ const (
 	testWorkloadTemplateId = "c3e006c1-e6aa-4591-950b-6f3bedd944d3"
 )
 
+var (
+	// testRecorder shared by all test clients, so a cassette recorded once
+	testRecorder     *client.Recorder
+	testRecorderOnce sync.Once
+
+	// testSim shared by all test clients when TCA_SIM=true
+	testSim     *httptest.Server
+	testSimOnce sync.Once
+
+	// testSimServer simulator behind testSim, nil if tests run against TCA
+	testSimServer *tcasim.Server
+)
+
 //
 func getAuthenticatedClient() *client.RestClient {
 
 	r := getClient()
-	ok, err := r.GetAuthorization()
+	ok, err := r.GetAuthorization(context.Background())
 	if err != nil {
 		io.CheckErr(err)
 	}
@@ -99,6 +116,13 @@ func GetTestAssetsDir() string {
 	return wd
 }
 
+// hasTestAssets return true if test assets dir present,
+// source snapshots might not ship it
+func hasTestAssets() bool {
+	_, err := os.Stat(testutil.RunOnRootFolder() + "/test_assets")
+	return err == nil
+}
+
 // SetLoggingFlags Sets logging flag for logging tracer
 func SetLoggingFlags() {
 
@@ -116,21 +140,62 @@ func SetLoggingFlags() {
 	glog.Info("Logging configured")
 }
 
-// getClient() return tca client for unit testing
+// getSimClient return client for in-memory TCA simulator,
+// all tests share a single simulator and its state.
+func getSimClient() *client.RestClient {
+
+	testSimOnce.Do(func() {
+		testSimServer = tcasim.NewServer(nil, tcasim.Options{})
+		testSim = httptest.NewTLSServer(testSimServer)
+	})
+
+	r, err := client.NewRestClient(testSim.URL, true, tcasim.DefaultUsername, tcasim.DefaultPassword)
+	if err != nil {
+		io.CheckErr(err)
+	}
+
+	return r
+}
+
+// getClient() return tca client for unit testing,
+// TCA_CASSETTE replays TCA responds from a cassette file,
+// with TCA_RECORD=true responds recorded to a cassette file
+// and with TCA_SIM=true tests run against in-memory simulator.
 func getClient() *client.RestClient {
 
+	if os.Getenv("TCA_SIM") == "true" {
+		return getSimClient()
+	}
+
+	record := os.Getenv("TCA_RECORD") == "true"
+	testRecorderOnce.Do(func() {
+		var err error
+		testRecorder, err = client.NewRecorderFromEnv("TCA_CASSETTE", record)
+		io.CheckErr(err)
+	})
+	recorder := testRecorder
+
 	tcaUrl := os.Getenv("TCA_URL")
+	if len(tcaUrl) == 0 && recorder != nil && !record {
+		tcaUrl = "https://tca.cassette"
+	}
 	if len(tcaUrl) == 0 {
 		io.PrintAndExit("TCA_URL not set")
 	}
 
 	tcaUsername := os.Getenv("TCA_USERNAME")
-	if len(tcaUrl) == 0 {
+	if len(tcaUsername) == 0 && recorder != nil && !record {
+		tcaUsername = "cassette"
+	}
+	if len(tcaUsername) == 0 {
 		io.PrintAndExit("TCA_USERNAME not set")
 	}
 
 	tcaPassword := os.Getenv("TCA_PASSWORD")
-	if len(tcaUrl) == 0 {
+	if len(tcaPassword) == 0 && recorder != nil && !record {
+		tcaPassword = "cassette"
+	}
+	if len(tcaPassword) == 0 {
 		io.PrintAndExit("TCA_PASSWORD not set")
 	}
 
@@ -143,6 +208,10 @@ func getClient() *client.RestClient {
 		io.CheckErr(err)
 	}
 
+	if recorder != nil {
+		r.WrapTransport(recorder.Wrap)
+	}
+
 	return r
 }
 
@@ -297,6 +366,7 @@ func generateName() string {
 }
 
 var yamlMgmtTemplate = `
+kind: template
 clusterType: MANAGEMENT
 clusterConfig:
     kubernetesVersion: v1.20.4+vmware.1
@@ -329,6 +399,7 @@ workerNodes:
 `
 
 var yamlInvalidMgmtTemplate = `
+kind: template
 clusterType: MANAGEMENT
 clusterConfig:
     kubernetesVersion: v1.20.4+vmware.1
@@ -351,6 +422,7 @@ workerNodes:
 `
 
 var yamlInvalidMgmtTemplate2 = `
+kind: template
 clusterType: MANAGEMENT
 clusterConfig:
     kubernetesVersion: v1.20.4+vmware.1
@@ -368,6 +440,7 @@ name: min
 `
 
 var yamlInvalidMgmtTemplate3 = `
+kind: template
 clusterType: 
 clusterConfig:
     kubernetesVersion: v1.20.4+vmware.1
@@ -400,6 +473,7 @@ workerNodes:
 `
 
 var yamlInvalidMgmtTemplate4 = `
+kind: template
 clusterType: MANAGEMENT
 clusterConfig:
     kubernetesVersion: v1.20.4+vmware.1
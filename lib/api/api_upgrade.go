@@ -0,0 +1,528 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// UpgradeStepControlPlane upgrade step of cluster control plane
+	UpgradeStepControlPlane = "control-plane"
+
+	// UpgradeStepNodePool upgrade step of a node pool
+	UpgradeStepNodePool = "nodepool"
+
+	// UpgradeStatusPlanned step not started yet
+	UpgradeStatusPlanned = "PLANNED"
+
+	// UpgradeStatusSkipped step skipped since previous step failed
+	UpgradeStatusSkipped = "SKIPPED"
+)
+
+const (
+	checkClusterHealth     = "cluster-health"
+	checkUpgradePath       = "upgrade-path"
+	checkManagementVersion = "management-version"
+	checkClusterTemplate   = "cluster-template"
+	checkPoolHealth        = "pool-health"
+)
+
+// UpgradeCheck pre-flight check result
+type UpgradeCheck struct {
+	Name    string `json:"name" yaml:"name"`
+	Passed  bool   `json:"passed" yaml:"passed"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// UpgradeStep control plane or node pool upgrade step
+type UpgradeStep struct {
+	Kind        string        `json:"kind" yaml:"kind"`
+	Name        string        `json:"name" yaml:"name"`
+	Id          string        `json:"id" yaml:"id"`
+	From        string        `json:"from,omitempty" yaml:"from,omitempty"`
+	To          string        `json:"to" yaml:"to"`
+	Status      string        `json:"status" yaml:"status"`
+	OperationId string        `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Elapsed     time.Duration `json:"elapsed,omitempty" yaml:"elapsed,omitempty"`
+	Error       string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// UpgradePlan upgrade plan, pre-flight checks and, once upgrade
+// finished, status of each step.
+type UpgradePlan struct {
+	Cluster     string         `json:"cluster" yaml:"cluster"`
+	ClusterId   string         `json:"clusterId" yaml:"clusterId"`
+	ClusterType string         `json:"clusterType" yaml:"clusterType"`
+	Current     string         `json:"current" yaml:"current"`
+	Target      string         `json:"target" yaml:"target"`
+	Paths       []string       `json:"paths" yaml:"paths"`
+	Template    string         `json:"template,omitempty" yaml:"template,omitempty"`
+	TemplateId  string         `json:"templateId,omitempty" yaml:"templateId,omitempty"`
+	Checks      []UpgradeCheck `json:"checks" yaml:"checks"`
+	Steps       []UpgradeStep  `json:"steps" yaml:"steps"`
+}
+
+// FailedChecks returns pre-flight checks that failed
+func (p *UpgradePlan) FailedChecks() []UpgradeCheck {
+	var failed []UpgradeCheck
+	for _, c := range p.Checks {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// IsReady returns true if all pre-flight checks passed
+func (p *UpgradePlan) IsReady() bool {
+	return len(p.FailedChecks()) == 0
+}
+
+// check adds pre-flight check result
+func (p *UpgradePlan) check(name string, passed bool, format string, args ...interface{}) {
+	p.Checks = append(p.Checks, UpgradeCheck{
+		Name:    name,
+		Passed:  passed,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// UpgradeBlocked error returned if upgrade pre-flight checks failed
+type UpgradeBlocked struct {
+	Checks []UpgradeCheck
+}
+
+func (e *UpgradeBlocked) Error() string {
+	var msg []string
+	for _, c := range e.Checks {
+		msg = append(msg, c.Name+": "+c.Message)
+	}
+	return "upgrade blocked by pre-flight checks, " + strings.Join(msg, "; ")
+}
+
+func (e *UpgradeBlocked) Unwrap() error {
+	return api_errors.ErrValidation
+}
+
+// parseK8sVersion parses version in form v1.20.4+vmware.1
+// to major, minor, patch and vendor build.
+func parseK8sVersion(v string) ([4]int, bool) {
+
+	var parsed [4]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	core, build := v, ""
+	if i := strings.Index(v, "+"); i >= 0 {
+		core, build = v[:i], v[i+1:]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+
+	if i := strings.LastIndex(build, "."); i >= 0 {
+		if n, err := strconv.Atoi(build[i+1:]); err == nil {
+			parsed[3] = n
+		}
+	}
+
+	return parsed, true
+}
+
+// compareK8sVersion returns -1, 0 or 1 if version a older,
+// same or newer than b. Versions that can't be parsed compared as strings.
+func compareK8sVersion(a, b string) int {
+
+	va, okA := parseK8sVersion(a)
+	vb, okB := parseK8sVersion(b)
+	if !okA || !okB {
+		return strings.Compare(a, b)
+	}
+
+	for i := range va {
+		if va[i] < vb[i] {
+			return -1
+		}
+		if va[i] > vb[i] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// normalizeK8sVersion adds v prefix, i.e 1.21.2+vmware.1 to v1.21.2+vmware.1
+func normalizeK8sVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > 0 && !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// upgradePaths returns versions supported for a cluster type that are newer than
+// current, TCA upgrades kubernetes at most one minor version at a time.
+func upgradePaths(current string, clusterType string, supported *client.SupportedVersion) []string {
+
+	var paths []string
+	if supported == nil {
+		return paths
+	}
+
+	cur, ok := parseK8sVersion(current)
+	for _, item := range supported.Items {
+		if !strings.EqualFold(item.ClusterType, clusterType) {
+			continue
+		}
+		for _, s := range item.SupportedVersions {
+			v, isString := s.(string)
+			if !isString || compareK8sVersion(v, current) <= 0 {
+				continue
+			}
+			if next, parsed := parseK8sVersion(v); ok && parsed &&
+				(next[0] != cur[0] || next[1] > cur[1]+1) {
+				continue
+			}
+			paths = append(paths, v)
+		}
+	}
+
+	// sort oldest first
+	for i := 1; i < len(paths); i++ {
+		for j := i; j > 0 && compareK8sVersion(paths[j-1], paths[j]) > 0; j-- {
+			paths[j-1], paths[j] = paths[j], paths[j-1]
+		}
+	}
+
+	return paths
+}
+
+// clusterVersion returns cluster kubernetes version, from cluster
+// template version or kubernetes version of cluster template.
+func (a *TcaApi) clusterVersion(ctx context.Context, spec *response.ClusterSpec) (string, error) {
+
+	if spec.ClusterTemplate == nil {
+		return "", api_errors.NewInvalidSpec("cluster " + spec.ClusterName + " has no cluster template")
+	}
+
+	if len(spec.ClusterTemplate.Version) > 0 {
+		return spec.ClusterTemplate.Version, nil
+	}
+
+	t, err := a.rest.GetClusterTemplate(ctx, spec.ClusterTemplate.Id)
+	if err != nil {
+		return "", err
+	}
+	if t.ClusterConfig == nil || len(t.ClusterConfig.KubernetesVersion) == 0 {
+		return "", api_errors.NewInvalidSpec("cluster template " + t.Name + " has no kubernetes version")
+	}
+
+	return t.ClusterConfig.KubernetesVersion, nil
+}
+
+// UpgradePaths returns kubernetes versions a cluster can be upgraded to, oldest first.
+func (a *TcaApi) UpgradePaths(ctx context.Context, cluster string) ([]string, error) {
+
+	spec, err := a.GetCluster(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := a.clusterVersion(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	supported, err := a.rest.GetClusterCompatability(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return upgradePaths(current, spec.ClusterType, supported), nil
+}
+
+// planTemplate resolves cluster template for target version, if name
+// indicated, template must match cluster type and target version.
+func (a *TcaApi) planTemplate(ctx context.Context, plan *UpgradePlan, name string) error {
+
+	templates, err := a.rest.GetClusterTemplates(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches := func(t *response.ClusterTemplateSpec) bool {
+		return strings.EqualFold(t.ClusterType, plan.ClusterType) &&
+			t.ClusterConfig != nil && t.ClusterConfig.KubernetesVersion == plan.Target
+	}
+
+	if len(name) > 0 {
+		t, err := templates.GetTemplate(name)
+		if err != nil {
+			plan.check(checkClusterTemplate, false, "cluster template %s not found", name)
+			return nil
+		}
+		plan.Template, plan.TemplateId = t.Name, t.Id
+		if !matches(t) {
+			plan.check(checkClusterTemplate, false,
+				"cluster template %s is not a %s template with kubernetes version %s",
+				t.Name, plan.ClusterType, plan.Target)
+			return nil
+		}
+		plan.check(checkClusterTemplate, true, "cluster template %s", t.Name)
+		return nil
+	}
+
+	for i := range templates.ClusterTemplates {
+		t := &templates.ClusterTemplates[i]
+		if matches(t) {
+			plan.Template, plan.TemplateId = t.Name, t.Id
+			plan.check(checkClusterTemplate, true, "cluster template %s", t.Name)
+			return nil
+		}
+	}
+
+	plan.check(checkClusterTemplate, false,
+		"no %s cluster template with kubernetes version %s", plan.ClusterType, plan.Target)
+
+	return nil
+}
+
+// planManagement checks that management cluster already runs target version,
+// workload cluster can't run version newer than its management cluster.
+func (a *TcaApi) planManagement(ctx context.Context, plan *UpgradePlan, spec *response.ClusterSpec) error {
+
+	if !strings.EqualFold(spec.ClusterType, models.TemplateWorkload) {
+		plan.check(checkManagementVersion, true, "%s is a management cluster", spec.ClusterName)
+		return nil
+	}
+
+	mgmt, err := a.GetCluster(ctx, spec.ManagementClusterId)
+	if err != nil {
+		return err
+	}
+
+	version, err := a.clusterVersion(ctx, mgmt)
+	if err != nil {
+		return err
+	}
+
+	plan.check(checkManagementVersion, compareK8sVersion(version, plan.Target) >= 0,
+		"management cluster %s version %s", mgmt.ClusterName, version)
+
+	return nil
+}
+
+// PlanUpgrade resolves upgrade target, runs pre-flight checks and returns
+// upgrade steps, control plane first followed by each node pool.
+// If request indicates node pool, only node pool upgraded to control plane version.
+func (a *TcaApi) PlanUpgrade(ctx context.Context, req *UpgradeApiReq) (*UpgradePlan, error) {
+
+	if req == nil {
+		return nil, api_errors.NewInvalidArgument("nil request")
+	}
+	if len(req.Cluster) == 0 {
+		return nil, api_errors.NewInvalidArgument("cluster name or id is empty")
+	}
+
+	spec, err := a.GetCluster(ctx, req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := a.clusterVersion(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &UpgradePlan{
+		Cluster:     spec.ClusterName,
+		ClusterId:   spec.Id,
+		ClusterType: spec.ClusterType,
+		Current:     current,
+		Target:      current,
+	}
+
+	plan.check(checkClusterHealth, spec.Status == StateActive && spec.ActiveTasksCount == 0,
+		"cluster status %s, active tasks %d", spec.Status, spec.ActiveTasksCount)
+
+	pools, err := a.rest.GetClusterNodePools(ctx, spec.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.NodePool) > 0 {
+		pool, err := pools.GetPool(req.NodePool)
+		if err != nil {
+			return nil, err
+		}
+		pools = response.NewNodePool(pool)
+	} else {
+		supported, err := a.rest.GetClusterCompatability(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Paths = upgradePaths(current, spec.ClusterType, supported)
+		switch {
+		case len(req.Version) > 0:
+			plan.Target = normalizeK8sVersion(req.Version)
+			allowed := false
+			for _, p := range plan.Paths {
+				allowed = allowed || p == plan.Target
+			}
+			plan.check(checkUpgradePath, allowed, "upgrade %s to %s, allowed %s",
+				current, plan.Target, strings.Join(plan.Paths, ", "))
+		case len(plan.Paths) > 0:
+			plan.Target = plan.Paths[len(plan.Paths)-1]
+			plan.check(checkUpgradePath, true, "upgrade %s to %s", current, plan.Target)
+		default:
+			plan.check(checkUpgradePath, false, "no upgrade available for %s", current)
+		}
+
+		if err := a.planManagement(ctx, plan, spec); err != nil {
+			return nil, err
+		}
+		if err := a.planTemplate(ctx, plan, req.Template); err != nil {
+			return nil, err
+		}
+
+		plan.Steps = append(plan.Steps, UpgradeStep{
+			Kind:   UpgradeStepControlPlane,
+			Name:   spec.ClusterName,
+			Id:     spec.Id,
+			From:   current,
+			To:     plan.Target,
+			Status: UpgradeStatusPlanned,
+		})
+	}
+
+	for _, pool := range pools.Pools {
+		plan.check(checkPoolHealth, pool.Status == StateActive && pool.ActiveTasksCount == 0,
+			"node pool %s status %s, active tasks %d", pool.Name, pool.Status, pool.ActiveTasksCount)
+
+		step := UpgradeStep{
+			Kind:   UpgradeStepNodePool,
+			Name:   pool.Name,
+			Id:     pool.Id,
+			To:     plan.Target,
+			Status: UpgradeStatusPlanned,
+		}
+		if len(req.NodePool) == 0 {
+			step.From = current
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	return plan, nil
+}
+
+// upgradeStep starts control plane or node pool upgrade and waits
+// until task finished and cluster or node pool is active again.
+func (a *TcaApi) upgradeStep(ctx context.Context, plan *UpgradePlan, step *UpgradeStep, vmTemplate string) error {
+
+	var (
+		task *models.TcaTask
+		cond WaitCondition
+		err  error
+	)
+
+	switch step.Kind {
+	case UpgradeStepControlPlane:
+		task, err = a.rest.UpgradeCluster(ctx, plan.ClusterId, &client.ClusterUpgradeSpec{
+			ClusterTemplateId: plan.TemplateId,
+			VmTemplate:        vmTemplate,
+		})
+		cond = a.ClusterCondition(plan.ClusterId, StateActive)
+	case UpgradeStepNodePool:
+		task, err = a.rest.UpgradeNodePool(ctx, plan.ClusterId, step.Id)
+		cond = a.NodePoolCondition(plan.ClusterId, step.Id, StateActive)
+	default:
+		return api_errors.NewInvalidArgument("unknown upgrade step " + step.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(task.Id) == 0 {
+		task.Id = step.Id
+	}
+	step.OperationId = task.OperationId
+
+	if err := a.WaitTask(ctx, task, TaskStateSuccess); err != nil {
+		return err
+	}
+
+	return a.Waiter().Wait(ctx, step.Kind+" "+step.Name, cond)
+}
+
+// Upgrade upgrades cluster control plane and then each node pool in turn.
+// Dry run returns plan, if pre-flight checks failed UpgradeBlocked returned.
+// Plan returned with status of each step, once a step fails rest skipped.
+func (a *TcaApi) Upgrade(ctx context.Context, req *UpgradeApiReq) (*UpgradePlan, error) {
+
+	plan, err := a.PlanUpgrade(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsDryRun {
+		return plan, nil
+	}
+
+	if !plan.IsReady() {
+		return plan, &UpgradeBlocked{Checks: plan.FailedChecks()}
+	}
+
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		glog.Infof("Upgrading %s %s to %s", step.Kind, step.Name, step.To)
+
+		start := time.Now()
+		err := a.upgradeStep(ctx, plan, step, req.VmTemplate)
+		step.Elapsed = time.Since(start).Round(time.Second)
+		if err != nil {
+			step.Status = TaskStateFailed
+			step.Error = err.Error()
+			for j := i + 1; j < len(plan.Steps); j++ {
+				plan.Steps[j].Status = UpgradeStatusSkipped
+			}
+			return plan, err
+		}
+
+		step.Status = TaskStateSuccess
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/tcasim"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	simMgmtClusterId  = "9ceb62ef-c48d-4504-86c5-cc9ce6ae1aae"
+	simUpgradeVersion = "v1.21.2+vmware.1"
+)
+
+// addUpgradeTemplates adds management and workload
+// cluster templates with kubernetes version to simulator
+func addUpgradeTemplates(sim *tcasim.Server, version string) {
+
+	state := sim.State()
+	for _, t := range append(state.Templates[:0:0], state.Templates...) {
+		cfg := *t.ClusterConfig
+		cfg.KubernetesVersion = version
+		t.ClusterConfig = &cfg
+		t.Id = t.Id + "-" + version
+		t.Name = t.Name + "-" + version
+		state.Templates = append(state.Templates, t)
+	}
+}
+
+func TestCompareK8sVersion(t *testing.T) {
+
+	assert.Equal(t, -1, compareK8sVersion("v1.20.4+vmware.1", "v1.20.5+vmware.1"))
+	assert.Equal(t, -1, compareK8sVersion("v1.20.4+vmware.1", "v1.20.4+vmware.2"))
+	assert.Equal(t, 1, compareK8sVersion("v1.21.2+vmware.1", "v1.20.12+vmware.1"))
+	assert.Equal(t, 0, compareK8sVersion("1.21.2+vmware.1", "v1.21.2+vmware.1"))
+}
+
+func TestUpgradePaths(t *testing.T) {
+
+	supported := &client.SupportedVersion{}
+	supported.Items = append(supported.Items, struct {
+		ClusterType       string        `json:"clusterType" yaml:"cluster_type"`
+		SupportedVersions []interface{} `json:"supportedVersions" yaml:"supported_versions"`
+	}{
+		ClusterType: "WORKLOAD",
+		SupportedVersions: []interface{}{
+			"v1.22.1+vmware.1", "v1.21.2+vmware.1", "v1.20.4+vmware.1", "v1.20.5+vmware.2", "v1.19.1+vmware.2",
+		},
+	})
+
+	assert.Equal(t, []string{"v1.20.5+vmware.2", "v1.21.2+vmware.1"},
+		upgradePaths("v1.20.4+vmware.1", "WORKLOAD", supported), "newer versions, one minor at a time")
+	assert.Empty(t, upgradePaths("v1.20.4+vmware.1", "MANAGEMENT", supported))
+	assert.Empty(t, upgradePaths("v1.22.1+vmware.1", "WORKLOAD", supported))
+}
+
+func TestTcaApi_PlanUpgrade(t *testing.T) {
+
+	ctx := context.Background()
+	a, _ := getOperationSimApi(t, tcasim.Options{})
+
+	paths, err := a.UpgradePaths(ctx, "edge-test01")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.20.5+vmware.2", simUpgradeVersion}, paths)
+
+	req := &UpgradeApiReq{Cluster: "edge-test01", IsDryRun: true}
+	plan, err := a.Upgrade(ctx, req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "v1.20.4+vmware.1", plan.Current)
+	assert.Equal(t, simUpgradeVersion, plan.Target, "latest upgrade path by default")
+	assert.False(t, plan.IsReady())
+
+	var failed []string
+	for _, c := range plan.FailedChecks() {
+		failed = append(failed, c.Name)
+	}
+	assert.Equal(t, []string{checkManagementVersion, checkClusterTemplate}, failed)
+
+	if assert.Len(t, plan.Steps, 2) {
+		assert.Equal(t, UpgradeStepControlPlane, plan.Steps[0].Kind)
+		assert.Equal(t, UpgradeStepNodePool, plan.Steps[1].Kind)
+		assert.Equal(t, UpgradeStatusPlanned, plan.Steps[1].Status)
+	}
+
+	req.IsDryRun = false
+	var blocked *UpgradeBlocked
+	_, err = a.Upgrade(ctx, req)
+	assert.True(t, errors.As(err, &blocked), "pre-flight failed, got %v", err)
+	assert.True(t, errors.Is(err, api_errors.ErrValidation))
+
+	req.Version = "v1.19.1+vmware.2"
+	plan, err = a.PlanUpgrade(ctx, req)
+	assert.NoError(t, err)
+	for _, c := range plan.Checks {
+		if c.Name == checkUpgradePath {
+			assert.False(t, c.Passed, "downgrade not allowed")
+		}
+	}
+}
+
+func TestTcaApi_Upgrade(t *testing.T) {
+
+	ctx := context.Background()
+	a, sim := getOperationSimApi(t, tcasim.Options{})
+	addUpgradeTemplates(sim, simUpgradeVersion)
+
+	for _, cluster := range []string{simMgmtClusterId, "edge-test01"} {
+		plan, err := a.Upgrade(ctx, &UpgradeApiReq{Cluster: cluster})
+		if !assert.NoError(t, err, "upgrade %s", cluster) {
+			return
+		}
+		for _, s := range plan.Steps {
+			assert.Equal(t, TaskStateSuccess, s.Status, "%s %s", s.Kind, s.Name)
+			assert.NotEmpty(t, s.OperationId)
+		}
+	}
+
+	spec, err := a.GetCluster(ctx, simWorkloadClusterId)
+	assert.NoError(t, err)
+	assert.Equal(t, simUpgradeVersion, spec.ClusterTemplate.Version)
+
+	plan, err := a.Upgrade(ctx, &UpgradeApiReq{Cluster: "edge-test01", NodePool: simPoolId})
+	if assert.NoError(t, err) && assert.Len(t, plan.Steps, 1) {
+		assert.Equal(t, simUpgradeVersion, plan.Steps[0].To, "pool upgraded to control plane version")
+		assert.Equal(t, TaskStateSuccess, plan.Steps[0].Status)
+	}
+}
+
+func TestTcaApi_UpgradeFailedStep(t *testing.T) {
+
+	ctx := context.Background()
+	a, sim := getOperationSimApi(t, tcasim.Options{})
+	addUpgradeTemplates(sim, simUpgradeVersion)
+
+	sim.InjectFailure(simMgmtClusterId)
+	plan, err := a.Upgrade(ctx, &UpgradeApiReq{Cluster: simMgmtClusterId})
+	assert.Error(t, err)
+	if assert.NotNil(t, plan) && assert.Len(t, plan.Steps, 2) {
+		assert.Equal(t, TaskStateFailed, plan.Steps[0].Status)
+		assert.NotEmpty(t, plan.Steps[0].Error)
+		assert.Equal(t, UpgradeStatusSkipped, plan.Steps[1].Status, "pools not upgraded")
+	}
+}
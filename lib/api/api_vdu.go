@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/spyroot/tcactl/lib/api_errors"
@@ -25,7 +26,7 @@ import (
 )
 
 // GetVdu retrieve Vdu
-func (a *TcaApi) GetVdu(nfdName string) (*response.VduPackage, error) {
+func (a *TcaApi) GetVdu(ctx context.Context, nfdName string) (*response.VduPackage, error) {
 
 	if a.rest == nil {
 		return nil, fmt.Errorf("rest interface is nil")
@@ -35,7 +36,7 @@ func (a *TcaApi) GetVdu(nfdName string) (*response.VduPackage, error) {
 		return nil, api_errors.NewCatalogNotFound(nfdName)
 	}
 
-	vnfCatalog, err := a.rest.GetVnfPkgm("", "")
+	vnfCatalog, err := a.rest.GetVnfPkgm(ctx, "", "")
 	if err != nil || vnfCatalog == nil {
 		glog.Errorf("Failed retrieve vnf package information, error %v", err)
 		return nil, err
@@ -47,7 +48,7 @@ func (a *TcaApi) GetVdu(nfdName string) (*response.VduPackage, error) {
 		return nil, err
 	}
 
-	vnfd, err := a.rest.GetVnfPkgmVnfd(pkgCnf.PID)
+	vnfd, err := a.rest.GetVnfPkgmVnfd(ctx, pkgCnf.PID)
 	if err != nil || vnfd == nil {
 		glog.Errorf("Failed acquire VDU information for %v.", pkgCnf.PID)
 		return nil, err
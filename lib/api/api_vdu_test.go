@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"github.com/spyroot/tcactl/lib/client"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -50,13 +51,13 @@ func TestGetVdu(t *testing.T) {
 			name:    "Valid user defined name must be resolved",
 			rest:    getAuthenticatedClient(),
 			wantErr: false,
-			vduName: "app",
+			vduName: getTestCatalogName(),
 		},
 		{
 			name:             "Valid user defined name and simulated failure",
 			rest:             getAuthenticatedClient(),
 			wantErr:          true,
-			vduName:          "app",
+			vduName:          getTestCatalogName(),
 			simulateFailure:  true,
 			failureCondition: client.TcaVmwareTelcoPackages,
 		},
@@ -64,7 +65,7 @@ func TestGetVdu(t *testing.T) {
 			name:             "Valid user defined name and simulated failure",
 			rest:             getAuthenticatedClient(),
 			wantErr:          true,
-			vduName:          "app",
+			vduName:          getTestCatalogName(),
 			simulateFailure:  true,
 			failureCondition: client.TcaVmwareTelcoPackages + "/",
 		},
@@ -79,7 +80,7 @@ func TestGetVdu(t *testing.T) {
 			}
 			defer a.rest.SetSimulateFailure(false)
 
-			got, err := a.GetVdu(tt.vduName)
+			got, err := a.GetVdu(context.Background(), tt.vduName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetVdu() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -269,6 +269,9 @@ func TestTcaApi_GetVimNetworks(t *testing.T) {
 				t.Errorf("GetVimNetworks() error = %v, vimErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && err != nil {
+				return
+			}
 			if !tt.wantErr && got == nil {
 				t.Errorf("GetVimNetworks() error = %v, vimErr %v", err, tt.wantErr)
 				return
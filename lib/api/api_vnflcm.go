@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -33,9 +34,9 @@ import (
 
 // GetAllPackages return all catalog entries
 // as response.CnfsExtended object
-func (a *TcaApi) GetAllPackages() (*response.CnfsExtended, error) {
+func (a *TcaApi) GetAllPackages(ctx context.Context) (*response.CnfsExtended, error) {
 
-	respond, err := a.GetVnflcm()
+	respond, err := a.GetVnflcm(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -49,15 +50,15 @@ func (a *TcaApi) GetAllPackages() (*response.CnfsExtended, error) {
 }
 
 // GetVnflcm return lcm state
-func (a *TcaApi) GetVnflcm(f ...string) (interface{}, error) {
-	return a.rest.GetVnflcm(f...)
+func (a *TcaApi) GetVnflcm(ctx context.Context, f ...string) (interface{}, error) {
+	return a.rest.GetVnflcm(ctx, f...)
 }
 
 // GetAllInstances api method returns a list of CNF/VNF instances in
 // response.CnfsExtended that encapsulate in collection
-func (a *TcaApi) GetAllInstances() (*response.CnfsExtended, error) {
+func (a *TcaApi) GetAllInstances(ctx context.Context) (*response.CnfsExtended, error) {
 
-	genericRespond, err := a.rest.GetVnflcm()
+	genericRespond, err := a.rest.GetVnflcm(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +80,7 @@ func (a *TcaApi) GetAllInstances() (*response.CnfsExtended, error) {
 // that used to replace value in actual CSAR.
 // i.e  existing CSAR used as template and substitution
 // map applied a transformation.
-func (a *TcaApi) CreateCatalogEntity(
+func (a *TcaApi) CreateCatalogEntity(ctx context.Context,
 	fileName string,
 	catalogName string,
 	substitution map[string]string) (bool, error) {
@@ -120,7 +121,7 @@ func (a *TcaApi) CreateCatalogEntity(
 
 	newFileName := filepath.Base(newCsarFile)
 	uploadReq := client.NewPackageUpload(catalogName)
-	respond, err := a.rest.CreateVnfPkgmVnfd(uploadReq)
+	respond, err := a.rest.CreateVnfPkgmVnfd(ctx, uploadReq)
 	if err != nil {
 		glog.Errorf("Failed create catalog entity from generated csar %v", err)
 		return false, err
@@ -132,7 +133,7 @@ func (a *TcaApi) CreateCatalogEntity(
 	}
 
 	// upload csar to a catalog
-	ok, err := a.rest.UploadVnfPkgmVnfd(respond.Id, fileBytes, newFileName)
+	ok, err := a.rest.UploadVnfPkgmVnfd(ctx, respond.Id, fileBytes, newFileName)
 	if err != nil {
 		return false, err
 	}
@@ -142,7 +143,7 @@ func (a *TcaApi) CreateCatalogEntity(
 }
 
 // DeleteCatalogEntity api method deletes catalog entity
-func (a *TcaApi) DeleteCatalogEntity(catalogName string) (bool, error) {
+func (a *TcaApi) DeleteCatalogEntity(ctx context.Context, catalogName string) (bool, error) {
 
 	glog.Infof("Delete catalog entity %v.", catalogName)
 
@@ -150,12 +151,12 @@ func (a *TcaApi) DeleteCatalogEntity(catalogName string) (bool, error) {
 		return false, api_errors.NewInvalidArgument(catalogName)
 	}
 
-	catalogId, _, err := a.rest.GetPackageCatalogId(catalogName)
+	catalogId, _, err := a.rest.GetPackageCatalogId(ctx, catalogName)
 	if err != nil {
 		return false, err
 	}
 
-	ok, err := a.rest.DeleteVnfPkgmVnfd(catalogId)
+	ok, err := a.rest.DeleteVnfPkgmVnfd(ctx, catalogId)
 	if err != nil {
 		return false, err
 	}
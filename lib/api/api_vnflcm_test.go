@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"github.com/spyroot/tcactl/lib/client"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -45,7 +46,7 @@ func TestGetAllPackages(t *testing.T) {
 			a, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			entireCatalog, err := a.GetAllPackages()
+			entireCatalog, err := a.GetAllPackages(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllPackages() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -78,7 +79,7 @@ func TestGetByCatalogName(t *testing.T) {
 			a, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			entireCatalog, err := a.GetAllPackages()
+			entireCatalog, err := a.GetAllPackages(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllPackages() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -122,7 +123,7 @@ func TestResolveFromName(t *testing.T) {
 			a, err := NewTcaApi(tt.rest)
 			assert.NoError(t, err)
 
-			entireCatalog, err := a.GetAllPackages()
+			entireCatalog, err := a.GetAllPackages(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllPackages() error = %v, wantOnGetErr %v", err, tt.wantErr)
 				return
@@ -175,6 +176,10 @@ func TestCreateCatalogEntity(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
+			if !hasTestAssets() {
+				t.Skip("test assets dir not present")
+			}
+
 			dir := GetTestAssetsDir()
 			t.Log(dir)
 
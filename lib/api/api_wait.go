@@ -0,0 +1,207 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/lib/models"
+	"strings"
+)
+
+const (
+	// WaitKindCluster waits for cluster state
+	WaitKindCluster = "cluster"
+
+	// WaitKindNodePool waits for node pool state
+	WaitKindNodePool = "nodepool"
+
+	// WaitKindInstance waits for CNF/VNF instance state
+	WaitKindInstance = "cnf"
+
+	// WaitKindTask waits for cluster task state
+	WaitKindTask = "task"
+)
+
+// waitKinds maps kind and its aliases to a kind
+var waitKinds = map[string]string{
+	"cluster":   WaitKindCluster,
+	"clusters":  WaitKindCluster,
+	"nodepool":  WaitKindNodePool,
+	"nodepools": WaitKindNodePool,
+	"pool":      WaitKindNodePool,
+	"pools":     WaitKindNodePool,
+	"cnf":       WaitKindInstance,
+	"cnfi":      WaitKindInstance,
+	"vnf":       WaitKindInstance,
+	"instance":  WaitKindInstance,
+	"instances": WaitKindInstance,
+	"task":      WaitKindTask,
+	"tasks":     WaitKindTask,
+}
+
+// waitStates states each kind can wait for, first is default
+var waitStates = map[string][]string{
+	WaitKindCluster:  {StateActive},
+	WaitKindNodePool: {StateActive},
+	WaitKindInstance: {StateInstantiated, StateNotInstantiated, StateCompleted},
+	WaitKindTask:     {TaskStateSuccess},
+}
+
+// EntityFailed error returned if object reached failure state
+type EntityFailed struct {
+	What  string
+	State string
+}
+
+func (e *EntityFailed) Error() string {
+	return e.What + " failed, state " + e.State
+}
+
+// ParseWaitKind resolves kind or its alias
+func ParseWaitKind(kind string) (string, error) {
+	k, ok := waitKinds[strings.ToLower(kind)]
+	if !ok {
+		return "", api_errors.NewInvalidSpec(
+			fmt.Sprintf("unknown kind %s, supported cluster, nodepool, cnf, task", kind))
+	}
+	return k, nil
+}
+
+// WaitStates returns states kind can wait for, first is default
+func WaitStates(kind string) []string {
+	return waitStates[kind]
+}
+
+// parseWaitState returns state to wait for, a state
+// might be indicated as key=value, i.e. state=ACTIVE
+func parseWaitState(kind string, state string) (string, error) {
+
+	if i := strings.Index(state, "="); i >= 0 {
+		state = state[i+1:]
+	}
+	if len(state) == 0 {
+		return waitStates[kind][0], nil
+	}
+
+	state = strings.ToUpper(state)
+	for _, s := range waitStates[kind] {
+		if s == state {
+			return s, nil
+		}
+	}
+
+	return "", api_errors.NewInvalidSpec(fmt.Sprintf("%s can't wait for %s, supported %s",
+		kind, state, strings.Join(waitStates[kind], ", ")))
+}
+
+// statusCondition returns condition that holds once status reaches
+// waitFor, failure status fails condition.
+func statusCondition(what string, waitFor string,
+	status func(ctx context.Context) (string, int, error)) WaitCondition {
+
+	return func(ctx context.Context) (bool, []WaitEvent, error) {
+
+		s, tasks, err := status(ctx)
+		if err != nil {
+			return false, nil, err
+		}
+
+		e := WaitEvent{TaskId: what, Entity: what, Status: s}
+		reached := strings.EqualFold(s, waitFor) && tasks == 0
+		if reached {
+			e.Progress = 100
+		}
+		if IsFailedState(s) && !IsFailedState(waitFor) {
+			return false, []WaitEvent{e}, &EntityFailed{What: what, State: s}
+		}
+
+		return reached, []WaitEvent{e}, nil
+	}
+}
+
+// ClusterCondition returns condition that holds once cluster
+// reaches waitFor status and has no active tasks.
+func (a *TcaApi) ClusterCondition(cluster string, waitFor string) WaitCondition {
+	return statusCondition("cluster "+cluster, waitFor, func(ctx context.Context) (string, int, error) {
+		spec, err := a.GetCluster(ctx, cluster)
+		if err != nil {
+			return "", 0, err
+		}
+		return spec.Status, spec.ActiveTasksCount, nil
+	})
+}
+
+// NodePoolCondition returns condition that holds once node pool
+// reaches waitFor status and has no active tasks.
+func (a *TcaApi) NodePoolCondition(cluster string, pool string, waitFor string) WaitCondition {
+	return statusCondition("node pool "+pool, waitFor, func(ctx context.Context) (string, int, error) {
+		spec, err := a.GetClusterNodePool(ctx, cluster, pool)
+		if err != nil {
+			return "", 0, err
+		}
+		return spec.Status, spec.ActiveTasksCount, nil
+	})
+}
+
+// WaitFor blocks until object reaches a state, returns EntityFailed or
+// TcaTaskFailed if object reached failure state, WaitTimeout on timeout.
+func (a *TcaApi) WaitFor(ctx context.Context, req *WaitApiReq) error {
+
+	if req == nil {
+		return api_errors.NewInvalidSpec("wait request is nil")
+	}
+
+	kind, err := ParseWaitKind(req.Kind)
+	if err != nil {
+		return err
+	}
+
+	state, err := parseWaitState(kind, req.For)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 {
+		return api_errors.NewInvalidSpec(kind + " name is empty")
+	}
+
+	switch kind {
+	case WaitKindCluster:
+		return a.Waiter().Wait(ctx, "cluster "+req.Name+" "+state, a.ClusterCondition(req.Name, state))
+	case WaitKindNodePool:
+		cluster, pool := req.Cluster, req.Name
+		if i := strings.Index(pool, "/"); i > 0 {
+			cluster, pool = pool[:i], pool[i+1:]
+		}
+		if len(cluster) == 0 {
+			return api_errors.NewInvalidSpec("node pool requires cluster name")
+		}
+		return a.Waiter().Wait(ctx, "node pool "+pool+" "+state, a.NodePoolCondition(cluster, pool, state))
+	case WaitKindInstance:
+		instanceId, err := a.ResolveInstanceName(ctx, req.Name)
+		if err != nil {
+			return err
+		}
+		return a.WaitInstanceState(ctx, instanceId, state)
+	case WaitKindTask:
+		return a.WaitTask(ctx, &models.TcaTask{Id: req.Name}, state)
+	}
+
+	return nil
+}
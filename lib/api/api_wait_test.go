@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseWaitKind(t *testing.T) {
+
+	for kind, want := range map[string]string{
+		"cluster":  WaitKindCluster,
+		"Clusters": WaitKindCluster,
+		"pool":     WaitKindNodePool,
+		"cnfi":     WaitKindInstance,
+		"task":     WaitKindTask,
+	} {
+		k, err := ParseWaitKind(kind)
+		assert.NoError(t, err)
+		assert.Equal(t, want, k, kind)
+	}
+
+	_, err := ParseWaitKind("vim")
+	assert.ErrorIs(t, err, api_errors.ErrValidation)
+}
+
+func TestParseWaitState(t *testing.T) {
+
+	s, err := parseWaitState(WaitKindCluster, "")
+	assert.NoError(t, err)
+	assert.Equal(t, StateActive, s)
+
+	s, err = parseWaitState(WaitKindInstance, "condition=instantiated")
+	assert.NoError(t, err)
+	assert.Equal(t, StateInstantiated, s)
+
+	s, err = parseWaitState(WaitKindInstance, "completed")
+	assert.NoError(t, err)
+	assert.Equal(t, StateCompleted, s)
+
+	_, err = parseWaitState(WaitKindTask, StateActive)
+	assert.ErrorIs(t, err, api_errors.ErrValidation)
+}
+
+func TestTcaApi_WaitFor(t *testing.T) {
+
+	ctx := context.Background()
+	cluster := `{"id":"4a4c5e1c-9e5b-4a1b-8a3a-2b3c4d5e6f70","clusterName":"edge","status":"%s","activeTasksCount":%d}`
+	id := "4a4c5e1c-9e5b-4a1b-8a3a-2b3c4d5e6f70"
+
+	a := getWaiterApi(t, func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(cluster, "CREATING", 1)
+		}
+		if n < 4 {
+			return fmt.Sprintf(cluster, StateActive, 1)
+		}
+		return fmt.Sprintf(cluster, StateActive, 0)
+	})
+	assert.NoError(t, a.WaitFor(ctx, &WaitApiReq{Kind: "cluster", Name: id}))
+
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(cluster, "CREATE_FAILED", 0)
+	})
+	var failed *EntityFailed
+	if assert.True(t, errors.As(a.WaitFor(ctx, &WaitApiReq{Kind: "cluster", Name: id}), &failed)) {
+		assert.Equal(t, "CREATE_FAILED", failed.State)
+	}
+
+	instance := `{"id":"1","instantiationState":"INSTANTIATED","metadata":{"lcmOperation":"SCALE","lcmOperationState":"%s"}}`
+	a = getWaiterApi(t, func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(instance, "PROCESSING")
+		}
+		return fmt.Sprintf(instance, StateCompleted)
+	})
+	assert.NoError(t, a.WithWaiter(fastWaiter()).WaitInstanceState(ctx, "1", StateCompleted))
+
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(instance, StateFailedTemp)
+	})
+	var taskFailed *TcaTaskFailed
+	assert.True(t, errors.As(a.WaitInstanceState(ctx, "1", StateCompleted), &taskFailed))
+
+	err := a.WaitFor(ctx, &WaitApiReq{Kind: "nodepool", Name: "pool"})
+	assert.ErrorIs(t, err, api_errors.ErrValidation, "node pool requires cluster")
+}
+
+func TestIsFailedState(t *testing.T) {
+	assert.True(t, IsFailedState(StateFailedTemp))
+	assert.True(t, IsFailedState("create_failed"))
+	assert.True(t, IsFailedState(TaskStateFailed))
+	assert.False(t, IsFailedState(StateActive))
+	assert.False(t, IsFailedState(StateNotInstantiated))
+}
@@ -0,0 +1,579 @@
+// Package api
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWaitInterval initial interval between polls
+	DefaultWaitInterval = 2 * time.Second
+
+	// DefaultWaitMaxInterval max interval between polls
+	DefaultWaitMaxInterval = TaskPoolSeconds * time.Second
+
+	// DefaultWaitBackoff multiplier interval grows by after each poll
+	DefaultWaitBackoff = 1.5
+
+	// DefaultWaitTimeout time after which wait gives up
+	DefaultWaitTimeout = 2 * time.Hour
+)
+
+// WaitEvent progress of a task or an instance waiter observed
+type WaitEvent struct {
+
+	// TaskId task or instance id
+	TaskId string
+
+	// Type task type or LCM operation
+	Type string
+
+	// Entity name of entity task acts on
+	Entity string
+
+	// Step title of current step
+	Step string
+
+	// Progress task progress in percent
+	Progress int
+
+	// Status task status or LCM operation state
+	Status string
+
+	// Message last message of a task
+	Message string
+
+	// Steps all steps of a task
+	Steps []models.TaskSteps
+
+	// Time when event observed
+	Time time.Time
+}
+
+// WaitCondition polls state, returns true once state
+// reached, events reported to waiter progress channel.
+type WaitCondition func(ctx context.Context) (bool, []WaitEvent, error)
+
+// WaitTimeout error returned if state not reached before deadline
+// or max attempts, errors.Is(err, context.DeadlineExceeded) holds.
+type WaitTimeout struct {
+
+	// What waiter waited for
+	What string
+
+	// Elapsed time waiter waited
+	Elapsed time.Duration
+
+	// Attempts number of polls
+	Attempts int
+
+	// LastStatus last status waiter observed
+	LastStatus string
+}
+
+func (e *WaitTimeout) Error() string {
+	msg := fmt.Sprintf("timeout waiting for %s after %v and %d attempts",
+		e.What, e.Elapsed.Round(time.Second), e.Attempts)
+	if len(e.LastStatus) > 0 {
+		msg += ", last status " + e.LastStatus
+	}
+	return msg
+}
+
+func (e *WaitTimeout) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Waiter polls a condition until it holds. Interval between polls
+// grows by Backoff up to MaxInterval. Wait gives up after Timeout or
+// MaxAttempts and returns WaitTimeout.
+//
+//	events := make(chan api.WaitEvent)
+//	w := api.NewWaiter()
+//	w.Progress = events
+//	go func() {
+//		for e := range events {
+//			fmt.Println(e.Step, e.Progress)
+//		}
+//	}()
+//	err := a.WithWaiter(w).WaitTask(ctx, task, api.TaskStateSuccess)
+//	close(events)
+type Waiter struct {
+
+	// Interval initial interval between polls
+	Interval time.Duration
+
+	// MaxInterval max interval between polls
+	MaxInterval time.Duration
+
+	// Backoff multiplier interval grows by, 1 or less keeps interval fixed
+	Backoff float64
+
+	// Timeout overall deadline, 0 waits until context canceled
+	Timeout time.Duration
+
+	// MaxAttempts max number of polls, 0 unlimited
+	MaxAttempts int
+
+	// Progress optional channel waiter sends events to,
+	// caller must drain it, waiter never closes it.
+	Progress chan<- WaitEvent
+}
+
+// NewWaiter returns waiter with default interval, backoff and timeout.
+func NewWaiter() *Waiter {
+	return &Waiter{
+		Interval:    DefaultWaitInterval,
+		MaxInterval: DefaultWaitMaxInterval,
+		Backoff:     DefaultWaitBackoff,
+		Timeout:     DefaultWaitTimeout,
+	}
+}
+
+// nextInterval returns interval after backoff
+func (w *Waiter) nextInterval(interval time.Duration) time.Duration {
+
+	if w.Backoff > 1 {
+		interval = time.Duration(float64(interval) * w.Backoff)
+	}
+	if w.MaxInterval > 0 && interval > w.MaxInterval {
+		interval = w.MaxInterval
+	}
+
+	return interval
+}
+
+// send reports event to progress channel
+func (w *Waiter) send(ctx context.Context, e WaitEvent) error {
+
+	glog.Infof("Waiting id=%s type=%s step=%s progress=%d status=%s",
+		e.TaskId, e.Type, e.Step, e.Progress, e.Status)
+
+	if w.Progress == nil {
+		return nil
+	}
+
+	select {
+	case w.Progress <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait polls condition until it holds, condition error returned as is.
+func (w *Waiter) Wait(ctx context.Context, what string, cond WaitCondition) error {
+
+	if w == nil {
+		w = NewWaiter()
+	}
+
+	parent := ctx
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	var (
+		start      = time.Now()
+		interval   = w.Interval
+		lastStatus string
+	)
+
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	timeout := func(attempts int) error {
+		return &WaitTimeout{
+			What:       what,
+			Elapsed:    time.Since(start),
+			Attempts:   attempts,
+			LastStatus: lastStatus,
+		}
+	}
+
+	// own deadline reported as timeout, caller context error as is
+	ctxErr := func(err error, attempts int) error {
+		if parent.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return timeout(attempts)
+		}
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		if err := ctx.Err(); err != nil {
+			return ctxErr(err, attempt-1)
+		}
+
+		done, events, err := cond(ctx)
+		for _, e := range events {
+			if e.Time.IsZero() {
+				e.Time = time.Now()
+			}
+			lastStatus = e.Status
+			if sendErr := w.send(ctx, e); sendErr != nil {
+				return ctxErr(sendErr, attempt)
+			}
+		}
+
+		if err != nil {
+			return ctxErr(err, attempt)
+		}
+		if done {
+			return nil
+		}
+
+		if w.MaxAttempts > 0 && attempt >= w.MaxAttempts {
+			return timeout(attempt)
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return ctxErr(err, attempt)
+		}
+		interval = w.nextInterval(interval)
+	}
+}
+
+// WithWaiter returns copy of api that uses waiter for blocking calls
+func (a *TcaApi) WithWaiter(w *Waiter) *TcaApi {
+	c := *a
+	c.waiter = w
+	return &c
+}
+
+// SetWaiter sets waiter used by blocking calls, nil restores default
+func (a *TcaApi) SetWaiter(w *Waiter) {
+	if a != nil {
+		a.waiter = w
+	}
+}
+
+// Waiter returns waiter used by blocking calls
+func (a *TcaApi) Waiter() *Waiter {
+	if a == nil || a.waiter == nil {
+		return NewWaiter()
+	}
+	return a.waiter
+}
+
+// legacyWaiter returns copy of a waiter limited to maxRetry polls
+func legacyWaiter(w *Waiter, maxRetry int) *Waiter {
+	c := *w
+	c.MaxAttempts = maxRetry
+	return &c
+}
+
+// verboseOutput writer legacy blocking calls print progress to
+var verboseOutput io.Writer = os.Stdout
+
+// printEvents prints an event each time step or status
+// of a task changed, returns once events channel closed.
+func printEvents(out io.Writer, events <-chan WaitEvent) {
+
+	last := make(map[string]string)
+	for e := range events {
+		state := e.Step + "/" + e.Status
+		if last[e.TaskId] == state {
+			continue
+		}
+		last[e.TaskId] = state
+		_, _ = fmt.Fprintf(out, "Task id=%s type=%s step=%s progress=%d status=%s\n",
+			e.TaskId, e.Type, e.Step, e.Progress, e.Status)
+	}
+}
+
+// legacyWait calls wait with api whose waiter limited to maxRetry polls.
+// If verbose and waiter has no progress channel, progress printed
+// to verboseOutput.
+func (a *TcaApi) legacyWait(maxRetry int, verbose bool, wait func(a *TcaApi) error) error {
+
+	w := legacyWaiter(a.Waiter(), maxRetry)
+	if !verbose || w.Progress != nil {
+		return wait(a.WithWaiter(w))
+	}
+
+	var (
+		events  = make(chan WaitEvent)
+		stopped = make(chan struct{})
+	)
+
+	go func() {
+		defer close(stopped)
+		printEvents(verboseOutput, events)
+	}()
+
+	w.Progress = events
+	err := wait(a.WithWaiter(w))
+	close(events)
+	<-stopped
+
+	return err
+}
+
+// currentStep returns title of a step in progress, or last step
+func currentStep(steps []models.TaskSteps) string {
+	for _, s := range steps {
+		if s.Status != TaskStateSuccess {
+			return s.Title
+		}
+	}
+	if len(steps) > 0 {
+		return steps[len(steps)-1].Title
+	}
+	return ""
+}
+
+// operationItems returns items of operation, if respond has no
+// such item all items returned.
+func operationItems(items []models.TaskItems, operationId string) []models.TaskItems {
+
+	if len(operationId) == 0 {
+		return items
+	}
+
+	var filtered []models.TaskItems
+	for _, item := range items {
+		if item.TaskId == operationId {
+			filtered = append(filtered, item)
+		}
+	}
+	if len(filtered) == 0 {
+		return items
+	}
+
+	return filtered
+}
+
+// TaskCondition returns condition that holds once every item
+// of a task reaches waitFor status. Failed item fails condition.
+// If task respond has an item of task operation, other items,
+// i.e. earlier operations on the same entity, ignored. Task without
+// items not yet registered by TCA, condition keeps waiting.
+func (a *TcaApi) TaskCondition(task *models.TcaTask, waitFor string) WaitCondition {
+
+	req := specs.NewClusterTaskQuery(task.Id)
+
+	return func(ctx context.Context) (bool, []WaitEvent, error) {
+
+		tasks, err := a.rest.GetClustersTask(ctx, req)
+		if err != nil {
+			return false, nil, err
+		}
+		if tasks == nil {
+			return false, nil, &TaskNotFound{"task not found"}
+		}
+
+		items := operationItems(tasks.Items, task.OperationId)
+		if len(items) == 0 {
+			glog.Infof("Task %s has no items yet", task.Id)
+			return false, nil, nil
+		}
+
+		var (
+			events  []WaitEvent
+			waiting = 0
+		)
+
+		for _, item := range items {
+			events = append(events, WaitEvent{
+				TaskId:   item.TaskId,
+				Type:     item.Type,
+				Entity:   item.EntityDetails.Name,
+				Step:     currentStep(item.Steps),
+				Progress: item.Progress,
+				Status:   item.Status,
+				Message:  item.Message,
+				Steps:    item.Steps,
+			})
+
+			if item.Status == TaskStateFailed {
+				glog.Errorf("Task failed id=%s type=%s", item.TaskId, item.Type)
+				return false, events, &TcaTaskFailed{item.Type}
+			}
+			if item.Status != waitFor {
+				waiting++
+			}
+		}
+
+		return waiting == 0, events, nil
+	}
+}
+
+// LcmState LCM operation of an instance and its state
+type LcmState struct {
+
+	// Operation LCM operation, i.e. INSTANTIATE
+	Operation string
+
+	// State LCM operation state, i.e. COMPLETED
+	State string
+
+	// OccurrenceId id of the latest LCM operation occurrence,
+	// distinguishes operations of the same type.
+	OccurrenceId string
+}
+
+// isRunning returns true if LCM operation still in progress
+func (s LcmState) isRunning() bool {
+	switch s.State {
+	case StateStarting, StateProcessing, StateRollingBack:
+		return true
+	}
+	return false
+}
+
+// InstanceLcmState returns current LCM operation of an instance and its state
+func (a *TcaApi) InstanceLcmState(ctx context.Context, instanceId string) (*LcmState, error) {
+
+	instance, err := a.rest.GetRunningVnflcm(ctx, instanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LcmState{}
+	if instance.Metadata != nil {
+		s.Operation = instance.Metadata.LcmOperation
+		s.State = instance.Metadata.LcmOperationState
+	}
+
+	// without occurrence id operations compared by type and state
+	s.OccurrenceId, err = a.lcmOccurrence(ctx, instanceId)
+	if err != nil {
+		glog.Warningf("Failed to read LCM operations of %s: %v", instanceId, err)
+	}
+
+	return s, nil
+}
+
+// lcmOccurrence returns id of the latest LCM operation occurrence of
+// an instance, empty string if instance has no operations.
+func (a *TcaApi) lcmOccurrence(ctx context.Context, instanceId string) (string, error) {
+
+	occs, err := a.rest.GetVnflcmOpOccs(ctx, instanceId, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(occs.Items) == 0 {
+		return "", nil
+	}
+
+	return occs.Items[0].TaskId, nil
+}
+
+// InstanceCondition returns condition that holds once instance reaches
+// waitFor instantiation state, or LCM operation state if waitFor is
+// COMPLETED. FAILED_TEMP LCM operation fails condition.
+func (a *TcaApi) InstanceCondition(instanceId string, waitFor string) WaitCondition {
+	return a.InstanceConditionSince(instanceId, waitFor, nil)
+}
+
+// InstanceConditionSince returns InstanceCondition for a wait after an LCM
+// call, before is LCM state of instance prior to the call. Condition holds,
+// or FAILED_TEMP fails it, only once LCM operation, its state or occurrence
+// differs from before, so a failure left by an earlier operation is ignored
+// and an operation of the same type that finished between polls is seen.
+func (a *TcaApi) InstanceConditionSince(instanceId string, waitFor string, before *LcmState) WaitCondition {
+
+	changed := before == nil
+
+	return func(ctx context.Context) (bool, []WaitEvent, error) {
+
+		instance, err := a.rest.GetRunningVnflcm(ctx, instanceId)
+		if err != nil {
+			return false, nil, err
+		}
+
+		e := WaitEvent{
+			TaskId: instanceId,
+			Entity: instance.VnfInstanceName,
+			Step:   instance.InstantiationState,
+		}
+		if instance.Metadata != nil {
+			e.Type = instance.Metadata.LcmOperation
+			e.Status = instance.Metadata.LcmOperationState
+		}
+
+		current := LcmState{Operation: e.Type, State: e.Status}
+		if !changed {
+			changed = current.Operation != before.Operation || current.State != before.State
+		}
+		if !changed && len(before.OccurrenceId) > 0 {
+			occurrence, err := a.lcmOccurrence(ctx, instanceId)
+			if err != nil {
+				return false, nil, err
+			}
+			changed = occurrence != before.OccurrenceId
+		}
+		if !changed {
+			return false, []WaitEvent{e}, nil
+		}
+
+		reached := strings.HasPrefix(instance.InstantiationState, waitFor)
+		if waitFor == StateCompleted {
+			reached = e.Status == StateCompleted
+		}
+		if before != nil && current.isRunning() {
+			reached = false
+		}
+		if reached {
+			e.Progress = 100
+		}
+
+		if e.Status == StateFailedTemp {
+			return false, []WaitEvent{e}, &TcaTaskFailed{e.Type}
+		}
+
+		return e.Progress == 100, []WaitEvent{e}, nil
+	}
+}
+
+// WaitTask blocks until every item of a task reaches waitFor status,
+// returns TcaTaskFailed if task failed, WaitTimeout on timeout.
+func (a *TcaApi) WaitTask(ctx context.Context, task *models.TcaTask, waitFor string) error {
+
+	if task == nil {
+		return &TaskNotFound{"task is nil"}
+	}
+
+	glog.Infof("Waiting task id=%s type=%s", task.Id, task.OperationId)
+	return a.Waiter().Wait(ctx, "task "+task.Id, a.TaskCondition(task, waitFor))
+}
+
+// WaitInstanceState blocks until instance reaches waitFor state, returns
+// TcaTaskFailed if LCM operation failed, WaitTimeout on timeout.
+func (a *TcaApi) WaitInstanceState(ctx context.Context, instanceId string, waitFor string) error {
+	return a.Waiter().Wait(ctx, "instance "+instanceId+" "+waitFor, a.InstanceCondition(instanceId, waitFor))
+}
+
+// WaitInstanceStateSince blocks until an LCM operation started after
+// before brings instance to waitFor state, see InstanceConditionSince.
+func (a *TcaApi) WaitInstanceStateSince(ctx context.Context, instanceId string, waitFor string, before *LcmState) error {
+	return a.Waiter().Wait(ctx, "instance "+instanceId+" "+waitFor, a.InstanceConditionSince(instanceId, waitFor, before))
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastWaiter waiter that polls every millisecond
+func fastWaiter() *Waiter {
+	w := NewWaiter()
+	w.Interval = time.Millisecond
+	w.MaxInterval = 2 * time.Millisecond
+	return w
+}
+
+// getWaiterApi returns api for a server that responds with
+// body returned by respond for n-th request.
+func getWaiterApi(t *testing.T, respond func(n int32) string) *TcaApi {
+
+	var calls int32
+	return getHandlerApi(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(respond(atomic.AddInt32(&calls, 1))))
+	})
+}
+
+// getHandlerApi returns api for a server that handles requests with handler.
+func getHandlerApi(t *testing.T, handler http.HandlerFunc) *TcaApi {
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	rest, err := client.NewRestClient(server.URL, false, "admin", "password")
+	assert.NoError(t, err)
+	rest.RetryPolicy = nil
+
+	a, err := NewTcaApi(rest)
+	assert.NoError(t, err)
+	a.SetWaiter(fastWaiter())
+
+	return a
+}
+
+func TestWaiter_Wait(t *testing.T) {
+
+	ctx := context.Background()
+	events := make(chan WaitEvent, 16)
+
+	w := fastWaiter()
+	w.Progress = events
+
+	attempts := 0
+	err := w.Wait(ctx, "test", func(ctx context.Context) (bool, []WaitEvent, error) {
+		attempts++
+		return attempts == 3, []WaitEvent{{TaskId: "1", Progress: attempts * 30}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	close(events)
+
+	var progress []int
+	for e := range events {
+		assert.False(t, e.Time.IsZero())
+		progress = append(progress, e.Progress)
+	}
+	assert.Equal(t, []int{30, 60, 90}, progress)
+
+	failed := errors.New("failed")
+	err = w.Wait(ctx, "test", func(ctx context.Context) (bool, []WaitEvent, error) {
+		return false, nil, failed
+	})
+	assert.ErrorIs(t, err, failed)
+}
+
+func TestWaiter_nextInterval(t *testing.T) {
+
+	w := &Waiter{Backoff: 2, MaxInterval: 5 * time.Second}
+	assert.Equal(t, 4*time.Second, w.nextInterval(2*time.Second))
+	assert.Equal(t, 5*time.Second, w.nextInterval(4*time.Second))
+
+	w = &Waiter{}
+	assert.Equal(t, 2*time.Second, w.nextInterval(2*time.Second), "no backoff keeps interval")
+}
+
+func TestWaiter_Timeout(t *testing.T) {
+
+	pending := func(ctx context.Context) (bool, []WaitEvent, error) {
+		return false, []WaitEvent{{Status: "RUNNING"}}, nil
+	}
+
+	w := fastWaiter()
+	w.MaxAttempts = 5
+	err := w.Wait(context.Background(), "test", pending)
+
+	var timeout *WaitTimeout
+	if assert.True(t, errors.As(err, &timeout), "error %v", err) {
+		assert.Equal(t, 5, timeout.Attempts)
+		assert.Equal(t, "RUNNING", timeout.LastStatus)
+	}
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	w = fastWaiter()
+	w.Timeout = 20 * time.Millisecond
+	err = w.Wait(context.Background(), "test", pending)
+	assert.True(t, errors.As(err, &timeout), "deadline must return timeout, got %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = fastWaiter().Wait(ctx, "test", pending)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.As(err, &timeout), "caller cancel is not a timeout")
+}
+
+func TestTcaApi_WaitTask(t *testing.T) {
+
+	ctx := context.Background()
+	task := &models.TcaTask{Id: "task", OperationId: "CreateNodePool"}
+	item := `{"items":[{"taskId":"task","type":"Node Pool Creation","status":"%s","progress":%d,` +
+		`"entityDetails":{"name":"pool"},"steps":[{"title":"Validate","status":"SUCCESS"},{"title":"Deploy","status":"%s"}]}]}`
+
+	a := getWaiterApi(t, func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(item, "RUNNING", 50, "RUNNING")
+		}
+		return fmt.Sprintf(item, TaskStateSuccess, 100, TaskStateSuccess)
+	})
+
+	events := make(chan WaitEvent, 16)
+	w := fastWaiter()
+	w.Progress = events
+	assert.NoError(t, a.WithWaiter(w).WaitTask(ctx, task, TaskStateSuccess))
+	close(events)
+
+	e := <-events
+	assert.Equal(t, "Deploy", e.Step)
+	assert.Equal(t, "pool", e.Entity)
+	assert.Equal(t, 50, e.Progress)
+	assert.Len(t, e.Steps, 2)
+
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(item, TaskStateFailed, 50, TaskStateFailed)
+	})
+	var failed *TcaTaskFailed
+	assert.True(t, errors.As(a.WaitTask(ctx, task, TaskStateSuccess), &failed))
+
+	// task without items is not finished
+	var timeout *WaitTimeout
+	var polls int32
+	a = getWaiterApi(t, func(n int32) string {
+		atomic.StoreInt32(&polls, n)
+		if n < 3 {
+			return `{"items":[]}`
+		}
+		return fmt.Sprintf(item, TaskStateSuccess, 100, TaskStateSuccess)
+	})
+	assert.NoError(t, a.WithWaiter(fastWaiter()).WaitTask(ctx, task, TaskStateSuccess))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+
+	a = getWaiterApi(t, func(n int32) string {
+		return `{"items":[]}`
+	})
+	assert.True(t, errors.As(a.BlockWaitTaskFinish(ctx, task, TaskStateSuccess, 3, false), &timeout))
+
+	// legacy call honours max retry
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(item, "RUNNING", 50, "RUNNING")
+	})
+	assert.True(t, errors.As(a.BlockWaitTaskFinish(ctx, task, TaskStateSuccess, 3, false), &timeout))
+	assert.Equal(t, 3, timeout.Attempts)
+
+	// verbose legacy call prints each step change once
+	var out bytes.Buffer
+	verboseOutput = &out
+	defer func() { verboseOutput = os.Stdout }()
+	a = getWaiterApi(t, func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(item, "RUNNING", 50, "RUNNING")
+		}
+		return fmt.Sprintf(item, TaskStateSuccess, 100, TaskStateSuccess)
+	})
+	assert.NoError(t, a.BlockWaitTaskFinish(ctx, task, TaskStateSuccess, DefaultMaxRetry, true))
+	assert.Equal(t, 2, strings.Count(out.String(), "\n"), out.String())
+	assert.Contains(t, out.String(), "status="+TaskStateSuccess)
+}
+
+func TestTcaApi_WaitInstanceState(t *testing.T) {
+
+	ctx := context.Background()
+	instance := `{"id":"1","instantiationState":"%s","metadata":{"lcmOperation":"INSTANTIATE","lcmOperationState":"%s"}}`
+
+	a := getWaiterApi(t, func(n int32) string {
+		if n < 2 {
+			return fmt.Sprintf(instance, StateNotInstantiated, "PROCESSING")
+		}
+		return fmt.Sprintf(instance, StateInstantiated, StateCompleted)
+	})
+	assert.NoError(t, a.WaitInstanceState(ctx, "1", StateInstantiate))
+
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(instance, StateNotInstantiated, StateFailedTemp)
+	})
+	var failed *TcaTaskFailed
+	assert.True(t, errors.As(a.BlockWaitStateChange(ctx, "1", StateInstantiate, DefaultMaxRetry, false), &failed),
+		"FAILED_TEMP must fail")
+
+	a = getWaiterApi(t, func(n int32) string {
+		return fmt.Sprintf(instance, StateNotInstantiated, "PROCESSING")
+	})
+	var timeout *WaitTimeout
+	assert.True(t, errors.As(a.BlockWaitStateChange(ctx, "1", StateInstantiate, 2, false), &timeout),
+		"max retry must be honoured")
+}
+
+func TestTcaApi_WaitInstanceStateSince(t *testing.T) {
+
+	ctx := context.Background()
+	instance := `{"id":"1","instantiationState":"%s","metadata":{"lcmOperation":"%s","lcmOperationState":"%s"}}`
+	before := &LcmState{Operation: StateInstantiate, State: StateFailedTemp}
+
+	// re-instantiate, FAILED_TEMP left by a previous instantiate
+	a := getWaiterApi(t, func(n int32) string {
+		switch {
+		case n < 3:
+			return fmt.Sprintf(instance, StateNotInstantiated, StateInstantiate, StateFailedTemp)
+		case n < 5:
+			return fmt.Sprintf(instance, StateNotInstantiated, StateInstantiate, StateProcessing)
+		}
+		return fmt.Sprintf(instance, StateInstantiated, StateInstantiate, StateCompleted)
+	})
+	assert.NoError(t, a.WaitInstanceStateSince(ctx, "1", StateInstantiate, before))
+
+	// rollback, instance stays instantiated while operation runs
+	var polls int32
+	a = getWaiterApi(t, func(n int32) string {
+		atomic.StoreInt32(&polls, n)
+		switch {
+		case n < 3:
+			return fmt.Sprintf(instance, StateInstantiated, "UPGRADE", StateFailedTemp)
+		case n < 5:
+			return fmt.Sprintf(instance, StateInstantiated, "UPGRADE", StateRollingBack)
+		}
+		return fmt.Sprintf(instance, StateInstantiated, "UPGRADE", "ROLLED_BACK")
+	})
+	err := a.WaitInstanceStateSince(ctx, "1", StateInstantiate, &LcmState{Operation: "UPGRADE", State: StateFailedTemp})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&polls), "wait until rollback finished")
+
+	// operation of the same type finished between polls
+	var opPolls int32
+	a = getHandlerApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/vnf_lcm_op_occs") {
+			occurrence := "op1"
+			if atomic.AddInt32(&opPolls, 1) > 2 {
+				occurrence = "op2"
+			}
+			_, _ = fmt.Fprintf(w, `{"items":[{"taskId":"%s"}]}`, occurrence)
+			return
+		}
+		_, _ = fmt.Fprintf(w, instance, StateInstantiated, StateInstantiate, StateCompleted)
+	})
+	completed := &LcmState{Operation: StateInstantiate, State: StateCompleted, OccurrenceId: "op1"}
+	assert.NoError(t, a.WaitInstanceStateSince(ctx, "1", StateInstantiate, completed))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&opPolls), "wait until occurrence changed")
+
+	state, err := a.InstanceLcmState(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "op2", state.OccurrenceId)
+
+	// new operation failed
+	a = getWaiterApi(t, func(n int32) string {
+		if n < 3 {
+			return fmt.Sprintf(instance, StateNotInstantiated, StateInstantiate, StateProcessing)
+		}
+		return fmt.Sprintf(instance, StateNotInstantiated, StateInstantiate, StateFailedTemp)
+	})
+	var failed *TcaTaskFailed
+	err = a.WaitInstanceStateSince(ctx, "1", StateInstantiate, before)
+	assert.True(t, errors.As(err, &failed), "FAILED_TEMP after operation changed must fail, got %v", err)
+}
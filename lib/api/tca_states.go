@@ -37,8 +37,20 @@ const (
 	StateTerminate    = "TERMINATE"
 	StateFailedTemp   = "FAILED_TEMP"
 
+	// StateProcessing LCM operation in progress
+	StateProcessing = "PROCESSING"
+
+	// StateRollingBack LCM operation rolls back
+	StateRollingBack = "ROLLING_BACK"
+
 	StateNotInstantiated = "NOT_INSTANTIATED"
 
+	// StateActive cluster or node pool active
+	StateActive = "ACTIVE"
+
+	// StateFailed cluster or node pool failed
+	StateFailed = "FAILED"
+
 	DefaultMaxRetry = 32
 
 	// TaskStateSuccess task state successes
@@ -75,3 +87,10 @@ const (
 func IsInState(currentState string, predicate string) bool {
 	return strings.Contains(currentState, predicate)
 }
+
+// IsFailedState returns true if cluster, node pool, instance
+// LCM operation or task state indicates failure.
+func IsFailedState(state string) bool {
+	s := strings.ToUpper(state)
+	return s == StateFailed || s == StateFailedTemp || s == TaskStateFailed || strings.HasSuffix(s, "_FAILED")
+}
@@ -0,0 +1,106 @@
+package api_errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Kinds of errors TCA returns, an ApiError matches kind
+// of its status code, errors.Is(err, ErrNotFound)
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("validation failed")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrUnavailable     = errors.New("service unavailable")
+	ErrServer          = errors.New("server error")
+)
+
+// ApiError error returned by TCA or other rest endpoint,
+// use errors.As to access status code and details.
+type ApiError struct {
+
+	// StatusCode http status code
+	StatusCode int
+
+	// Code TCA error code
+	Code string
+
+	// Message error message
+	Message string
+
+	// Details error details
+	Details string
+
+	// Path api path server reports or request path
+	Path string
+
+	// RequestId request id server reports
+	RequestId string
+}
+
+// NewApiError returns api error for status code and message
+func NewApiError(statusCode int, code string, msg string) *ApiError {
+	return &ApiError{StatusCode: statusCode, Code: code, Message: msg}
+}
+
+func (e *ApiError) Error() string {
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("server return %d", e.StatusCode))
+	if len(e.Code) > 0 {
+		sb.WriteString(" code " + e.Code)
+	}
+	if len(e.Path) > 0 {
+		sb.WriteString(" path " + e.Path)
+	}
+	if len(e.Message) > 0 {
+		sb.WriteString(" msg " + e.Message)
+	}
+	if len(e.Details) > 0 && e.Details != e.Message {
+		sb.WriteString(" details " + e.Details)
+	}
+	if len(e.RequestId) > 0 {
+		sb.WriteString(" request id " + e.RequestId)
+	}
+
+	return sb.String()
+}
+
+// Kind returns kind of error for status code,
+// nil if status code has no kind.
+func (e *ApiError) Kind() error {
+
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusBadRequest ||
+		e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case e.StatusCode == http.StatusBadGateway ||
+		e.StatusCode == http.StatusServiceUnavailable ||
+		e.StatusCode == http.StatusGatewayTimeout:
+		return ErrUnavailable
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+
+	return nil
+}
+
+// Unwrap returns kind of error
+func (e *ApiError) Unwrap() error {
+	return e.Kind()
+}
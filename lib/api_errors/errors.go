@@ -12,6 +12,10 @@ func (e *TemplateNotFound) Error() string {
 	return " cluster template '" + e.errMsg + "' not found."
 }
 
+func (e *TemplateNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 type TemplateInvalidType struct {
 	errMsg string
 }
@@ -24,6 +28,10 @@ func (e *TemplateInvalidType) Error() string {
 	return e.errMsg
 }
 
+func (e *TemplateInvalidType) Unwrap() error {
+	return ErrValidation
+}
+
 type InvalidSpec struct {
 	errMsg string
 }
@@ -36,6 +44,10 @@ func (e *InvalidSpec) Error() string {
 	return e.errMsg
 }
 
+func (e *InvalidSpec) Unwrap() error {
+	return ErrValidation
+}
+
 type DatastoreNotFound struct {
 	errMsg string
 }
@@ -48,6 +60,10 @@ func (e *DatastoreNotFound) Error() string {
 	return e.errMsg
 }
 
+func (e *DatastoreNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 // CatalogNotFound error raised if tenant cloud not found
 type CatalogNotFound struct {
 	errMsg string
@@ -58,6 +74,10 @@ func (m *CatalogNotFound) Error() string {
 	return "Catalog entity '" + m.errMsg + "' not found"
 }
 
+func (m *CatalogNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 func NewCatalogNotFound(errMsg string) *CatalogNotFound {
 	return &CatalogNotFound{errMsg: errMsg}
 }
@@ -70,6 +90,10 @@ func (m *ExtensionsNotFound) Error() string {
 	return "extension '" + m.errMsg + "' not found"
 }
 
+func (m *ExtensionsNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 func NewExtensionsNotFound(errMsg string) *ExtensionsNotFound {
 	return &ExtensionsNotFound{errMsg: errMsg}
 }
@@ -86,6 +110,10 @@ func (e *TenantNotFound) Error() string {
 	return " tenant '" + e.errMsg + "' not found."
 }
 
+func (e *TenantNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 type VimNotFound struct {
 	errMsg string
 }
@@ -98,6 +126,10 @@ func (e *VimNotFound) Error() string {
 	return " vim '" + e.errMsg + "' not found."
 }
 
+func (e *VimNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 type FileNotFound struct {
 	errMsg string
 }
@@ -110,6 +142,10 @@ func (e *FileNotFound) Error() string {
 	return " file '" + e.errMsg + "' not found."
 }
 
+func (e *FileNotFound) Unwrap() error {
+	return ErrNotFound
+}
+
 type InvalidArgument struct {
 	errMsg string
 }
@@ -122,6 +158,10 @@ func (e *InvalidArgument) Error() string {
 	return " invalid '" + e.errMsg + "' argument."
 }
 
+func (e *InvalidArgument) Unwrap() error {
+	return ErrValidation
+}
+
 // InvalidVimFormat error must returned if client supplied incorrect format for vim ID
 type InvalidVimFormat struct {
 	errMsg string
@@ -131,6 +171,10 @@ func (m *InvalidVimFormat) Error() string {
 	return "vim id format " + m.errMsg + " invalid. Example vmware_FB40D3DE2967483FBF9033B451DC7571"
 }
 
+func (m *InvalidVimFormat) Unwrap() error {
+	return ErrValidation
+}
+
 // InvalidTaskId error must returned if client supplied incorrect task id
 type InvalidTaskId struct {
 	errMsg string
@@ -143,3 +187,24 @@ func NewInvalidTaskId(errMsg string) *InvalidTaskId {
 func (m *InvalidTaskId) Error() string {
 	return "invalid task id " + m.errMsg + ". Example 9411f70f-d24d-4842-ab56-b7214d39d1b1"
 }
+
+func (m *InvalidTaskId) Unwrap() error {
+	return ErrValidation
+}
+
+// AlreadyExists error must returned if object a create call creates already exists
+type AlreadyExists struct {
+	errMsg string
+}
+
+func NewAlreadyExists(errMsg string) *AlreadyExists {
+	return &AlreadyExists{errMsg: errMsg}
+}
+
+func (e *AlreadyExists) Error() string {
+	return e.errMsg + " already exists"
+}
+
+func (e *AlreadyExists) Unwrap() error {
+	return ErrConflict
+}
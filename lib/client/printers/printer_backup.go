@@ -0,0 +1,117 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/api"
+	"os"
+)
+
+// siteStepView a site step with error as a string
+type siteStepView struct {
+	Id     string `json:"id" yaml:"id"`
+	Kind   string `json:"kind" yaml:"kind"`
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// siteStepViews converts site steps to a printable form
+func siteStepViews(results []*api.SiteStepResult) []siteStepView {
+	views := make([]siteStepView, 0, len(results))
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		v := siteStepView{Id: r.Id, Kind: string(r.Kind), Action: string(r.Action)}
+		if r.Err != nil {
+			v.Error = r.Err.Error()
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// BackupManifestTablePrinter - prints all objects stored in backup archive
+func BackupManifestTablePrinter(m *api.BackupManifest, style ui.PrinterStyle) {
+
+	if m == nil {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Kind", "Name", "Cluster", "File"})
+	for i, e := range m.Entries {
+		t.AppendRow(table.Row{i, e.Kind, e.Name, e.Cluster, e.File})
+	}
+
+	tableStyle, ok := style.GetTableStyle().(table.Style)
+	if ok {
+		t.SetStyle(tableStyle)
+	}
+	t.Render()
+}
+
+// BackupManifestJsonPrinter - json printer for backup manifest
+func BackupManifestJsonPrinter(m *api.BackupManifest, style ui.PrinterStyle) {
+	DefaultJsonPrinter(m, style)
+}
+
+// BackupManifestYamlPrinter - yaml printer for backup manifest
+func BackupManifestYamlPrinter(m *api.BackupManifest, style ui.PrinterStyle) {
+	DefaultYamlPrinter(m, style)
+}
+
+// BackupManifestXmlPrinter - xml printer
+func BackupManifestXmlPrinter(m *api.BackupManifest, style ui.PrinterStyle) {
+	DefaultXmlPrinter(m, style)
+}
+
+// SiteStepTablePrinter - prints action or error of each step
+func SiteStepTablePrinter(results []*api.SiteStepResult, style ui.PrinterStyle) {
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Id", "Kind", "Action", "Error"})
+	for i, v := range siteStepViews(results) {
+		t.AppendRow(table.Row{i, v.Id, v.Kind, v.Action, v.Error})
+	}
+
+	tableStyle, ok := style.GetTableStyle().(table.Style)
+	if ok {
+		t.SetStyle(tableStyle)
+	}
+	t.Render()
+}
+
+// SiteStepJsonPrinter - json printer for site steps
+func SiteStepJsonPrinter(results []*api.SiteStepResult, style ui.PrinterStyle) {
+	DefaultJsonPrinter(siteStepViews(results), style)
+}
+
+// SiteStepYamlPrinter - yaml printer for site steps
+func SiteStepYamlPrinter(results []*api.SiteStepResult, style ui.PrinterStyle) {
+	DefaultYamlPrinter(siteStepViews(results), style)
+}
+
+// SiteStepXmlPrinter - xml printer
+func SiteStepXmlPrinter(results []*api.SiteStepResult, style ui.PrinterStyle) {
+	DefaultXmlPrinter(siteStepViews(results), style)
+}
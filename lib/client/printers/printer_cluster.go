@@ -53,6 +53,12 @@ func ClusterSpecYamlPrinter(spec *response.ClusterSpec, style ui.PrinterStyle) {
 
 }
 
+// ClusterSpecXmlPrinter - xml printer
+func ClusterSpecXmlPrinter(spec *response.ClusterSpec, style ui.PrinterStyle) {
+	DefaultXmlPrinter(spec, style)
+
+}
+
 // ClusterRequestJsonPrinter - json printer for new cluster creation request
 func ClusterRequestJsonPrinter(spec *specs.SpecCluster, style ui.PrinterStyle) {
 	DefaultJsonPrinter(spec, style)
@@ -63,6 +69,11 @@ func ClusterRequestYamlPrinter(specs *specs.SpecCluster, style ui.PrinterStyle)
 	DefaultYamlPrinter(specs, style)
 }
 
+// ClusterRequestXmlPrinter - xml printer
+func ClusterRequestXmlPrinter(specs *specs.SpecCluster, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
+
 func ClusterTaskTablePrinter(specs *models.ClusterTask, style ui.PrinterStyle) {
 	if specs == nil {
 		return
@@ -98,12 +109,23 @@ func ClusterTaskYamlPrinter(specs *models.ClusterTask, style ui.PrinterStyle) {
 	DefaultYamlPrinter(specs, style)
 }
 
+// ClusterTaskXmlPrinter - xml printer
+func ClusterTaskXmlPrinter(specs *models.ClusterTask, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
+
 // ConsumptionSpecYamlPrinter - json printer for tca lic consumption
 func ConsumptionSpecYamlPrinter(spec *models.ConsumptionResp, style ui.PrinterStyle) {
 	DefaultYamlPrinter(spec, style)
 
 }
 
+// ConsumptionSpecXmlPrinter - xml printer
+func ConsumptionSpecXmlPrinter(spec *models.ConsumptionResp, style ui.PrinterStyle) {
+	DefaultXmlPrinter(spec, style)
+
+}
+
 // ConsumptionJsonPrinter - json printer for new cluster creation request
 func ConsumptionJsonPrinter(spec *models.ConsumptionResp, style ui.PrinterStyle) {
 	DefaultJsonPrinter(spec, style)
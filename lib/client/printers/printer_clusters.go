@@ -50,3 +50,8 @@ func ClusterJsonPrinter(t *response.Clusters, style ui.PrinterStyle) {
 func ClusterYamlPrinter(t *response.Clusters, style ui.PrinterStyle) {
 	DefaultYamlPrinter(t, style)
 }
+
+// ClusterXmlPrinter - xml printer
+func ClusterXmlPrinter(t *response.Clusters, style ui.PrinterStyle) {
+	DefaultXmlPrinter(t, style)
+}
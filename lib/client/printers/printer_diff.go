@@ -0,0 +1,77 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/api"
+)
+
+// diffLine outputs a single line, colored if style is color
+func diffLine(style ui.PrinterStyle, color text.Colors, format string, a ...interface{}) {
+	line := fmt.Sprintf(format, a...)
+	if style.IsColor() {
+		line = color.Sprint(line)
+	}
+	fmt.Println(line)
+}
+
+// SpecDiffPrinter - field level unified diff printer,
+// lines removed from live object prefixed with '-',
+// lines added from a spec prefixed with '+'
+func SpecDiffPrinter(d *api.SpecDiff, style ui.PrinterStyle) {
+
+	if d == nil {
+		return
+	}
+
+	live := fmt.Sprintf("live/%s/%s", d.Kind, d.Name)
+	if d.IsNew {
+		live = "/dev/null"
+	}
+
+	diffLine(style, text.Colors{text.Bold}, "--- %s", live)
+	diffLine(style, text.Colors{text.Bold}, "+++ spec/%s/%s", d.Kind, d.Name)
+
+	for _, f := range d.Fields {
+		diffLine(style, text.Colors{text.FgCyan}, "@@ %s @@", f.Field)
+		if f.InLive {
+			diffLine(style, text.Colors{text.FgRed}, "-%s: %s", f.Field, f.Live)
+		}
+		if f.InSpec {
+			diffLine(style, text.Colors{text.FgGreen}, "+%s: %s", f.Field, f.Spec)
+		}
+	}
+}
+
+// SpecDiffJsonPrinter - json printer for spec diff
+func SpecDiffJsonPrinter(d *api.SpecDiff, style ui.PrinterStyle) {
+	DefaultJsonPrinter(d, style)
+}
+
+// SpecDiffYamlPrinter - yaml printer for spec diff
+func SpecDiffYamlPrinter(d *api.SpecDiff, style ui.PrinterStyle) {
+	DefaultYamlPrinter(d, style)
+}
+
+// SpecDiffXmlPrinter - xml printer
+func SpecDiffXmlPrinter(d *api.SpecDiff, style ui.PrinterStyle) {
+	DefaultXmlPrinter(d, style)
+}
@@ -13,3 +13,8 @@ func ExtensionsJsonPrinter(t *response.Clusters, style ui.PrinterStyle) {
 func ExtensionsYamlPrinter(t *response.Clusters, style ui.PrinterStyle) {
 	DefaultYamlPrinter(t, style)
 }
+
+// ExtensionsXmlPrinter - xml printer
+func ExtensionsXmlPrinter(t *response.Clusters, style ui.PrinterStyle) {
+	DefaultXmlPrinter(t, style)
+}
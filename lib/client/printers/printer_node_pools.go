@@ -73,6 +73,16 @@ func NodesYamlPrinter(nodePool *response.NodePool, style ui.PrinterStyle) {
 
 }
 
+// NodesXmlPrinter - xml printer
+func NodesXmlPrinter(nodePool *response.NodePool, style ui.PrinterStyle) {
+	if nodePool == nil {
+		return
+	}
+
+	DefaultXmlPrinter(nodePool, style)
+
+}
+
 // PoolSpecTablePrinter - tabular format printer for node list in node pool
 func PoolSpecTablePrinter(spec *response.NodesSpecs, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -107,3 +117,12 @@ func PoolSpecYamlPrinter(spec *response.NodesSpecs, style ui.PrinterStyle) {
 
 	DefaultYamlPrinter(spec, style)
 }
+
+// PoolSpecXmlPrinter - xml printer
+func PoolSpecXmlPrinter(spec *response.NodesSpecs, style ui.PrinterStyle) {
+	if spec == nil {
+		return
+	}
+
+	DefaultXmlPrinter(spec, style)
+}
@@ -0,0 +1,72 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"os"
+)
+
+// SchemaTablePrinter - prints all fields of a spec kind,
+// nested fields printed as dotted path.
+func SchemaTablePrinter(s *specs.JsonSchema, style ui.PrinterStyle) {
+
+	if s == nil {
+		return
+	}
+
+	fmt.Printf("KIND: %s\n", s.Title)
+	if len(s.Description) > 0 {
+		fmt.Printf("DESCRIPTION: %s\n", s.Description)
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Field", "Type", "Required", "Description"})
+	for _, f := range s.Fields() {
+		required := ""
+		if f.Required {
+			required = "yes"
+		}
+		t.AppendRow(table.Row{f.Path, f.Type, required, f.Description})
+	}
+
+	tableStyle, ok := style.GetTableStyle().(table.Style)
+	if ok {
+		t.SetStyle(tableStyle)
+	}
+	t.Render()
+}
+
+// SchemaJsonPrinter - prints json schema of a spec kind
+func SchemaJsonPrinter(s *specs.JsonSchema, style ui.PrinterStyle) {
+	DefaultJsonPrinter(s, style)
+}
+
+// SchemaYamlPrinter - prints json schema of a spec kind in yaml format
+func SchemaYamlPrinter(s *specs.JsonSchema, style ui.PrinterStyle) {
+	DefaultYamlPrinter(s, style)
+}
+
+// SchemaXmlPrinter - xml printer
+func SchemaXmlPrinter(s *specs.JsonSchema, style ui.PrinterStyle) {
+	DefaultXmlPrinter(s, style)
+}
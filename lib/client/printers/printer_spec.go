@@ -0,0 +1,38 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/client/specs"
+)
+
+// SpecYamlPrinter - prints a spec in yaml format
+func SpecYamlPrinter(s specs.RequestSpec, style ui.PrinterStyle) {
+	DefaultYamlPrinter(s, style)
+}
+
+// SpecJsonPrinter - prints a spec in json format
+func SpecJsonPrinter(s specs.RequestSpec, style ui.PrinterStyle) {
+	DefaultJsonPrinter(s, style)
+}
+
+// SpecXmlPrinter - xml printer
+func SpecXmlPrinter(s specs.RequestSpec, style ui.PrinterStyle) {
+	DefaultXmlPrinter(s, style)
+}
@@ -0,0 +1,277 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/models"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultProgressBarWidth width of a progress bar in characters
+	DefaultProgressBarWidth = 16
+
+	// stepIndent indent of each nesting level
+	stepIndent = "  "
+)
+
+// IsTerminal returns true if file is a terminal
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// TaskProgress live view of cluster and node pool tasks. On a terminal
+// view redraws every step in place, otherwise each step that changed
+// status printed as a plain line.
+//
+//	events := make(chan api.WaitEvent)
+//	view := printer.NewTaskProgress(os.Stdout, printer.IsTerminal(os.Stdout), style)
+//	go view.Run(events)
+type TaskProgress struct {
+
+	// BarWidth width of a progress bar
+	BarWidth int
+
+	out     io.Writer
+	isTTY   bool
+	isColor bool
+
+	// order task ids in order first seen
+	order  []string
+	events map[string]api.WaitEvent
+
+	// lines number of lines drawn by last refresh
+	lines int
+
+	// printed last status printed for each step in plain mode
+	printed map[string]string
+
+	now func() time.Time
+}
+
+// NewTaskProgress returns live view that writes to out,
+// isTTY false switches view to plain line output.
+func NewTaskProgress(out io.Writer, isTTY bool, style ui.PrinterStyle) *TaskProgress {
+
+	p := &TaskProgress{
+		BarWidth: DefaultProgressBarWidth,
+		out:      out,
+		isTTY:    isTTY,
+		events:   make(map[string]api.WaitEvent),
+		printed:  make(map[string]string),
+		now:      time.Now,
+	}
+
+	if style != nil && isTTY {
+		p.isColor = style.IsColor()
+	}
+
+	return p
+}
+
+// Run updates view for each event until events closed
+func (p *TaskProgress) Run(events <-chan api.WaitEvent) {
+	for e := range events {
+		p.Update(e)
+	}
+}
+
+// Update updates view with an event
+func (p *TaskProgress) Update(e api.WaitEvent) {
+
+	if _, ok := p.events[e.TaskId]; !ok {
+		p.order = append(p.order, e.TaskId)
+	}
+	p.events[e.TaskId] = e
+
+	if p.isTTY {
+		p.redraw()
+		return
+	}
+
+	p.printChanges(e)
+}
+
+// redraw moves cursor to the first line of a view and draws it again
+func (p *TaskProgress) redraw() {
+
+	var lines []string
+	for _, id := range p.order {
+		lines = append(lines, p.taskLines(p.events[id])...)
+	}
+
+	var b strings.Builder
+	if p.lines > 0 {
+		// cursor up and clear to the end of screen
+		fmt.Fprintf(&b, "\033[%dA\033[J", p.lines)
+	}
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\n")
+	}
+
+	_, _ = io.WriteString(p.out, b.String())
+	p.lines = len(lines)
+}
+
+// taskLines returns task header followed by steps
+func (p *TaskProgress) taskLines(e api.WaitEvent) []string {
+
+	title := strings.TrimSpace(e.Type + " " + e.Entity)
+	if len(title) == 0 {
+		title = e.TaskId
+	}
+	if len(e.Steps) == 0 && len(e.Step) > 0 {
+		title += " " + e.Step
+	}
+
+	lines := []string{fmt.Sprintf("%-36s %s %3d%% %s",
+		title, p.bar(e.Progress), e.Progress, p.status(e.Status))}
+
+	return append(lines, p.stepLines(e.Steps, 1)...)
+}
+
+// stepLines returns a line for each step and its children
+func (p *TaskProgress) stepLines(steps []models.TaskSteps, depth int) []string {
+
+	var (
+		lines  []string
+		indent = strings.Repeat(stepIndent, depth)
+		now    = p.now()
+	)
+
+	for i := range steps {
+		s := &steps[i]
+		progress := stepProgress(s)
+		lines = append(lines, fmt.Sprintf("%-36s %s %3d%% %-10s %s",
+			indent+s.Title, p.bar(progress), progress, p.status(s.Status), elapsed(s.Elapsed(now))))
+		lines = append(lines, p.stepLines(s.ChildSteps(), depth+1)...)
+	}
+
+	return lines
+}
+
+// printChanges prints a line for each step that changed status
+func (p *TaskProgress) printChanges(e api.WaitEvent) {
+
+	name := strings.TrimSpace(e.Type + " " + e.Entity)
+	if len(name) == 0 {
+		name = e.TaskId
+	}
+
+	key := e.TaskId
+	if len(e.Steps) == 0 {
+		key += "/" + e.Step
+	}
+	if state := fmt.Sprintf("%s %d", e.Status, e.Progress); p.printed[key] != state {
+		p.printed[key] = state
+		line := name
+		if len(e.Step) > 0 {
+			line += " " + e.Step
+		}
+		p.println(fmt.Sprintf("%s %d%% %s", line, e.Progress, e.Status))
+	}
+
+	p.printStepChanges(e.TaskId, name, e.Steps)
+}
+
+// printStepChanges prints steps and nested steps that changed status
+func (p *TaskProgress) printStepChanges(key string, name string, steps []models.TaskSteps) {
+
+	now := p.now()
+	for i := range steps {
+		s := &steps[i]
+		stepKey := key + "/" + s.Title
+		if p.printed[stepKey] != s.Status {
+			p.printed[stepKey] = s.Status
+			line := fmt.Sprintf("%s step %s %s", name, s.Title, s.Status)
+			if d := s.Elapsed(now); d > 0 && s.EndTime > 0 {
+				line += " " + elapsed(d)
+			}
+			p.println(line)
+		}
+		p.printStepChanges(stepKey, name+" "+s.Title, s.ChildSteps())
+	}
+}
+
+// println prints line prefixed with time
+func (p *TaskProgress) println(line string) {
+	_, _ = fmt.Fprintf(p.out, "%s %s\n", p.now().Format("15:04:05"), line)
+}
+
+// bar returns progress bar
+func (p *TaskProgress) bar(progress int) string {
+
+	width := p.BarWidth
+	if width <= 0 {
+		width = DefaultProgressBarWidth
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 100 {
+		progress = 100
+	}
+
+	done := progress * width / 100
+	return "[" + strings.Repeat("#", done) + strings.Repeat(".", width-done) + "]"
+}
+
+// status returns status colored by its state
+func (p *TaskProgress) status(status string) string {
+
+	if !p.isColor {
+		return status
+	}
+
+	switch {
+	case api.IsFailedState(status):
+		return text.Colors{text.FgRed}.Sprint(status)
+	case status == api.TaskStateSuccess || status == api.StateActive || status == api.StateCompleted:
+		return text.Colors{text.FgGreen}.Sprint(status)
+	}
+
+	return text.Colors{text.FgYellow}.Sprint(status)
+}
+
+// stepProgress returns step progress, TCA
+// reports zero progress for some finished steps.
+func stepProgress(s *models.TaskSteps) int {
+	if s.Status == api.TaskStateSuccess {
+		return 100
+	}
+	return s.Progress
+}
+
+// elapsed returns duration rounded to seconds, empty if zero
+func elapsed(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,74 @@
+package printer
+
+import (
+	"bytes"
+	"github.com/spyroot/tcactl/lib/api"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func progressEvent(deploy string, child string) api.WaitEvent {
+
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+
+	children := []interface{}{
+		map[string]interface{}{"title": "Deploy VM", "status": child, "progress": 40},
+	}
+
+	return api.WaitEvent{
+		TaskId:   "task",
+		Type:     "Node Pool Creation",
+		Entity:   "pool",
+		Progress: 50,
+		Status:   "RUNNING",
+		Steps: []models.TaskSteps{
+			{Title: "Validate", Status: api.TaskStateSuccess,
+				StartTime: ms(start), EndTime: ms(start.Add(12 * time.Second))},
+			{Title: "Deploy", Status: deploy, Progress: 40,
+				StartTime: ms(start.Add(12 * time.Second)), Children: &children},
+		},
+	}
+}
+
+func TestTaskProgress_Terminal(t *testing.T) {
+
+	var out bytes.Buffer
+	p := NewTaskProgress(&out, true, nil)
+	p.now = func() time.Time { return time.Date(2021, 6, 1, 10, 1, 12, 0, time.UTC) }
+
+	p.Update(progressEvent("RUNNING", "RUNNING"))
+	first := out.String()
+	assert.Equal(t, 4, strings.Count(first, "\n"), "task, two steps and a child")
+	assert.Contains(t, first, "Validate")
+	assert.Contains(t, first, "12s")
+	assert.Contains(t, first, "    Deploy VM")
+	assert.Contains(t, first, "1m0s", "running step elapsed since start")
+	assert.Contains(t, first, "[################] 100%")
+	assert.NotContains(t, first, "\033[")
+
+	out.Reset()
+	p.Update(progressEvent(api.TaskStateSuccess, api.TaskStateSuccess))
+	assert.True(t, strings.HasPrefix(out.String(), "\033[4A\033[J"), "view redrawn in place")
+}
+
+func TestTaskProgress_Plain(t *testing.T) {
+
+	var out bytes.Buffer
+	p := NewTaskProgress(&out, false, nil)
+
+	p.Update(progressEvent("RUNNING", "RUNNING"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 4)
+
+	out.Reset()
+	p.Update(progressEvent("RUNNING", "RUNNING"))
+	assert.Empty(t, out.String(), "unchanged steps not printed")
+
+	p.Update(progressEvent(api.TaskStateSuccess, "RUNNING"))
+	assert.Contains(t, out.String(), "Node Pool Creation pool step Deploy SUCCESS")
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+}
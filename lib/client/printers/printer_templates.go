@@ -39,11 +39,21 @@ func TemplateSpecYamlPrinter(spec *response.ClusterTemplateSpec, style ui.Printe
 	DefaultYamlPrinter(spec, style)
 }
 
+// TemplateSpecXmlPrinter - xml printer
+func TemplateSpecXmlPrinter(spec *response.ClusterTemplateSpec, style ui.PrinterStyle) {
+	DefaultXmlPrinter(spec, style)
+}
+
 // TemplatesYamlPrinter - yaml printer for cluster templates
 func TemplatesYamlPrinter(specs []response.ClusterTemplateSpec, style ui.PrinterStyle) {
 	DefaultYamlPrinter(specs, style)
 }
 
+// TemplatesXmlPrinter - xml printer
+func TemplatesXmlPrinter(specs []response.ClusterTemplateSpec, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
+
 // TemplateSpecTablePrinter - tabular format printer for cluster templates
 func TemplateSpecTablePrinter(spec *response.ClusterTemplateSpec, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -0,0 +1,114 @@
+// Package printer
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package printer
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spyroot/tcactl/app/main/cmds/ui"
+	"github.com/spyroot/tcactl/lib/api"
+	"os"
+	"strings"
+)
+
+// upgradeTable renders table in a printer style
+func upgradeTable(style ui.PrinterStyle, header table.Row, rows []table.Row) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(header)
+	t.AppendRows(rows)
+	tableStyle, ok := style.GetTableStyle().(table.Style)
+	if ok {
+		t.SetStyle(tableStyle)
+	}
+	t.Render()
+}
+
+// upgradeStatus returns status colored if style is color
+func upgradeStatus(style ui.PrinterStyle, status string) string {
+
+	if !style.IsColor() {
+		return status
+	}
+
+	switch status {
+	case api.TaskStateSuccess, "PASSED":
+		return text.Colors{text.FgGreen}.Sprint(status)
+	case api.TaskStateFailed:
+		return text.Colors{text.FgRed}.Sprint(status)
+	}
+
+	return text.Colors{text.FgYellow}.Sprint(status)
+}
+
+// UpgradePlanTablePrinter - prints upgrade summary, allowed
+// upgrade paths, pre-flight checks and status of each step.
+func UpgradePlanTablePrinter(p *api.UpgradePlan, style ui.PrinterStyle) {
+
+	if p == nil {
+		return
+	}
+
+	paths := strings.Join(p.Paths, ", ")
+	if len(paths) == 0 {
+		paths = "none"
+	}
+
+	fmt.Printf("Cluster:   %s (%s)\n", p.Cluster, p.ClusterType)
+	fmt.Printf("Version:   %s -> %s\n", p.Current, p.Target)
+	fmt.Printf("Paths:     %s\n", paths)
+	if len(p.Template) > 0 {
+		fmt.Printf("Template:  %s\n", p.Template)
+	}
+
+	var checks []table.Row
+	for _, c := range p.Checks {
+		result := "PASSED"
+		if !c.Passed {
+			result = api.TaskStateFailed
+		}
+		checks = append(checks, table.Row{c.Name, upgradeStatus(style, result), c.Message})
+	}
+	upgradeTable(style, table.Row{"Check", "Result", "Message"}, checks)
+
+	var steps []table.Row
+	for i, s := range p.Steps {
+		elapsed := ""
+		if s.Elapsed > 0 {
+			elapsed = s.Elapsed.String()
+		}
+		steps = append(steps, table.Row{i + 1, s.Kind, s.Name, s.From, s.To,
+			upgradeStatus(style, s.Status), elapsed, s.Error})
+	}
+	upgradeTable(style, table.Row{"#", "Kind", "Name", "From", "To", "Status", "Elapsed", "Error"}, steps)
+}
+
+// UpgradePlanJsonPrinter - json printer for upgrade plan
+func UpgradePlanJsonPrinter(p *api.UpgradePlan, style ui.PrinterStyle) {
+	DefaultJsonPrinter(p, style)
+}
+
+// UpgradePlanYamlPrinter - yaml printer for upgrade plan
+func UpgradePlanYamlPrinter(p *api.UpgradePlan, style ui.PrinterStyle) {
+	DefaultYamlPrinter(p, style)
+}
+
+// UpgradePlanXmlPrinter - xml printer
+func UpgradePlanXmlPrinter(p *api.UpgradePlan, style ui.PrinterStyle) {
+	DefaultXmlPrinter(p, style)
+}
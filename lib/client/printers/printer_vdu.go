@@ -51,3 +51,8 @@ func VduJsonPrinter(t *response.VduPackage, style ui.PrinterStyle) {
 func VduYamlPrinter(t *response.VduPackage, style ui.PrinterStyle) {
 	DefaultYamlPrinter(t, style)
 }
+
+// VduXmlPrinter - xml printer
+func VduXmlPrinter(t *response.VduPackage, style ui.PrinterStyle) {
+	DefaultXmlPrinter(t, style)
+}
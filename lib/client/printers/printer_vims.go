@@ -30,6 +30,11 @@ func TenantsResponseYamlPrinter(spec *response.TenantSpecs, style ui.PrinterStyl
 	DefaultJsonPrinter(spec, style)
 }
 
+// TenantsResponseXmlPrinter - xml printer
+func TenantsResponseXmlPrinter(spec *response.TenantSpecs, style ui.PrinterStyle) {
+	DefaultXmlPrinter(spec, style)
+}
+
 // TenantsResponseJsonPrinter - json printer for cluster templates
 func TenantsResponseJsonPrinter(specs *response.TenantSpecs, style ui.PrinterStyle) {
 	DefaultJsonPrinter(specs, style)
@@ -57,6 +62,11 @@ func VmwareInventoryYamlPrinter(spec *models.VMwareClusters, style ui.PrinterSty
 	DefaultYamlPrinter(spec, style)
 }
 
+// VmwareInventoryXmlPrinter - xml printer
+func VmwareInventoryXmlPrinter(spec *models.VMwareClusters, style ui.PrinterStyle) {
+	DefaultXmlPrinter(spec, style)
+}
+
 // VmwareInventoryJsonPrinter - json printer for cluster templates
 func VmwareInventoryJsonPrinter(specs *models.VMwareClusters, style ui.PrinterStyle) {
 	DefaultJsonPrinter(specs, style)
@@ -154,6 +164,11 @@ func VmwareNetworkYamlPrinter(specs *models.CloudNetworks, style ui.PrinterStyle
 	DefaultYamlPrinter(specs, style)
 }
 
+// VmwareNetworkXmlPrinter - xml printer
+func VmwareNetworkXmlPrinter(specs *models.CloudNetworks, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
+
 // VmwareTemplateTablePrinter - tabular format printer printer for VMware template
 // attached to compute cluster
 func VmwareTemplateTablePrinter(specs *models.VcInventory, style ui.PrinterStyle) {
@@ -187,6 +202,11 @@ func VmwareTemplateYamlPrinter(specs *models.VcInventory, style ui.PrinterStyle)
 	DefaultYamlPrinter(specs, style)
 }
 
+// VmwareTemplateXmlPrinter - xml printer
+func VmwareTemplateXmlPrinter(specs *models.VcInventory, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
+
 // VmwareResourcePoolTablePrinter - a tabular format printer resource pools
 // attached to compute cluster
 func VmwareResourcePoolTablePrinter(specs *models.ResourcePool, style ui.PrinterStyle) {
@@ -221,3 +241,8 @@ func VmwareResourcePoolJsonPrinter(specs *models.ResourcePool, style ui.PrinterS
 func VmwareResourcePoolYamlPrinter(specs *models.ResourcePool, style ui.PrinterStyle) {
 	DefaultYamlPrinter(specs, style)
 }
+
+// VmwareResourcePoolXmlPrinter - xml printer
+func VmwareResourcePoolXmlPrinter(specs *models.ResourcePool, style ui.PrinterStyle) {
+	DefaultXmlPrinter(specs, style)
+}
@@ -23,10 +23,12 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spyroot/tcactl/app/main/cmds/ui"
 	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
 	"github.com/spyroot/tcactl/pkg/io"
 	"github.com/tidwall/pretty"
 	"gopkg.in/yaml.v3"
 	"os"
+	"reflect"
 	"strings"
 )
 
@@ -53,6 +55,33 @@ func DefaultYamlPrinter(t interface{}, style ui.PrinterStyle) {
 	}
 }
 
+// xmlRootName returns xml root element name for a printed value,
+// spec printed as spec element so output can be read back as a spec,
+// other types use lower camel case type name, lists use items.
+func xmlRootName(t interface{}) string {
+
+	if _, ok := t.(specs.RequestSpec); ok {
+		return specs.XmlSpecElement
+	}
+
+	rt := reflect.TypeOf(t)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || len(rt.Name()) == 0 || rt.Kind() == reflect.Slice {
+		return io.XmlItemsElement
+	}
+
+	return strings.ToLower(rt.Name()[:1]) + rt.Name()[1:]
+}
+
+// DefaultXmlPrinter Default Xml printer, element names are json field names
+func DefaultXmlPrinter(t interface{}, style ui.PrinterStyle) {
+	b, err := io.XmlMarshalIndent(t, xmlRootName(t), "  ")
+	io.CheckErr(err)
+	fmt.Print(string(b))
+}
+
 // CnfPackageTablePrinter table printer
 func CnfPackageTablePrinter(cnfs *response.VnfPackages, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -93,6 +122,16 @@ func CnfPackageYamlPrinter(cnfs *response.VnfPackages, style ui.PrinterStyle) {
 	DefaultYamlPrinter(cnfs, style)
 }
 
+// CnfPackageXmlPrinter - xml printer
+func CnfPackageXmlPrinter(cnfs *response.VnfPackages, style ui.PrinterStyle) {
+
+	if cnfs == nil {
+		return
+	}
+
+	DefaultXmlPrinter(cnfs, style)
+}
+
 // CnfInstanceTablePrinter table printer
 func CnfInstanceTablePrinter(cnfs *response.Cnfs, style ui.PrinterStyle) {
 
@@ -144,6 +183,16 @@ func CnfInstanceYamlPrinter(cnfs *response.Cnfs, style ui.PrinterStyle) {
 	DefaultYamlPrinter(cnfs, style)
 }
 
+// CnfInstanceXmlPrinter - xml printer
+func CnfInstanceXmlPrinter(cnfs *response.Cnfs, style ui.PrinterStyle) {
+
+	if cnfs == nil {
+		return
+	}
+
+	DefaultXmlPrinter(cnfs, style)
+}
+
 // CnfInstanceExtendedTablePrinter table printer
 func CnfInstanceExtendedTablePrinter(cnfs *response.CnfsExtended, style ui.PrinterStyle) {
 
@@ -206,6 +255,15 @@ func CnfInstanceExtendedYamlPrinter(cnfs *response.CnfsExtended, style ui.Printe
 	DefaultYamlPrinter(cnfs, style)
 }
 
+// CnfInstanceExtendedXmlPrinter - xml printer
+func CnfInstanceExtendedXmlPrinter(cnfs *response.CnfsExtended, style ui.PrinterStyle) {
+
+	if cnfs == nil {
+		return
+	}
+	DefaultXmlPrinter(cnfs, style)
+}
+
 //RepoTablePrinter - tabular format printer for repos
 func RepoTablePrinter(repo *response.ReposList, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -246,6 +304,16 @@ func RepoYamlPrinter(r *response.ReposList, style ui.PrinterStyle) {
 	DefaultYamlPrinter(r, style)
 }
 
+// RepoXmlPrinter - xml printer
+func RepoXmlPrinter(r *response.ReposList, style ui.PrinterStyle) {
+
+	if r == nil {
+		return
+	}
+
+	DefaultXmlPrinter(r, style)
+}
+
 //TenantsTablePrinter - tabular format printer for repos
 func TenantsTablePrinter(tenants *response.Tenants, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -297,6 +365,11 @@ func TenantsYamlPrinter(t *response.Tenants, style ui.PrinterStyle) {
 	DefaultYamlPrinter(t, style)
 }
 
+// TenantsXmlPrinter - xml printer
+func TenantsXmlPrinter(t *response.Tenants, style ui.PrinterStyle) {
+	DefaultXmlPrinter(t, style)
+}
+
 //NodePoolTablePrinter - tabular format printer for node pool
 func NodePoolTablePrinter(p *response.NodePool, style ui.PrinterStyle) {
 	t := table.NewWriter()
@@ -359,6 +432,14 @@ func NodePoolYamlPrinter(t *response.NodePool, style ui.PrinterStyle) {
 	DefaultYamlPrinter(t, style)
 }
 
+// NodePoolXmlPrinter - xml printer
+func NodePoolXmlPrinter(t *response.NodePool, style ui.PrinterStyle) {
+	if t == nil {
+		return
+	}
+	DefaultXmlPrinter(t, style)
+}
+
 // TenantTabularPinter - print tenant data in tabular format
 func TenantTabularPinter(tenants *response.Tenants, style ui.PrinterStyle) {
 
@@ -401,3 +482,12 @@ func TenantYamlPrinter(t *response.Tenants, style ui.PrinterStyle) {
 
 	DefaultYamlPrinter(t, style)
 }
+
+// TenantXmlPrinter - xml printer
+func TenantXmlPrinter(t *response.Tenants, style ui.PrinterStyle) {
+	if t == nil {
+		return
+	}
+
+	DefaultXmlPrinter(t, style)
+}
@@ -190,6 +190,11 @@ func (m *TemplateNotFound) Error() string {
 	return " cluster template '" + m.errMsg + "' not found."
 }
 
+// Unwrap - TemplateNotFound matches api_errors.ErrNotFound
+func (m *TemplateNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // GetTemplateId return cluster template id
 func (t *ClusterTemplates) GetTemplateId(q string) (string, error) {
 
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/models"
 	"github.com/spyroot/tcactl/pkg/netutils"
 	"github.com/spyroot/tcactl/pkg/str"
@@ -136,6 +137,11 @@ func (m *ClusterNotFound) Error() string {
 	return "cluster '" + m.ErrMsg + "' not found"
 }
 
+// Unwrap - ClusterNotFound matches api_errors.ErrNotFound
+func (m *ClusterNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // GetClusterSpec return cluster information,
 // loop up up by name or id, if not found return error
 func (c *Clusters) GetClusterSpec(cluster string) (*ClusterSpec, error) {
@@ -20,6 +20,7 @@ package response
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/models"
 	"reflect"
 	"strings"
@@ -240,6 +241,11 @@ func (m *CnfNotFound) Error() string {
 	return "cnf '" + m.errMsg + "' not found"
 }
 
+// Unwrap - CnfNotFound matches api_errors.ErrNotFound
+func (m *CnfNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // FindByName - tries to find CNF by product name, id.
 func (c *CnfsExtended) FindByName(s string) (*CnfLcmExtended, error) {
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/models"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -121,6 +122,11 @@ func (m *PoolNotFound) Error() string {
 	return "pool '" + m.ErrMsg + "' not found"
 }
 
+// Unwrap - PoolNotFound matches api_errors.ErrNotFound
+func (m *PoolNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // GetPool - search for particular pool
 // by name or id
 func (n *NodePool) GetPool(q string) (*NodesSpecs, error) {
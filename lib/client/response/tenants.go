@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/models"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -86,6 +87,11 @@ func (m *TenantCloudNotFound) Error() string {
 	return "tenant '" + m.errMsg + "' not found"
 }
 
+// Unwrap - TenantCloudNotFound matches api_errors.ErrNotFound
+func (m *TenantCloudNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // Tenants list of Tenants
 type Tenants struct {
 	TenantsList []TenantsDetails `json:"items" yaml:"items"`
@@ -27,6 +27,9 @@ const (
 	// authorizationHeader - TCA authorization header
 	authorizationHeader = "x-hm-authorization"
 
+	// maxErrorBody max length of not parsable error respond kept in error
+	maxErrorBody = 256
+
 	// DefaultVersion api version used
 	defaultVersion = "2"
 
@@ -108,6 +111,9 @@ const (
 	//TcaVmwareVnflcmUpdate update state
 	TcaVmwareVnflcmUpdate = "/hybridity/api/vnflcm/v1/vnf_instances/%s/update_state"
 
+	//TcaVmwareVnflcmOpOccs LCM operation occurrences of an instance
+	TcaVmwareVnflcmOpOccs = "/hybridity/api/vnflcm/v1/vnf_instances/%s/vnf_lcm_op_occs"
+
 	// TcaInfraPoolRetry Retry task
 	TcaInfraPoolRetry = "/hybridity/api/infra/k8s/operations/%s/retry"
 
@@ -128,6 +134,9 @@ const (
 	// TcaClustersNodePoolUpgrade upgrade existing node pool
 	TcaClustersNodePoolUpgrade = "/hybridity/infra/k8s/cluster/%s/nodepool/%s/upgrade"
 
+	// TcaClusterUpgrade upgrade control plane of existing cluster
+	TcaClusterUpgrade = "/hybridity/api/infra/k8s/clusters/%s/upgrade"
+
 	// TcaInfraSupportedVer return list of supported version
 	TcaInfraSupportedVer = "/hybridity/api/infra/k8s/supportedK8sVersions"
 
@@ -137,3 +146,6 @@ const (
 	// TcaConsumption return consumption
 	TcaConsumption = "/hybridity/api/licensing/consumption"
 )
+
+// requestIdHeaders headers server reports request id in
+var requestIdHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Hm-Request-Id"}
@@ -0,0 +1,359 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/golang/glog"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL default time cached respond stays valid
+	DefaultCacheTTL = 30 * time.Second
+
+	// ResponseCacheFile a file response cache persisted in
+	ResponseCacheFile = "cache"
+)
+
+// cachedReads TCA endpoints that used to resolve names to ids,
+// responds to GET request cached.
+var cachedReads = []*regexp.Regexp{
+	regexp.MustCompile("^" + apiTenants + "$"),
+	regexp.MustCompile("^" + TcaInfraClusters + "$"),
+	regexp.MustCompile("^" + TcaInfraCluster + "/[^/]+/nodepools$"),
+	regexp.MustCompile("^" + apiClusterTemplates + "(/[^/]+)?$"),
+	regexp.MustCompile("^" + TcaVmwareTelcoPackages + "$"),
+	regexp.MustCompile("^" + TcaApiVnfLcmExtensionVnfInstance + "$"),
+	regexp.MustCompile("^" + TcaVmwareExtensions + "$"),
+	regexp.MustCompile("^" + TcaInfraSupportedVer + "$"),
+}
+
+// cachedQueries TCA endpoints that take query in POST body,
+// respond cached, request doesn't invalidate cache.
+var cachedQueries = []*regexp.Regexp{
+	regexp.MustCompile("^" + TcaVmwareClusters + "$"),
+	regexp.MustCompile("^" + TcaVmwareVmTemplates + "$"),
+	regexp.MustCompile("^" + TcaVmwareNetworks + "$"),
+	regexp.MustCompile("^" + TcaVmwareVmContainers + "$"),
+	regexp.MustCompile("^" + TcaVmwareRepositories + "$"),
+}
+
+// uncachedQueries TCA endpoints that take query in POST body,
+// used to poll state, so never cached and doesn't invalidate cache.
+var uncachedQueries = []*regexp.Regexp{
+	regexp.MustCompile("^" + uriAuthorize + "$"),
+	regexp.MustCompile("^" + TcaInfraClusterTask + "$"),
+}
+
+// cachedRespond a respond stored in cache
+type cachedRespond struct {
+	Expires    time.Time   `json:"expires"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// ResponseCache read-through cache of TCA responds used to resolve
+// names, keyed by endpoint and query. Responds stay valid for TTL,
+// any request that mutates TCA state invalidates entire cache.
+// If File set, cache persisted, so consecutive tcactl
+// invocations (i.e. shell completion) share it.
+type ResponseCache struct {
+	// TTL time cached respond stays valid
+	TTL time.Duration
+
+	// File optional file cache persisted in
+	File string
+
+	lock    sync.Mutex
+	entries map[string]*cachedRespond
+	now     func() time.Time
+}
+
+// NewResponseCache returns in memory cache, if file
+// is not empty cache loaded from and persisted to a file.
+func NewResponseCache(ttl time.Duration, file string) *ResponseCache {
+
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	r := &ResponseCache{
+		TTL:     ttl,
+		File:    file,
+		entries: make(map[string]*cachedRespond),
+		now:     time.Now,
+	}
+	r.load()
+
+	return r
+}
+
+// DefaultResponseCache returns a cache persisted to ~/.tcactl/cache
+func DefaultResponseCache(ttl time.Duration) (*ResponseCache, error) {
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponseCache(ttl, filepath.Join(home, ".tcactl", ResponseCacheFile)), nil
+}
+
+// load reads persisted cache, expired responds dropped.
+func (r *ResponseCache) load() {
+
+	if len(r.File) == 0 {
+		return
+	}
+
+	b, err := ioutil.ReadFile(r.File)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]*cachedRespond
+	if err := json.Unmarshal(b, &entries); err != nil {
+		glog.Warningf("Ignoring corrupted response cache %s %v", r.File, err)
+		return
+	}
+
+	now := r.now()
+	for k, e := range entries {
+		if e != nil && now.Before(e.Expires) {
+			r.entries[k] = e
+		}
+	}
+}
+
+// save persist cache, caller must hold lock.
+func (r *ResponseCache) save() {
+
+	if len(r.File) == 0 {
+		return
+	}
+
+	b, err := json.Marshal(r.entries)
+	if err != nil {
+		glog.Warningf("Failed serialize response cache %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.File), 0700); err != nil {
+		glog.Warningf("Failed create response cache dir %v", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(r.File, b, 0600); err != nil {
+		glog.Warningf("Failed persist response cache %v", err)
+	}
+}
+
+// Invalidate drops all cached responds
+func (r *ResponseCache) Invalidate() {
+
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.entries) == 0 {
+		return
+	}
+
+	glog.Infof("Invalidating response cache")
+	r.entries = make(map[string]*cachedRespond)
+	r.save()
+}
+
+// Len returns number of cached responds
+func (r *ResponseCache) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.entries)
+}
+
+// get returns cached respond if not expired
+func (r *ResponseCache) get(key string) (*cachedRespond, bool) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !r.now().Before(e.Expires) {
+		delete(r.entries, key)
+		return nil, false
+	}
+
+	return e, true
+}
+
+// put stores respond
+func (r *ResponseCache) put(key string, e *cachedRespond) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e.Expires = r.now().Add(r.TTL)
+	r.entries[key] = e
+	r.save()
+}
+
+// matchAny return true if path matches any of patterns
+func matchAny(patterns []*regexp.Regexp, path string) bool {
+	for _, p := range patterns {
+		if p.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCacheable returns true if respond to request cached
+func isCacheable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet:
+		return matchAny(cachedReads, req.URL.Path)
+	case http.MethodPost:
+		if req.URL.Path == apiTenants {
+			return req.URL.RawQuery == apiTenantAction
+		}
+		return matchAny(cachedQueries, req.URL.Path)
+	}
+	return false
+}
+
+// isMutating returns true if request might change TCA state
+func isMutating(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !isCacheable(req) && !matchAny(uncachedQueries, req.URL.Path)
+}
+
+// cacheTransport round tripper that serves responds from a cache
+type cacheTransport struct {
+	cache *ResponseCache
+	scope string
+	next  http.RoundTripper
+}
+
+// Wrap returns transport that serves cached responds, wrap
+// is client.TransportWrapper.
+func (r *ResponseCache) Wrap(next http.RoundTripper) http.RoundTripper {
+	return r.wrap(next, "")
+}
+
+// wrap returns transport that serves cached responds, scope
+// separates responds of different users of same TCA.
+func (r *ResponseCache) wrap(next http.RoundTripper, scope string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{cache: r, scope: scope, next: next}
+}
+
+// key returns cache key of a request, body of query
+// requests is part of a key.
+func (t *cacheTransport) key(req *http.Request) (string, error) {
+
+	h := sha256.New()
+	h.Write([]byte(t.scope + "\n" + req.Method + " " + req.URL.String() + "\n"))
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// RoundTrip serves cacheable requests from cache,
+// mutating request invalidates cache.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if isMutating(req) {
+		resp, err := t.next.RoundTrip(req)
+		t.cache.Invalidate()
+		return resp, err
+	}
+
+	if !isCacheable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	key, err := t.key(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := t.cache.get(key); ok {
+		glog.Infof("Serving %s %s from cache", req.Method, req.URL.Path)
+		return &http.Response{
+			Status:        http.StatusText(e.StatusCode),
+			StatusCode:    e.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        e.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+			ContentLength: int64(len(e.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.cache.put(key, &cachedRespond{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+	})
+
+	return resp, nil
+}
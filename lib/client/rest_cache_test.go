@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newCacheServer returns a server that counts requests per path
+func newCacheServer(calls map[string]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, "key")
+			return
+		}
+		calls[r.Method+" "+r.URL.Path]++
+		if r.URL.Path == TcaInfraClusters+"/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+}
+
+func newCacheClient(t *testing.T, url string, cache *ResponseCache) *RestClient {
+	c := newRetryClient(t, url, nil)
+	c.ResponseCache = cache
+	c.Client = nil
+	return c
+}
+
+func TestResponseCache(t *testing.T) {
+
+	calls := map[string]int{}
+	server := newCacheServer(calls)
+	defer server.Close()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewResponseCache(time.Minute, "")
+	cache.now = func() time.Time { return now }
+
+	c := newCacheClient(t, server.URL, cache)
+	ctx := context.Background()
+
+	_, err := c.GetClusters(ctx)
+	assert.NoError(t, err)
+	_, err = c.GetClusters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls["GET "+TcaInfraClusters], "second call must be served from cache")
+
+	_, err = c.GetTenantsQuery(ctx, &specs.TenantsNfFilter{})
+	assert.NoError(t, err)
+	_, err = c.GetTenantsQuery(ctx, &specs.TenantsNfFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls["POST "+apiTenants], "query must be served from cache")
+
+	_, _ = c.GetClustersTask(ctx, specs.NewClusterTaskQuery("id"))
+	_, _ = c.GetClustersTask(ctx, specs.NewClusterTaskQuery("id"))
+	assert.Equal(t, 2, calls["POST "+TcaInfraClusterTask], "task query must not be cached")
+	assert.Equal(t, 2, cache.Len(), "task query must not invalidate cache")
+
+	_, _ = c.GetCluster(ctx, "missing")
+	_, _ = c.GetCluster(ctx, "missing")
+	assert.Equal(t, 2, calls["GET "+TcaInfraClusters+"/missing"], "error must not be cached")
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.GetClusters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["GET "+TcaInfraClusters], "expired respond must be fetched")
+
+	_, _ = c.DeleteCluster(ctx, "id")
+	assert.Equal(t, 0, cache.Len(), "mutating call must invalidate cache")
+
+	_, err = c.GetClusters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls["GET "+TcaInfraClusters])
+}
+
+func TestResponseCache_Persist(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	calls := map[string]int{}
+	server := newCacheServer(calls)
+	defer server.Close()
+
+	file := filepath.Join(dir, ResponseCacheFile)
+	ctx := context.Background()
+
+	_, err = newCacheClient(t, server.URL, NewResponseCache(time.Minute, file)).GetClusters(ctx)
+	assert.NoError(t, err)
+
+	info, err := os.Stat(file)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	_, err = newCacheClient(t, server.URL, NewResponseCache(time.Minute, file)).GetClusters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls["GET "+TcaInfraClusters], "persisted respond must be reused")
+
+	c := newCacheClient(t, server.URL, NewResponseCache(time.Minute, file))
+	c.Username = "other"
+	c.Client = nil
+	_, err = c.GetClusters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["GET "+TcaInfraClusters], "respond must not be shared between users")
+}
@@ -18,15 +18,19 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/pkg/io"
+	"github.com/spyroot/tcactl/pkg/netutils"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
 type ErrorResponse struct {
@@ -95,6 +99,10 @@ type RestClient struct {
 	// CertKey path to cert key "certs/client.key"
 	CertKey string
 
+	// Transport optional TLS and proxy settings, proxy, CA bundle,
+	// client certificate, pinned fingerprints and min TLS version.
+	Transport *netutils.TransportSpec
+
 	// dump server respond (debug server output)
 	isTrace bool
 
@@ -104,6 +112,37 @@ type RestClient struct {
 	// set debug mode
 	IsDebug bool
 
+	// SessionTTL age of a session after which client
+	// authenticates again, default DefaultSessionTTL
+	SessionTTL time.Duration
+
+	// SessionCache optional on disk cache of api keys
+	SessionCache *SessionCache
+
+	// RetryPolicy retry policy for idempotent requests, nil disables retry
+	RetryPolicy *RetryPolicy
+
+	// TransportWrappers wraps http transport, for example a Recorder,
+	// applied in order after tls settings.
+	TransportWrappers []TransportWrapper
+
+	// ResponseCache optional read-through cache of responds used
+	// to resolve names, nil disables cache.
+	ResponseCache *ResponseCache
+
+	// RateLimiter optional limit of request rate and number of
+	// concurrent requests, nil disables limit.
+	RateLimiter *RateLimiter
+
+	// time current session created, api key and time session
+	// created guarded by keyLock, sessionLock serializes renewal
+	sessionCreated time.Time
+	keyLock        sync.RWMutex
+	sessionLock    sync.Mutex
+
+	// clientLock guards lazy creation of a client shared by goroutines
+	clientLock sync.Mutex
+
 	// this mainly for testing
 	simulateFail bool
 	failureType  map[string]string
@@ -121,33 +160,158 @@ func NewRestClient(baseURL string, skipSsl bool, username string, password strin
 	if len(password) == 0 {
 		return nil, errors.New("password is empty string")
 	}
-	return &RestClient{BaseURL: baseURL, SkipSsl: skipSsl, Username: username, Password: password, failureType: map[string]string{}}, nil
+	return &RestClient{BaseURL: baseURL, SkipSsl: skipSsl, Username: username, Password: password, RetryPolicy: DefaultRetryPolicy(), failureType: map[string]string{}}, nil
 }
 
-// makeDefaultHeaders default headers
-func (c *RestClient) makeDefaultHeaders() {
-	c.Client.SetHeader("Content-Type", defaultContentType)
-	c.Client.SetHeader("Version", defaultVersion)
-	c.Client.SetHeader("Accept", defaultAccept)
-	c.Client.SetHeader("Authorization", c.apiKey)
-	c.Client.SetHeader("x-hm-authorization", c.apiKey)
+// makeDefaultHeaders default headers, set once when client
+// created since client headers shared by concurrent requests.
+func makeDefaultHeaders(client *resty.Client) {
+	client.SetHeader("Content-Type", defaultContentType)
+	client.SetHeader("Version", defaultVersion)
+	client.SetHeader("Accept", defaultAccept)
 }
 
-// GetClient return rest client
-func (c *RestClient) GetClient() {
+// currentSession returns api key and time current session created
+func (c *RestClient) currentSession() (string, time.Time) {
+	c.keyLock.RLock()
+	defer c.keyLock.RUnlock()
+	return c.apiKey, c.sessionCreated
+}
+
+// setSession updates api key and time session created
+func (c *RestClient) setSession(apiKey string, created time.Time) {
+	c.keyLock.Lock()
+	defer c.keyLock.Unlock()
+	c.apiKey, c.sessionCreated = apiKey, created
+}
+
+// authorize attaches api key of current session to a request. Key attached
+// to each request, client headers shared by all requests never updated.
+func (c *RestClient) authorize(_ *resty.Client, r *resty.Request) error {
+	if apiKey, _ := c.currentSession(); len(apiKey) > 0 {
+		r.SetHeader("Authorization", apiKey)
+		r.SetHeader(authorizationHeader, apiKey)
+	}
+	return nil
+}
+
+// newClient creates a rest client, applies TLS and proxy settings.
+// Client replays once a request TCA rejected because session expired
+// and retries idempotent requests according to retry policy.
+func (c *RestClient) newClient() *resty.Client {
+
+	client := resty.New()
+	client.SetTransport(c.newTransport())
+
+	// wrappers applied after tls and proxy settings
+	if len(c.TransportWrappers) > 0 || c.ResponseCache != nil || c.RateLimiter != nil {
+		var transport = client.GetClient().Transport
+		for _, wrap := range c.TransportWrappers {
+			transport = wrap(transport)
+		}
+		if c.RateLimiter != nil {
+			transport = c.RateLimiter.Wrap(transport)
+		}
+		// cache is outermost, so cached respond never reach a recorder
+		// and never consumes rate limit
+		if c.ResponseCache != nil {
+			transport = c.ResponseCache.wrap(transport, c.Username)
+		}
+		client.SetTransport(transport)
+	}
+
+	makeDefaultHeaders(client)
+	client.OnBeforeRequest(c.authorize)
+	c.RetryPolicy.apply(client, c)
+
+	return client
+}
+
+// transportSpec returns TLS and proxy settings of a client, legacy
+// CertFile and CertKey merged into Transport spec, SkipSsl used
+// only if Transport is nil.
+func (c *RestClient) transportSpec() *netutils.TransportSpec {
+
+	spec := netutils.TransportSpec{Insecure: c.SkipSsl}
+	if c.Transport != nil {
+		spec = *c.Transport
+	}
+
+	if len(spec.CertFile) == 0 && len(c.CertFile) > 0 && len(c.CertKey) > 0 {
+		if io.FileExists(c.CertFile) && io.FileExists(c.CertKey) {
+			spec.CertFile, spec.KeyFile = c.CertFile, c.CertKey
+		}
+	}
+
+	return &spec
+}
+
+// errTransport fails every request, used when transport
+// settings are invalid so client never falls back to defaults.
+type errTransport struct {
+	err error
+}
+
+func (t *errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// newTransport returns http transport that honours TLS and proxy settings.
+func (c *RestClient) newTransport() http.RoundTripper {
+
+	tr, err := netutils.NewTransport(c.transportSpec())
+	if err != nil {
+		glog.Errorf("Invalid transport settings %v", err)
+		return &errTransport{err: fmt.Errorf("invalid transport settings: %v", err)}
+	}
+
+	return tr
+}
+
+// TransportWrapper wraps http transport used by a rest client
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+// WrapTransport adds transport wrapper, client re-created on next request.
+func (c *RestClient) WrapTransport(wrapper TransportWrapper) {
+	if wrapper == nil {
+		return
+	}
+	c.TransportWrappers = append(c.TransportWrappers, wrapper)
+	c.setClient(nil)
+}
+
+// ensureClient creates rest client once, client
+// safe for use by concurrent requests.
+func (c *RestClient) ensureClient() {
+
+	c.clientLock.Lock()
+	defer c.clientLock.Unlock()
 
 	if c.Client == nil {
 		glog.Infof("Creating a new rest client")
-		c.Client = resty.New()
-		if c.SkipSsl {
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: c.SkipSsl},
-			}
-			c.Client.SetTransport(tr)
-		}
+		c.Client = c.newClient()
 	}
+}
+
+// setClient replaces a client, nil client re-created on next request.
+func (c *RestClient) setClient(client *resty.Client) {
 
-	c.makeDefaultHeaders()
+	c.clientLock.Lock()
+	defer c.clientLock.Unlock()
+
+	c.Client = client
+}
+
+// GetClient return rest client
+func (c *RestClient) GetClient() {
+
+	c.ensureClient()
+
+	if apiKey, expired := c.isSessionExpired(); expired {
+		if err := c.renewSession(context.Background(), apiKey); err != nil {
+			glog.Errorf("Failed renew session %v", err)
+		}
+	}
 }
 
 // SetDumpRespond sets client in output mode.
@@ -156,30 +320,30 @@ func (c *RestClient) SetDumpRespond(dumpRespond bool) {
 }
 
 // GetAuthorization retrieve API key from TCA
-// and update internal state.
-func (c *RestClient) GetAuthorization() (bool, error) {
-
-	c.Client = resty.New()
-
-	// loads cert or skip ssl
-	if len(c.CertKey) > 0 && len(c.CertKey) > 0 {
-		if io.FileExists(c.CertFile) && io.FileExists(c.CertKey) {
-			cert, err := tls.LoadX509KeyPair(c.CertFile, c.CertKey)
-			if err != nil {
-				glog.Fatalf("ERROR client certificate: %s", err)
-			}
-			c.Client.SetCertificates(cert)
+// and update internal state.  If session cache
+// holds a key that is not expired, key reused.
+func (c *RestClient) GetAuthorization(ctx context.Context) (bool, error) {
+
+	// settings changed after client created take effect
+	c.setClient(c.newClient())
+
+	if apiKey, created, ok := c.SessionCache.Load(c.BaseURL, c.Username); ok {
+		if time.Since(created) < c.sessionTTL() {
+			glog.Infof("Using cached TCA session, age %v", time.Since(created))
+			c.setSession(apiKey, created)
+			return true, nil
 		}
 	}
 
-	if c.SkipSsl {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		c.Client.SetTransport(tr)
-	}
+	return c.login(ctx)
+}
+
+// login authenticates and updates api key and session age.
+func (c *RestClient) login(ctx context.Context) (bool, error) {
 
-	resp, err := c.Client.R().
+	c.ensureClient()
+
+	resp, err := c.Client.R().SetContext(ctx).
 		SetHeader("Content-Type", defaultContentType).
 		SetBody(AuthorizationReq{
 			Username: c.Username,
@@ -202,63 +366,129 @@ func (c *RestClient) GetAuthorization() (bool, error) {
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			if strings.ToLower(errRes.Error) == "unauthorized" {
-				glog.Errorf("Server return error %s", errRes.Message)
-				return false, fmt.Errorf("authentication failed for username %s", c.Username)
-			}
-			glog.Errorf("Server return error %s", errRes.Message)
-			return false, fmt.Errorf("error %s", errRes.Message)
-		} else {
-			glog.Errorf("Failed parse server respond.")
+		apiErr := newApiError(resp)
+		glog.Errorf("Server return error %s", apiErr.Message)
+		if strings.ToLower(apiErr.Code) == "unauthorized" || apiErr.StatusCode == http.StatusUnauthorized {
+			apiErr.Message = fmt.Sprintf("authentication failed for username %s", c.Username)
 		}
+		return false, apiErr
 	}
 
 	if resp.StatusCode() == http.StatusOK {
-		c.apiKey = resp.Header().Get(authorizationHeader)
-		return len(c.apiKey) > 0, nil
+		apiKey := resp.Header().Get(authorizationHeader)
+		if len(apiKey) == 0 {
+			return false, nil
+		}
+
+		created := time.Now()
+		c.setSession(apiKey, created)
+		if err := c.SessionCache.Store(c.BaseURL, c.Username, apiKey, created); err != nil {
+			glog.Warningf("Failed update session cache %v", err)
+		}
+
+		return true, nil
 	}
 
-	return false, fmt.Errorf("server return %v", resp.StatusCode())
+	return false, c.checkError(resp)
 }
 
-// checkError - check error, log it
-func (c *RestClient) checkError(r *resty.Response) error {
+// errorBody union of error formats TCA returns,
+// hybridity error, list of errors and SOL problem details.
+type errorBody struct {
+	Code     interface{}     `json:"code"`
+	Message  string          `json:"message"`
+	Error    string          `json:"error"`
+	Detail   string          `json:"detail"`
+	Path     string          `json:"path"`
+	Title    string          `json:"title"`
+	Instance string          `json:"instance"`
+	Errors   []ErrorResponse `json:"errors"`
+}
 
-	if r.StatusCode() == http.StatusNotFound {
-		glog.Error("API resource not found")
-		return fmt.Errorf("api resource not found")
+// newApiError returns typed api error for a server respond
+func newApiError(r *resty.Response) *api_errors.ApiError {
+
+	apiErr := &api_errors.ApiError{StatusCode: r.StatusCode()}
+	if r.Request != nil && r.Request.RawRequest != nil {
+		apiErr.Path = r.Request.RawRequest.URL.Path
 	}
 
-	var errRes ErrorResponse
-	if err := json.Unmarshal(r.Body(), &errRes); err == nil {
-		glog.Errorf("Server return error %s path %s msg %s", errRes.Error, errRes.Path, errRes.Message)
-		return fmt.Errorf("error %s path %s msg %s", errRes.Error, errRes.Path, errRes.Message)
-	} else {
+	for _, h := range requestIdHeaders {
+		if id := r.Header().Get(h); len(id) > 0 {
+			apiErr.RequestId = id
+			break
+		}
+	}
+
+	var body errorBody
+	if err := json.Unmarshal(r.Body(), &body); err != nil {
 		glog.Errorf("Failed parse server respond.")
+		apiErr.Message = strings.TrimSpace(string(r.Body()))
+		if len(apiErr.Message) > maxErrorBody {
+			apiErr.Message = apiErr.Message[:maxErrorBody]
+		}
+		if len(apiErr.Message) == 0 {
+			apiErr.Message = http.StatusText(r.StatusCode())
+		}
+		return apiErr
 	}
 
-	return fmt.Errorf("unknown error, status code: %v ", r.StatusCode())
-}
+	if body.Code != nil {
+		apiErr.Code = fmt.Sprint(body.Code)
+	} else {
+		apiErr.Code = body.Error
+	}
 
-// checkErrors - check list of errors.
-func (c *RestClient) checkErrors(r *resty.Response) error {
+	apiErr.Message = body.Message
+	if len(apiErr.Message) == 0 {
+		apiErr.Message = body.Title
+	}
+	if len(apiErr.Message) == 0 {
+		apiErr.Message = body.Error
+	}
 
-	if r.StatusCode() == http.StatusNotFound {
-		glog.Errorf("API resource not found")
-		return fmt.Errorf("api resource not found")
+	apiErr.Details = body.Detail
+	if len(apiErr.Message) == 0 {
+		apiErr.Message = body.Detail
+	}
+
+	if len(body.Errors) > 0 {
+		errs := ErrorsResponse{ErrorResponses: body.Errors}
+		apiErr.Message = strings.Join(errs.GetErrors(), ", ")
+		if len(apiErr.Code) == 0 {
+			apiErr.Code = body.Errors[0].Code
+		}
 	}
 
-	var errRes ErrorsResponse
-	if err := json.Unmarshal(r.Body(), &errRes); err == nil {
-		glog.Errorf("Server return error %v", errRes.GetErrors())
-		return fmt.Errorf("error %v", errRes.GetErrors())
+	if len(body.Path) > 0 {
+		apiErr.Path = body.Path
+	} else if len(body.Instance) > 0 {
+		apiErr.Path = body.Instance
+	}
+
+	if len(apiErr.Message) == 0 {
+		apiErr.Message = http.StatusText(r.StatusCode())
+	}
+
+	return apiErr
+}
+
+// checkError - check error, log it and return typed api error
+func (c *RestClient) checkError(r *resty.Response) error {
+
+	apiErr := newApiError(r)
+	if r.StatusCode() == http.StatusNotFound {
+		glog.Errorf("API resource not found %s", apiErr.Path)
 	} else {
-		glog.Errorf("Failed parse server respond.")
+		glog.Errorf("Server return error %v", apiErr)
 	}
 
-	return fmt.Errorf("unknown error, status code: %v ", r.StatusCode())
+	return apiErr
+}
+
+// checkErrors - check list of errors, log it and return typed api error
+func (c *RestClient) checkErrors(r *resty.Response) error {
+	return c.checkError(r)
 }
 
 // SetTrace enables trace server responds
@@ -289,5 +519,6 @@ func (c *RestClient) AddFailureCondition(s string) {
 
 // GetApiKey TCA return api key , it used for authentication.
 func (c *RestClient) GetApiKey() string {
-	return c.apiKey
+	apiKey, _ := c.currentSession()
+	return apiKey
 }
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"github.com/spyroot/tcactl/pkg/netutils"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestClient_checkError(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		header      map[string]string
+		wantKind    error
+		wantCode    string
+		wantMessage string
+		wantPath    string
+		wantId      string
+	}{
+		{
+			name:        "hybridity error",
+			status:      http.StatusConflict,
+			body:        `{"code":"DUPLICATE","message":"cluster exists","error":"Conflict","path":"/hybridity/api/infra/k8s/clusters"}`,
+			wantKind:    api_errors.ErrConflict,
+			wantCode:    "DUPLICATE",
+			wantMessage: "cluster exists",
+			wantPath:    "/hybridity/api/infra/k8s/clusters",
+		},
+		{
+			name:        "list of errors",
+			status:      http.StatusBadRequest,
+			body:        `{"errors":[{"code":"E1","message":"name required"},{"code":"E2","message":"bad cidr"}]}`,
+			wantKind:    api_errors.ErrValidation,
+			wantCode:    "E1",
+			wantMessage: "name required, bad cidr",
+			wantPath:    "/resource",
+		},
+		{
+			name:        "problem details",
+			status:      http.StatusNotFound,
+			body:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"package missing","instance":"/telco/api/vnfpkgm/v2/vnf_packages/1"}`,
+			header:      map[string]string{"X-Request-Id": "req-1"},
+			wantKind:    api_errors.ErrNotFound,
+			wantMessage: "Not Found",
+			wantPath:    "/telco/api/vnfpkgm/v2/vnf_packages/1",
+			wantId:      "req-1",
+		},
+		{
+			name:        "not parsable",
+			status:      http.StatusBadGateway,
+			body:        `<html>bad gateway</html>`,
+			wantKind:    api_errors.ErrUnavailable,
+			wantMessage: "<html>bad gateway</html>",
+			wantPath:    "/resource",
+		},
+		{
+			name:        "empty",
+			status:      http.StatusInternalServerError,
+			wantKind:    api_errors.ErrServer,
+			wantMessage: http.StatusText(http.StatusInternalServerError),
+			wantPath:    "/resource",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c, err := NewRestClient(server.URL, false, "admin", "password")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.RetryPolicy = nil
+			c.GetClient()
+
+			resp, err := c.Client.R().Get(server.URL + "/resource")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.checkError(resp)
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("checkError() = %v, want kind %v", err, tt.wantKind)
+			}
+
+			var apiErr *api_errors.ApiError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("checkError() = %T, want *api_errors.ApiError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %s, want %s", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %s, want %s", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Path != tt.wantPath {
+				t.Errorf("Path = %s, want %s", apiErr.Path, tt.wantPath)
+			}
+			if apiErr.RequestId != tt.wantId {
+				t.Errorf("RequestId = %s, want %s", apiErr.RequestId, tt.wantId)
+			}
+		})
+	}
+}
+
+// writeClientCert writes self-signed client certificate and key to dir
+func writeClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tcactl"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return certFile, keyFile, cert
+}
+
+func TestRestClient_Transport(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile, cert := writeClientCert(t, dir)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(authorizationHeader, "key")
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	pin := netutils.Fingerprint(server.Certificate().Raw)
+	ctx := context.Background()
+
+	// client certificate must survive skip ssl
+	c, err := NewRestClient(server.URL, true, "admin", "password")
+	assert.NoError(t, err)
+	c.CertFile, c.CertKey = certFile, keyFile
+	ok, err := c.GetAuthorization(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	c, err = NewRestClient(server.URL, false, "admin", "password")
+	assert.NoError(t, err)
+	c.Transport = &netutils.TransportSpec{CertFile: certFile, KeyFile: keyFile, PinSha256: []string{pin}}
+	ok, err = c.GetAuthorization(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	c.Transport.PinSha256 = []string{strings.Repeat("0", 64)}
+	_, err = c.GetAuthorization(ctx)
+	assert.Error(t, err, "pin mismatch must fail")
+
+	c.Transport = &netutils.TransportSpec{Proxy: "ftp://proxy"}
+	_, err = c.GetAuthorization(ctx)
+	assert.Error(t, err, "invalid settings must fail")
+}
@@ -23,11 +23,11 @@ import (
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
 	"github.com/spyroot/tcactl/lib/models"
 	ioutils "github.com/spyroot/tcactl/pkg/io"
-	"net/http"
 )
 
 // GetClusters returns infrastructure k8s clusters
@@ -47,13 +47,7 @@ func (c *RestClient) GetClusters(ctx context.Context) (*response.Clusters, error
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v", errRes.Message)
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
 	var clusters response.Clusters
@@ -97,7 +91,7 @@ func (c *RestClient) GetCluster(ctx context.Context, clusterId string) (*respons
 
 // GetClusterNodePools - returns cluster k8s node pools list
 // each list hold specs, caller need indicate valid cluster ID not a name.
-func (c *RestClient) GetClusterNodePools(clusterId string) (*response.NodePool, error) {
+func (c *RestClient) GetClusterNodePools(ctx context.Context, clusterId string) (*response.NodePool, error) {
 
 	glog.Infof("Retrieving node pool for cluster id %v", clusterId)
 
@@ -106,7 +100,7 @@ func (c *RestClient) GetClusterNodePools(clusterId string) (*response.NodePool,
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		Get(c.BaseURL + TcaInfraCluster + "/" + clusterId + "/nodepools")
 
 	if err != nil {
@@ -118,14 +112,8 @@ func (c *RestClient) GetClusterNodePools(clusterId string) (*response.NodePool,
 		fmt.Println(string(resp.Body()))
 	}
 
-	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusBadRequest {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v %v %v", errRes.Details, errRes.Message, string(resp.Body()))
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
 	}
 
 	var pools response.NodePool
@@ -156,7 +144,7 @@ func (c *RestClient) GetNamedClusterNodePools(ctx context.Context, clusterName s
 		return nil, "", err
 	}
 
-	nodePool, err := c.GetClusterNodePools(clusterId)
+	nodePool, err := c.GetClusterNodePools(ctx, clusterId)
 	if err != nil {
 		return nil, "", err
 	}
@@ -172,6 +160,11 @@ func (m *TaskNotFound) Error() string {
 	return m.errMsg + " cluster not found"
 }
 
+// Unwrap - TaskNotFound matches api_errors.ErrNotFound
+func (m *TaskNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 // GetClustersTask - returns infrastructure k8s clusters task list
 // Before adjusting cluster task , caller must first check existing task list.
 // each task can fail.
@@ -222,13 +215,7 @@ func (c *RestClient) GetClusterTask(ctx context.Context, clusterId string) (*mod
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v %v %v", errRes.Details, errRes.Message, string(resp.Body()))
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
 	var task models.ClusterTask
@@ -245,7 +232,7 @@ func (c *RestClient) GetClusterTask(ctx context.Context, clusterId string) (*mod
 // TCA also require each type of cluster has valid template.
 // raw rest call doesn't do any validation, while API interface does basic validation check
 // for spec.
-func (c *RestClient) CreateCluster(spec *specs.SpecCluster) (*models.TcaTask, error) {
+func (c *RestClient) CreateCluster(ctx context.Context, spec *specs.SpecCluster) (*models.TcaTask, error) {
 
 	c.GetClient()
 	glog.Infof("Creating cluster %v", spec)
@@ -257,7 +244,7 @@ func (c *RestClient) CreateCluster(spec *specs.SpecCluster) (*models.TcaTask, er
 
 	ioutils.PrettyString(spec)
 
-	resp, err := c.Client.R().SetBody(spec).Post(c.BaseURL + TcaInfraClusters)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(spec).Post(c.BaseURL + TcaInfraClusters)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -314,3 +301,51 @@ func (c *RestClient) DeleteCluster(ctx context.Context, clusterId string) (*mode
 
 	return &task, nil
 }
+
+// ClusterUpgradeSpec control plane upgrade request, cluster template
+// indicates target kubernetes version.
+type ClusterUpgradeSpec struct {
+	ClusterTemplateId string `json:"clusterTemplateId" yaml:"clusterTemplateId"`
+	VmTemplate        string `json:"vmTemplate,omitempty" yaml:"vmTemplate,omitempty"`
+}
+
+// UpgradeCluster - upgrade control plane of k8s cluster to kubernetes
+// version of a cluster template. Node pools upgraded separately.
+// Method return models.TcaTask that can monitored.
+func (c *RestClient) UpgradeCluster(ctx context.Context, clusterId string, spec *ClusterUpgradeSpec) (*models.TcaTask, error) {
+
+	if len(clusterId) == 0 {
+		return nil, fmt.Errorf("cluster id is empty string")
+	}
+
+	if spec == nil {
+		return nil, errors.New("cluster upgrade spec is nil")
+	}
+
+	c.GetClient()
+	glog.Infof("Upgrading cluster %v template %v", clusterId, spec.ClusterTemplateId)
+
+	resp, err := c.Client.R().SetContext(ctx).SetBody(spec).Put(c.BaseURL + fmt.Sprintf(TcaClusterUpgrade, clusterId))
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
+
+	if c.isTrace && resp != nil {
+		fmt.Println(string(resp.Body()))
+	}
+
+	if !resp.IsSuccess() {
+		return nil, c.checkErrors(resp)
+	}
+
+	var task models.TcaTask
+	if err := json.Unmarshal(resp.Body(), &task); err != nil {
+		glog.Errorf("Failed parse servers respond. %v", err)
+		return nil, err
+	}
+
+	glog.Infof("SpecCluster upgrade task created task id %s op id %s", task.Id, task.OperationId)
+
+	return &task, nil
+}
@@ -25,41 +25,56 @@ import (
 	"github.com/golang/glog"
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
 	ioutils "github.com/spyroot/tcactl/pkg/io"
+	"strconv"
 )
 
 // GetVnflcm - Retrieves information about a CNF/VNF instance by reading
-// an "Individual VNF instance" resource.
+// an "Individual VNF instance" resource.  Without arguments or with
+// a filter, method follows SOL013 pages and returns entire list.
 //
 // Example of filter
 // (eq,id,5c11bd9c-085d-4913-a453-572457ddffe2)
-func (c *RestClient) GetVnflcm(req ...string) (interface{}, error) {
+func (c *RestClient) GetVnflcm(ctx context.Context, req ...string) (interface{}, error) {
+
+	// if two argument will retrieve particular catalog entity
+	if len(req) == 2 {
+		return c.getVnflcmInstance(ctx, req[1])
+	}
+
+	var filter string
+	if len(req) == 1 {
+		filter = req[0]
+	}
 
 	var (
-		resp    *resty.Response
-		err     error
-		isArray = true
+		extended response.CnfsExtended
+		marker   string
 	)
 
-	c.GetClient()
+	for {
+		page, next, err := c.GetVnflcmPage(ctx, filter, marker)
+		if err != nil {
+			return nil, err
+		}
 
-	// no args will return entire list
-	if len(req) == 0 {
-		resp, err = c.Client.R().Get(c.BaseURL + TcaApiVnfLcmExtensionVnfInstance)
-	}
-	// attach filter and dispatch
-	if len(req) == 1 {
-		var queryFilter = req[0]
-		resp, err = c.Client.R().
-			SetQueryParams(map[string]string{"filter": queryFilter}).
-			Get(c.BaseURL + TcaApiVnfLcmExtensionVnfInstance)
-	}
-	// if two argument will retrieve particular catalog entity
-	if len(req) == 2 {
-		isArray = false
-		resp, err = c.Client.R().Get(c.BaseURL + TcaApiVnfLcmVnfInstance + "/" + req[1])
+		extended.CnfLcms = append(extended.CnfLcms, page.CnfLcms...)
+		if len(next) == 0 || next == marker {
+			break
+		}
+		marker = next
 	}
 
+	return &extended, nil
+}
+
+// getVnflcmInstance retrieves single instance, for single cnf request
+// result packed in array.
+func (c *RestClient) getVnflcmInstance(ctx context.Context, instanceId string) (*response.Cnfs, error) {
+
+	c.GetClient()
+	resp, err := c.Client.R().SetContext(ctx).Get(c.BaseURL + TcaApiVnfLcmVnfInstance + "/" + instanceId)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -69,44 +84,27 @@ func (c *RestClient) GetVnflcm(req ...string) (interface{}, error) {
 		fmt.Println(string(resp.Body()))
 	}
 
-	//
 	if !resp.IsSuccess() {
-		var errRes response.CnfInstancesError
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			return nil, fmt.Errorf(errRes.Detail)
-		}
-		return nil, fmt.Errorf("unknown error, status code: %d", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
-	// for single cnf request, pack return result in array.
-	if !isArray {
-		var (
-			cnflcm  response.LcmInfo
-			cnfslcm response.Cnfs
-		)
-
-		if err := json.Unmarshal(resp.Body(), &cnflcm); err != nil {
-			glog.Errorf("Failed parse servers respond. %v", err)
-			return nil, err
-		}
-
-		cnfslcm.CnfLcms = append(cnfslcm.CnfLcms, cnflcm)
-		return &cnfslcm, nil
-	}
+	var (
+		cnflcm  response.LcmInfo
+		cnfslcm response.Cnfs
+	)
 
-	// default case
-	var extended response.CnfsExtended
-	if err := json.Unmarshal(resp.Body(), &extended.CnfLcms); err != nil {
+	if err := json.Unmarshal(resp.Body(), &cnflcm); err != nil {
 		glog.Errorf("Failed parse servers respond. %v", err)
 		return nil, err
 	}
 
-	return &extended, nil
+	cnfslcm.CnfLcms = append(cnfslcm.CnfLcms, cnflcm)
+	return &cnfslcm, nil
 }
 
 // GetRunningVnflcm rest call return state of CNF or VNF
 // state described as response.LcmInfo
-func (c *RestClient) GetRunningVnflcm(instanceId string) (*response.LcmInfo, error) {
+func (c *RestClient) GetRunningVnflcm(ctx context.Context, instanceId string) (*response.LcmInfo, error) {
 
 	var (
 		resp *resty.Response
@@ -116,7 +114,7 @@ func (c *RestClient) GetRunningVnflcm(instanceId string) (*response.LcmInfo, err
 	glog.Infof("Retrieving running instancing %v", instanceId)
 
 	c.GetClient()
-	resp, err = c.Client.R().
+	resp, err = c.Client.R().SetContext(ctx).
 		Get(c.BaseURL + TcaApiVnfLcmVnfInstance + "/" + instanceId)
 
 	if err != nil {
@@ -129,13 +127,7 @@ func (c *RestClient) GetRunningVnflcm(instanceId string) (*response.LcmInfo, err
 	}
 
 	if !resp.IsSuccess() {
-		var errRes response.CnfInstancesError
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Detail)
-			return nil, fmt.Errorf(errRes.Detail)
-		}
-		glog.Errorf("unknown error, status code: %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
 	//
@@ -148,10 +140,41 @@ func (c *RestClient) GetRunningVnflcm(instanceId string) (*response.LcmInfo, err
 	return &lcmInfo, nil
 }
 
+// GetVnflcmOpOccs rest call returns LCM operation occurrences
+// of CNF or VNF, latest first, at most pageSize of them.
+func (c *RestClient) GetVnflcmOpOccs(ctx context.Context, instanceId string, pageSize int) (*models.ClusterTask, error) {
+
+	c.GetClient()
+	resp, err := c.Client.R().SetContext(ctx).
+		SetQueryParams(map[string]string{"pageNumber": "1", "pageSize": strconv.Itoa(pageSize)}).
+		Get(c.BaseURL + fmt.Sprintf(TcaVmwareVnflcmOpOccs, instanceId))
+
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
+
+	if c.isTrace && resp != nil {
+		fmt.Println(string(resp.Body()))
+	}
+
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
+	}
+
+	var occs models.ClusterTask
+	if err := json.Unmarshal(resp.Body(), &occs); err != nil {
+		glog.Errorf("Failed parse servers respond. %v", err)
+		return nil, err
+	}
+
+	return &occs, nil
+}
+
 // TerminateInstance rest call, terminates CNF/VNF
 // terminateReq *specs.LcmTerminateRequest describes
 // a request.
-func (c *RestClient) TerminateInstance(terminateUri string, terminateReq *specs.LcmTerminateRequest) error {
+func (c *RestClient) TerminateInstance(ctx context.Context, terminateUri string, terminateReq *specs.LcmTerminateRequest) error {
 
 	if terminateReq == nil {
 		return fmt.Errorf("nil request")
@@ -160,7 +183,7 @@ func (c *RestClient) TerminateInstance(terminateUri string, terminateReq *specs.
 	glog.Infof("Terminating instancing %v", terminateUri)
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		SetBody(terminateReq).
 		Post(terminateUri)
 
@@ -174,11 +197,7 @@ func (c *RestClient) TerminateInstance(terminateUri string, terminateReq *specs.
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err = json.NewDecoder(resp.RawResponse.Body).Decode(&errRes); err == nil {
-			return fmt.Errorf(errRes.Message)
-		}
-		return fmt.Errorf("unknown error, status code: %d", resp.StatusCode())
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -204,13 +223,7 @@ func (c *RestClient) CnfRollback(ctx context.Context, href string) error {
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -236,13 +249,7 @@ func (c *RestClient) CnfResetState(ctx context.Context, href string) error {
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -270,13 +277,7 @@ func (c *RestClient) CreateInstance(ctx context.Context, req *specs.LcmCreateReq
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return nil, fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %d", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
 	var vnfCreateResp response.VNFInstantiate
@@ -364,13 +365,7 @@ func (c *RestClient) InstanceInstantiate(ctx context.Context, instanceId string,
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return fmt.Errorf("unknown error, status code: %v %v", resp.StatusCode(), string(resp.Body()))
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -437,13 +432,7 @@ func (c *RestClient) InstanceReconfigure(ctx context.Context, r *specs.LcmReconf
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return fmt.Errorf("unknown error, status code: %v %v", resp.StatusCode(), string(resp.Body()))
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -467,13 +456,7 @@ func (c *RestClient) DeleteInstance(ctx context.Context, id string) error {
 	}
 
 	if !resp.IsSuccess() {
-		var errRes ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error %v", errRes.Details)
-			return fmt.Errorf(errRes.Message)
-		}
-		glog.Errorf("Server return unknown error %v", string(resp.Body()))
-		return fmt.Errorf("unknown error, status code: %v %v", resp.StatusCode(), string(resp.Body()))
+		return c.checkError(resp)
 	}
 
 	return nil
@@ -21,7 +21,7 @@ func TestRestClient_GetConsumption(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := tt.client.GetAuthorization()
+			_, err := tt.client.GetAuthorization(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -183,7 +183,7 @@ func (c *RestClient) UpdateExtension(ctx context.Context, spec *specs.SpecExtens
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		SetContext(ctx).SetBody(spec).Post(c.BaseURL + fmt.Sprintf(TcaVmwareUpdateExtensions, eid))
 
 	if err != nil {
@@ -0,0 +1,187 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterExpr single SOL013 attribute filter (op,attr,value...)
+type FilterExpr struct {
+	Op     string
+	Attr   []string
+	Values []string
+}
+
+// Filter SOL013 attribute based filter, a document
+// matches if it matches all expressions.
+type Filter []FilterExpr
+
+// FilterEq returns SOL013 filter that matches attribute value
+func FilterEq(attr string, value string) string {
+	return fmt.Sprintf("(eq,%s,%s)", attr, value)
+}
+
+// ParseFilter parses SOL013 filter, for example
+// (eq,id,5c11bd9c);(cont,vnfInstanceName,test)
+func ParseFilter(filter string) (Filter, error) {
+
+	var f Filter
+	for _, term := range strings.Split(filter, ";") {
+		term = strings.TrimSpace(term)
+		if len(term) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(term, "(") || !strings.HasSuffix(term, ")") {
+			return nil, fmt.Errorf("invalid filter expression %s", term)
+		}
+
+		parts := strings.Split(term[1:len(term)-1], ",")
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid filter expression %s", term)
+		}
+
+		e := FilterExpr{
+			Op:     strings.ToLower(parts[0]),
+			Attr:   strings.FieldsFunc(parts[1], func(r rune) bool { return r == '/' || r == '.' }),
+			Values: parts[2:],
+		}
+		if !isFilterOp(e.Op) {
+			return nil, fmt.Errorf("unsupported filter operator %s", e.Op)
+		}
+
+		f = append(f, e)
+	}
+
+	return f, nil
+}
+
+// isFilterOp return true if SOL013 operator supported
+func isFilterOp(op string) bool {
+	switch op {
+	case "eq", "in", "neq", "nin", "cont", "ncont", "gt", "gte", "lt", "lte":
+		return true
+	}
+	return false
+}
+
+// lookup returns attribute value as string
+func lookup(doc interface{}, attr []string) (string, bool) {
+
+	for _, a := range attr {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if doc, ok = m[a]; !ok {
+			return "", false
+		}
+	}
+
+	switch v := doc.(type) {
+	case string:
+		return v, true
+	case nil:
+		return "", true
+	default:
+		b, _ := json.Marshal(v)
+		return string(b), true
+	}
+}
+
+// compare compares numbers if both are numbers, otherwise strings
+func compare(a string, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// Match return true if expression matches a decoded json document
+func (e FilterExpr) Match(doc interface{}) (bool, error) {
+
+	v, ok := lookup(doc, e.Attr)
+
+	any := func(f func(string) bool) bool {
+		for _, value := range e.Values {
+			if f(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch e.Op {
+	case "eq", "in":
+		return ok && any(func(s string) bool { return v == s }), nil
+	case "neq", "nin":
+		return !ok || !any(func(s string) bool { return v == s }), nil
+	case "cont":
+		return ok && any(func(s string) bool { return strings.Contains(v, s) }), nil
+	case "ncont":
+		return !ok || !any(func(s string) bool { return strings.Contains(v, s) }), nil
+	case "gt":
+		return ok && compare(v, e.Values[0]) > 0, nil
+	case "gte":
+		return ok && compare(v, e.Values[0]) >= 0, nil
+	case "lt":
+		return ok && compare(v, e.Values[0]) < 0, nil
+	case "lte":
+		return ok && compare(v, e.Values[0]) <= 0, nil
+	}
+
+	return false, fmt.Errorf("unsupported filter operator %s", e.Op)
+}
+
+// Match return true if v matches all filter expressions,
+// attributes resolved from v json representation.
+func (f Filter) Match(v interface{}) (bool, error) {
+
+	if len(f) == 0 {
+		return true, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return false, err
+	}
+
+	for _, e := range f {
+		ok, err := e.Match(doc)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilter_Match(t *testing.T) {
+
+	doc := map[string]interface{}{
+		"id":   "5c11bd9c",
+		"name": "unit_test",
+		"meta": map[string]interface{}{"count": 3},
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Empty filter matches", filter: "", want: true},
+		{name: "Equal", filter: "(eq,id,5c11bd9c)", want: true},
+		{name: "Not equal", filter: "(neq,id,5c11bd9c)", want: false},
+		{name: "In list", filter: "(in,name,a,unit_test)", want: true},
+		{name: "Contains", filter: "(cont,name,unit)", want: true},
+		{name: "Nested attribute", filter: "(gt,meta/count,2)", want: true},
+		{name: "Nested attribute dot", filter: "(lte,meta.count,2)", want: false},
+		{name: "All must match", filter: "(eq,id,5c11bd9c);(eq,name,other)", want: false},
+		{name: "Missing attribute", filter: "(eq,other,1)", want: false},
+		{name: "Invalid expression", filter: "eq,id,1", wantErr: true},
+		{name: "Invalid operator", filter: "(like,id,1)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFilter(tt.filter)
+			if err == nil {
+				var ok bool
+				ok, err = f.Match(doc)
+				assert.Equal(t, tt.want, ok)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,14 +19,14 @@ package client
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/golang/glog"
 	"github.com/spyroot/tcactl/lib/client/response"
-	"github.com/spyroot/tcactl/pkg/io"
 	"net/http"
+	"time"
 )
 
 const (
@@ -35,28 +35,15 @@ const (
 )
 
 // Harbor
-func (c *RestClient) HarborAuthenticate() (bool, error) {
+func (c *RestClient) HarborAuthenticate(ctx context.Context) (bool, error) {
 
-	c.Client = resty.New()
-	// loads cert or skip ssl
-	if len(c.CertKey) > 0 && len(c.CertKey) > 0 {
-		if io.FileExists(c.CertFile) && io.FileExists(c.CertKey) {
-			cert, err := tls.LoadX509KeyPair(c.CertFile, c.CertKey)
-			if err != nil {
-				glog.Fatalf("ERROR client certificate: %s", err)
-			}
-			c.Client.SetCertificates(cert)
-		}
-	}
-
-	if c.SkipSsl {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		c.Client.SetTransport(tr)
-	}
+	client := resty.New()
+	client.SetTransport(c.newTransport())
+	makeDefaultHeaders(client)
+	client.OnBeforeRequest(c.authorize)
+	c.setClient(client)
 
-	resp, err := c.Client.R().SetBasicAuth(c.Username, c.Password).
+	resp, err := c.Client.R().SetContext(ctx).SetBasicAuth(c.Username, c.Password).
 		SetHeader("Content-Type", defaultContentType).
 		SetBody(AuthorizationReq{
 			Username: c.Username,
@@ -73,20 +60,25 @@ func (c *RestClient) HarborAuthenticate() (bool, error) {
 	glog.Infof("Response status: %v", resp.Status())
 	glog.Infof("Response time: %v", resp.Time())
 
+	if !resp.IsSuccess() {
+		return false, c.checkError(resp)
+	}
+
 	if resp.StatusCode() == http.StatusOK {
-		c.apiKey = resp.Header().Get(authorizationHeader)
-		return len(c.apiKey) > 0, nil
+		apiKey := resp.Header().Get(authorizationHeader)
+		c.setSession(apiKey, time.Time{})
+		return len(apiKey) > 0, nil
 	}
 
-	return false, fmt.Errorf("server return %v", resp.StatusCode())
+	return false, c.checkError(resp)
 }
 
 // UploadHelm - Uploads Harbor chart
-func (c *RestClient) UploadHelm(csar []byte, fileName string) (bool, error) {
+func (c *RestClient) UploadHelm(ctx context.Context, csar []byte, fileName string) (bool, error) {
 
 	c.GetClient()
 
-	resp, err := c.Client.R().SetBasicAuth(c.Username, c.Password).
+	resp, err := c.Client.R().SetContext(ctx).SetBasicAuth(c.Username, c.Password).
 		SetFileReader("file", fileName, bytes.NewReader(csar)).
 		SetHeader("Content-Type", "application/zip").
 		SetContentLength(true).
@@ -110,7 +102,7 @@ func (c *RestClient) UploadHelm(csar []byte, fileName string) (bool, error) {
 }
 
 // GetCharts - return all charts
-func (c *RestClient) GetCharts() ([]response.HelmChart, error) {
+func (c *RestClient) GetCharts(ctx context.Context) ([]response.HelmChart, error) {
 
 	c.GetClient()
 	var (
@@ -120,9 +112,9 @@ func (c *RestClient) GetCharts() ([]response.HelmChart, error) {
 	)
 
 	if c.isBasicAuthentication {
-		resp, err = c.Client.R().SetBasicAuth(c.Username, c.Password).Get(c.BaseURL + HarborChartRepo)
+		resp, err = c.Client.R().SetContext(ctx).SetBasicAuth(c.Username, c.Password).Get(c.BaseURL + HarborChartRepo)
 	} else {
-		resp, err = c.Client.R().Get(c.BaseURL + HarborChartRepo)
+		resp, err = c.Client.R().SetContext(ctx).Get(c.BaseURL + HarborChartRepo)
 	}
 
 	if err != nil {
@@ -147,7 +139,7 @@ func (c *RestClient) GetCharts() ([]response.HelmChart, error) {
 }
 
 // GetRepos - return all charts
-func (c *RestClient) GetRepos() ([]response.Repos, error) {
+func (c *RestClient) GetRepos(ctx context.Context) ([]response.Repos, error) {
 
 	c.GetClient()
 	var (
@@ -157,9 +149,9 @@ func (c *RestClient) GetRepos() ([]response.Repos, error) {
 	)
 
 	if c.isBasicAuthentication {
-		resp, err = c.Client.R().SetBasicAuth(c.Username, c.Password).Get(c.BaseURL + HarborChartRepo)
+		resp, err = c.Client.R().SetContext(ctx).SetBasicAuth(c.Username, c.Password).Get(c.BaseURL + HarborChartRepo)
 	} else {
-		resp, err = c.Client.R().Get(c.BaseURL + HarborRepos)
+		resp, err = c.Client.R().SetContext(ctx).Get(c.BaseURL + HarborRepos)
 	}
 
 	if err != nil {
@@ -184,11 +176,11 @@ func (c *RestClient) GetRepos() ([]response.Repos, error) {
 }
 
 // GetChart -
-func (c *RestClient) GetChart(chartName string) (bool, error) {
+func (c *RestClient) GetChart(ctx context.Context, chartName string) (bool, error) {
 
 	c.GetClient()
 
-	resp, err := c.Client.R().Get(c.BaseURL + HarborChartRepo + "/" + chartName)
+	resp, err := c.Client.R().SetContext(ctx).Get(c.BaseURL + HarborChartRepo + "/" + chartName)
 
 	if err != nil {
 		glog.Error(err)
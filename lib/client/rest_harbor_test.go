@@ -18,6 +18,7 @@
 package client
 
 import (
+	"context"
 	"github.com/spyroot/tcactl/pkg/io"
 	"testing"
 )
@@ -39,7 +40,7 @@ func TestRestClient_GetChart(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := tt.client.GetCharts()
+			_, err := tt.client.GetCharts(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TestRestClient_GetChart() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -65,7 +66,7 @@ func TestRestClient_GetRepos(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r, err := tt.client.GetRepos()
+			r, err := tt.client.GetRepos(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TestRestClient_GetChart() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,338 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/glog"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EndpointInfra infra/k8s endpoint family, clusters, node pools and templates
+	EndpointInfra = "infra"
+
+	// EndpointVnfLcm telco/vnflcm endpoint family, CNF and VNF instances
+	EndpointVnfLcm = "vnflcm"
+
+	// EndpointVnfPkgm telco/vnfpkgm endpoint family, catalog
+	EndpointVnfPkgm = "vnfpkgm"
+)
+
+// endpointFamilies url prefix of each endpoint family
+var endpointFamilies = map[string]string{
+	EndpointInfra:   "/hybridity/api/infra/",
+	EndpointVnfLcm:  "/telco/api/vnflcm/",
+	EndpointVnfPkgm: "/telco/api/vnfpkgm/",
+}
+
+// EndpointFamily returns family of TCA endpoint,
+// empty string if path doesn't belong to a family.
+func EndpointFamily(path string) string {
+	for family, prefix := range endpointFamilies {
+		if strings.HasPrefix(path, prefix) {
+			return family
+		}
+	}
+	return ""
+}
+
+// IsEndpointFamily returns true if family is known
+func IsEndpointFamily(family string) bool {
+	_, ok := endpointFamilies[family]
+	return ok
+}
+
+// RateLimit token bucket rate and concurrency limit,
+// zero value of each field means unlimited.
+type RateLimit struct {
+
+	// Rate requests per second
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty" mapstructure:"rate"`
+
+	// Burst max number of requests sent at once, default rate rounded up
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty" mapstructure:"burst"`
+
+	// MaxInFlight max number of concurrent requests
+	MaxInFlight int `json:"max-in-flight,omitempty" yaml:"max-in-flight,omitempty" mapstructure:"max-in-flight"`
+}
+
+// IsUnlimited returns true if limit doesn't restrict requests
+func (l RateLimit) IsUnlimited() bool {
+	return l.Rate <= 0 && l.MaxInFlight <= 0
+}
+
+// limiter token bucket and semaphore of a single scope
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// pausedUntil time server asked to hold requests
+	pausedUntil time.Time
+
+	slots chan struct{}
+	lock  sync.Mutex
+}
+
+// newLimiter returns limiter, bucket is full at start
+func newLimiter(l RateLimit) *limiter {
+
+	r := &limiter{rate: l.Rate}
+	if l.Rate > 0 {
+		r.burst = float64(l.Burst)
+		if r.burst < 1 {
+			r.burst = math.Max(1, math.Ceil(l.Rate))
+		}
+		r.tokens = r.burst
+	}
+	if l.MaxInFlight > 0 {
+		r.slots = make(chan struct{}, l.MaxInFlight)
+	}
+
+	return r
+}
+
+// reserve takes a token and returns time caller must wait before
+// sending a request, either for a token or end of a pause.
+func (l *limiter) reserve(now time.Time) time.Duration {
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var wait time.Duration
+	if now.Before(l.pausedUntil) {
+		wait = l.pausedUntil.Sub(now)
+	}
+
+	if l.rate <= 0 {
+		return wait
+	}
+
+	if l.last.IsZero() {
+		l.last = now
+	}
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+		l.last = now
+	}
+
+	l.tokens--
+	if l.tokens < 0 {
+		if d := time.Duration(-l.tokens / l.rate * float64(time.Second)); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+// pause holds all requests until a time
+func (l *limiter) pause(until time.Time) {
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// acquire waits for a token and a free slot
+func (l *limiter) acquire(ctx context.Context, now time.Time) error {
+
+	if wait := l.reserve(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if l.slots == nil {
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// RateLimiter limits rate and number of concurrent requests to TCA,
+// globally and per endpoint family. If TCA responds with Retry-After
+// requests of the same family held until time server asked.
+type RateLimiter struct {
+	global   *limiter
+	families map[string]*limiter
+	now      func() time.Time
+}
+
+// NewRateLimiter returns rate limiter, families maps
+// endpoint family to its limit.
+func NewRateLimiter(global RateLimit, families map[string]RateLimit) *RateLimiter {
+
+	r := &RateLimiter{
+		global:   newLimiter(global),
+		families: make(map[string]*limiter),
+		now:      time.Now,
+	}
+
+	for family, limit := range families {
+		r.families[family] = newLimiter(limit)
+	}
+
+	return r
+}
+
+// limiters returns limiters request must pass, family first.
+func (r *RateLimiter) limiters(req *http.Request) []*limiter {
+	if l, ok := r.families[EndpointFamily(req.URL.Path)]; ok {
+		return []*limiter{l, r.global}
+	}
+	return []*limiter{r.global}
+}
+
+// Wrap returns transport that limits requests, wrap
+// is client.TransportWrapper.
+func (r *RateLimiter) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &limitTransport{limiter: r, next: next}
+}
+
+// limitTransport round tripper that waits for rate limiter
+type limitTransport struct {
+	limiter *RateLimiter
+	next    http.RoundTripper
+}
+
+// RoundTrip waits for a token and a free slot, slot
+// released when caller closes respond body.
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	limiters := t.limiter.limiters(req)
+	for i, l := range limiters {
+		if err := l.acquire(req.Context(), t.limiter.now()); err != nil {
+			for _, acquired := range limiters[:i] {
+				acquired.release()
+			}
+			return nil, err
+		}
+	}
+
+	release := func() {
+		for _, l := range limiters {
+			l.release()
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return resp, err
+	}
+
+	if wait, ok := retryAfter(resp, t.limiter.now()); ok {
+		glog.Warningf("%s %s server asked to retry after %v", req.Method, req.URL.Path, wait)
+		limiters[0].pause(t.limiter.now().Add(wait))
+	}
+
+	if resp.Body == nil {
+		release()
+		return resp, nil
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseBody releases limiter slots once body closed
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// retryAfter returns time server asked to wait before next request,
+// Retry-After honoured for 429 and 503 respond, in seconds or http date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+
+	if resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if sec, err := strconv.Atoi(v); err == nil {
+		if sec < 0 {
+			return 0, false
+		}
+		return time.Duration(sec) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// retryAfterFunc returns resty callback that waits
+// time server asked, otherwise resty backoff used.
+func retryAfterFunc(_ *resty.Client, r *resty.Response) (time.Duration, error) {
+	if r == nil || r.RawResponse == nil {
+		return 0, nil
+	}
+	wait, _ := retryAfter(r.RawResponse, time.Now())
+	return wait, nil
+}
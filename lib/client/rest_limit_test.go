@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointFamily(t *testing.T) {
+	assert.Equal(t, EndpointInfra, EndpointFamily(TcaInfraClusters))
+	assert.Equal(t, EndpointVnfLcm, EndpointFamily(TcaVmwareVnflcmInstances))
+	assert.Equal(t, EndpointVnfPkgm, EndpointFamily(TcaVmwareTelcoPackages))
+	assert.Equal(t, "", EndpointFamily(uriAuthorize))
+}
+
+func TestLimiter_reserve(t *testing.T) {
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	l := newLimiter(RateLimit{Rate: 2})
+
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(now), "bucket empty")
+	assert.Equal(t, time.Second, l.reserve(now))
+
+	// two tokens refilled, two requests already waiting
+	now = now.Add(time.Second)
+	assert.Equal(t, 500*time.Millisecond, l.reserve(now))
+
+	l.pause(now.Add(3 * time.Second))
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	l.pause(now.Add(3 * time.Second))
+	assert.Equal(t, 3*time.Second, l.reserve(now), "pause must hold request")
+
+	unlimited := newLimiter(RateLimit{})
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), unlimited.reserve(now))
+	}
+}
+
+func TestRateLimiter_MaxInFlight(t *testing.T) {
+
+	var inFlight, maxInFlight, infraInFlight, infraMax int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for m := atomic.LoadInt32(&maxInFlight); n > m && !atomic.CompareAndSwapInt32(&maxInFlight, m, n); {
+			m = atomic.LoadInt32(&maxInFlight)
+		}
+		if EndpointFamily(r.URL.Path) == EndpointInfra {
+			n := atomic.AddInt32(&infraInFlight, 1)
+			defer atomic.AddInt32(&infraInFlight, -1)
+			for m := atomic.LoadInt32(&infraMax); n > m && !atomic.CompareAndSwapInt32(&infraMax, m, n); {
+				m = atomic.LoadInt32(&infraMax)
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(RateLimit{MaxInFlight: 3},
+		map[string]RateLimit{EndpointInfra: {MaxInFlight: 1}})
+	c := &http.Client{Transport: limiter.Wrap(nil)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		path := TcaVmwareVnflcmInstances
+		if i%2 == 0 {
+			path = TcaInfraClusters
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(server.URL + path)
+			if assert.NoError(t, err) {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+	assert.Equal(t, int32(1), infraMax, "infra family limited to single request")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+TcaInfraClusters, nil)
+	_, err := c.Do(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRateLimiter_RetryAfter(t *testing.T) {
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, "key")
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.WaitTime = time.Millisecond
+	policy.MaxWaitTime = 5 * time.Second
+
+	c := newRetryClient(t, server.URL, policy)
+	c.RateLimiter = NewRateLimiter(RateLimit{}, map[string]RateLimit{EndpointInfra: {Rate: 100}})
+	c.Client = nil
+
+	start := time.Now()
+	_, err := c.GetClusters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second), "retry must wait Retry-After")
+	assert.False(t, c.RateLimiter.families[EndpointInfra].pausedUntil.IsZero(), "family must be paused")
+}
+
+func TestRetryAfter(t *testing.T) {
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	resp := func(code int, v string) *http.Response {
+		r := &http.Response{StatusCode: code, Header: http.Header{}}
+		if len(v) > 0 {
+			r.Header.Set("Retry-After", v)
+		}
+		return r
+	}
+
+	d, ok := retryAfter(resp(http.StatusTooManyRequests, "5"), now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = retryAfter(resp(http.StatusServiceUnavailable, now.Add(time.Minute).Format(http.TimeFormat)), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	_, ok = retryAfter(resp(http.StatusOK, "5"), now)
+	assert.False(t, ok)
+
+	_, ok = retryAfter(resp(http.StatusTooManyRequests, ""), now)
+	assert.False(t, ok)
+
+	_, ok = retryAfter(resp(http.StatusTooManyRequests, "soon"), now)
+	assert.False(t, ok)
+}
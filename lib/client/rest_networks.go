@@ -18,6 +18,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
@@ -27,10 +28,10 @@ import (
 
 // GetInfraNetworks - return list of cluster templates
 // TODO
-func (c *RestClient) GetInfraNetworks(tenantId string) (*models.CloudNetworks, error) {
+func (c *RestClient) GetInfraNetworks(ctx context.Context, tenantId string) (*models.CloudNetworks, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().Get(c.BaseURL + TcaVmwareNfvNetworks + "/" + tenantId)
+	resp, err := c.Client.R().SetContext(ctx).Get(c.BaseURL + TcaVmwareNfvNetworks + "/" + tenantId)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -47,7 +47,7 @@ func TestRestClient_GetInfraNetworks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			_, err := tt.client.GetAuthorization()
+			_, err := tt.client.GetAuthorization(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -67,7 +67,7 @@ func TestRestClient_GetInfraNetworks(t *testing.T) {
 			}
 
 			for _, details := range tenants.TenantsList {
-				_, err = tt.client.GetInfraNetworks(details.TenantID)
+				_, err = tt.client.GetInfraNetworks(context.Background(), details.TenantID)
 				if (err != nil) != tt.wantErr {
 					t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 					return
@@ -18,6 +18,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
@@ -51,7 +52,7 @@ type PasswordUpdateSpec struct {
 //}
 
 // GetClusterNodePool returns k8s node pool detail.
-func (c *RestClient) GetClusterNodePool(clusterId string, nodePoolId string) (*response.NodesSpecs, error) {
+func (c *RestClient) GetClusterNodePool(ctx context.Context, clusterId string, nodePoolId string) (*response.NodesSpecs, error) {
 
 	if len(clusterId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
@@ -62,7 +63,7 @@ func (c *RestClient) GetClusterNodePool(clusterId string, nodePoolId string) (*r
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		Get(c.BaseURL + fmt.Sprintf(TcaClustersNodePool, clusterId, nodePoolId))
 
 	if err != nil {
@@ -89,7 +90,7 @@ func (c *RestClient) GetClusterNodePool(clusterId string, nodePoolId string) (*r
 // CreateNewNodePool api method create a new node pool for
 // a target cluster, method return models.TcaTask that progress
 // can be monitored.
-func (c *RestClient) CreateNewNodePool(r *specs.SpecNodePool, clusterId string) (*models.TcaTask, error) {
+func (c *RestClient) CreateNewNodePool(ctx context.Context, r *specs.SpecNodePool, clusterId string) (*models.TcaTask, error) {
 
 	if len(clusterId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
@@ -97,7 +98,7 @@ func (c *RestClient) CreateNewNodePool(r *specs.SpecNodePool, clusterId string)
 
 	c.GetClient()
 
-	resp, err := c.Client.R().SetBody(r).
+	resp, err := c.Client.R().SetContext(ctx).SetBody(r.TcaPayload()).
 		Post(c.BaseURL + fmt.Sprintf(TcaInfraCreatPool, clusterId))
 
 	if err != nil {
@@ -123,7 +124,7 @@ func (c *RestClient) CreateNewNodePool(r *specs.SpecNodePool, clusterId string)
 }
 
 // DeleteNodePool - delete a note pool
-func (c *RestClient) DeleteNodePool(clusterId string, nodePoolId string) (*models.TcaTask, error) {
+func (c *RestClient) DeleteNodePool(ctx context.Context, clusterId string, nodePoolId string) (*models.TcaTask, error) {
 
 	if len(clusterId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
@@ -134,7 +135,7 @@ func (c *RestClient) DeleteNodePool(clusterId string, nodePoolId string) (*model
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().Delete(c.BaseURL + TcaInfraCluster + "/" + clusterId + "/nodepool/" + nodePoolId)
+	resp, err := c.Client.R().SetContext(ctx).Delete(c.BaseURL + TcaInfraCluster + "/" + clusterId + "/nodepool/" + nodePoolId)
 
 	if err != nil {
 		glog.Error(err)
@@ -145,14 +146,8 @@ func (c *RestClient) DeleteNodePool(clusterId string, nodePoolId string) (*model
 		fmt.Println(string(resp.Body()))
 	}
 
-	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusBadRequest {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v %v %v", errRes.Details, errRes.Message, string(resp.Body()))
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
 	}
 
 	var task models.TcaTask
@@ -165,7 +160,7 @@ func (c *RestClient) DeleteNodePool(clusterId string, nodePoolId string) (*model
 }
 
 // UpdateNodePool - update a note pool
-func (c *RestClient) UpdateNodePool(r *specs.SpecNodePool, clusterId string, nodePoolId string) (*models.TcaTask, error) {
+func (c *RestClient) UpdateNodePool(ctx context.Context, r *specs.SpecNodePool, clusterId string, nodePoolId string) (*models.TcaTask, error) {
 
 	if len(clusterId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
@@ -176,7 +171,7 @@ func (c *RestClient) UpdateNodePool(r *specs.SpecNodePool, clusterId string, nod
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(r).Put(c.BaseURL + fmt.Sprintf(TcaInfraUpdatePool, clusterId, nodePoolId))
+	resp, err := c.Client.R().SetContext(ctx).SetBody(r.TcaPayload()).Put(c.BaseURL + fmt.Sprintf(TcaInfraUpdatePool, clusterId, nodePoolId))
 
 	if err != nil {
 		glog.Error(err)
@@ -202,14 +197,14 @@ func (c *RestClient) UpdateNodePool(r *specs.SpecNodePool, clusterId string, nod
 }
 
 // NodePoolRetryTask - retry task related to node pool
-func (c *RestClient) NodePoolRetryTask(taskId string) (*models.TcaTask, error) {
+func (c *RestClient) NodePoolRetryTask(ctx context.Context, taskId string) (*models.TcaTask, error) {
 
 	if len(taskId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().Post(c.BaseURL + fmt.Sprintf(TcaInfraPoolRetry, taskId))
+	resp, err := c.Client.R().SetContext(ctx).Post(c.BaseURL + fmt.Sprintf(TcaInfraPoolRetry, taskId))
 
 	if err != nil {
 		glog.Error(err)
@@ -234,14 +229,14 @@ func (c *RestClient) NodePoolRetryTask(taskId string) (*models.TcaTask, error) {
 }
 
 // NodePoolAbortTask - retry task related to node pool
-func (c *RestClient) NodePoolAbortTask(taskId string) (*models.TcaTask, error) {
+func (c *RestClient) NodePoolAbortTask(ctx context.Context, taskId string) (*models.TcaTask, error) {
 
 	if len(taskId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().Post(c.BaseURL + fmt.Sprintf(TcaInfraPoolAbort, taskId))
+	resp, err := c.Client.R().SetContext(ctx).Post(c.BaseURL + fmt.Sprintf(TcaInfraPoolAbort, taskId))
 
 	if err != nil {
 		glog.Error(err)
@@ -266,10 +261,10 @@ func (c *RestClient) NodePoolAbortTask(taskId string) (*models.TcaTask, error) {
 }
 
 // GetClusterCompatability returns k8s node pool detail.
-func (c *RestClient) GetClusterCompatability() (*SupportedVersion, error) {
+func (c *RestClient) GetClusterCompatability(ctx context.Context) (*SupportedVersion, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		Get(c.BaseURL + TcaInfraSupportedVer)
 
 	if err != nil {
@@ -281,14 +276,8 @@ func (c *RestClient) GetClusterCompatability() (*SupportedVersion, error) {
 		fmt.Println(string(resp.Body()))
 	}
 
-	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusBadRequest {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v %v %v", errRes.Details, errRes.Message, string(resp.Body()))
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
 	}
 
 	var l SupportedVersion
@@ -300,10 +289,10 @@ func (c *RestClient) GetClusterCompatability() (*SupportedVersion, error) {
 }
 
 // UpdateClusterPassword update cluster password.
-func (c *RestClient) UpdateClusterPassword(req *PasswordUpdateSpec, clusterId string) (*models.TcaTask, error) {
+func (c *RestClient) UpdateClusterPassword(ctx context.Context, req *PasswordUpdateSpec, clusterId string) (*models.TcaTask, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(req).
+	resp, err := c.Client.R().SetContext(ctx).SetBody(req).
 		Put(c.BaseURL + fmt.Sprintf(TcaClusterChangePassword, clusterId))
 
 	if err != nil {
@@ -315,14 +304,8 @@ func (c *RestClient) UpdateClusterPassword(req *PasswordUpdateSpec, clusterId st
 		fmt.Println(string(resp.Body()))
 	}
 
-	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusBadRequest {
-		var errRes ErrorResponse
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("server return error %v %v %v", errRes.Details, errRes.Message, string(resp.Body()))
-			return nil, fmt.Errorf("server return error %v", errRes.Message)
-		}
-		glog.Errorf("server return unknown error %v %v", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
 	}
 
 	var l models.TcaTask
@@ -334,7 +317,7 @@ func (c *RestClient) UpdateClusterPassword(req *PasswordUpdateSpec, clusterId st
 }
 
 // UpgradeNodePool - delete a note pool
-func (c *RestClient) UpgradeNodePool(clusterId string, nodePoolId string) (*models.TcaTask, error) {
+func (c *RestClient) UpgradeNodePool(ctx context.Context, clusterId string, nodePoolId string) (*models.TcaTask, error) {
 
 	if len(clusterId) == 0 {
 		return nil, fmt.Errorf("cluster id is empty string")
@@ -345,7 +328,7 @@ func (c *RestClient) UpgradeNodePool(clusterId string, nodePoolId string) (*mode
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().Post(c.BaseURL + fmt.Sprintf(TcaClustersNodePoolUpgrade, clusterId, nodePoolId))
+	resp, err := c.Client.R().SetContext(ctx).Post(c.BaseURL + fmt.Sprintf(TcaClustersNodePoolUpgrade, clusterId, nodePoolId))
 
 	if err != nil {
 		glog.Error(err)
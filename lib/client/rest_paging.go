@@ -0,0 +1,152 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"net/url"
+	"strings"
+)
+
+const (
+	// NextPageMarker SOL013 query parameter server uses
+	// in Link header to point to a next page.
+	NextPageMarker = "nextpage_opaque_marker"
+
+	// queryFilter SOL013 attribute filter query parameter
+	queryFilter = "filter"
+)
+
+// nextPageMarker returns nextpage_opaque_marker from SOL013
+// Link header, i.e. <https://tca/path?nextpage_opaque_marker=xyz>; rel="next"
+// empty string if respond is last page.
+func nextPageMarker(resp *resty.Response) string {
+
+	if resp == nil {
+		return ""
+	}
+
+	for _, h := range resp.Header().Values("Link") {
+		for _, link := range strings.Split(h, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+
+			isNext := false
+			for _, p := range parts[1:] {
+				p = strings.ReplaceAll(strings.TrimSpace(p), "\"", "")
+				if p == "rel=next" {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+			if err != nil {
+				glog.Errorf("Failed parse next page link %v", err)
+				return ""
+			}
+
+			return u.Query().Get(NextPageMarker)
+		}
+	}
+
+	return ""
+}
+
+// getPage dispatch SOL013 list request for a filter and page marker,
+// and returns respond body and marker of a next page.
+func (c *RestClient) getPage(ctx context.Context, uri string, filter string, marker string) ([]byte, string, error) {
+
+	c.GetClient()
+	r := c.Client.R().SetContext(ctx)
+
+	if len(filter) > 0 {
+		r.SetQueryParam(queryFilter, filter)
+	}
+	if len(marker) > 0 {
+		r.SetQueryParam(NextPageMarker, marker)
+	}
+
+	resp, err := r.Get(c.BaseURL + uri)
+	if err != nil {
+		glog.Error(err)
+		return nil, "", err
+	}
+
+	if c.isTrace && resp != nil {
+		fmt.Println(string(resp.Body()))
+	}
+
+	if !resp.IsSuccess() {
+		return nil, "", c.checkError(resp)
+	}
+
+	return resp.Body(), nextPageMarker(resp), nil
+}
+
+// GetVnflcmPage returns single page of CNF/VNF instances.
+// filter is optional SOL013 filter, marker is nextpage_opaque_marker
+// returned by a previous call, empty marker returns a first page.
+// Method returns a marker of next page, empty if it last page.
+func (c *RestClient) GetVnflcmPage(ctx context.Context, filter string, marker string) (*response.CnfsExtended, string, error) {
+
+	body, next, err := c.getPage(ctx, TcaApiVnfLcmExtensionVnfInstance, filter, marker)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var extended response.CnfsExtended
+	if err := json.Unmarshal(body, &extended.CnfLcms); err != nil {
+		glog.Errorf("Failed parse servers respond. %v", err)
+		return nil, "", err
+	}
+
+	return &extended, next, nil
+}
+
+// GetVnfPkgmPage returns single page of VNF/CNF catalog entities.
+// filter is optional SOL013 filter, marker is nextpage_opaque_marker
+// returned by a previous call, empty marker returns a first page.
+// Method returns a marker of next page, empty if it last page.
+func (c *RestClient) GetVnfPkgmPage(ctx context.Context, filter string, marker string) (*response.VnfPackages, string, error) {
+
+	if c.IsSimulateFailure(TcaVmwareTelcoPackages) {
+		return nil, "", fmt.Errorf("simulated failure in %s req", TcaVmwareTelcoPackages)
+	}
+
+	body, next, err := c.getPage(ctx, TcaVmwareTelcoPackages, filter, marker)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pkgs response.VnfPackages
+	if err := json.Unmarshal(body, &pkgs.Entity); err != nil {
+		glog.Errorf("failed unmarshal %v", err)
+		return nil, "", err
+	}
+
+	return &pkgs, next, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagingServer returns a server that returns instances
+// one per page and links pages via nextpage_opaque_marker.
+func newPagingServer(names []string, filters *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, "key")
+			return
+		}
+
+		*filters = append(*filters, r.URL.Query().Get(queryFilter))
+
+		i, _ := strconv.Atoi(r.URL.Query().Get(NextPageMarker))
+		if i+1 < len(names) {
+			w.Header().Set("Link", fmt.Sprintf("<http://%s%s?%s=%d>; rel=\"next\"",
+				r.Host, r.URL.Path, NextPageMarker, i+1))
+		}
+		_, _ = fmt.Fprintf(w, `[{"id": "%d", "vnfInstanceName": "%s"}]`, i, names[i])
+	}))
+}
+
+func TestRestClient_GetVnflcmPages(t *testing.T) {
+
+	var filters []string
+	names := []string{"a", "b", "c"}
+	server := newPagingServer(names, &filters)
+	defer server.Close()
+
+	c := newRetryClient(t, server.URL, nil)
+	ctx := context.Background()
+
+	page, next, err := c.GetVnflcmPage(ctx, "(eq,vnfInstanceName,a)", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", next)
+	if assert.Len(t, page.CnfLcms, 1) {
+		assert.Equal(t, "a", page.CnfLcms[0].VnfInstanceName)
+	}
+	assert.Equal(t, "(eq,vnfInstanceName,a)", filters[0])
+
+	generic, err := c.GetVnflcm(ctx)
+	assert.NoError(t, err)
+	if assert.NotNil(t, generic) {
+		var got []string
+		for _, lcm := range generic.(*response.CnfsExtended).CnfLcms {
+			got = append(got, lcm.VnfInstanceName)
+		}
+		assert.Equal(t, names, got)
+	}
+}
+
+func TestRestClient_GetVnfPkgmPages(t *testing.T) {
+
+	var filters []string
+	server := newPagingServer([]string{"a", "b"}, &filters)
+	defer server.Close()
+
+	c := newRetryClient(t, server.URL, nil)
+
+	pkgs, err := c.GetVnfPkgm(context.Background(), "(eq,id,0)", "")
+	assert.NoError(t, err)
+	assert.Len(t, pkgs.Entity, 2)
+	assert.Equal(t, []string{"(eq,id,0)", "(eq,id,0)"}, filters)
+}
+
+func Test_nextPageMarker(t *testing.T) {
+
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{name: "No link", link: "", want: ""},
+		{name: "Next page",
+			link: "<https://tca/telco/api/vnfpkgm/v2/vnf_packages?nextpage_opaque_marker=abc>; rel=\"next\"",
+			want: "abc"},
+		{name: "Multiple links",
+			link: "<https://tca/x?nextpage_opaque_marker=1>; rel=\"prev\", <https://tca/x?nextpage_opaque_marker=3>; rel=next",
+			want: "3"},
+		{name: "Only previous", link: "<https://tca/x?nextpage_opaque_marker=1>; rel=\"prev\"", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &resty.Response{RawResponse: &http.Response{Header: http.Header{}}}
+			if len(tt.link) > 0 {
+				resp.RawResponse.Header.Set("Link", tt.link)
+			}
+			assert.Equal(t, tt.want, nextPageMarker(resp))
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecorderMode mode of recorder transport
+type RecorderMode int
+
+const (
+	// RecorderModeRecord forwards requests and records interactions
+	RecorderModeRecord RecorderMode = iota
+
+	// RecorderModeReplay serves recorded interactions, no request sent
+	RecorderModeReplay
+)
+
+const (
+	// CassetteVersion cassette format version
+	CassetteVersion = 1
+
+	// redacted value of sanitized header or field
+	redacted = "REDACTED"
+)
+
+// sensitiveHeaders headers value of which never recorded
+var sensitiveHeaders = []string{
+	"Authorization", "X-Hm-Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization",
+}
+
+// sensitiveFields json body fields value of which never recorded
+var sensitiveFields = regexp.MustCompile(`(?i)(password|passwd|secret|token|apikey|api_key|privatekey|private_key)`)
+
+// RecordedRequest sanitized request
+type RecordedRequest struct {
+	Method string      `json:"method" yaml:"method"`
+	Url    string      `json:"url" yaml:"url"`
+	Header http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body   string      `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// RecordedResponse sanitized response
+type RecordedResponse struct {
+	StatusCode int           `json:"statusCode" yaml:"statusCode"`
+	Header     http.Header   `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string        `json:"body,omitempty" yaml:"body,omitempty"`
+	Duration   time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Interaction a request and response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request" yaml:"request"`
+	Response RecordedResponse `json:"response" yaml:"response"`
+}
+
+// Cassette list of recorded interactions
+type Cassette struct {
+	Version      int            `json:"version" yaml:"version"`
+	Created      time.Time      `json:"created" yaml:"created"`
+	Interactions []*Interaction `json:"interactions" yaml:"interactions"`
+}
+
+// ReadCassette reads cassette file
+func ReadCassette(fileName string) (*Cassette, error) {
+
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed parse cassette %s: %v", fileName, err)
+	}
+
+	if c.Version != CassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d", c.Version)
+	}
+
+	return &c, nil
+}
+
+// Write writes cassette file, file readable only by owner.
+func (c *Cassette) Write(fileName string) error {
+
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0600)
+}
+
+// Recorder http.RoundTripper that records sanitized interactions to
+// a cassette file or replays interactions from a cassette.
+// In replay mode interactions matched by method and url,
+// repeated requests served in order they were recorded.
+type Recorder struct {
+
+	// Mode record or replay
+	Mode RecorderMode
+
+	// FileName cassette file
+	FileName string
+
+	// Transport transport requests forwarded to in record mode
+	Transport http.RoundTripper
+
+	cassette *Cassette
+	played   map[string]int
+	lock     sync.Mutex
+}
+
+// NewRecorder returns recorder for a cassette file, in replay mode
+// cassette must exist, in record mode file created or overwritten.
+func NewRecorder(fileName string, mode RecorderMode) (*Recorder, error) {
+
+	if len(fileName) == 0 {
+		return nil, fmt.Errorf("cassette file name is empty string")
+	}
+
+	r := &Recorder{
+		Mode:     mode,
+		FileName: fileName,
+		played:   map[string]int{},
+	}
+
+	if mode == RecorderModeReplay {
+		c, err := ReadCassette(fileName)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		return r, nil
+	}
+
+	r.cassette = &Cassette{Version: CassetteVersion, Created: time.Now()}
+	if err := r.cassette.Write(fileName); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Wrap wraps base transport, recorder forwards requests to base.
+// Wrap is a TransportWrapper.
+func (r *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	r.Transport = base
+	return r
+}
+
+// Interactions returns recorded interactions
+func (r *Recorder) Interactions() []*Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.cassette.Interactions
+}
+
+// RoundTrip records or replays a request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Mode == RecorderModeReplay {
+		return r.replay(req)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Url:    req.URL.RequestURI(),
+			Header: sanitizeHeader(req.Header),
+			Body:   sanitizeBody(req.Header.Get("Content-Type"), body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     sanitizeHeader(resp.Header),
+			Body:       sanitizeBody(resp.Header.Get("Content-Type"), respBody),
+			Duration:   time.Since(start),
+		},
+	})
+
+	if err := r.cassette.Write(r.FileName); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// replay serves next recorded interaction for a request
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// repeated requests served in order, once recorded
+	// interactions exhausted last one served again.
+	key := req.Method + " " + req.URL.RequestURI()
+	var match *Interaction
+
+	n := 0
+	for _, i := range r.cassette.Interactions {
+		if i.Request.Method+" "+i.Request.Url != key {
+			continue
+		}
+		match = i
+		if n == r.played[key] {
+			break
+		}
+		n++
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("cassette %s has no interaction for %s", r.FileName, key)
+	}
+	r.played[key]++
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        match.Response.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(match.Response.Body)),
+		ContentLength: int64(len(match.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads and restores request body
+func readRequestBody(req *http.Request) ([]byte, error) {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+// sanitizeHeader returns copy of header with sensitive values redacted
+func sanitizeHeader(h http.Header) http.Header {
+
+	if h == nil {
+		return nil
+	}
+
+	c := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if len(c.Values(k)) > 0 {
+			c.Set(k, redacted)
+		}
+	}
+
+	return c
+}
+
+// sanitizeBody redacts sensitive fields of json body,
+// binary bodies such as packages are not recorded.
+func sanitizeBody(contentType string, body []byte) string {
+
+	if len(body) == 0 {
+		return ""
+	}
+
+	if strings.Contains(contentType, "zip") ||
+		strings.Contains(contentType, "octet-stream") ||
+		strings.HasPrefix(contentType, "multipart/") {
+		return fmt.Sprintf("<%d bytes not recorded>", len(body))
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(redact(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+// redact replaces values of sensitive fields
+func redact(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if sensitiveFields.MatchString(k) {
+				if _, ok := val.(string); ok {
+					t[k] = redacted
+					continue
+				}
+			}
+			t[k] = redact(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+
+	return v
+}
+
+// NewRecorderFromEnv returns recorder if env variable names cassette file,
+// record selects record mode, otherwise nil.
+func NewRecorderFromEnv(env string, record bool) (*Recorder, error) {
+
+	fileName := os.Getenv(env)
+	if len(fileName) == 0 {
+		return nil, nil
+	}
+
+	if record {
+		return NewRecorder(fileName, RecorderModeRecord)
+	}
+
+	return NewRecorder(fileName, RecorderModeReplay)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_RecordReplay(t *testing.T) {
+
+	f := &fakeSessionServer{}
+	server := httptest.NewServer(f)
+
+	cassette := filepath.Join(t.TempDir(), "cassette.yaml")
+	recorder, err := NewRecorder(cassette, RecorderModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewRestClient(server.URL, false, "admin", "secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = nil
+	c.WrapTransport(recorder.Wrap)
+
+	if ok, err := c.GetAuthorization(context.Background()); !ok || err != nil {
+		t.Fatalf("GetAuthorization() ok = %v error = %v", ok, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetVnfPkgmContent(context.Background(), "pkg"); err != nil {
+			t.Fatalf("GetVnfPkgmContent() error = %v", err)
+		}
+	}
+	server.Close()
+
+	if n := len(recorder.Interactions()); n != 3 {
+		t.Fatalf("recorded %d interactions, want 3", n)
+	}
+
+	b, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"secret-password", "key-1", server.Listener.Addr().String()} {
+		if strings.Contains(string(b), s) {
+			t.Errorf("cassette contains %s", s)
+		}
+	}
+
+	// replay, server is closed, all responds served from cassette
+	replay, err := NewRecorder(cassette, RecorderModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = NewRestClient(server.URL, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = nil
+	c.WrapTransport(replay.Wrap)
+
+	if ok, err := c.GetAuthorization(context.Background()); !ok || err != nil {
+		t.Fatalf("replay GetAuthorization() ok = %v error = %v", ok, err)
+	}
+	content, err := c.GetVnfPkgmContent(context.Background(), "pkg")
+	if err != nil {
+		t.Fatalf("replay GetVnfPkgmContent() error = %v", err)
+	}
+	if string(content) != "content" {
+		t.Errorf("replay GetVnfPkgmContent() = %s, want content", string(content))
+	}
+
+	if _, err := c.GetVnfPkgmContent(context.Background(), "unknown"); err == nil {
+		t.Errorf("replay GetVnfPkgmContent() expected error for request not in cassette")
+	}
+}
+
+func TestRecorder_Sanitize(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"password", "application/json", `{"username":"admin","password":"p"}`, `{"password":"REDACTED","username":"admin"}`},
+		{"nested", "application/json", `{"spec":[{"apiKey":"k","name":"n"}]}`, `{"spec":[{"apiKey":"REDACTED","name":"n"}]}`},
+		{"plain", "text/plain", `content`, `content`},
+		{"binary", "application/zip", `PK`, `<2 bytes not recorded>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeBody(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("sanitizeBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
@@ -27,13 +28,13 @@ import (
 
 // LinkedRepositories - return linked repos to tenant's vim
 // note repo must be linked
-func (c *RestClient) LinkedRepositories(tenantId string, repo string) (string, error) {
+func (c *RestClient) LinkedRepositories(ctx context.Context, tenantId string, repo string) (string, error) {
 
 	if c == nil {
 		return "", fmt.Errorf("uninitialized object")
 	}
 
-	repos, err := c.RepositoriesQuery(&specs.RepoQuery{
+	repos, err := c.RepositoriesQuery(ctx, &specs.RepoQuery{
 		QueryFilter: specs.Filter{
 			ExtraFilter: specs.AdditionalFilters{
 				VimID: tenantId,
@@ -49,7 +50,7 @@ func (c *RestClient) LinkedRepositories(tenantId string, repo string) (string, e
 }
 
 // RepositoriesQuery - query repositories linked to vim
-func (c *RestClient) RepositoriesQuery(query *specs.RepoQuery) (*response.ReposList, error) {
+func (c *RestClient) RepositoriesQuery(ctx context.Context, query *specs.RepoQuery) (*response.ReposList, error) {
 
 	if c == nil {
 		return nil, fmt.Errorf("uninitialized rest client")
@@ -57,7 +58,7 @@ func (c *RestClient) RepositoriesQuery(query *specs.RepoQuery) (*response.ReposL
 
 	c.GetClient()
 
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		SetBody(query).
 		Post(c.BaseURL + TcaVmwareRepositories)
 
@@ -84,7 +85,7 @@ func (c *RestClient) RepositoriesQuery(query *specs.RepoQuery) (*response.ReposL
 }
 
 // GetRepositoriesQuery - query repositories linked to vim
-func (c *RestClient) GetRepositoriesQuery(query *specs.RepoQuery) (*response.ReposList, error) {
+func (c *RestClient) GetRepositoriesQuery(ctx context.Context, query *specs.RepoQuery) (*response.ReposList, error) {
 
 	if c == nil {
 		return nil, fmt.Errorf("uninitialized rest client")
@@ -92,7 +93,7 @@ func (c *RestClient) GetRepositoriesQuery(query *specs.RepoQuery) (*response.Rep
 
 	c.GetClient()
 
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		SetBody(query).
 		Post(c.BaseURL + TcaVmwareRepos)
 
@@ -0,0 +1,168 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/glog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts number of attempts including first request
+	DefaultRetryMaxAttempts = 4
+
+	// DefaultRetryWaitTime initial backoff between attempts
+	DefaultRetryWaitTime = 1 * time.Second
+
+	// DefaultRetryMaxWaitTime max backoff between attempts
+	DefaultRetryMaxWaitTime = 30 * time.Second
+)
+
+// DefaultRetryableCodes status codes TCA or proxy in
+// front of TCA return when appliance temporary unavailable.
+var DefaultRetryableCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy describes how rest client retries idempotent
+// requests that failed with transport error or retryable status code.
+// Backoff grows exponentially from WaitTime up to MaxWaitTime with jitter.
+type RetryPolicy struct {
+
+	// MaxAttempts number of attempts including first request,
+	// 1 or less disables retry.
+	MaxAttempts int
+
+	// WaitTime initial backoff
+	WaitTime time.Duration
+
+	// MaxWaitTime max backoff
+	MaxWaitTime time.Duration
+
+	// RetryableCodes http status codes request retried on
+	RetryableCodes []int
+}
+
+// DefaultRetryPolicy returns default retry policy
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    DefaultRetryMaxAttempts,
+		WaitTime:       DefaultRetryWaitTime,
+		MaxWaitTime:    DefaultRetryMaxWaitTime,
+		RetryableCodes: DefaultRetryableCodes,
+	}
+}
+
+// isIdempotent returns true if request with http method
+// can be sent more than once without side effect.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isReplayable returns false for multipart requests,
+// file readers consumed by first attempt.
+func isReplayable(r *resty.Request) bool {
+	return !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/")
+}
+
+// isRetryableCode returns true if status code is retryable
+func (p *RetryPolicy) isRetryableCode(code int) bool {
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldRetry returns true if policy permits to retry idempotent request
+// failed with transport error or retryable status code. Any request
+// rejected with 429 Too Many Requests retried.
+func (p *RetryPolicy) ShouldRetry(r *resty.Response, err error) bool {
+
+	if p == nil || p.MaxAttempts <= 1 || r == nil || r.Request == nil {
+		return false
+	}
+
+	if r.Request.Attempt >= p.MaxAttempts || !isReplayable(r.Request) {
+		return false
+	}
+
+	// request rejected by rate limit never reached TCA,
+	// so it safe to send it again regardless of method.
+	if !isIdempotent(r.Request.Method) &&
+		(err != nil || r.StatusCode() != http.StatusTooManyRequests || !p.isRetryableCode(r.StatusCode())) {
+		return false
+	}
+
+	if ctx := r.Request.Context(); ctx != nil && ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		glog.Warningf("%s %s attempt %d failed %v, retrying",
+			r.Request.Method, r.Request.URL, r.Request.Attempt, err)
+		return true
+	}
+
+	if p.isRetryableCode(r.StatusCode()) {
+		glog.Warningf("%s %s attempt %d server return %v, retrying",
+			r.Request.Method, r.Request.URL, r.Request.Attempt, r.StatusCode())
+		return true
+	}
+
+	return false
+}
+
+// apply sets retry and backoff on rest client, a request rejected
+// because session expired replayed once regardless of policy.
+func (p *RetryPolicy) apply(client *resty.Client, c *RestClient) {
+
+	retryCount := 1
+	if p != nil {
+		if p.MaxAttempts-1 > retryCount {
+			retryCount = p.MaxAttempts - 1
+		}
+		if p.WaitTime > 0 {
+			client.SetRetryWaitTime(p.WaitTime)
+		}
+		if p.MaxWaitTime > 0 {
+			client.SetRetryMaxWaitTime(p.MaxWaitTime)
+		}
+		// backoff honours Retry-After up to max wait
+		client.SetRetryAfter(retryAfterFunc)
+	}
+
+	client.SetRetryCount(retryCount).
+		AddRetryCondition(func(r *resty.Response, err error) bool {
+			return shouldReplay(r) || p.ShouldRetry(r, err)
+		}).
+		AddRetryHook(c.onUnauthorized)
+}
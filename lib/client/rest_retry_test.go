@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryServer returns a server that fails with failCode
+// first failures requests for every resource.
+func newRetryServer(failures int32, failCode int, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, "key")
+			return
+		}
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(failCode)
+			return
+		}
+		_, _ = w.Write([]byte("content"))
+	}))
+}
+
+func newRetryClient(t *testing.T, url string, policy *RetryPolicy) *RestClient {
+
+	c, err := NewRestClient(url, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = policy
+	if _, err := c.GetAuthorization(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRetryPolicy(t *testing.T) {
+
+	fastPolicy := func(attempts int) *RetryPolicy {
+		p := DefaultRetryPolicy()
+		p.MaxAttempts = attempts
+		p.WaitTime = time.Millisecond
+		p.MaxWaitTime = 5 * time.Millisecond
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		policy    *RetryPolicy
+		failures  int32
+		failCode  int
+		post      bool
+		wantErr   bool
+		wantCalls int32
+	}{
+		{
+			name:      "retry bad gateway",
+			policy:    fastPolicy(3),
+			failures:  2,
+			failCode:  http.StatusBadGateway,
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exhausted",
+			policy:    fastPolicy(3),
+			failures:  5,
+			failCode:  http.StatusServiceUnavailable,
+			wantErr:   true,
+			wantCalls: 3,
+		},
+		{
+			name:      "not retryable code",
+			policy:    fastPolicy(3),
+			failures:  1,
+			failCode:  http.StatusInternalServerError,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "retry disabled",
+			policy:    nil,
+			failures:  1,
+			failCode:  http.StatusBadGateway,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "post not retried",
+			policy:    fastPolicy(3),
+			failures:  1,
+			failCode:  http.StatusBadGateway,
+			post:      true,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "post retried on too many requests",
+			policy:    fastPolicy(3),
+			failures:  1,
+			failCode:  http.StatusTooManyRequests,
+			post:      true,
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var calls int32
+			server := newRetryServer(tt.failures, tt.failCode, &calls)
+			defer server.Close()
+
+			c := newRetryClient(t, server.URL, tt.policy)
+
+			var err error
+			if tt.post {
+				var resp *resty.Response
+				c.GetClient()
+				resp, err = c.Client.R().SetBody("{}").Post(server.URL + "/resource")
+				if err == nil && !resp.IsSuccess() {
+					err = c.checkError(resp)
+				}
+			} else {
+				_, err = c.GetVnfPkgmContent(context.Background(), "pkg")
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_TransportError(t *testing.T) {
+
+	var calls int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, "key")
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// reset connection
+			server.CloseClientConnections()
+			return
+		}
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	p := DefaultRetryPolicy()
+	p.WaitTime = time.Millisecond
+	p.MaxWaitTime = 5 * time.Millisecond
+
+	c := newRetryClient(t, server.URL, p)
+	b, err := c.GetVnfPkgmContent(context.Background(), "pkg")
+	if err != nil {
+		t.Fatalf("GetVnfPkgmContent() error = %v", err)
+	}
+	if string(b) != "content" || calls != 2 {
+		t.Errorf("GetVnfPkgmContent() = %s calls %d, want content and 2", string(b), calls)
+	}
+}
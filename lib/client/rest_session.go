@@ -0,0 +1,254 @@
+// Package client
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/glog"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSessionTTL age of a session after which client
+	// authenticates again before next request. TCA expires
+	// session that is idle for 30 minutes.
+	DefaultSessionTTL = 25 * time.Minute
+
+	// SessionCacheFile a file session cache stored in
+	SessionCacheFile = "sessions.json"
+)
+
+// session an api key cached for TCA endpoint and user
+type session struct {
+	ApiKey  string    `json:"apiKey"`
+	Created time.Time `json:"created"`
+}
+
+// SessionCache stores TCA api keys on disk, so consecutive
+// invocations reuse a session instead of authenticating each time.
+// Key of a session is a hash of TCA url and username.
+type SessionCache struct {
+
+	// Dir directory cache file stored in
+	Dir string
+
+	lock sync.Mutex
+}
+
+// NewSessionCache returns a session cache stored in dir
+func NewSessionCache(dir string) *SessionCache {
+	return &SessionCache{Dir: dir}
+}
+
+// DefaultSessionCache returns a session cache stored in ~/.tcactl
+func DefaultSessionCache() (*SessionCache, error) {
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSessionCache(filepath.Join(home, ".tcactl")), nil
+}
+
+// sessionKey returns a cache key for TCA url and username
+func sessionKey(baseUrl string, username string) string {
+	h := sha256.Sum256([]byte(strings.TrimRight(baseUrl, "/") + "|" + username))
+	return hex.EncodeToString(h[:])
+}
+
+// read reads all cached sessions, missing or
+// corrupted cache treated as empty.
+func (s *SessionCache) read() map[string]session {
+
+	sessions := map[string]session{}
+
+	b, err := ioutil.ReadFile(filepath.Join(s.Dir, SessionCacheFile))
+	if err != nil {
+		return sessions
+	}
+
+	if err := json.Unmarshal(b, &sessions); err != nil {
+		glog.Warningf("Ignoring corrupted session cache %v", err)
+		return map[string]session{}
+	}
+
+	return sessions
+}
+
+// write writes all sessions, file readable only by owner
+func (s *SessionCache) write(sessions map[string]session) error {
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(s.Dir, SessionCacheFile), b, 0600)
+}
+
+// Load returns cached api key and time session created
+func (s *SessionCache) Load(baseUrl string, username string) (string, time.Time, bool) {
+
+	if s == nil {
+		return "", time.Time{}, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cached, ok := s.read()[sessionKey(baseUrl, username)]
+	if !ok || len(cached.ApiKey) == 0 {
+		return "", time.Time{}, false
+	}
+
+	return cached.ApiKey, cached.Created, true
+}
+
+// Store stores api key for TCA url and username
+func (s *SessionCache) Store(baseUrl string, username string, apiKey string, created time.Time) error {
+
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sessions := s.read()
+	sessions[sessionKey(baseUrl, username)] = session{ApiKey: apiKey, Created: created}
+
+	return s.write(sessions)
+}
+
+// Delete removes cached api key for TCA url and username
+func (s *SessionCache) Delete(baseUrl string, username string) error {
+
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sessions := s.read()
+	delete(sessions, sessionKey(baseUrl, username))
+
+	return s.write(sessions)
+}
+
+// SessionAge returns time since current session created
+func (c *RestClient) SessionAge() time.Duration {
+	_, created := c.currentSession()
+	if created.IsZero() {
+		return 0
+	}
+	return time.Since(created)
+}
+
+// sessionTTL returns session ttl or default
+func (c *RestClient) sessionTTL() time.Duration {
+	if c.SessionTTL > 0 {
+		return c.SessionTTL
+	}
+	return DefaultSessionTTL
+}
+
+// isSessionExpired returns api key of current session
+// and true if session older than ttl
+func (c *RestClient) isSessionExpired() (string, bool) {
+	apiKey, created := c.currentSession()
+	return apiKey, len(apiKey) > 0 && !created.IsZero() && time.Since(created) > c.sessionTTL()
+}
+
+// isUnauthorized returns true if TCA rejected request because
+// session expired, authorization request itself never matches.
+func isUnauthorized(r *resty.Response) bool {
+
+	if r == nil || r.IsSuccess() {
+		return false
+	}
+
+	if r.Request != nil && strings.HasSuffix(r.Request.URL, uriAuthorize) {
+		return false
+	}
+
+	if r.StatusCode() == http.StatusUnauthorized {
+		return true
+	}
+
+	var errRes ErrorResponse
+	if err := json.Unmarshal(r.Body(), &errRes); err == nil {
+		return strings.ToLower(errRes.Error) == "unauthorized"
+	}
+
+	return false
+}
+
+// renewSession authenticates again unless other request
+// already renewed a session rejected key belongs to.
+func (c *RestClient) renewSession(ctx context.Context, rejectedKey string) error {
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	if apiKey, _ := c.currentSession(); len(rejectedKey) > 0 && rejectedKey != apiKey {
+		return nil
+	}
+
+	glog.Infof("TCA session expired, age %v, authenticating again", c.SessionAge())
+	if err := c.SessionCache.Delete(c.BaseURL, c.Username); err != nil {
+		glog.Warningf("Failed update session cache %v", err)
+	}
+
+	_, err := c.login(ctx)
+	return err
+}
+
+// shouldReplay returns true if request rejected because session
+// expired and request not replayed yet.
+func shouldReplay(r *resty.Response) bool {
+	return isUnauthorized(r) && r.Request.Attempt == 1 && isReplayable(r.Request)
+}
+
+// onUnauthorized retry hook, renews session, so a rejected request
+// replayed once with a new api key attached by authorize.
+func (c *RestClient) onUnauthorized(r *resty.Response, _ error) {
+
+	if !shouldReplay(r) {
+		return
+	}
+
+	if err := c.renewSession(r.Request.Context(), r.Request.Header.Get(authorizationHeader)); err != nil {
+		glog.Errorf("Failed authenticate again %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSessionServer issues a new api key on every login,
+// only last issued key accepted.
+type fakeSessionServer struct {
+	lock     sync.Mutex
+	logins   int
+	requests int
+	key      string
+}
+
+func (f *fakeSessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if r.URL.Path == uriAuthorize {
+		f.logins++
+		f.key = fmt.Sprintf("key-%d", f.logins)
+		w.Header().Set(authorizationHeader, f.key)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	f.requests++
+	if r.Header.Get(authorizationHeader) != f.key {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"Unauthorized","message":"session expired"}`))
+		return
+	}
+
+	_, _ = w.Write([]byte("content"))
+}
+
+// expire invalidates current session on server side
+func (f *fakeSessionServer) expire() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.key = "expired"
+}
+
+func TestSession_ReplayUnauthorized(t *testing.T) {
+
+	f := &fakeSessionServer{}
+	server := httptest.NewServer(f)
+	defer server.Close()
+
+	c, err := NewRestClient(server.URL, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = nil
+
+	if ok, err := c.GetAuthorization(context.Background()); !ok || err != nil {
+		t.Fatalf("GetAuthorization() ok = %v error = %v", ok, err)
+	}
+
+	f.expire()
+
+	b, err := c.GetVnfPkgmContent(context.Background(), "pkg")
+	if err != nil {
+		t.Fatalf("GetVnfPkgmContent() error = %v", err)
+	}
+	if string(b) != "content" {
+		t.Errorf("GetVnfPkgmContent() = %s, want content", string(b))
+	}
+	if f.logins != 2 || f.requests != 2 {
+		t.Errorf("logins = %d requests = %d, want 2 and 2", f.logins, f.requests)
+	}
+	if c.GetApiKey() != "key-2" {
+		t.Errorf("GetApiKey() = %s, want key-2", c.GetApiKey())
+	}
+
+	// server keeps rejecting, request replayed only once
+	f.lock.Lock()
+	f.logins, f.requests = 0, 0
+	f.lock.Unlock()
+
+	c.Password = "wrong"
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		if strings.HasSuffix(r.URL.Path, uriAuthorize) {
+			f.logins++
+			w.Header().Set(authorizationHeader, "rejected")
+			return
+		}
+		f.requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := c.GetVnfPkgmContent(context.Background(), "pkg"); err == nil {
+		t.Errorf("GetVnfPkgmContent() expected error")
+	}
+	if f.logins != 1 || f.requests != 2 {
+		t.Errorf("logins = %d requests = %d, want 1 and 2", f.logins, f.requests)
+	}
+}
+
+func TestSession_Expired(t *testing.T) {
+
+	f := &fakeSessionServer{}
+	server := httptest.NewServer(f)
+	defer server.Close()
+
+	c, err := NewRestClient(server.URL, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = nil
+	c.SessionTTL = time.Minute
+
+	if _, err := c.GetAuthorization(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.sessionCreated = time.Now().Add(-2 * time.Minute)
+	if _, err := c.GetVnfPkgmContent(context.Background(), "pkg"); err != nil {
+		t.Fatalf("GetVnfPkgmContent() error = %v", err)
+	}
+
+	if f.logins != 2 || f.requests != 1 {
+		t.Errorf("logins = %d requests = %d, want 2 and 1", f.logins, f.requests)
+	}
+	if c.SessionAge() > time.Minute {
+		t.Errorf("SessionAge() = %v, expected renewed session", c.SessionAge())
+	}
+}
+
+func TestSession_ConcurrentRenew(t *testing.T) {
+
+	var logins int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uriAuthorize {
+			w.Header().Set(authorizationHeader, fmt.Sprintf("key-%d", atomic.AddInt32(&logins, 1)))
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get(authorizationHeader), "key-") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	c, err := NewRestClient(server.URL, false, "admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RetryPolicy = nil
+	c.SessionTTL = time.Nanosecond
+
+	if _, err := c.GetAuthorization(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// every request finds session expired, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetVnfPkgmContent(context.Background(), "pkg"); err != nil {
+				t.Errorf("GetVnfPkgmContent() error = %v", err)
+			}
+			_ = c.SessionAge()
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&logins) < 2 {
+		t.Errorf("logins = %d, expected session renewed", logins)
+	}
+}
+
+func TestSessionCache(t *testing.T) {
+
+	f := &fakeSessionServer{}
+	server := httptest.NewServer(f)
+	defer server.Close()
+
+	cache := NewSessionCache(t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		c, err := NewRestClient(server.URL, false, "admin", "password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SessionCache = cache
+		if ok, err := c.GetAuthorization(context.Background()); !ok || err != nil {
+			t.Fatalf("GetAuthorization() ok = %v error = %v", ok, err)
+		}
+		if c.GetApiKey() != "key-1" {
+			t.Errorf("GetApiKey() = %s, want key-1", c.GetApiKey())
+		}
+	}
+
+	if f.logins != 1 {
+		t.Errorf("logins = %d, want 1", f.logins)
+	}
+
+	key, _, ok := cache.Load(server.URL, "admin")
+	if !ok || key != "key-1" {
+		t.Errorf("Load() = %s %v, want key-1", key, ok)
+	}
+	if _, _, ok := cache.Load(server.URL, "other"); ok {
+		t.Errorf("Load() expected no session for other user")
+	}
+
+	// expired cached session
+	if err := cache.Store(server.URL, "admin", "key-1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := NewRestClient(server.URL, false, "admin", "password")
+	c.SessionCache = cache
+	if _, err := c.GetAuthorization(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetApiKey() != "key-2" {
+		t.Errorf("GetApiKey() = %s, want key-2", c.GetApiKey())
+	}
+
+	if err := cache.Delete(server.URL, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := cache.Load(server.URL, "admin"); ok {
+		t.Errorf("Load() expected deleted session")
+	}
+}
@@ -18,13 +18,14 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/spyroot/tcactl/lib/api_errors"
 	"github.com/spyroot/tcactl/lib/client/response"
 	"github.com/spyroot/tcactl/lib/client/specs"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -33,10 +34,10 @@ const (
 )
 
 // GetClusterTemplates - return list of all cluster templates
-func (c *RestClient) GetClusterTemplates() (*response.ClusterTemplates, error) {
+func (c *RestClient) GetClusterTemplates(ctx context.Context) (*response.ClusterTemplates, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().Get(c.BaseURL + apiClusterTemplates)
+	resp, err := c.Client.R().SetContext(ctx).Get(c.BaseURL + apiClusterTemplates)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -60,10 +61,10 @@ func (c *RestClient) GetClusterTemplates() (*response.ClusterTemplates, error) {
 
 // CreateClusterTemplate - creates cluster template from
 // json or yaml specs.
-func (c *RestClient) CreateClusterTemplate(spec *specs.SpecClusterTemplate) error {
+func (c *RestClient) CreateClusterTemplate(ctx context.Context, spec *specs.SpecClusterTemplate) error {
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(spec).Post(c.BaseURL + apiClusterTemplates)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(spec).Post(c.BaseURL + apiClusterTemplates)
 	if err != nil {
 		glog.Error(err)
 		return err
@@ -74,14 +75,7 @@ func (c *RestClient) CreateClusterTemplate(spec *specs.SpecClusterTemplate) erro
 	}
 
 	if !resp.IsSuccess() {
-		var errResp ErrorsResponse
-		if err = json.Unmarshal(resp.Body(), &errResp); err == nil {
-			errs := errResp.GetErrors()
-			return fmt.Errorf(strings.Join(errs, "\n"))
-		} else {
-			glog.Errorf("Failed to parse error respond. %v", err)
-		}
-		return fmt.Errorf("unknown error, status code: %v ", resp.StatusCode())
+		return c.checkError(resp)
 	}
 
 	if resp.StatusCode() == http.StatusOK {
@@ -94,10 +88,10 @@ func (c *RestClient) CreateClusterTemplate(spec *specs.SpecClusterTemplate) erro
 // UpdateClusterTemplate - updates existing cluster template
 // Template must be already defined. Template id in a spec used
 // to update template
-func (c *RestClient) UpdateClusterTemplate(spec *specs.SpecClusterTemplate) error {
+func (c *RestClient) UpdateClusterTemplate(ctx context.Context, spec *specs.SpecClusterTemplate) error {
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(spec).Put(c.BaseURL + apiClusterTemplates + "/" + spec.Id)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(spec).Put(c.BaseURL + apiClusterTemplates + "/" + spec.Id)
 	if err != nil {
 		glog.Error(err)
 		return err
@@ -108,14 +102,7 @@ func (c *RestClient) UpdateClusterTemplate(spec *specs.SpecClusterTemplate) erro
 	}
 
 	if !resp.IsSuccess() {
-		var errResp ErrorsResponse
-		if err = json.Unmarshal(resp.Body(), &errResp); err == nil {
-			errs := errResp.GetErrors()
-			return fmt.Errorf(strings.Join(errs, "\n"))
-		} else {
-			glog.Errorf("Failed to parse error respond. %v", err)
-		}
-		return fmt.Errorf("unknown error, status code: %v ", resp.StatusCode())
+		return c.checkError(resp)
 	}
 
 	if resp.StatusCode() == http.StatusOK {
@@ -126,10 +113,10 @@ func (c *RestClient) UpdateClusterTemplate(spec *specs.SpecClusterTemplate) erro
 }
 
 // GetClusterTemplate - return list of cluster templates
-func (c *RestClient) GetClusterTemplate(clusterId string) (*response.ClusterTemplateSpec, error) {
+func (c *RestClient) GetClusterTemplate(ctx context.Context, clusterId string) (*response.ClusterTemplateSpec, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().Get(c.BaseURL + apiClusterTemplates + "/" + clusterId)
+	resp, err := c.Client.R().SetContext(ctx).Get(c.BaseURL + apiClusterTemplates + "/" + clusterId)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -141,7 +128,7 @@ func (c *RestClient) GetClusterTemplate(clusterId string) (*response.ClusterTemp
 
 	// it doesn't responds with not found or proper payload.
 	if resp.StatusCode() == http.StatusInternalServerError {
-		return nil, fmt.Errorf("template not found")
+		return nil, api_errors.NewTemplateNotFound(clusterId)
 	}
 
 	if !resp.IsSuccess() {
@@ -157,10 +144,10 @@ func (c *RestClient) GetClusterTemplate(clusterId string) (*response.ClusterTemp
 }
 
 // DeleteClusterTemplate - deletes cluster template
-func (c *RestClient) DeleteClusterTemplate(clusterId string) error {
+func (c *RestClient) DeleteClusterTemplate(ctx context.Context, clusterId string) error {
 
 	c.GetClient()
-	resp, err := c.Client.R().Delete(c.BaseURL + apiClusterTemplates + "/" + clusterId)
+	resp, err := c.Client.R().SetContext(ctx).Delete(c.BaseURL + apiClusterTemplates + "/" + clusterId)
 	if err != nil {
 		glog.Error(err)
 		return err
@@ -89,10 +89,10 @@ func (c *RestClient) GetVim(ctx context.Context, vimId string) (*response.Tenant
 
 // GetTenantsQuery returns list of cloud provider attached to TCA.
 // tenant filter , filter result
-func (c *RestClient) GetTenantsQuery(f *specs.TenantsNfFilter) (*response.Tenants, error) {
+func (c *RestClient) GetTenantsQuery(ctx context.Context, f *specs.TenantsNfFilter) (*response.Tenants, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(f).SetQueryString(apiTenantAction).Post(c.BaseURL + apiTenants)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(f).SetQueryString(apiTenantAction).Post(c.BaseURL + apiTenants)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -116,12 +116,12 @@ func (c *RestClient) GetTenantsQuery(f *specs.TenantsNfFilter) (*response.Tenant
 }
 
 // RegisterCloudProvider method register new cloud provider.
-func (c *RestClient) RegisterCloudProvider(r *specs.SpecCloudProvider) (*models.RegistrationRespond, error) {
+func (c *RestClient) RegisterCloudProvider(ctx context.Context, r *specs.SpecCloudProvider) (*models.RegistrationRespond, error) {
 
 	glog.Infof("Cloud provider registration request %s", r.HcxCloudUrl)
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(r).Post(c.BaseURL + apiTenants)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(r).Post(c.BaseURL + apiTenants)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -145,12 +145,12 @@ func (c *RestClient) RegisterCloudProvider(r *specs.SpecCloudProvider) (*models.
 }
 
 // DeleteTenant delete tenant cluster
-func (c *RestClient) DeleteTenant(tenantCluster string) (*models.TcaTask, error) {
+func (c *RestClient) DeleteTenant(ctx context.Context, tenantCluster string) (*models.TcaTask, error) {
 
 	glog.Infof("Deleting tenant cluster %v", tenantCluster)
 
 	c.GetClient()
-	resp, err := c.Client.R().Delete(c.BaseURL + fmt.Sprintf(TcaDeleteTenant, tenantCluster))
+	resp, err := c.Client.R().SetContext(ctx).Delete(c.BaseURL + fmt.Sprintf(TcaDeleteTenant, tenantCluster))
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -134,7 +134,7 @@ func (c *RestClient) GetVMwareTemplates(ctx context.Context, f *specs.VMwareTemp
 
 // GetVMwareFolders - return VMware folder view
 // Typically Query filters based on cloud provider id.
-func (c *RestClient) GetVMwareFolders(f *specs.VmwareFolderQuery) (*models.Folders, error) {
+func (c *RestClient) GetVMwareFolders(ctx context.Context, f *specs.VmwareFolderQuery) (*models.Folders, error) {
 
 	if f == nil {
 		glog.Error("folder filter query is nil")
@@ -142,7 +142,7 @@ func (c *RestClient) GetVMwareFolders(f *specs.VmwareFolderQuery) (*models.Folde
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(f).Post(c.BaseURL + TcaVmwareVmContainers)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(f).Post(c.BaseURL + TcaVmwareVmContainers)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
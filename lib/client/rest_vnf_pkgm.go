@@ -19,6 +19,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
@@ -74,9 +75,9 @@ type PackageCreatedSuccess struct {
 }
 
 // GetPackageCatalogId api return vnf package id and vnfd id
-func (c *RestClient) GetPackageCatalogId(IdOrName string) (string, string, error) {
+func (c *RestClient) GetPackageCatalogId(ctx context.Context, IdOrName string) (string, string, error) {
 
-	vnfCatalog, err := c.GetVnfPkgm("", "")
+	vnfCatalog, err := c.GetVnfPkgm(ctx, "", "")
 	if err != nil || vnfCatalog == nil {
 		glog.Errorf("Failed acquire package catalog information. %v", err)
 		return "", "", err
@@ -92,37 +93,53 @@ func (c *RestClient) GetPackageCatalogId(IdOrName string) (string, string, error
 }
 
 // GetAllCatalog TCA api call return entire catalog
-func (c *RestClient) GetAllCatalog() (*response.VnfPackages, error) {
-	return c.GetVnfPkgm("", "")
+func (c *RestClient) GetAllCatalog(ctx context.Context) (*response.VnfPackages, error) {
+	return c.GetVnfPkgm(ctx, "", "")
 }
 
 // GetVnfPkgm gets VNF/CNF catalog entity
 // pkgId is catalog id and filter is optional argument
-// is filter query
-func (c *RestClient) GetVnfPkgm(filter string, catalogId string) (*response.VnfPackages, error) {
+// is filter query. Without catalog id method follows
+// SOL013 pages and returns entire list.
+func (c *RestClient) GetVnfPkgm(ctx context.Context, filter string, catalogId string) (*response.VnfPackages, error) {
 
 	if c == nil {
 		return nil, fmt.Errorf("rest interface is nil")
 	}
 
+	if len(catalogId) == 0 {
+		var (
+			pkgs   response.VnfPackages
+			marker string
+		)
+		for {
+			page, next, err := c.GetVnfPkgmPage(ctx, filter, marker)
+			if err != nil {
+				return nil, err
+			}
+
+			pkgs.Entity = append(pkgs.Entity, page.Entity...)
+			if len(next) == 0 || next == marker {
+				break
+			}
+			marker = next
+		}
+		return &pkgs, nil
+	}
+
 	c.GetClient()
-	r := c.Client.R()
+	r := c.Client.R().SetContext(ctx)
 
 	if c.IsSimulateFailure(TcaVmwareTelcoPackages) {
 		return nil, fmt.Errorf("simulated failure in %s req", TcaVmwareTelcoPackages)
 	}
 
-	var restReq = c.BaseURL + TcaVmwareTelcoPackages
-	if len(catalogId) != 0 {
-		restReq = c.BaseURL + TcaVmwareTelcoPackages + "/" + catalogId
-	}
-
 	// attach query filter
 	if len(filter) != 0 {
 		r.SetQueryParams(map[string]string{"filter": filter})
 	}
 
-	resp, err := r.Get(restReq)
+	resp, err := r.Get(c.BaseURL + TcaVmwareTelcoPackages + "/" + catalogId)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -133,33 +150,24 @@ func (c *RestClient) GetVnfPkgm(filter string, catalogId string) (*response.VnfP
 	}
 
 	if !resp.IsSuccess() {
-		var errRes response.VnfPackagesError
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			return nil, fmt.Errorf(errRes.Detail)
-		}
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
-	var pkgs response.VnfPackages
-	if len(catalogId) != 0 {
-		var pkg response.VnfPackage
-		if err := json.Unmarshal(resp.Body(), &pkg); err != nil {
-			glog.Errorf("failed unmarshal %v", err)
-			return nil, err
-		}
-		pkgs.Entity = append(pkgs.Entity, pkg)
-	} else {
-		if err := json.Unmarshal(resp.Body(), &pkgs.Entity); err != nil {
-			glog.Errorf("failed unmarshal %v", err)
-			return nil, err
-		}
+	var (
+		pkgs response.VnfPackages
+		pkg  response.VnfPackage
+	)
+	if err := json.Unmarshal(resp.Body(), &pkg); err != nil {
+		glog.Errorf("failed unmarshal %v", err)
+		return nil, err
 	}
+	pkgs.Entity = append(pkgs.Entity, pkg)
 
 	return &pkgs, nil
 }
 
 // GetVnfPkgmVnfd return CNF/VNF catalog entity.
-func (c *RestClient) GetVnfPkgmVnfd(pkgId string) (*response.VduPackage, error) {
+func (c *RestClient) GetVnfPkgmVnfd(ctx context.Context, pkgId string) (*response.VduPackage, error) {
 
 	c.GetClient()
 
@@ -176,7 +184,7 @@ func (c *RestClient) GetVnfPkgmVnfd(pkgId string) (*response.VduPackage, error)
 		restReq = c.BaseURL + "/telco/api/vnfpkgm/v2/vnf_packages/" + pkgId + "/vnfd"
 	}
 
-	r := c.Client.R()
+	r := c.Client.R().SetContext(ctx)
 	resp, err := r.Get(restReq)
 	if err != nil {
 		glog.Error(err)
@@ -188,11 +196,7 @@ func (c *RestClient) GetVnfPkgmVnfd(pkgId string) (*response.VduPackage, error)
 	}
 
 	if !resp.IsSuccess() {
-		var errRes response.VnfPackagesError
-		if err = json.Unmarshal(resp.Body(), &errRes); err == nil {
-			return nil, fmt.Errorf(errRes.Detail)
-		}
-		return nil, fmt.Errorf("unknown error, status code: %v", resp.StatusCode())
+		return nil, c.checkError(resp)
 	}
 
 	var pkg response.VduPackage
@@ -203,11 +207,34 @@ func (c *RestClient) GetVnfPkgmVnfd(pkgId string) (*response.VduPackage, error)
 	return &pkg, nil
 }
 
+// GetVnfPkgmContent - return CSAR of CNF/VNF catalog entity.
+func (c *RestClient) GetVnfPkgmContent(ctx context.Context, pkgId string) ([]byte, error) {
+
+	if len(pkgId) == 0 {
+		return nil, fmt.Errorf("received empty package id")
+	}
+
+	c.GetClient()
+	resp, err := c.Client.R().SetContext(ctx).
+		SetHeader("Accept", UploadMultipartContentType).
+		Get(c.BaseURL + TcaVmwareTelcoPackages + "/" + pkgId + "/package_content")
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, c.checkError(resp)
+	}
+
+	return resp.Body(), nil
+}
+
 // DeleteVnfPkgmVnfd - delete package
-func (c *RestClient) DeleteVnfPkgmVnfd(pkgId string) (bool, error) {
+func (c *RestClient) DeleteVnfPkgmVnfd(ctx context.Context, pkgId string) (bool, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().Delete(c.BaseURL + TcaVmwareTelcoPackages + "/" + pkgId)
+	resp, err := c.Client.R().SetContext(ctx).Delete(c.BaseURL + TcaVmwareTelcoPackages + "/" + pkgId)
 	if err != nil {
 		glog.Error(err)
 		return false, err
@@ -228,10 +255,10 @@ func (c *RestClient) DeleteVnfPkgmVnfd(pkgId string) (bool, error) {
 // CreateVnfPkgmVnfd - create a new CNF/VNF package
 // Note this call only creates catalog entry,
 // entity disabled when it created.
-func (c *RestClient) CreateVnfPkgmVnfd(pkg *PackageUpload) (*PackageCreatedSuccess, error) {
+func (c *RestClient) CreateVnfPkgmVnfd(ctx context.Context, pkg *PackageUpload) (*PackageCreatedSuccess, error) {
 
 	c.GetClient()
-	resp, err := c.Client.R().SetBody(pkg).Post(c.BaseURL + TcaVmwareTelcoPackages)
+	resp, err := c.Client.R().SetContext(ctx).SetBody(pkg).Post(c.BaseURL + TcaVmwareTelcoPackages)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -242,15 +269,6 @@ func (c *RestClient) CreateVnfPkgmVnfd(pkg *PackageUpload) (*PackageCreatedSucce
 	}
 
 	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusBadRequest {
-		var errRes PackageCreateError
-		if err := json.Unmarshal(resp.Body(), &errRes); err == nil {
-			glog.Errorf("Server return error '%s' details '%s' instance '%s'",
-				errRes.Title, errRes.Detail, errRes.Instance)
-			return nil, fmt.Errorf("error '%s', details '%s', instance '%s'",
-				errRes.Title, errRes.Detail, errRes.Instance)
-		} else {
-			glog.Errorf("Failed parse server respond.")
-		}
 		return nil, c.checkError(resp)
 	}
 
@@ -266,7 +284,7 @@ func (c *RestClient) CreateVnfPkgmVnfd(pkg *PackageUpload) (*PackageCreatedSucce
 // UploadVnfPkgmVnfd - Uploads vnf package,
 // note method doesn't check if catalog entity
 // already create or not.
-func (c *RestClient) UploadVnfPkgmVnfd(pkgId string, csar []byte, name string) (bool, error) {
+func (c *RestClient) UploadVnfPkgmVnfd(ctx context.Context, pkgId string, csar []byte, name string) (bool, error) {
 
 	if len(name) == 0 {
 		glog.Error("Received empty name.")
@@ -279,7 +297,7 @@ func (c *RestClient) UploadVnfPkgmVnfd(pkgId string, csar []byte, name string) (
 	}
 
 	c.GetClient()
-	resp, err := c.Client.R().
+	resp, err := c.Client.R().SetContext(ctx).
 		SetFileReader(MultipartFilePart, name, bytes.NewReader(csar)).
 		SetHeader("Content-Type", UploadMultipartContentType).
 		SetContentLength(true).
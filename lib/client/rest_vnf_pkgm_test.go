@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"github.com/spyroot/tcactl/lib/csar"
 	testUtil "github.com/spyroot/tcactl/pkg/testing"
 	"github.com/stretchr/testify/assert"
@@ -28,13 +29,13 @@ func TestRestClient_CreateVnfPkgmVnfd(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			_, err := tt.client.GetAuthorization()
+			_, err := tt.client.GetAuthorization(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
-			respond, err := tt.client.CreateVnfPkgmVnfd(tt.arg)
+			respond, err := tt.client.CreateVnfPkgmVnfd(context.Background(), tt.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -44,7 +45,7 @@ func TestRestClient_CreateVnfPkgmVnfd(t *testing.T) {
 			assert.Equal(t, respond.OnboardingState, "CREATED", "Status must created.")
 			assert.NotEqualValuesf(t, len(respond.Id), 0, "Server must respond with id")
 
-			ok, err := tt.client.DeleteVnfPkgmVnfd(respond.Id)
+			ok, err := tt.client.DeleteVnfPkgmVnfd(context.Background(), respond.Id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -76,7 +77,7 @@ func TestRestClient_UploadVnfPkgmVnfd(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			_, err := tt.client.GetAuthorization()
+			_, err := tt.client.GetAuthorization(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetInfraNetworks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,7 +95,7 @@ func TestRestClient_UploadVnfPkgmVnfd(t *testing.T) {
 			fileBytes, _ := ioutil.ReadAll(file)
 			fileName := filepath.Base(newCsarFile)
 
-			respond, err := tt.client.CreateVnfPkgmVnfd(tt.arg)
+			respond, err := tt.client.CreateVnfPkgmVnfd(context.Background(), tt.arg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TestRestClient_UploadVnfPkgmVnfd() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -104,7 +105,7 @@ func TestRestClient_UploadVnfPkgmVnfd(t *testing.T) {
 			assert.Equal(t, respond.OnboardingState, "CREATED", "Status must created.")
 			assert.NotEqualValuesf(t, len(respond.Id), 0, "Server must respond with id")
 
-			ok, err := tt.client.UploadVnfPkgmVnfd(respond.Id, fileBytes, fileName)
+			ok, err := tt.client.UploadVnfPkgmVnfd(context.Background(), respond.Id, fileBytes, fileName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TestRestClient_UploadVnfPkgmVnfd() error = %v, wantErr %v", err, tt.wantErr)
 				return
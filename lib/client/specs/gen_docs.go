@@ -0,0 +1,135 @@
+//go:build ignore
+// +build ignore
+
+// Package main
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+// gen_docs collects doc comments of spec types and fields
+// and writes spec_docs.go, used by json schema generator.
+// Run go generate in specs package after spec doc changes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// packages that hold spec types, relative to specs package
+var docPackages = []string{".", "../../models"}
+
+// docText returns comment text in a single line,
+// trims a leading type or field name and // separators.
+func docText(name string, groups ...*ast.CommentGroup) string {
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		s := strings.Join(strings.Fields(g.Text()), " ")
+		s = strings.TrimPrefix(s, name)
+		s = strings.TrimLeft(s, " -:")
+		if strings.IndexFunc(s, unicode.IsLetter) >= 0 {
+			return s
+		}
+	}
+	return ""
+}
+
+func main() {
+
+	docs := map[string]string{}
+
+	for _, dir := range docPackages {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for pkgName, pkg := range pkgs {
+			if strings.HasSuffix(pkgName, "_test") {
+				continue
+			}
+			for fileName, f := range pkg.Files {
+				if strings.HasSuffix(fileName, "_test.go") {
+					continue
+				}
+				for _, decl := range f.Decls {
+					gen, ok := decl.(*ast.GenDecl)
+					if !ok || gen.Tok != token.TYPE {
+						continue
+					}
+					for _, s := range gen.Specs {
+						ts := s.(*ast.TypeSpec)
+						st, ok := ts.Type.(*ast.StructType)
+						if !ok || !ts.Name.IsExported() {
+							continue
+						}
+						typeName := pkgName + "." + ts.Name.Name
+						if d := docText(ts.Name.Name, ts.Doc, gen.Doc); len(d) > 0 {
+							docs[typeName] = d
+						}
+						for _, field := range st.Fields.List {
+							for _, name := range field.Names {
+								if !name.IsExported() {
+									continue
+								}
+								if d := docText(name.Name, field.Doc, field.Comment); len(d) > 0 {
+									docs[typeName+"."+name.Name] = d
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_docs.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package specs\n\n")
+	buf.WriteString("// specDocs doc comments of spec types and fields,\n")
+	buf.WriteString("// keyed by package.Type and package.Type.Field\n")
+	buf.WriteString("var specDocs = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, docs[k])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("spec_docs.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -93,21 +93,50 @@ type ClusterConfig struct {
 
 // SpecCluster new cluster creation request
 type SpecCluster struct {
-	SpecType            SpecType                 `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
-	Name                string                   `json:"name" yaml:"name" valid:"required~name is mandatory spec field"`
-	ClusterPassword     string                   `json:"clusterPassword" yaml:"clusterPassword" valid:"required~clusterPassword is mandatory spec field"`
-	ClusterTemplateId   string                   `json:"clusterTemplateId" yaml:"clusterTemplateId" valid:"required~clusterTemplateId is mandatory spec field"`
-	ClusterType         string                   `json:"clusterType" yaml:"clusterType" valid:"required~clusterType is mandatory spec field"`
-	Description         string                   `json:"description,omitempty" yaml:"description,omitempty"`
-	Location            *models.Location         `json:"location,omitempty" yaml:"location,omitempty"`
-	ClusterConfig       *ClusterConfig           `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
-	HcxCloudUrl         string                   `json:"hcxCloudUrl" yaml:"hcxCloudUrl" valid:"required~vmTemplate is mandatory spec field"`
-	EndpointIP          string                   `json:"endpointIP" yaml:"endpointIP" valid:"required~vmTemplate is mandatory spec field"`
-	ManagementClusterId string                   `json:"managementClusterId,omitempty" yaml:"managementClusterId,omitempty"`
-	VmTemplate          string                   `json:"vmTemplate" yaml:"vmTemplate" valid:"required~vmTemplate is mandatory spec field"`
-	MasterNodes         []models.TypeNode        `json:"masterNodes" yaml:"masterNodes" valid:"required"`
-	WorkerNodes         []models.TypeNode        `json:"workerNodes" yaml:"workerNodes" valid:"required"`
-	PlacementParams     []models.PlacementParams `json:"placementParams" yaml:"placementParams" valid:"required"`
+	// SpecType indicate a spec type, must be cluster
+	SpecType SpecType `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
+
+	// Name cluster name
+	Name string `json:"name" yaml:"name" valid:"required~name is mandatory spec field"`
+
+	// ClusterPassword password for cluster nodes
+	ClusterPassword string `json:"clusterPassword" yaml:"clusterPassword" valid:"required~clusterPassword is mandatory spec field"`
+
+	// ClusterTemplateId cluster template name or id
+	ClusterTemplateId string `json:"clusterTemplateId" yaml:"clusterTemplateId" valid:"required~clusterTemplateId is mandatory spec field"`
+
+	// ClusterType management or workload
+	ClusterType string `json:"clusterType" yaml:"clusterType" valid:"required~clusterType is mandatory spec field"`
+
+	// Description optional cluster description
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Location optional cluster location
+	Location *models.Location `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// ClusterConfig csi, tools and system settings of a cluster
+	ClusterConfig *ClusterConfig `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
+
+	// HcxCloudUrl HCX CP full url of a cloud provider
+	HcxCloudUrl string `json:"hcxCloudUrl" yaml:"hcxCloudUrl" valid:"required~vmTemplate is mandatory spec field"`
+
+	// EndpointIP cluster control plane ip address
+	EndpointIP string `json:"endpointIP" yaml:"endpointIP" valid:"required~vmTemplate is mandatory spec field"`
+
+	// ManagementClusterId management cluster name or id, required for workload cluster
+	ManagementClusterId string `json:"managementClusterId,omitempty" yaml:"managementClusterId,omitempty"`
+
+	// VmTemplate vm template path or name in a target cloud
+	VmTemplate string `json:"vmTemplate" yaml:"vmTemplate" valid:"required~vmTemplate is mandatory spec field"`
+
+	// MasterNodes control plane nodes, name must match a cluster template
+	MasterNodes []models.TypeNode `json:"masterNodes" yaml:"masterNodes" valid:"required"`
+
+	// WorkerNodes worker nodes, name must match a cluster template
+	WorkerNodes []models.TypeNode `json:"workerNodes" yaml:"workerNodes" valid:"required"`
+
+	// PlacementParams cluster placement, datacenter, folder, datastore and resource pool
+	PlacementParams []models.PlacementParams `json:"placementParams" yaml:"placementParams" valid:"required"`
 
 	specError error
 }
@@ -0,0 +1,157 @@
+// Code generated by gen_docs.go; DO NOT EDIT.
+
+package specs
+
+// specDocs doc comments of spec types and fields,
+// keyed by package.Type and package.Type.Field
+var specDocs = map[string]string{
+	"models.CnfPolicyUri":                              "CNF policy URI",
+	"models.ConfigurableProperties":                    "ConfigurableProperties",
+	"models.ExtensionTypes":                            "Extension abstraction used by TCA type is type and under sub-type",
+	"models.Folders":                                   "VMware VC folder container view.",
+	"models.Infra":                                     "infrastructure requirement section",
+	"models.InfraRequirements":                         "infrastructure requirements",
+	"models.Interfaces":                                "Vnflcm interfaces",
+	"models.Kernel":                                    "kernel type",
+	"models.KernelType":                                "kernel type name and version",
+	"models.Location":                                  "location details",
+	"models.Network":                                   "Generic Network json struct. it used in many specs",
+	"models.NetworkNotFound":                           "error raised if network not found",
+	"models.NetworkSpec":                               "is network spec returned by API Example \"status\" : \"ACTIVE\", \"tenantId\" : \"20210212053126765-51947d48-91b1-447e-ba40-668eb411f545\", \"id\" : \"dvportgroup-69009\", \"name\" : \"tkg-dhcp-vlan1007-10.241.7.0\", \"dvsName\" : \"core02-services\", \"fullNetworkPath\" : \"/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0\", \"networkType\" : \"vlan\", \"isShared\" : false, \"type\" : \"DistributedVirtualPortgroup\"",
+	"models.NetworkSpec.DvsName":                       "dvsName for example core02-services",
+	"models.NetworkSpec.Id":                            "is vc id dvportgroup-69009",
+	"models.NetworkSpec.Name":                          "is port-group name for example tkg-dhcp-vlan100x-10.x.x.0",
+	"models.NetworkSpec.NetworkType":                   "vlan is vlan type",
+	"models.NetworkSpec.TenantId":                      "tenantId is in format 20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+	"models.NetworkSpec.Type":                          "DistributedVirtualPortgroup",
+	"models.NodeComponents":                            "kernel key",
+	"models.NodePoolConfig":                            "hold all metadata about node pools",
+	"models.NodeTemplates":                             "node template section",
+	"models.PlacementParams":                           "Node Placement",
+	"models.ResourcePool":                              "VMware resource pool container view",
+	"models.SubstitutionMappings":                      "csar file contains a substitution sub-section each section model based based on node_type",
+	"models.TaskErrors":                                "error for give task",
+	"models.TaskPayload":                               "task payload",
+	"models.TaskSteps":                                 "Current execution or executed task list.",
+	"models.TaskTools":                                 "tool provision for task",
+	"models.TcaTask":                                   "generic task respond",
+	"models.TopologyTemplate":                          "topology template section of csar",
+	"models.ToscaProperties":                           "Properties",
+	"models.TypeNode":                                  "Networks",
+	"models.VMwareClusters":                            "Vmware Clusters Container",
+	"models.VMwareDisks":                               "VM template Disk information",
+	"models.VcInventory":                               "list of vc inventory",
+	"models.VimConnection":                             "Connection status",
+	"models.VimConnectionInfo":                         "Contains extra information including vim id , type",
+	"models.VmwareContainerView":                       "tca encapsulates all VMware view to very large json",
+	"specs.AdditionalFilters":                          "Filter for repo query",
+	"specs.ClusterFilterQuery":                         "filter based on endpoint cloud id",
+	"specs.FilterCloud.EndpointId":                     "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+	"specs.InstanceRequestSpec":                        "new instance request",
+	"specs.InstanceRequestSpec.AdditionalParams":       "additional placement details",
+	"specs.InstanceRequestSpec.CloudName":              "target cloud name",
+	"specs.InstanceRequestSpec.ClusterName":            "target cluster name",
+	"specs.InstanceRequestSpec.DoAutoName":             "fix name conflict",
+	"specs.InstanceRequestSpec.FlavorName":             "flavor name",
+	"specs.InstanceRequestSpec.Namespace":              "target Namespace",
+	"specs.InstanceRequestSpec.NfdName":                "catalog name",
+	"specs.InstanceRequestSpec.UseLinkedRepo":          "user linked Repo",
+	"specs.InstanceRequestSpec.VimType":                "VC or K8S vim name",
+	"specs.InvalidCloudSpec":                           "error if cloud provider specs is invalid",
+	"specs.InvalidClusterSpec":                         "error if specs invalid",
+	"specs.InvalidExtensionSpec":                       "error if specs invalid",
+	"specs.InvalidInstanceSpec":                        "error if specs invalid",
+	"specs.InvalidNodePoolSpec":                        "error if specs invalid",
+	"specs.InvalidPoolSpec":                            "error if specs invalid",
+	"specs.InvalidSpecDocument":                        "error raised if a document in multi document spec can't be decoded.",
+	"specs.InvalidSpecDocument.Document":               "index of a document in a file, starts from 1",
+	"specs.InvalidSpecDocument.File":                   "a spec file, empty if spec read from reader",
+	"specs.InvalidSpecDocument.Line":                   "a line where document starts, zero if unknown",
+	"specs.InvalidSpecPatch":                           "error raised if a patch can't be parsed or applied",
+	"specs.InvalidSpecPatch.File":                      "a patch file, empty if patch read from reader",
+	"specs.InvalidSpecTemplate":                        "error raised if a spec template can't be parsed or rendered.",
+	"specs.InvalidSpecTemplate.File":                   "a spec file, empty if spec read from reader",
+	"specs.InvalidTemplateSpec":                        "error if specs invalid",
+	"specs.JsonSchema":                                 "json schema of a spec or spec field, only subset of json schema that spec types need. AdditionalProperties is false for spec objects and a schema of a value for maps.",
+	"specs.SchemaError":                                "a spec error found by offline validation",
+	"specs.SchemaError.File":                           "a spec file, empty if spec read from reader",
+	"specs.SchemaError.Line":                           "and Column a position of an error in a file, starts from 1, column is zero if parser doesn't report it",
+	"specs.SchemaError.Path":                           "dotted path of a field, empty for document level error",
+	"specs.SchemaField":                                "a single field of a spec, used to explain a spec kind",
+	"specs.SchemaField.Description":                    "field doc",
+	"specs.SchemaField.Path":                           "dotted path of a field, list items denoted by []",
+	"specs.SchemaField.Required":                       "true if field is mandatory",
+	"specs.SchemaField.Type":                           "json schema type",
+	"specs.SpecAccessInfo.Password":                    "is base64 encoded string",
+	"specs.SpecAccessInfo.Username":                    "for Harbor it username that has admin access",
+	"specs.SpecCloudProvider":                          "main spec for cloud provider registration",
+	"specs.SpecCloudProvider.HcxCloudUrl":              "HCX CP full url",
+	"specs.SpecCloudProvider.Password":                 "password used for for SSO domain authentication",
+	"specs.SpecCloudProvider.TenantName":               "for VC it \"DEFAULT\"",
+	"specs.SpecCloudProvider.Username":                 "used used to SSO domain",
+	"specs.SpecCloudProvider.VimName":                  "Name that TCA show",
+	"specs.SpecCluster":                                "new cluster creation request",
+	"specs.SpecCluster.ClusterConfig":                  "csi, tools and system settings of a cluster",
+	"specs.SpecCluster.ClusterPassword":                "password for cluster nodes",
+	"specs.SpecCluster.ClusterTemplateId":              "cluster template name or id",
+	"specs.SpecCluster.ClusterType":                    "management or workload",
+	"specs.SpecCluster.Description":                    "optional cluster description",
+	"specs.SpecCluster.EndpointIP":                     "cluster control plane ip address",
+	"specs.SpecCluster.HcxCloudUrl":                    "HCX CP full url of a cloud provider",
+	"specs.SpecCluster.Location":                       "optional cluster location",
+	"specs.SpecCluster.ManagementClusterId":            "management cluster name or id, required for workload cluster",
+	"specs.SpecCluster.MasterNodes":                    "control plane nodes, name must match a cluster template",
+	"specs.SpecCluster.Name":                           "cluster name",
+	"specs.SpecCluster.PlacementParams":                "cluster placement, datacenter, folder, datastore and resource pool",
+	"specs.SpecCluster.SpecType":                       "indicate a spec type, must be cluster",
+	"specs.SpecCluster.VmTemplate":                     "vm template path or name in a target cloud",
+	"specs.SpecCluster.WorkerNodes":                    "worker nodes, name must match a cluster template",
+	"specs.SpecClusterConfig":                          "cluster config spec is workload cluster config template",
+	"specs.SpecClusterTemplate":                        "Cluster template spec",
+	"specs.SpecClusterTemplate.ClusterConfig":          "cni, csi and tools of a workload cluster",
+	"specs.SpecClusterTemplate.ClusterType":            "MANAGEMENT or WORKLOAD",
+	"specs.SpecClusterTemplate.Description":            "optional template description",
+	"specs.SpecClusterTemplate.Id":                     "template id, populated by TCA",
+	"specs.SpecClusterTemplate.KubernetesVersion":      "kubernetes version of a cluster",
+	"specs.SpecClusterTemplate.MasterNodes":            "control plane node templates",
+	"specs.SpecClusterTemplate.Name":                   "cluster template name",
+	"specs.SpecClusterTemplate.SpecType":               "indicate a spec type, must be template",
+	"specs.SpecClusterTemplate.Tags":                   "template tags",
+	"specs.SpecClusterTemplate.WorkerNodes":            "worker node templates",
+	"specs.SpecCpuManagerPolicy":                       "cluster template parameter cpu manager policy",
+	"specs.SpecExtension":                              "extension such as Harbor registered in TCA",
+	"specs.SpecExtension.Name":                         "is extension name",
+	"specs.SpecExtension.SpecType":                     "indicate a spec type and meet Spec interface requirements.",
+	"specs.SpecExtension.Version":                      "for harbor it 1.x 2.x",
+	"specs.SpecHealthCheck":                            "cluster template parameter health check",
+	"specs.SpecNodePool":                               "a request to create new node pool and attach to a target.",
+	"specs.SpecNodePool.ActiveTasksCount":              "number of active tasks, populated by TCA",
+	"specs.SpecNodePool.CloneMode":                     "linkedClone or fullClone, default linkedClone",
+	"specs.SpecNodePool.Cluster":                       "optional target cluster name or id, it used only by tcactl and never sent to TCA",
+	"specs.SpecNodePool.Config":                        "node configuration, cpu manager policy and health check",
+	"specs.SpecNodePool.Cpu":                           "number of vCPU for each node",
+	"specs.SpecNodePool.Id":                            "node pool id, populated by TCA",
+	"specs.SpecNodePool.IsNodeCustomizationDeprecated": "populated by TCA",
+	"specs.SpecNodePool.Labels":                        "node labels in key=value format",
+	"specs.SpecNodePool.Memory":                        "memory in MB for each node",
+	"specs.SpecNodePool.Name":                          "node pool name",
+	"specs.SpecNodePool.Networks":                      "node networks, label MANAGEMENT is mandatory",
+	"specs.SpecNodePool.Nodes":                         "node pool nodes, populated by TCA",
+	"specs.SpecNodePool.PlacementParams":               "node placement, datacenter, folder, datastore and resource pool",
+	"specs.SpecNodePool.Replica":                       "number of nodes, default 1",
+	"specs.SpecNodePool.SpecType":                      "indicate a spec type, must be node_pool",
+	"specs.SpecNodePool.Status":                        "node pool status, populated by TCA",
+	"specs.SpecNodePool.Storage":                       "disk size in GB for each node",
+	"specs.SpecPatch":                                  "a patch of a cluster, cluster template or node pool spec. Patch field names are yaml names of a spec if patch is yaml and json names if patch is json.",
+	"specs.SpecPatch.File":                             "a patch file, empty if patch read from bytes",
+	"specs.SpecPatch.Kind":                             "spec kind patch applies to, empty if patch applies to any kind",
+	"specs.SpecPatch.Type":                             "patch type, list is json patch, object is strategic merge patch",
+	"specs.SpecRenderer":                               "renders Go template of a spec before spec parsed, template data is .Values that holds values from value files and key=value pairs, and .Env that holds environment variables. In strict mode a missing value is an error, otherwise it rendered as empty string.",
+	"specs.SpecRenderer.Strict":                        "fails rendering if a value is missing",
+	"specs.SpecRenderer.Values":                        "template values",
+	"specs.TaskFilter":                                 "Task filter Query filter",
+	"specs.TenantFilter":                               "filter by nfType nad NfdId",
+	"specs.TenantsNfFilter":                            "filter",
+	"specs.VMwareNetworkQuery":                         "filter",
+	"specs.VmwareFolderQuery":                          "folder view query",
+}
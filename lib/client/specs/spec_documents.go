@@ -0,0 +1,250 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InvalidSpecDocument error raised if a document
+// in multi document spec can't be decoded.
+type InvalidSpecDocument struct {
+	errMsg string
+
+	// File a spec file, empty if spec read from reader
+	File string
+
+	// Document index of a document in a file, starts from 1
+	Document int
+
+	// Line a line where document starts, zero if unknown
+	Line int
+}
+
+func (m *InvalidSpecDocument) Error() string {
+	loc := fmt.Sprintf("document %d", m.Document)
+	if len(m.File) > 0 {
+		loc = m.File + " " + loc
+	}
+	if m.Line > 0 {
+		loc = fmt.Sprintf("%s line %d", loc, m.Line)
+	}
+	return loc + ": " + m.errMsg
+}
+
+// isSpecFile returns true if file extension is a known spec format
+func isSpecFile(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".yaml" || ext == ".yml" || ext == ".json" || ext == ".xml"
+}
+
+// decodeYamlDocuments decodes all yaml documents separated by ---,
+// each document routed by kind field.
+func decodeYamlDocuments(r io.Reader, fileName string) ([]RequestSpec, error) {
+
+	var result []RequestSpec
+
+	decoder := yaml.NewDecoder(r)
+	for doc := 1; ; doc++ {
+
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc}
+		}
+
+		// empty document
+		if len(node.Content) == 0 || node.Content[0].Kind == yaml.ScalarNode && node.Content[0].Tag == "!!null" {
+			continue
+		}
+
+		var k specKind
+		if err := node.Decode(&k); err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc, Line: node.Line}
+		}
+
+		spec, err := NewRequestSpec(k.Kind)
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc, Line: node.Line}
+		}
+
+		if err := node.Decode(spec); err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc, Line: node.Line}
+		}
+
+		if err := spec.Default(); err != nil {
+			return nil, err
+		}
+
+		result = append(result, spec)
+	}
+
+	return result, nil
+}
+
+// decodeJsonDocuments decodes a stream of json documents,
+// each document routed by kind field.
+func decodeJsonDocuments(r io.Reader, fileName string) ([]RequestSpec, error) {
+
+	var result []RequestSpec
+
+	decoder := json.NewDecoder(r)
+	for doc := 1; ; doc++ {
+
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc}
+		}
+
+		var k specKind
+		if err := json.Unmarshal(raw, &k); err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc}
+		}
+
+		spec, err := NewRequestSpec(k.Kind)
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc}
+		}
+
+		if err := json.Unmarshal(raw, spec); err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: doc}
+		}
+
+		if err := spec.Default(); err != nil {
+			return nil, err
+		}
+
+		result = append(result, spec)
+	}
+
+	return result, nil
+}
+
+// RequestSpecsFromReader reads one or more spec documents from a reader,
+// yaml documents separated by ---, json documents concatenated,
+// xml specs are spec elements under specs root element.
+// Each document routed to a spec type by kind field, result
+// returned in same order as documents.
+func RequestSpecsFromReader(r io.Reader, f ...SpecFormatType) ([]RequestSpec, error) {
+
+	if len(f) > 0 && f[0] == Json {
+		return decodeJsonDocuments(r, "")
+	}
+
+	if len(f) > 0 && f[0] == Xml {
+		return decodeXmlDocuments(r, "")
+	}
+
+	return decodeYamlDocuments(r, "")
+}
+
+// RequestSpecsFromString reads one or more spec documents from a string
+func RequestSpecsFromString(s string, f ...SpecFormatType) ([]RequestSpec, error) {
+	return RequestSpecsFromReader(strings.NewReader(s), f...)
+}
+
+// RequestSpecsFromFile reads one or more spec documents from a file,
+// format detected from file extension, default is yaml.
+// File rendered by DefaultRenderer before documents decoded.
+func RequestSpecsFromFile(fileName string) ([]RequestSpec, error) {
+
+	b, err := RenderSpecFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	file := bytes.NewReader(b)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return decodeJsonDocuments(file, fileName)
+	case ".xml":
+		return decodeXmlDocuments(file, fileName)
+	}
+
+	return decodeYamlDocuments(file, fileName)
+}
+
+// specFiles returns a file itself or all spec files in a directory,
+// directory walked recursively, files sorted in lexical order of a path.
+func specFiles(path string) ([]string, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && isSpecFile(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// RequestSpecsFromPath reads all specs from a file or a directory.
+// Directory walked recursively, spec files read in lexical order
+// of a path, so a caller can order specs by a file name.
+func RequestSpecsFromPath(path string) ([]RequestSpec, error) {
+
+	files, err := specFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []RequestSpec
+	for _, f := range files {
+		fileSpecs, err := RequestSpecsFromFile(f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, fileSpecs...)
+	}
+
+	return result, nil
+}
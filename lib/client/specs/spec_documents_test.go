@@ -0,0 +1,128 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Read multi document spec and route each document by kind
+func TestRequestSpecsFromString(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		spec      string
+		format    SpecFormatType
+		wantKinds []SpecType
+		wantErr   bool
+	}{
+		{
+			name:      "yaml multi document",
+			spec:      multiDocSite,
+			format:    Yaml,
+			wantKinds: []SpecType{SpecKindProvider, SpecKindExtension, SpecKindNodePool},
+			wantErr:   false,
+		},
+		{
+			name:      "json stream",
+			spec:      jsonNodeSpec + vimRegistrationJson,
+			format:    Json,
+			wantKinds: []SpecType{SpecKindNodePool, SpecKindProvider},
+			wantErr:   false,
+		},
+		{
+			name:    "unknown kind",
+			spec:    "kind: cluster\n---\nkind: unknown\nname: test\n",
+			format:  Yaml,
+			wantErr: true,
+		},
+		{
+			name:    "no kind",
+			spec:    "name: test\n",
+			format:  Yaml,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := RequestSpecsFromString(tt.spec, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantKinds), len(got))
+			for i, s := range got {
+				assert.Equal(t, tt.wantKinds[i], s.Kind())
+			}
+		})
+	}
+}
+
+// Read all specs from a directory in order of file names
+func TestRequestSpecsFromPath(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "specs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "02-pool.json"), []byte(jsonNodeSpec), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "01-cloud.yaml"), []byte(vimRegistrationYaml), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# site"), 0644))
+
+	got, err := RequestSpecsFromPath(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, SpecKindProvider, got[0].Kind())
+	assert.Equal(t, SpecKindNodePool, got[1].Kind())
+
+	pool, ok := got[1].(*SpecNodePool)
+	assert.True(t, ok)
+	assert.Equal(t, "temp1234", pool.Name)
+}
+
+var multiDocSite = `
+---
+kind: provider
+hcxCloudUrl: https://tca-cp03.cnfdemo.io
+vimName: core
+username: administrator@vsphere.local
+password: VMware1!
+---
+kind: extensions
+name: repo
+version: v2.x
+type: Repository
+extensionSubtype: Harbor
+interfaceInfo:
+  url: https://1.1.1.1
+---
+kind: node_pool
+name: pool01
+cpu: 2
+memory: 16384
+storage: 50
+labels:
+  - type=pool01
+`
@@ -66,22 +66,56 @@ func (m *InvalidPoolSpec) Error() string {
 
 // SpecNodePool - a request to create new node pool and attach to a target.
 type SpecNodePool struct {
-	SpecType                      SpecType                 `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
-	Id                            string                   `json:"id,omitempty" yaml:"id,omitempty"`
-	Name                          string                   `json:"name" yaml:"name" validate:"required" valid:"required~name is mandatory spec field"`
-	CloneMode                     string                   `json:"cloneMode,omitempty" yaml:"clone_mode,omitempty" valid:"required~clone_mode is mandatory spec field"`
-	Cpu                           int                      `json:"cpu" yaml:"cpu" validate:"required" valid:"required~cpu is mandatory spec field"`
-	Memory                        int                      `json:"memory" yaml:"memory" validate:"required" valid:"required~memory is mandatory spec field"`
-	Replica                       int                      `json:"replica" yaml:"replica" validate:"required" valid:"required~replica is mandatory spec field"`
-	Storage                       int                      `json:"storage" yaml:"storage" validate:"required" valid:"required~storage is mandatory spec field"`
-	Labels                        []string                 `json:"labels,omitempty" yaml:"labels" validate:"required" valid:"required~labels is mandatory spec field"`
-	Networks                      []models.Network         `json:"networks" yaml:"networks" validate:"required" valid:"required~networks is mandatory spec field"`
-	PlacementParams               []models.PlacementParams `json:"placementParams" yaml:"placementParams" validate:"required" valid:"required~placementParams is mandatory spec field"`
-	Config                        *models.NodeConfig       `json:"config,omitempty" yaml:"config,omitempty"`
-	Status                        string                   `json:"status" yaml:"status"`
-	Nodes                         []models.Nodes           `json:"nodes,omitempty" yaml:"nodes,omitempty"`
-	ActiveTasksCount              int                      `json:"activeTasksCount" yaml:"activeTasksCount"`
-	IsNodeCustomizationDeprecated bool                     `json:"isNodeCustomizationDeprecated" yaml:"isNodeCustomizationDeprecated"`
+	// SpecType indicate a spec type, must be node_pool
+	SpecType SpecType `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
+
+	// Id node pool id, populated by TCA
+	Id string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Name node pool name
+	Name string `json:"name" yaml:"name" validate:"required" valid:"required~name is mandatory spec field"`
+
+	// Cluster optional target cluster name or id, it used only by tcactl and never sent to TCA
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+
+	// CloneMode linkedClone or fullClone, default linkedClone
+	CloneMode string `json:"cloneMode,omitempty" yaml:"clone_mode,omitempty" valid:"required~clone_mode is mandatory spec field"`
+
+	// Cpu number of vCPU for each node
+	Cpu int `json:"cpu" yaml:"cpu" validate:"required" valid:"required~cpu is mandatory spec field"`
+
+	// Memory memory in MB for each node
+	Memory int `json:"memory" yaml:"memory" validate:"required" valid:"required~memory is mandatory spec field"`
+
+	// Replica number of nodes, default 1
+	Replica int `json:"replica" yaml:"replica" validate:"required" valid:"required~replica is mandatory spec field"`
+
+	// Storage disk size in GB for each node
+	Storage int `json:"storage" yaml:"storage" validate:"required" valid:"required~storage is mandatory spec field"`
+
+	// Labels node labels in key=value format
+	Labels []string `json:"labels,omitempty" yaml:"labels" validate:"required" valid:"required~labels is mandatory spec field"`
+
+	// Networks node networks, label MANAGEMENT is mandatory
+	Networks []models.Network `json:"networks" yaml:"networks" validate:"required" valid:"required~networks is mandatory spec field"`
+
+	// PlacementParams node placement, datacenter, folder, datastore and resource pool
+	PlacementParams []models.PlacementParams `json:"placementParams" yaml:"placementParams" validate:"required" valid:"required~placementParams is mandatory spec field"`
+
+	// Config node configuration, cpu manager policy and health check
+	Config *models.NodeConfig `json:"config,omitempty" yaml:"config,omitempty"`
+
+	// Status node pool status, populated by TCA
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Nodes node pool nodes, populated by TCA
+	Nodes []models.Nodes `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+
+	// ActiveTasksCount number of active tasks, populated by TCA
+	ActiveTasksCount int `json:"activeTasksCount,omitempty" yaml:"activeTasksCount,omitempty"`
+
+	// IsNodeCustomizationDeprecated populated by TCA
+	IsNodeCustomizationDeprecated bool `json:"isNodeCustomizationDeprecated,omitempty" yaml:"isNodeCustomizationDeprecated,omitempty"`
 
 	// specError hold spec validator error
 	specError error
@@ -111,6 +145,17 @@ func (t *SpecNodePool) Kind() SpecType {
 	return t.SpecType
 }
 
+// TcaPayload returns a copy of a spec that sent to TCA,
+// target cluster used only by tcactl removed.
+func (t *SpecNodePool) TcaPayload() *SpecNodePool {
+	if t == nil {
+		return nil
+	}
+	payload := *t
+	payload.Cluster = ""
+	return &payload
+}
+
 //Default  sets all all optional parameter to default value
 func (t *SpecNodePool) Default() error {
 
@@ -1,6 +1,7 @@
 package specs
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"testing"
@@ -108,6 +109,22 @@ func TestNewNodePoolSpec_SpecsFromString(t *testing.T) {
 	}
 }
 
+// Test target cluster read from a json spec and never sent to TCA
+func TestSpecNodePool_TcaPayload(t *testing.T) {
+
+	var spec SpecNodePool
+	assert.NoError(t, json.Unmarshal([]byte(`{"kind": "node_pool", "name": "pool01", "cluster": "edge"}`), &spec))
+	assert.Equal(t, "edge", spec.Cluster)
+
+	b, err := json.Marshal(spec.TcaPayload())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "cluster")
+	assert.Equal(t, "edge", spec.Cluster, "spec itself unchanged")
+
+	var nilSpec *SpecNodePool
+	assert.Nil(t, nilSpec.TcaPayload())
+}
+
 var jsonNodeSpec = `
 {
 	"kind": "node_pool",
@@ -0,0 +1,597 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchType a type of spec patch
+type PatchType string
+
+const (
+	// JsonPatch RFC 6902 list of add, remove, replace, move, copy and test operations
+	JsonPatch PatchType = "json"
+
+	// MergePatch RFC 7386 merge patch, null removes a field, lists replaced
+	MergePatch PatchType = "merge"
+
+	// StrategicMergePatch RFC 7386 merge patch where lists of objects
+	// merged by a merge key instead of being replaced
+	StrategicMergePatch PatchType = "strategic"
+
+	// PatchDirective list item field of strategic merge patch,
+	// value delete removes item with same merge key
+	PatchDirective = "$patch"
+
+	// PatchDelete directive that removes list item
+	PatchDelete = "delete"
+
+	// defaultMergeKey merge key of object lists
+	defaultMergeKey = "name"
+)
+
+// patchMergeKeys merge keys of lists that items have no name
+var patchMergeKeys = map[string]string{
+	"networks":        "label",
+	"placementParams": "type",
+}
+
+// InvalidSpecPatch error raised if a patch can't be parsed or applied
+type InvalidSpecPatch struct {
+	errMsg string
+
+	// File a patch file, empty if patch read from reader
+	File string
+}
+
+func (m *InvalidSpecPatch) Error() string {
+	if len(m.File) > 0 {
+		return m.File + ": " + m.errMsg
+	}
+	return m.errMsg
+}
+
+// SpecPatch a patch of a cluster, cluster template or node pool spec.
+// Patch field names are yaml names of a spec if patch is yaml and
+// json names if patch is json.
+type SpecPatch struct {
+
+	// Type patch type, list is json patch, object is strategic merge patch
+	Type PatchType
+
+	// Kind spec kind patch applies to, empty if patch applies to any kind
+	Kind SpecType
+
+	// File a patch file, empty if patch read from bytes
+	File string
+
+	format SpecFormatType
+	patch  interface{}
+}
+
+// IsPatchable returns true if spec kind support patches
+func IsPatchable(spec RequestSpec) bool {
+	switch spec.(type) {
+	case *SpecCluster, *SpecClusterTemplate, *SpecNodePool:
+		return true
+	}
+	return false
+}
+
+// NewSpecPatch parses yaml or json patch, patch type detected from
+// a document, list is RFC 6902 json patch, object is merge patch
+// where lists merged by name. Kind field of merge patch
+// restricts patch to a spec kind.
+func NewSpecPatch(b []byte, f ...SpecFormatType) (*SpecPatch, error) {
+
+	p := &SpecPatch{format: Yaml}
+	if len(f) > 0 && f[0] == Json {
+		p.format = Json
+	}
+
+	if err := yaml.Unmarshal(b, &p.patch); err != nil {
+		return nil, &InvalidSpecPatch{errMsg: err.Error()}
+	}
+
+	switch v := p.patch.(type) {
+	case []interface{}:
+		p.Type = JsonPatch
+	case map[string]interface{}:
+		p.Type = StrategicMergePatch
+		if kind, ok := v["kind"].(string); ok {
+			p.Kind = SpecType(kind)
+		}
+	default:
+		return nil, &InvalidSpecPatch{errMsg: "patch must be a list of json patch operations or an object"}
+	}
+
+	return p, nil
+}
+
+// SpecPatchFromFile reads a patch file, format detected from file extension.
+// A patch file is rendered by DefaultRenderer as a spec file.
+func SpecPatchFromFile(fileName string) (*SpecPatch, error) {
+
+	b, err := RenderSpecFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	f := Yaml
+	if strings.ToLower(filepath.Ext(fileName)) == ".json" {
+		f = Json
+	}
+
+	p, err := NewSpecPatch(b, f)
+	if err != nil {
+		if e, ok := err.(*InvalidSpecPatch); ok {
+			e.File = fileName
+		}
+		return nil, err
+	}
+	p.File = fileName
+
+	return p, nil
+}
+
+// Matches returns true if patch applies to a spec
+func (p *SpecPatch) Matches(spec RequestSpec) bool {
+	return IsPatchable(spec) && (len(p.Kind) == 0 || p.Kind == spec.Kind())
+}
+
+// specValue converts spec to generic value, field names
+// are yaml or json names.
+func specValue(spec RequestSpec, f SpecFormatType) (interface{}, error) {
+
+	var (
+		b   []byte
+		err error
+		v   interface{}
+	)
+
+	if f == Json {
+		if b, err = json.Marshal(spec); err == nil {
+			err = json.Unmarshal(b, &v)
+		}
+	} else {
+		if b, err = yaml.Marshal(spec); err == nil {
+			err = yaml.Unmarshal(b, &v)
+		}
+	}
+
+	return v, err
+}
+
+// Apply applies patch to a spec and returns a new spec, original spec unchanged.
+func (p *SpecPatch) Apply(spec RequestSpec) (RequestSpec, error) {
+
+	if !IsPatchable(spec) {
+		return nil, api_errors.NewInvalidSpec(fmt.Sprintf("spec kind %s doesn't support patch", spec.Kind()))
+	}
+
+	if !p.Matches(spec) {
+		return nil, &InvalidSpecPatch{errMsg: fmt.Sprintf("patch of %s can't be applied to %s spec", p.Kind, spec.Kind()), File: p.File}
+	}
+
+	v, err := specValue(spec, p.format)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Type {
+	case JsonPatch:
+		v, err = applyJsonPatch(v, p.patch)
+	case MergePatch:
+		v = mergePatch(v, p.patch, "", false)
+	default:
+		v = mergePatch(v, p.patch, "", true)
+	}
+	if err != nil {
+		return nil, &InvalidSpecPatch{errMsg: err.Error(), File: p.File}
+	}
+
+	patched, err := NewRequestSpec(spec.Kind())
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	if p.format == Json {
+		if b, err = json.Marshal(v); err == nil {
+			err = json.Unmarshal(b, patched)
+		}
+	} else {
+		if b, err = yaml.Marshal(v); err == nil {
+			err = yaml.Unmarshal(b, patched)
+		}
+	}
+	if err != nil {
+		return nil, &InvalidSpecPatch{errMsg: err.Error(), File: p.File}
+	}
+
+	return patched, nil
+}
+
+// PatchSpecs applies patches in order to each spec patch matches,
+// specs that don't support patch returned as is. Patch that doesn't
+// match any spec is an error.
+func PatchSpecs(specs []RequestSpec, patches ...*SpecPatch) ([]RequestSpec, error) {
+
+	result := append([]RequestSpec(nil), specs...)
+	for _, p := range patches {
+		applied := false
+		for i, spec := range result {
+			if !p.Matches(spec) {
+				continue
+			}
+			patched, err := p.Apply(spec)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = patched
+			applied = true
+		}
+		if !applied {
+			return nil, &InvalidSpecPatch{errMsg: "patch doesn't match any cluster, template or node pool spec", File: p.File}
+		}
+	}
+
+	return result, nil
+}
+
+// PatchSpecsFromFiles reads patch files and applies to specs
+func PatchSpecsFromFiles(specs []RequestSpec, files ...string) ([]RequestSpec, error) {
+
+	var patches []*SpecPatch
+	for _, f := range files {
+		p, err := SpecPatchFromFile(f)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, p)
+	}
+
+	return PatchSpecs(specs, patches...)
+}
+
+// listMergeKey returns merge key of list items if all items are
+// objects that hold merge key, otherwise empty string.
+func listMergeKey(field string, lists ...[]interface{}) string {
+
+	key, ok := patchMergeKeys[field]
+	if !ok {
+		key = defaultMergeKey
+	}
+
+	for _, l := range lists {
+		for _, item := range l {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			if _, ok := m[key]; !ok {
+				return ""
+			}
+		}
+	}
+
+	return key
+}
+
+// mergeList merges list items by a merge key, patch item with
+// $patch: delete removes item from a list.
+func mergeList(base []interface{}, patch []interface{}, key string) []interface{} {
+
+	result := append([]interface{}(nil), base...)
+	for _, item := range patch {
+
+		pm := item.(map[string]interface{})
+		idx := -1
+		for i, b := range result {
+			if reflect.DeepEqual(b.(map[string]interface{})[key], pm[key]) {
+				idx = i
+				break
+			}
+		}
+
+		if pm[PatchDirective] == PatchDelete {
+			if idx >= 0 {
+				result = append(result[:idx], result[idx+1:]...)
+			}
+			continue
+		}
+
+		if idx >= 0 {
+			result[idx] = mergePatch(result[idx], pm, "", true)
+		} else {
+			result = append(result, mergePatch(nil, pm, "", true))
+		}
+	}
+
+	return result
+}
+
+// mergePatch applies RFC 7386 merge patch to a value, field is a name
+// of a value in a parent object. In strategic mode lists of objects
+// merged by a merge key.
+func mergePatch(base interface{}, patch interface{}, field string, strategic bool) interface{} {
+
+	switch pv := patch.(type) {
+	case map[string]interface{}:
+		bm, ok := base.(map[string]interface{})
+		if !ok {
+			bm = map[string]interface{}{}
+		} else {
+			cp := make(map[string]interface{}, len(bm))
+			for k, v := range bm {
+				cp[k] = v
+			}
+			bm = cp
+		}
+		for k, v := range pv {
+			if k == PatchDirective {
+				continue
+			}
+			if v == nil {
+				delete(bm, k)
+				continue
+			}
+			bm[k] = mergePatch(bm[k], v, k, strategic)
+		}
+		return bm
+	case []interface{}:
+		if bl, ok := base.([]interface{}); ok && strategic {
+			if key := listMergeKey(field, bl, pv); len(key) > 0 {
+				return mergeList(bl, pv, key)
+			}
+		}
+		l := make([]interface{}, len(pv))
+		for i, v := range pv {
+			l[i] = mergePatch(nil, v, "", strategic)
+		}
+		return l
+	}
+
+	return patch
+}
+
+// jsonPointer splits RFC 6901 json pointer to unescaped tokens
+func jsonPointer(path string) ([]string, error) {
+
+	if len(path) == 0 {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid json pointer '%s'", path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// listIndex parses list index token, "-" is an index past the end
+func listIndex(token string, l []interface{}, isAdd bool) (int, error) {
+
+	if token == "-" && isAdd {
+		return len(l), nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (isAdd && i > len(l)) || (!isAdd && i >= len(l)) {
+		return 0, fmt.Errorf("invalid list index '%s'", token)
+	}
+
+	return i, nil
+}
+
+// jsonPointerGet returns value referenced by pointer tokens
+func jsonPointerGet(doc interface{}, tokens []string) (interface{}, error) {
+
+	v := doc
+	for _, t := range tokens {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = c[t]; !ok {
+				return nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+			}
+		case []interface{}:
+			i, err := listIndex(t, c, false)
+			if err != nil {
+				return nil, err
+			}
+			v = c[i]
+		default:
+			return nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+		}
+	}
+
+	return v, nil
+}
+
+// jsonPointerUpdate replaces, adds or removes (value is nil and remove true)
+// a value referenced by pointer tokens, returns updated document.
+func jsonPointerUpdate(doc interface{}, tokens []string, value interface{}, isAdd bool, remove bool) (interface{}, error) {
+
+	if len(tokens) == 0 {
+		if remove {
+			return nil, fmt.Errorf("can't remove document root")
+		}
+		return value, nil
+	}
+
+	t := tokens[0]
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		child, ok := c[t]
+		if len(tokens) == 1 {
+			if !ok && !isAdd {
+				return nil, fmt.Errorf("field %s not found", t)
+			}
+			if remove {
+				delete(c, t)
+			} else {
+				c[t] = value
+			}
+			return c, nil
+		}
+		if !ok {
+			return nil, fmt.Errorf("field %s not found", t)
+		}
+		v, err := jsonPointerUpdate(child, tokens[1:], value, isAdd, remove)
+		if err != nil {
+			return nil, err
+		}
+		c[t] = v
+		return c, nil
+	case []interface{}:
+		i, err := listIndex(t, c, isAdd && len(tokens) == 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			switch {
+			case remove:
+				return append(c[:i:i], c[i+1:]...), nil
+			case isAdd:
+				l := append(c[:i:i], value)
+				return append(l, c[i:]...), nil
+			default:
+				c[i] = value
+				return c, nil
+			}
+		}
+		v, err := jsonPointerUpdate(c[i], tokens[1:], value, isAdd, remove)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = v
+		return c, nil
+	}
+
+	return nil, fmt.Errorf("path element %s not found", t)
+}
+
+// deepCopy copies generic value
+func deepCopy(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, e := range c {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(c))
+		for i, e := range c {
+			l[i] = deepCopy(e)
+		}
+		return l
+	}
+	return v
+}
+
+// jsonEqual compares two generic values, numbers compared by value
+func jsonEqual(a interface{}, b interface{}) bool {
+	ab, err1 := json.Marshal(a)
+	bb, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(ab, bb)
+}
+
+// applyJsonPatch applies RFC 6902 operations in order,
+// patch fails on a first failed operation.
+func applyJsonPatch(doc interface{}, patch interface{}) (interface{}, error) {
+
+	ops, ok := patch.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("json patch must be a list of operations")
+	}
+
+	doc = deepCopy(doc)
+	for n, o := range ops {
+
+		op, ok := o.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("operation %d must be an object", n)
+		}
+
+		name, _ := op["op"].(string)
+		path, _ := op["path"].(string)
+		tokens, err := jsonPointer(path)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %v", n, err)
+		}
+
+		value, hasValue := op["value"]
+		if (name == "add" || name == "replace" || name == "test") && !hasValue {
+			return nil, fmt.Errorf("operation %d: %s requires value", n, name)
+		}
+
+		var from []string
+		if name == "move" || name == "copy" {
+			f, _ := op["from"].(string)
+			if from, err = jsonPointer(f); err != nil {
+				return nil, fmt.Errorf("operation %d: %v", n, err)
+			}
+			if value, err = jsonPointerGet(doc, from); err != nil {
+				return nil, fmt.Errorf("operation %d: %v", n, err)
+			}
+			value = deepCopy(value)
+		}
+
+		switch name {
+		case "add", "copy":
+			doc, err = jsonPointerUpdate(doc, tokens, value, true, false)
+		case "replace":
+			if _, err = jsonPointerGet(doc, tokens); err == nil {
+				doc, err = jsonPointerUpdate(doc, tokens, value, false, false)
+			}
+		case "remove":
+			doc, err = jsonPointerUpdate(doc, tokens, nil, false, true)
+		case "move":
+			if doc, err = jsonPointerUpdate(doc, from, nil, false, true); err == nil {
+				doc, err = jsonPointerUpdate(doc, tokens, value, true, false)
+			}
+		case "test":
+			var actual interface{}
+			if actual, err = jsonPointerGet(doc, tokens); err == nil && !jsonEqual(actual, value) {
+				err = fmt.Errorf("test failed, %s value differs", path)
+			}
+		default:
+			err = fmt.Errorf("unknown operation '%s'", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %v", n, err)
+		}
+	}
+
+	return doc, nil
+}
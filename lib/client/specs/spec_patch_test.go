@@ -0,0 +1,251 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// readTestSpec reads a spec for patch test
+func readTestSpec(t *testing.T, s string, f SpecFormatType) RequestSpec {
+	all, err := RequestSpecsFromString(s, f)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(all))
+	return all[0]
+}
+
+// Strategic merge patch of a cluster, lists merged by name
+func TestSpecPatch_StrategicMerge(t *testing.T) {
+
+	base := readTestSpec(t, patchBaseCluster, Yaml)
+
+	p, err := NewSpecPatch([]byte(patchClusterOverlay))
+	assert.NoError(t, err)
+	assert.Equal(t, StrategicMergePatch, p.Type)
+	assert.Equal(t, SpecKindCluster, p.Kind)
+
+	patched, err := p.Apply(base)
+	assert.NoError(t, err)
+
+	c, ok := patched.(*SpecCluster)
+	assert.True(t, ok)
+	assert.Equal(t, "edge02", c.Name)
+	assert.Equal(t, "10.241.8.222", c.EndpointIP)
+	assert.Empty(t, c.Description)
+
+	// default-pool01 merged, pool02 deleted, pool03 added
+	assert.Equal(t, 2, len(c.WorkerNodes))
+	assert.Equal(t, "default-pool01", c.WorkerNodes[0].Name)
+	assert.Equal(t, "vlan1008", c.WorkerNodes[0].Networks[0].NetworkName)
+	assert.Equal(t, []string{"10.241.28.1"}, c.WorkerNodes[0].Networks[0].Nameservers)
+	assert.Equal(t, 2, len(c.WorkerNodes[0].PlacementParams))
+	assert.Equal(t, "vsanDatastore02", c.WorkerNodes[0].PlacementParams[1].Name)
+	assert.Equal(t, "pool03", c.WorkerNodes[1].Name)
+
+	// base spec unchanged
+	b := base.(*SpecCluster)
+	assert.Equal(t, "edge01", b.Name)
+	assert.Equal(t, 2, len(b.WorkerNodes))
+	assert.Equal(t, "vlan1007", b.WorkerNodes[0].Networks[0].NetworkName)
+
+	// plain merge patch replaces lists
+	p.Type = MergePatch
+	patched, err = p.Apply(base)
+	assert.NoError(t, err)
+	c = patched.(*SpecCluster)
+	assert.Equal(t, 3, len(c.WorkerNodes))
+	assert.Equal(t, 1, len(c.WorkerNodes[0].PlacementParams))
+	assert.Empty(t, c.WorkerNodes[0].Networks[0].Nameservers)
+}
+
+// Json patch of a node pool
+func TestSpecPatch_JsonPatch(t *testing.T) {
+
+	base := readTestSpec(t, jsonNodeSpec, Json)
+
+	tests := []struct {
+		name    string
+		patch   string
+		format  SpecFormatType
+		check   func(t *testing.T, p *SpecNodePool)
+		wantErr bool
+	}{
+		{
+			name: "replace and add",
+			patch: `[
+				{"op": "test", "path": "/name", "value": "temp1234"},
+				{"op": "replace", "path": "/cpu", "value": 8},
+				{"op": "add", "path": "/labels/-", "value": "site=edge01"},
+				{"op": "add", "path": "/networks/0/nameservers/0", "value": "10.246.2.8"}
+			]`,
+			format: Json,
+			check: func(t *testing.T, p *SpecNodePool) {
+				assert.Equal(t, 8, p.Cpu)
+				assert.Equal(t, []string{"type=hub", "site=edge01"}, p.Labels)
+				assert.Equal(t, []string{"10.246.2.8", "10.246.2.9"}, p.Networks[0].Nameservers)
+			},
+		},
+		{
+			name:   "yaml remove, copy and move",
+			patch:  "- op: remove\n  path: /placementParams/2\n- op: copy\n  from: /name\n  path: /labels/0\n- op: move\n  from: /storage\n  path: /memory\n",
+			format: Yaml,
+			check: func(t *testing.T, p *SpecNodePool) {
+				assert.Equal(t, 2, len(p.PlacementParams))
+				assert.Equal(t, []string{"temp1234", "type=hub"}, p.Labels)
+				assert.Equal(t, 50, p.Memory)
+				assert.Equal(t, 0, p.Storage)
+			},
+		},
+		{
+			name:    "test failed",
+			patch:   `[{"op": "test", "path": "/cpu", "value": 4}]`,
+			format:  Json,
+			wantErr: true,
+		},
+		{
+			name:    "path not found",
+			patch:   `[{"op": "replace", "path": "/networks/3/label", "value": "x"}]`,
+			format:  Json,
+			wantErr: true,
+		},
+		{
+			name:    "unknown operation",
+			patch:   `[{"op": "merge", "path": "/cpu", "value": 4}]`,
+			format:  Json,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			p, err := NewSpecPatch([]byte(tt.patch), tt.format)
+			assert.NoError(t, err)
+			assert.Equal(t, JsonPatch, p.Type)
+
+			patched, err := p.Apply(base)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			pool, ok := patched.(*SpecNodePool)
+			assert.True(t, ok)
+			tt.check(t, pool)
+		})
+	}
+}
+
+// Patch applied only to specs of patch kind
+func TestPatchSpecs(t *testing.T) {
+
+	all, err := RequestSpecsFromString(multiDocSite, Yaml)
+	assert.NoError(t, err)
+
+	p, err := NewSpecPatch([]byte("kind: node_pool\ncpu: 16\nlabels:\n  - type=edge\n"))
+	assert.NoError(t, err)
+
+	patched, err := PatchSpecs(all, p)
+	assert.NoError(t, err)
+	assert.Equal(t, len(all), len(patched))
+	for i, s := range patched {
+		assert.Equal(t, all[i].Kind(), s.Kind())
+		if pool, ok := s.(*SpecNodePool); ok {
+			assert.Equal(t, 16, pool.Cpu)
+			assert.Equal(t, []string{"type=edge"}, pool.Labels)
+			assert.Equal(t, all[i].(*SpecNodePool).Cluster, pool.Cluster)
+		} else {
+			assert.Equal(t, all[i], s)
+		}
+	}
+
+	p, err = NewSpecPatch([]byte("kind: cluster\nname: test\n"))
+	assert.NoError(t, err)
+	_, err = PatchSpecs(all, p)
+	assert.Error(t, err)
+
+	_, err = NewSpecPatch([]byte("test"))
+	assert.Error(t, err)
+}
+
+var patchBaseCluster = `
+kind: cluster
+name: edge01
+description: edge cluster
+managementClusterId: edge-mgmt-test01
+clusterPassword: VMware1!
+clusterTemplateId: myworkload
+clusterType: workload
+hcxCloudUrl: https://tca-pod03-cp.cnfdemo.io
+endpointIP: 10.241.7.222
+vmTemplate: photon-3-kube-v1.20.4+vmware.1
+masterNodes:
+  - name: master
+    networks:
+      - label: MANAGEMENT
+        networkName: vlan1007
+        nameservers:
+          - 10.241.28.1
+    placementParams:
+      - name: vsanDatastore
+        type: Datastore
+workerNodes:
+  - name: default-pool01
+    networks:
+      - label: MANAGEMENT
+        networkName: vlan1007
+        nameservers:
+          - 10.241.28.1
+    placementParams:
+      - name: k8s
+        type: ResourcePool
+      - name: vsanDatastore
+        type: Datastore
+  - name: pool02
+    networks:
+      - label: MANAGEMENT
+        networkName: vlan1007
+    placementParams:
+      - name: vsanDatastore
+        type: Datastore
+placementParams:
+  - name: vsanDatastore
+    type: Datastore
+`
+
+var patchClusterOverlay = `
+kind: cluster
+name: edge02
+description: null
+endpointIP: 10.241.8.222
+workerNodes:
+  - name: default-pool01
+    networks:
+      - label: MANAGEMENT
+        networkName: vlan1008
+    placementParams:
+      - name: vsanDatastore02
+        type: Datastore
+  - name: pool02
+    $patch: delete
+  - name: pool03
+    networks:
+      - label: MANAGEMENT
+        networkName: vlan1008
+`
@@ -0,0 +1,229 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+)
+
+const (
+	// templateNoValue text/template output of a missing value
+	templateNoValue = "<no value>"
+)
+
+// InvalidSpecTemplate error raised if a spec template
+// can't be parsed or rendered.
+type InvalidSpecTemplate struct {
+	errMsg string
+
+	// File a spec file, empty if spec read from reader
+	File string
+}
+
+func (m *InvalidSpecTemplate) Error() string {
+	if len(m.File) > 0 {
+		return m.File + ": " + m.errMsg
+	}
+	return m.errMsg
+}
+
+// SpecValues values of a spec template, nested maps
+// accessed in a template as .Values.site.name
+type SpecValues map[string]interface{}
+
+// merge deep merges src values to values, src value overwrites a value
+func (v SpecValues) merge(src map[string]interface{}) {
+	for k, sv := range src {
+		if sm, ok := sv.(map[string]interface{}); ok {
+			if dm, ok := v[k].(map[string]interface{}); ok {
+				SpecValues(dm).merge(sm)
+				continue
+			}
+		}
+		v[k] = sv
+	}
+}
+
+// SpecRenderer renders Go template of a spec before spec parsed,
+// template data is .Values that holds values from value files and
+// key=value pairs, and .Env that holds environment variables.
+// In strict mode a missing value is an error, otherwise
+// it rendered as empty string.
+type SpecRenderer struct {
+
+	// Values template values
+	Values SpecValues
+
+	// Strict fails rendering if a value is missing
+	Strict bool
+}
+
+// DefaultRenderer renderer used when a spec read from a file,
+// tcactl populates it from --set, --values and --strict flags.
+var DefaultRenderer = NewSpecRenderer()
+
+// NewSpecRenderer returns renderer without values
+func NewSpecRenderer() *SpecRenderer {
+	return &SpecRenderer{Values: SpecValues{}}
+}
+
+// SetValue sets a value from key=value string, dotted key
+// sets a nested value. For example site.name=edge01
+// Value parsed as yaml scalar, so numbers and booleans keep a type.
+func (r *SpecRenderer) SetValue(kv string) error {
+
+	i := strings.Index(kv, "=")
+	if i <= 0 {
+		return &InvalidSpecTemplate{errMsg: fmt.Sprintf("value '%s' must be key=value", kv)}
+	}
+
+	var v interface{} = kv[i+1:]
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(kv[i+1:]), &scalar); err == nil {
+		switch scalar.(type) {
+		case int, float64, bool:
+			v = scalar
+		}
+	}
+
+	keys := strings.Split(kv[:i], ".")
+	for j := len(keys) - 1; j > 0; j-- {
+		v = map[string]interface{}{keys[j]: v}
+	}
+
+	r.Values.merge(map[string]interface{}{keys[0]: v})
+
+	return nil
+}
+
+// ReadValuesFile merges values from a yaml or json file,
+// values from a file overwrite values set before.
+func (r *SpecRenderer) ReadValuesFile(fileName string) error {
+
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(b, &values); err != nil {
+		return &InvalidSpecTemplate{errMsg: err.Error(), File: fileName}
+	}
+
+	r.Values.merge(values)
+
+	return nil
+}
+
+// environ returns environment variables as a map
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.Index(kv, "="); i > 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	return env
+}
+
+// templateFuncs functions available in a spec template
+func (r *SpecRenderer) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// env returns environment variable, in strict mode variable must be set
+		"env": func(name string) (string, error) {
+			v, ok := os.LookupEnv(name)
+			if !ok && r.Strict {
+				return "", fmt.Errorf("environment variable %s is not set", name)
+			}
+			return v, nil
+		},
+		// default returns a default value if a value is empty
+		"default": func(d interface{}, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return d
+			}
+			return v
+		},
+		// required fails if value is empty, regardless of strict mode
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if v == nil || v == "" {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"quote": func(v interface{}) string {
+			return fmt.Sprintf("%q", fmt.Sprint(v))
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
+
+// Render renders spec template, name used in error messages.
+// Spec without template actions returned as is.
+func (r *SpecRenderer) Render(name string, b []byte) ([]byte, error) {
+
+	if !bytes.Contains(b, []byte("{{")) {
+		return b, nil
+	}
+
+	t := template.New(name).Funcs(r.templateFuncs())
+	if r.Strict {
+		t = t.Option("missingkey=error")
+	}
+
+	// template errors already hold a name, line and column
+	t, err := t.Parse(string(b))
+	if err != nil {
+		return nil, &InvalidSpecTemplate{errMsg: err.Error()}
+	}
+
+	data := map[string]interface{}{
+		"Values": map[string]interface{}(r.Values),
+		"Env":    environ(),
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, &InvalidSpecTemplate{errMsg: err.Error()}
+	}
+
+	if r.Strict {
+		return buf.Bytes(), nil
+	}
+
+	return bytes.ReplaceAll(buf.Bytes(), []byte(templateNoValue), nil), nil
+}
+
+// RenderSpecFile reads a spec file and renders it with default renderer
+func RenderSpecFile(fileName string) ([]byte, error) {
+
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return DefaultRenderer.Render(fileName, b)
+}
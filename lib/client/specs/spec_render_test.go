@@ -0,0 +1,186 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Set values from key=value pairs
+func TestSpecRenderer_SetValue(t *testing.T) {
+
+	r := NewSpecRenderer()
+	assert.NoError(t, r.SetValue("site.name=edge01"))
+	assert.NoError(t, r.SetValue("site.cpu=4"))
+	assert.NoError(t, r.SetValue("enabled=true"))
+	assert.NoError(t, r.SetValue("url=https://host:443/a=b"))
+	assert.Error(t, r.SetValue("noValue"))
+	assert.Error(t, r.SetValue("=value"))
+
+	assert.Equal(t, SpecValues{
+		"site":    map[string]interface{}{"name": "edge01", "cpu": 4},
+		"enabled": true,
+		"url":     "https://host:443/a=b",
+	}, r.Values)
+}
+
+// Render spec template
+func TestSpecRenderer_Render(t *testing.T) {
+
+	assert.NoError(t, os.Setenv("TCACTL_TEST_NET", "vlan1007"))
+	defer os.Unsetenv("TCACTL_TEST_NET")
+
+	tests := []struct {
+		name    string
+		spec    string
+		values  []string
+		strict  bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "no template",
+			spec:   "kind: node_pool\nname: test\n",
+			values: nil,
+			want:   "kind: node_pool\nname: test\n",
+		},
+		{
+			name:   "values and env",
+			spec:   "name: {{ .Values.site.name }}-pool\nnetwork: {{ .Env.TCACTL_TEST_NET }}\ncpu: {{ .Values.cpu }}",
+			values: []string{"site.name=edge01", "cpu=4"},
+			want:   "name: edge01-pool\nnetwork: vlan1007\ncpu: 4",
+		},
+		{
+			name:   "functions",
+			spec:   `{{ env "TCACTL_TEST_NET" | upper }} {{ .Values.missing | default "k8s" }} {{ .Values.name | quote }}`,
+			values: []string{"name=test"},
+			want:   `VLAN1007 k8s "test"`,
+		},
+		{
+			name:   "missing value",
+			spec:   "name: {{ .Values.name }}",
+			values: nil,
+			want:   "name: ",
+		},
+		{
+			name:    "missing value strict",
+			spec:    "name: {{ .Values.name }}",
+			values:  nil,
+			strict:  true,
+			wantErr: true,
+		},
+		{
+			name:    "missing env strict",
+			spec:    `name: {{ env "TCACTL_TEST_UNSET" }}`,
+			values:  nil,
+			strict:  true,
+			wantErr: true,
+		},
+		{
+			name:    "required",
+			spec:    `name: {{ required "name is required" .Values.name }}`,
+			values:  nil,
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			spec:    "name: {{ .Values.name ",
+			values:  nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			r := NewSpecRenderer()
+			r.Strict = tt.strict
+			for _, kv := range tt.values {
+				assert.NoError(t, r.SetValue(kv))
+			}
+
+			got, err := r.Render(tt.name, []byte(tt.spec))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+// Read rendered spec from a file with values file
+func TestReadSpecFromFromFile_Template(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "render")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	specFile := filepath.Join(dir, "pool.yaml")
+	valuesFile := filepath.Join(dir, "values.yaml")
+	assert.NoError(t, ioutil.WriteFile(specFile, []byte(templateNodeSpec), 0644))
+	assert.NoError(t, ioutil.WriteFile(valuesFile, []byte("name: edge01\ncpu: 2\nsite:\n  network: vlan1007\n"), 0644))
+
+	old := DefaultRenderer
+	defer func() { DefaultRenderer = old }()
+
+	DefaultRenderer = NewSpecRenderer()
+	assert.NoError(t, DefaultRenderer.ReadValuesFile(valuesFile))
+	assert.NoError(t, DefaultRenderer.SetValue("cpu=8"))
+
+	s, err := SpecNodePool{}.SpecsFromFile(specFile)
+	assert.NoError(t, err)
+	spec, ok := (*s).(*SpecNodePool)
+	assert.True(t, ok)
+	assert.Equal(t, "edge01-pool", spec.Name)
+	assert.Equal(t, 8, spec.Cpu)
+	assert.Equal(t, "vlan1007", spec.Networks[0].NetworkName)
+
+	all, err := RequestSpecsFromFile(specFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(all))
+
+	DefaultRenderer.Strict = true
+	DefaultRenderer.Values = SpecValues{}
+	_, err = SpecNodePool{}.SpecsFromFile(specFile)
+	assert.Error(t, err)
+}
+
+var templateNodeSpec = `
+kind: node_pool
+name: {{ .Values.name }}-pool
+storage: 50
+cpu: {{ .Values.cpu }}
+memory: 16384
+replica: 1
+labels:
+  - type=hub
+networks:
+  - label: MANAGEMENT
+    networkName: {{ .Values.site.network }}
+    nameservers:
+      - 10.246.2.9
+placementParams:
+  - type: ClusterComputeResource
+    name: hubsite
+`
@@ -0,0 +1,350 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+//go:generate go run gen_docs.go
+
+package specs
+
+import (
+	"fmt"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	// JsonSchemaDraft json schema version generated schema conforms
+	JsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+)
+
+// SpecKinds all spec kinds
+var SpecKinds = []SpecType{
+	SpecKindProvider,
+	SpecKindTemplate,
+	SpecKindCluster,
+	SpecKindNodePool,
+	SpecKindExtension,
+	SpecKindInstance,
+}
+
+// JsonSchema json schema of a spec or spec field,
+// only subset of json schema that spec types need.
+// AdditionalProperties is false for spec objects and
+// a schema of a value for maps.
+type JsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Enum        []string               `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Properties  map[string]*JsonSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	Items       *JsonSchema            `json:"items,omitempty" yaml:"items,omitempty"`
+
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+
+	// order of properties as declared in a spec struct
+	order []string
+}
+
+// PropertyNames returns property names in order fields declared
+func (s *JsonSchema) PropertyNames() []string {
+	return s.order
+}
+
+// IsRequired returns true if property is required
+func (s *JsonSchema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// valueSchema returns schema of map values, nil if not a map
+func (s *JsonSchema) valueSchema() *JsonSchema {
+	v, _ := s.AdditionalProperties.(*JsonSchema)
+	return v
+}
+
+// isClosed returns true if object doesn't allow unknown properties
+func (s *JsonSchema) isClosed() bool {
+	closed, ok := s.AdditionalProperties.(bool)
+	return ok && !closed
+}
+
+// fieldName returns name of a field for a given tag (yaml or json),
+// empty string if field not serialized, true if field inlined.
+func fieldName(f reflect.StructField, tagName string) (string, bool) {
+
+	tag, ok := f.Tag.Lookup(tagName)
+	if !ok {
+		tag = f.Tag.Get("json")
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] == "-" {
+		return "", false
+	}
+
+	for _, p := range parts[1:] {
+		if p == "inline" {
+			return "", true
+		}
+	}
+
+	if len(parts[0]) > 0 {
+		return parts[0], false
+	}
+
+	return strings.ToLower(f.Name), false
+}
+
+// isRequiredField returns true if validator tags mark field as required
+func isRequiredField(f reflect.StructField) bool {
+
+	for _, tag := range []string{"valid", "validate"} {
+		for _, rule := range strings.Split(f.Tag.Get(tag), ",") {
+			if rule == "required" || strings.HasPrefix(rule, "required~") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// typeDoc returns doc comment of a named type
+func typeDoc(t reflect.Type) string {
+	if len(t.Name()) == 0 {
+		return ""
+	}
+	return specDocs[t.String()]
+}
+
+// schemaBuilder builds json schema from spec types,
+// tag is a struct tag used for field names,
+// visiting tracks recursive types.
+type schemaBuilder struct {
+	tag      string
+	visiting map[reflect.Type]bool
+}
+
+// build returns json schema for a type
+func (b *schemaBuilder) build(t reflect.Type) *JsonSchema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := &JsonSchema{Description: typeDoc(t)}
+
+	switch t.Kind() {
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		s.Type = "number"
+	case reflect.Slice, reflect.Array:
+		s.Type = "array"
+		s.Items = b.build(t.Elem())
+	case reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = b.build(t.Elem())
+	case reflect.Struct:
+		s.Type = "object"
+		if b.visiting[t] {
+			return s
+		}
+		b.visiting[t] = true
+		b.buildProperties(t, s)
+		delete(b.visiting, t)
+		s.AdditionalProperties = false
+	}
+
+	return s
+}
+
+// buildProperties adds struct fields to object schema
+func (b *schemaBuilder) buildProperties(t reflect.Type, s *JsonSchema) {
+
+	if s.Properties == nil {
+		s.Properties = map[string]*JsonSchema{}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+
+		name, inline := fieldName(f, b.tag)
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			b.buildProperties(ft, s)
+			continue
+		}
+		if len(name) == 0 {
+			continue
+		}
+
+		p := b.build(f.Type)
+		if doc, ok := specDocs[t.String()+"."+f.Name]; ok {
+			p.Description = doc
+		}
+
+		s.Properties[name] = p
+		s.order = append(s.order, name)
+		if isRequiredField(f) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// schemaOf builds json schema of a spec type, tag is a struct tag used for field names
+func schemaOf(spec interface{}, tag string) *JsonSchema {
+	b := &schemaBuilder{tag: tag, visiting: map[reflect.Type]bool{}}
+	return b.build(reflect.TypeOf(spec))
+}
+
+// defaultedFields returns names of top level fields
+// that spec Default() populates, such fields are optional.
+func defaultedFields(spec RequestSpec, tag string) map[string]bool {
+
+	defaulted := map[string]bool{}
+	if err := spec.Default(); err != nil {
+		return defaulted
+	}
+
+	v := reflect.ValueOf(spec).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if len(f.PkgPath) > 0 || v.Field(i).IsZero() {
+			continue
+		}
+		if name, _ := fieldName(f, tag); len(name) > 0 {
+			defaulted[name] = true
+		}
+	}
+
+	return defaulted
+}
+
+// NewJsonSchema returns json schema for a spec kind. By default field
+// names are yaml names, Json format uses json names.
+func NewJsonSchema(kind SpecType, f ...SpecFormatType) (*JsonSchema, error) {
+
+	spec, err := NewRequestSpec(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := "yaml"
+	if len(f) > 0 && f[0] == Json {
+		tag = "json"
+	}
+
+	s := schemaOf(spec, tag)
+	s.Schema = JsonSchemaDraft
+	s.Title = string(kind)
+
+	// spec kind is always a constant
+	k, ok := s.Properties["kind"]
+	if !ok {
+		return nil, api_errors.NewInvalidSpec(fmt.Sprintf("spec kind %s has no kind field", kind))
+	}
+	k.Enum = []string{string(kind)}
+
+	defaulted := defaultedFields(spec, tag)
+	required := []string{"kind"}
+	for _, name := range s.Required {
+		if name != "kind" && !defaulted[name] {
+			required = append(required, name)
+		}
+	}
+	s.Required = required
+
+	return s, nil
+}
+
+// SchemaField a single field of a spec, used to explain a spec kind
+type SchemaField struct {
+
+	// Path dotted path of a field, list items denoted by []
+	Path string
+
+	// Type json schema type
+	Type string
+
+	// Required true if field is mandatory
+	Required bool
+
+	// Description field doc
+	Description string
+}
+
+// Fields returns all fields of a schema in order declared,
+// nested objects and list items flattened to dotted path.
+func (s *JsonSchema) Fields() []SchemaField {
+	var fields []SchemaField
+	s.fields("", &fields)
+	return fields
+}
+
+func (s *JsonSchema) fields(prefix string, out *[]SchemaField) {
+
+	for _, name := range s.order {
+
+		p := s.Properties[name]
+		path := name
+		if len(prefix) > 0 {
+			path = prefix + "." + name
+		}
+
+		t := p.Type
+		item := p
+		for item.Type == "array" && item.Items != nil {
+			item = item.Items
+			path += "[]"
+			t = "[]" + item.Type
+		}
+		if v := item.valueSchema(); v != nil {
+			t = "map[string]" + v.Type
+		}
+
+		*out = append(*out, SchemaField{
+			Path:        path,
+			Type:        t,
+			Required:    s.IsRequired(name),
+			Description: p.Description,
+		})
+
+		if item.Type == "object" {
+			item.fields(path, out)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// Test json schema generation for all spec kinds
+func TestNewJsonSchema(t *testing.T) {
+
+	for _, kind := range SpecKinds {
+		t.Run(string(kind), func(t *testing.T) {
+			s, err := NewJsonSchema(kind)
+			assert.NoError(t, err)
+			assert.Equal(t, "object", s.Type)
+			assert.Equal(t, []string{string(kind)}, s.Properties["kind"].Enum)
+			assert.True(t, s.IsRequired("kind"))
+			assert.Equal(t, false, s.AdditionalProperties)
+			assert.NotEmpty(t, s.Fields())
+		})
+	}
+
+	_, err := NewJsonSchema("unknown")
+	assert.Error(t, err)
+}
+
+// Test that field names follow format and defaulted fields are optional
+func TestNewJsonSchema_NodePool(t *testing.T) {
+
+	s, err := NewJsonSchema(SpecKindNodePool)
+	assert.NoError(t, err)
+
+	assert.Contains(t, s.Properties, "clone_mode")
+	assert.Contains(t, s.Properties, "cluster")
+	assert.True(t, s.IsRequired("name"))
+	assert.True(t, s.IsRequired("cpu"))
+	assert.False(t, s.IsRequired("clone_mode"), "clone mode set by Default()")
+	assert.False(t, s.IsRequired("replica"), "replica set by Default()")
+	assert.Equal(t, "object", s.Properties["networks"].Items.Type)
+	assert.Equal(t, "node pool name", s.Properties["name"].Description)
+
+	s, err = NewJsonSchema(SpecKindNodePool, Json)
+	assert.NoError(t, err)
+	assert.Contains(t, s.Properties, "cloneMode")
+	assert.Contains(t, s.Properties, "cluster")
+
+	var paths []string
+	for _, f := range s.Fields() {
+		paths = append(paths, f.Path)
+	}
+	assert.Contains(t, paths, "networks[].label")
+	assert.Contains(t, strings.Join(paths, " "), "placementParams[].name")
+}
@@ -108,19 +108,39 @@ type SpecClusterConfig struct {
 
 // SpecClusterTemplate Cluster template spec
 type SpecClusterTemplate struct {
-	SpecType          SpecType           `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
-	Id                string             `json:"id,omitempty" yaml:"id,omitempty"`
-	Name              string             `json:"name" yaml:"name" valid:"required~name is mandatory spec field"`
-	ClusterType       string             `json:"clusterType" yaml:"clusterType" valid:"required~clusterType is mandatory spec field"`
-	KubernetesVersion string             `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
-	ClusterConfig     *SpecClusterConfig `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
-	Description       string             `json:"description,omitempty" yaml:"description,omitempty"`
-	MasterNodes       []SpecNodeTemplate `json:"masterNodes" yaml:"masterNodes"`
-	WorkerNodes       []SpecNodeTemplate `json:"workerNodes" yaml:"workerNodes"`
-	Tags              []struct {
+	// SpecType indicate a spec type, must be template
+	SpecType SpecType `json:"kind,omitempty" yaml:"kind,omitempty" valid:"required~kind is mandatory spec field"`
+
+	// Id template id, populated by TCA
+	Id string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Name cluster template name
+	Name string `json:"name" yaml:"name" valid:"required~name is mandatory spec field"`
+
+	// ClusterType MANAGEMENT or WORKLOAD
+	ClusterType string `json:"clusterType" yaml:"clusterType" valid:"required~clusterType is mandatory spec field"`
+
+	// KubernetesVersion kubernetes version of a cluster
+	KubernetesVersion string `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+
+	// ClusterConfig cni, csi and tools of a workload cluster
+	ClusterConfig *SpecClusterConfig `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
+
+	// Description optional template description
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// MasterNodes control plane node templates
+	MasterNodes []SpecNodeTemplate `json:"masterNodes" yaml:"masterNodes"`
+
+	// WorkerNodes worker node templates
+	WorkerNodes []SpecNodeTemplate `json:"workerNodes" yaml:"workerNodes"`
+
+	// Tags template tags
+	Tags []struct {
 		AutoCreated bool   `json:"autoCreated" yaml:"autoCreated"`
 		Name        string `json:"name" yaml:"name"`
 	} `json:"tags,omitempty" yaml:"tags,omitempty"`
+
 	specError error
 }
 
@@ -193,6 +213,21 @@ func (t *SpecClusterTemplate) validateCloneMode() bool {
 	return true
 }
 
+// validateNetworks each node template network must have a label
+func (t *SpecClusterTemplate) validateNetworks() bool {
+	for _, nodes := range [][]SpecNodeTemplate{t.MasterNodes, t.WorkerNodes} {
+		for _, node := range nodes {
+			for _, n := range node.Networks {
+				if len(strings.TrimSpace(n.Label)) == 0 {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
 //Validate method validate node pool specs
 func (t *SpecClusterTemplate) Validate() error {
 
@@ -216,6 +251,11 @@ func (t *SpecClusterTemplate) Validate() error {
 		return t.specError
 	}
 
+	if !t.validateNetworks() {
+		t.specError = &InvalidTemplateSpec{errMsg: "node template network must contain label"}
+		return t.specError
+	}
+
 	if !t.IsManagement() && !t.IsWorkload() {
 		t.specError = &InvalidTemplateSpec{errMsg: "cluster type must be either workload or management"}
 		return t.specError
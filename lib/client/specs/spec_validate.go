@@ -0,0 +1,405 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlErrLine extracts a line from yaml parser error
+var yamlErrLine = regexp.MustCompile(`line (\d+)`)
+
+// SchemaError a spec error found by offline validation
+type SchemaError struct {
+	errMsg string
+
+	// File a spec file, empty if spec read from reader
+	File string
+
+	// Line and Column a position of an error in a file, starts from 1,
+	// column is zero if parser doesn't report it
+	Line   int
+	Column int
+
+	// Path dotted path of a field, empty for document level error
+	Path string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Line == 0 {
+		if len(e.File) > 0 {
+			return e.File + ": " + e.errMsg
+		}
+		return e.errMsg
+	}
+	loc := fmt.Sprintf("%d", e.Line)
+	if e.Column > 0 {
+		loc = fmt.Sprintf("%d:%d", e.Line, e.Column)
+	}
+	if len(e.File) > 0 {
+		loc = e.File + ":" + loc
+	}
+	if len(e.Path) > 0 {
+		return loc + ": " + e.Path + ": " + e.errMsg
+	}
+	return loc + ": " + e.errMsg
+}
+
+// schemaValidator validates yaml nodes of spec documents
+// kind is used for a document without kind field.
+type schemaValidator struct {
+	file    string
+	kind    SpecType
+	format  SpecFormatType
+	schemas map[SpecType]*JsonSchema
+	errs    []*SchemaError
+}
+
+// add adds error at node position
+func (v *schemaValidator) add(n *yaml.Node, path string, format string, a ...interface{}) {
+	v.errs = append(v.errs, &SchemaError{
+		errMsg: fmt.Sprintf(format, a...),
+		File:   v.file,
+		Line:   n.Line,
+		Column: n.Column,
+		Path:   path,
+	})
+}
+
+// nodeType returns json schema type of yaml node
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	case yaml.ScalarNode:
+		switch n.Tag {
+		case "!!int":
+			return "integer"
+		case "!!float":
+			return "number"
+		case "!!bool":
+			return "boolean"
+		case "!!null":
+			return "null"
+		}
+	}
+	return "string"
+}
+
+// isTypeOf returns true if node can be decoded to a schema type,
+// any scalar can be decoded to a string and null to any type.
+func isTypeOf(n *yaml.Node, t string) bool {
+
+	actual := nodeType(n)
+	if actual == "null" || len(t) == 0 {
+		return true
+	}
+
+	switch t {
+	case "string":
+		return n.Kind == yaml.ScalarNode
+	case "number":
+		return actual == "number" || actual == "integer"
+	}
+
+	return actual == t
+}
+
+// joinPath appends a field to dotted path
+func joinPath(path string, field string) string {
+	if len(path) == 0 {
+		return field
+	}
+	return path + "." + field
+}
+
+// validateNode validates a node against a schema, path is a node path
+func (v *schemaValidator) validateNode(n *yaml.Node, s *JsonSchema, path string) {
+
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+
+	if !isTypeOf(n, s.Type) {
+		v.add(n, path, "expected %s, got %s", s.Type, nodeType(n))
+		return
+	}
+
+	if nodeType(n) == "null" {
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		valid := false
+		for _, e := range s.Enum {
+			valid = valid || n.Value == e
+		}
+		if !valid {
+			v.add(n, path, "value '%s' must be one of %s", n.Value, strings.Join(s.Enum, ", "))
+		}
+	}
+
+	switch s.Type {
+	case "array":
+		for i, item := range n.Content {
+			v.validateNode(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "object":
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			seen[key.Value] = true
+			if p, ok := s.Properties[key.Value]; ok {
+				v.validateNode(value, p, joinPath(path, key.Value))
+				continue
+			}
+			if vs := s.valueSchema(); vs != nil {
+				v.validateNode(value, vs, joinPath(path, key.Value))
+				continue
+			}
+			if s.isClosed() {
+				v.add(key, joinPath(path, key.Value), "unknown field")
+			}
+		}
+		for _, r := range s.Required {
+			if !seen[r] {
+				v.add(n, joinPath(path, r), "required field is missing")
+			}
+		}
+	}
+}
+
+// schema returns cached schema for a kind
+func (v *schemaValidator) schema(kind SpecType) (*JsonSchema, error) {
+
+	if s, ok := v.schemas[kind]; ok {
+		return s, nil
+	}
+
+	s, err := NewJsonSchema(kind, v.format)
+	if err != nil {
+		return nil, err
+	}
+	v.schemas[kind] = s
+
+	return s, nil
+}
+
+// validateSpec decodes a valid document and runs spec validator
+func (v *schemaValidator) validateSpec(n *yaml.Node, kind SpecType) {
+
+	spec, err := NewRequestSpec(kind)
+	if err != nil {
+		v.add(n, "", "%s", err)
+		return
+	}
+
+	if v.format == Json {
+		var raw interface{}
+		if err := n.Decode(&raw); err != nil {
+			v.add(n, "", "%s", err)
+			return
+		}
+		b, err := json.Marshal(raw)
+		if err == nil {
+			err = json.Unmarshal(b, spec)
+		}
+		if err != nil {
+			v.add(n, "", "%s", err)
+			return
+		}
+	} else if err := n.Decode(spec); err != nil {
+		v.add(n, "", "%s", err)
+		return
+	}
+
+	if err := spec.Default(); err != nil {
+		v.add(n, "", "%s", err)
+		return
+	}
+
+	if err := spec.Validate(); err != nil {
+		v.add(n, "", "%s", err)
+	}
+}
+
+// validateDocument validates a single spec document
+func (v *schemaValidator) validateDocument(doc *yaml.Node) {
+
+	if len(doc.Content) == 0 {
+		return
+	}
+
+	n := doc.Content[0]
+	if nodeType(n) == "null" {
+		return
+	}
+
+	if n.Kind != yaml.MappingNode {
+		v.add(n, "", "spec must be an object, got %s", nodeType(n))
+		return
+	}
+
+	var kindNode *yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == "kind" {
+			kindNode = n.Content[i+1]
+		}
+	}
+
+	if kindNode == nil {
+		if len(v.kind) == 0 {
+			v.add(n, "kind", "required field is missing")
+			return
+		}
+		// document without kind validated as a given kind
+		kindNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(v.kind), Line: n.Line, Column: n.Column}
+		withKind := *n
+		withKind.Content = append([]*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: "kind"}, kindNode}, n.Content...)
+		n = &withKind
+	}
+
+	kind := SpecType(kindNode.Value)
+	s, err := v.schema(kind)
+	if err != nil {
+		v.add(kindNode, "kind", "%s", err)
+		return
+	}
+
+	numErrs := len(v.errs)
+	v.validateNode(n, s, "")
+
+	// spec validator runs only on a spec that matches schema
+	if len(v.errs) == numErrs {
+		v.validateSpec(n, kind)
+	}
+}
+
+// ValidateSpecReader validates all spec documents read from a reader
+// against json schema of each document kind, it doesn't require TCA.
+// kind is used for documents without kind field, if empty such document
+// is an error. Method returns all errors found, each error hold line
+// and column, error returned only if reader fails.
+func ValidateSpecReader(r io.Reader, fileName string, kind SpecType, f ...SpecFormatType) ([]*SchemaError, error) {
+
+	v := &schemaValidator{file: fileName, kind: kind, format: Yaml, schemas: map[SpecType]*JsonSchema{}}
+	if len(f) > 0 && f[0] == Json {
+		v.format = Json
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			e := &SchemaError{errMsg: err.Error(), File: fileName}
+			if m := yamlErrLine.FindStringSubmatch(err.Error()); m != nil {
+				e.Line, _ = strconv.Atoi(m[1])
+			}
+			v.errs = append(v.errs, e)
+			break
+		}
+		v.validateDocument(&doc)
+	}
+
+	return v.errs, nil
+}
+
+// ValidateSpecFile validates all spec documents in a file,
+// format detected from file extension. A spec template rendered
+// by DefaultRenderer, template error reported as spec error.
+func ValidateSpecFile(fileName string, kind SpecType) ([]*SchemaError, error) {
+
+	b, err := RenderSpecFile(fileName)
+	if err != nil {
+		if _, ok := err.(*InvalidSpecTemplate); ok {
+			return []*SchemaError{{errMsg: err.Error()}}, nil
+		}
+		return nil, err
+	}
+	file := bytes.NewReader(b)
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return ValidateSpecReader(file, fileName, kind, Json)
+	case ".xml":
+		return validateXmlSpec(file, fileName)
+	}
+
+	return ValidateSpecReader(file, fileName, kind)
+}
+
+// validateXmlSpec validates xml spec documents, xml decoder doesn't
+// report position of a value, hence errors hold only a file.
+func validateXmlSpec(r io.Reader, fileName string) ([]*SchemaError, error) {
+
+	docs, err := decodeXmlDocuments(r, fileName)
+	if err != nil {
+		// document error already holds a file name
+		return []*SchemaError{{errMsg: err.Error()}}, nil
+	}
+
+	var errs []*SchemaError
+	for _, spec := range docs {
+		if err := spec.Validate(); err != nil {
+			errs = append(errs, &SchemaError{errMsg: string(spec.Kind()) + ": " + err.Error(), File: fileName})
+		}
+	}
+
+	return errs, nil
+}
+
+// ValidateSpecPath validates a spec file or all spec files in a directory
+func ValidateSpecPath(path string, kind SpecType) ([]*SchemaError, error) {
+
+	files, err := specFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []*SchemaError
+	for _, f := range files {
+		fileErrs, err := ValidateSpecFile(f, kind)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, fileErrs...)
+	}
+
+	return errs, nil
+}
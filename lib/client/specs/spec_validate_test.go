@@ -0,0 +1,127 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// Test offline spec validation, each error reported with a position
+func TestValidateSpecReader(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		spec     string
+		kind     SpecType
+		format   SpecFormatType
+		wantErrs []string
+	}{
+		{
+			name:   "valid yaml",
+			spec:   vimRegistrationYaml,
+			format: Yaml,
+		},
+		{
+			name:   "multi document",
+			spec:   multiDocSite,
+			format: Yaml,
+			wantErrs: []string{
+				"test.yaml:17:1: networks: required field is missing",
+				"test.yaml:17:1: placementParams: required field is missing",
+			},
+		},
+		{
+			name:   "valid json",
+			spec:   jsonNodeSpec,
+			format: Json,
+		},
+		{
+			name:   "invalid node pool",
+			spec:   invalidPoolSpec,
+			format: Yaml,
+			wantErrs: []string{
+				"test.yaml:3:6: cpu: expected integer, got string",
+				"test.yaml:6:9: labels: expected array, got string",
+				"test.yaml:9:5: networks[0].bogus: unknown field",
+				"test.yaml:1:1: placementParams: required field is missing",
+			},
+		},
+		{
+			name:     "unknown kind",
+			spec:     "kind: cluster1\nname: test\n",
+			format:   Yaml,
+			wantErrs: []string{"test.yaml:1:7: kind: unknown spec kind 'cluster1'"},
+		},
+		{
+			name:     "no kind",
+			spec:     "name: test\n",
+			format:   Yaml,
+			wantErrs: []string{"test.yaml:1:1: kind: required field is missing"},
+		},
+		{
+			name:     "no kind validated as given kind",
+			spec:     "name: test\n",
+			kind:     SpecKindExtension,
+			format:   Yaml,
+			wantErrs: []string{"test.yaml:1:1: version: required field is missing"},
+		},
+		{
+			name:     "parse error",
+			spec:     "kind: node_pool\nname: [test\n",
+			format:   Yaml,
+			wantErrs: []string{"test.yaml:1: yaml: line 1:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			errs, err := ValidateSpecReader(strings.NewReader(tt.spec), "test.yaml", tt.kind, tt.format)
+			assert.NoError(t, err)
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+
+			if len(tt.wantErrs) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.GreaterOrEqual(t, len(got), len(tt.wantErrs), "errors %v", got)
+			for i, want := range tt.wantErrs {
+				if i < len(got) {
+					assert.True(t, strings.HasPrefix(got[i], want), "got %s want %s", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+var invalidPoolSpec = `kind: node_pool
+name: pool01
+cpu: two
+memory: 16384
+storage: 50
+labels: type=pool01
+networks:
+  - label: MANAGEMENT
+    bogus: 1
+`
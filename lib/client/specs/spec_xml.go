@@ -0,0 +1,178 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	ioutils "github.com/spyroot/tcactl/pkg/io"
+	"io"
+	"strconv"
+)
+
+const (
+	// XmlSpecElement root element of xml spec
+	XmlSpecElement = "spec"
+
+	// XmlSpecsElement root element of xml document that holds many specs
+	XmlSpecsElement = "specs"
+)
+
+// coerceXml converts generic value decoded from xml to types of json schema,
+// xml has no types, so a single element may be a list, and numbers,
+// booleans are strings. Value that can't be converted left as is,
+// so json decoder reports an error.
+func coerceXml(v interface{}, s *JsonSchema) interface{} {
+
+	if s == nil {
+		return v
+	}
+
+	str, isString := v.(string)
+
+	switch s.Type {
+	case "array":
+		if l, ok := v.([]interface{}); ok {
+			for i := range l {
+				l[i] = coerceXml(l[i], s.Items)
+			}
+			return l
+		}
+		if isString && len(str) == 0 {
+			return []interface{}{}
+		}
+		return []interface{}{coerceXml(v, s.Items)}
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			if isString && len(str) == 0 {
+				return map[string]interface{}{}
+			}
+			return v
+		}
+		for k, field := range m {
+			if p, ok := s.Properties[k]; ok {
+				m[k] = coerceXml(field, p)
+			} else if vs := s.valueSchema(); vs != nil {
+				m[k] = coerceXml(field, vs)
+			}
+		}
+		return m
+	case "integer":
+		if i, err := strconv.ParseInt(str, 10, 64); isString && err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(str, 64); isString && err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(str); isString && err == nil {
+			return b
+		}
+	}
+
+	return v
+}
+
+// specFromXml decodes generic xml value to a spec
+func specFromXml(v interface{}, spec RequestSpec) error {
+
+	b, err := json.Marshal(coerceXml(v, schemaOf(spec, "json")))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, spec)
+}
+
+// xmlSpecKind returns kind field of generic xml value
+func xmlSpecKind(v interface{}) (SpecType, error) {
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("xml spec must be an element with child elements")
+	}
+
+	kind, _ := m["kind"].(string)
+	if len(kind) == 0 {
+		return "", fmt.Errorf("spec must contain kind field")
+	}
+
+	return SpecType(kind), nil
+}
+
+// decodeXmlDocuments decodes xml spec, root element spec holds a single spec,
+// root element specs holds many spec elements. Each spec routed by kind field.
+func decodeXmlDocuments(r io.Reader, fileName string) ([]RequestSpec, error) {
+
+	root, v, err := ioutils.XmlUnmarshal(r)
+	if err != nil {
+		return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: 1}
+	}
+
+	docs := []interface{}{v}
+	if root == XmlSpecsElement {
+		docs = nil
+		if m, ok := v.(map[string]interface{}); ok {
+			if l, ok := m[XmlSpecElement].([]interface{}); ok {
+				docs = l
+			} else if s, ok := m[XmlSpecElement]; ok {
+				docs = []interface{}{s}
+			}
+		}
+	}
+
+	var result []RequestSpec
+	for i, doc := range docs {
+
+		kind, err := xmlSpecKind(doc)
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: i + 1}
+		}
+
+		spec, err := NewRequestSpec(kind)
+		if err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: i + 1}
+		}
+
+		if err := specFromXml(doc, spec); err != nil {
+			return nil, &InvalidSpecDocument{errMsg: err.Error(), File: fileName, Document: i + 1}
+		}
+
+		if err := spec.Default(); err != nil {
+			return nil, err
+		}
+
+		result = append(result, spec)
+	}
+
+	return result, nil
+}
+
+// isXml returns true if buffer looks like xml document
+func isXml(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("<"))
+}
+
+// SpecToXml encodes a spec as xml document, element names are json field names.
+func SpecToXml(spec RequestSpec) ([]byte, error) {
+	return ioutils.XmlMarshalIndent(spec, XmlSpecElement, "  ")
+}
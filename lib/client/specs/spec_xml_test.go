@@ -0,0 +1,161 @@
+// Package specs
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+package specs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// Encode spec as xml and read it back
+func TestSpecToXml(t *testing.T) {
+
+	tests := []struct {
+		name string
+		spec string
+		kind SpecType
+	}{
+		{
+			name: "node pool",
+			spec: jsonNodeSpec,
+			kind: SpecKindNodePool,
+		},
+		{
+			name: "cloud provider",
+			spec: vimRegistrationJson,
+			kind: SpecKindProvider,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			spec, err := NewRequestSpec(tt.kind)
+			assert.NoError(t, err)
+			want, err := ReadSpecFromFromString(tt.spec, spec, Json)
+			assert.NoError(t, err)
+
+			b, err := SpecToXml(*want)
+			assert.NoError(t, err)
+			assert.True(t, strings.Contains(string(b), "<"+XmlSpecElement+">"))
+
+			kind, err := SpecKindFromBytes(b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.kind, kind)
+
+			spec, err = NewRequestSpec(tt.kind)
+			assert.NoError(t, err)
+			got, err := ReadSpecFromFromString(string(b), spec)
+			assert.NoError(t, err)
+			assert.Equal(t, *want, *got)
+		})
+	}
+}
+
+// Read xml specs, single element list and typed values
+func TestRequestSpecsFromString_Xml(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		spec      string
+		wantKinds []SpecType
+		wantErr   bool
+	}{
+		{
+			name:      "single spec",
+			spec:      xmlNodeSpec,
+			wantKinds: []SpecType{SpecKindNodePool},
+			wantErr:   false,
+		},
+		{
+			name:      "many specs",
+			spec:      "<specs>" + xmlNodeSpec + "<spec><kind>template</kind><name>test</name></spec></specs>",
+			wantKinds: []SpecType{SpecKindNodePool, SpecKindTemplate},
+			wantErr:   false,
+		},
+		{
+			name:    "no kind",
+			spec:    "<spec><name>test</name></spec>",
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			spec:    "<spec><kind>node_pool</kind><cpu>two</cpu></spec>",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			spec:    "<spec><kind>node_pool</kind>",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := RequestSpecsFromString(tt.spec, Xml)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantKinds), len(got))
+			for i, s := range got {
+				assert.Equal(t, tt.wantKinds[i], s.Kind())
+			}
+
+			pool, ok := got[0].(*SpecNodePool)
+			assert.True(t, ok)
+			assert.Equal(t, 2, pool.Cpu)
+			assert.Equal(t, []string{"type=hub"}, pool.Labels)
+			assert.Equal(t, 1, len(pool.Networks))
+			assert.Equal(t, []string{"10.246.2.9"}, pool.Networks[0].Nameservers)
+			assert.Equal(t, 3, len(pool.PlacementParams))
+			assert.Equal(t, "linkedClone", pool.CloneMode)
+		})
+	}
+}
+
+var xmlNodeSpec = `
+<spec>
+  <kind>node_pool</kind>
+  <name>temp1234</name>
+  <storage>50</storage>
+  <cpu>2</cpu>
+  <memory>16384</memory>
+  <replica>1</replica>
+  <labels>type=hub</labels>
+  <networks>
+    <label>MANAGEMENT</label>
+    <networkName>/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0</networkName>
+    <nameservers>10.246.2.9</nameservers>
+  </networks>
+  <placementParams>
+    <type>ClusterComputeResource</type>
+    <name>hubsite</name>
+  </placementParams>
+  <placementParams>
+    <type>Datastore</type>
+    <name>vsanDatastore</name>
+  </placementParams>
+  <placementParams>
+    <type>ResourcePool</type>
+    <name>k8s</name>
+  </placementParams>
+</spec>
+`
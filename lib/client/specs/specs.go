@@ -18,11 +18,13 @@
 package specs
 
 import (
+	"bytes"
 	"encoding/json"
+	"github.com/spyroot/tcactl/lib/api_errors"
+	ioutils "github.com/spyroot/tcactl/pkg/io"
 	"gopkg.in/yaml.v3"
 	"io"
 	"io/ioutil"
-	"os"
 	"path"
 	"strings"
 )
@@ -67,14 +69,88 @@ type RequestSpec interface {
 	IsValid() bool
 }
 
+// specKind used to peek a kind field of a spec
+type specKind struct {
+	Kind SpecType `json:"kind" yaml:"kind"`
+}
+
+// NewRequestSpec - returns a new empty spec for a given kind
+func NewRequestSpec(kind SpecType) (RequestSpec, error) {
+
+	switch kind {
+	case SpecKindProvider:
+		return new(SpecCloudProvider), nil
+	case SpecKindExtension:
+		return new(SpecExtension), nil
+	case SpecKindNodePool:
+		return new(SpecNodePool), nil
+	case SpecKindTemplate:
+		return new(SpecClusterTemplate), nil
+	case SpecKindInstance:
+		return new(InstanceRequestSpec), nil
+	case SpecKindCluster:
+		return new(SpecCluster), nil
+	}
+
+	return nil, api_errors.NewInvalidSpec("unknown spec kind '" + string(kind) + "'")
+}
+
+// SpecKindFromBytes - reads kind field from raw spec,
+// yaml parser used since it accepts json as well.
+func SpecKindFromBytes(b []byte) (SpecType, error) {
+
+	if isXml(b) {
+		_, v, err := ioutils.XmlUnmarshal(bytes.NewReader(b))
+		if err != nil {
+			return "", err
+		}
+		return xmlSpecKind(v)
+	}
+
+	var k specKind
+	if err := yaml.Unmarshal(b, &k); err != nil {
+		return "", err
+	}
+
+	if len(k.Kind) == 0 {
+		return "", api_errors.NewInvalidSpec("spec must contain kind field")
+	}
+
+	return k.Kind, nil
+}
+
+// RequestSpecFromFile - reads a spec from file,
+// spec type is detected from spec kind field.
+func RequestSpecFromFile(fileName string, f ...SpecFormatType) (*RequestSpec, error) {
+
+	b, err := RenderSpecFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := SpecKindFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := NewRequestSpec(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadSpecFromFromFile(fileName, spec, f...)
+}
+
 // ReadSpecFromFromFile - reads instance spec from file
-// and return TenantSpecs instance
+// and return TenantSpecs instance, spec file is a Go template
+// rendered by DefaultRenderer before spec parsed.
 func ReadSpecFromFromFile(fileName string, spec RequestSpec, f ...SpecFormatType) (*RequestSpec, error) {
 
-	file, err := os.Open(fileName)
+	b, err := RenderSpecFile(fileName)
 	if err != nil {
 		return nil, err
 	}
+	file := bytes.NewReader(b)
 
 	if len(f) == 0 {
 		fileName = path.Base(fileName)
@@ -92,6 +168,13 @@ func ReadSpecFromFromFile(fileName string, spec RequestSpec, f ...SpecFormatType
 				}
 				return spec, nil
 			}
+			if strings.HasSuffix(fileName, ".xml") {
+				spec, err := ReadSpec(file, spec, Xml)
+				if err != nil {
+					return nil, err
+				}
+				return spec, nil
+			}
 		}
 	}
 
@@ -106,7 +189,7 @@ func ReadSpecFromFromString(str string, spec RequestSpec, f ...SpecFormatType) (
 }
 
 // ReadSpec - Read spec from io reader
-// detects format and uses either yaml or json parser
+// detects format and uses either yaml, json or xml parser
 func ReadSpec(b io.Reader, spec RequestSpec, f ...SpecFormatType) (*RequestSpec, error) {
 
 	buffer, err := ioutil.ReadAll(b)
@@ -121,15 +204,31 @@ func ReadSpec(b io.Reader, spec RequestSpec, f ...SpecFormatType) (*RequestSpec,
 
 	isYamlSpec := false
 	isJsonSpec := false
+	isXmlSpec := false
 
 	switch sType {
 	case Yaml:
 		isYamlSpec = true
 	case Json:
 		isJsonSpec = true
+	case Xml:
+		isXmlSpec = true
 	default:
 		isYamlSpec = true
 		isJsonSpec = true
+		isXmlSpec = isXml(buffer)
+	}
+
+	if isXmlSpec {
+		if _, v, err := ioutils.XmlUnmarshal(bytes.NewReader(buffer)); err == nil {
+			if err = specFromXml(v, spec); err == nil {
+				err = spec.Default()
+				if err != nil {
+					return nil, err
+				}
+				return &spec, nil
+			}
+		}
 	}
 
 	if isJsonSpec {
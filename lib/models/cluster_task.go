@@ -18,8 +18,10 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // TcaTask - generic task respond
@@ -50,6 +52,46 @@ type TaskSteps struct {
 	Errors *[]TaskErrors `json:"errors,omitempty" yaml:"errors,omitempty"`
 }
 
+// millisTime converts TCA timestamp in milliseconds to time
+func millisTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// Elapsed returns time step took, or time since step
+// started if step still running, zero if step not started.
+func (s *TaskSteps) Elapsed(now time.Time) time.Duration {
+
+	if s.StartTime <= 0 {
+		return 0
+	}
+	if s.EndTime > 0 {
+		return millisTime(s.EndTime).Sub(millisTime(s.StartTime))
+	}
+
+	return now.Sub(millisTime(s.StartTime))
+}
+
+// ChildSteps returns nested steps, TCA reports children
+// in the same format as a step.
+func (s *TaskSteps) ChildSteps() []TaskSteps {
+
+	if s.Children == nil || len(*s.Children) == 0 {
+		return nil
+	}
+
+	buffer, err := json.Marshal(s.Children)
+	if err != nil {
+		return nil
+	}
+
+	var children []TaskSteps
+	if err := json.Unmarshal(buffer, &children); err != nil {
+		return nil
+	}
+
+	return children
+}
+
 type TaskInterfaceInfo struct {
 	Url                string `json:"url" yaml:"url"`
 	Description        string `json:"description" yaml:"description"`
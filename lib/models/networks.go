@@ -1,6 +1,9 @@
 package models
 
-import "strings"
+import (
+	"github.com/spyroot/tcactl/lib/api_errors"
+	"strings"
+)
 
 const (
 	// NetworkActive is active or not
@@ -89,6 +92,11 @@ func (m *NetworkNotFound) Error() string {
 	return " network '" + m.errMsg + "' not found"
 }
 
+// Unwrap - NetworkNotFound matches api_errors.ErrNotFound
+func (m *NetworkNotFound) Unwrap() error {
+	return api_errors.ErrNotFound
+}
+
 //GetNetwork return network spec as NetworkSpec
 func (n *CloudNetworks) GetNetwork(name string) (*NetworkSpec, error) {
 
@@ -0,0 +1,860 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"fmt"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	pathExtensions = "/hybridity/api/extensions"
+	pathPackages   = "/telco/api/vnfpkgm/v2/vnf_packages"
+	pathInstances  = "/telco/api/vnflcm/v2/vnf_instances"
+
+	entityInstance = "VnfInstance"
+
+	instantiated    = "INSTANTIATED"
+	notInstantiated = "NOT_INSTANTIATED"
+	nfTypeCnf       = "CNF"
+)
+
+// registerCatalog registers extensions, repositories, vnfpkgm and vnflcm api
+func (s *Server) registerCatalog() {
+
+	s.handle(http.MethodGet, pathExtensions, s.getExtensions)
+	s.handle(http.MethodPost, pathExtensions, s.createExtension)
+	s.handle(http.MethodGet, pathExtensions+"/types", s.getExtensionTypes)
+	s.handle(http.MethodGet, pathExtensions+"/"+reId, s.getExtension)
+	s.handle(http.MethodPost, pathExtensions+"/"+reId, s.updateExtension)
+	s.handle(http.MethodDelete, pathExtensions+"/"+reId, s.deleteExtension)
+
+	s.handle(http.MethodPost, "/hybridity/api/repositories/query", s.getRepos)
+	s.handle(http.MethodPost, "/hybridity/api/repositories", s.getRepos)
+
+	s.handle(http.MethodGet, pathPackages, s.getPackages)
+	s.handle(http.MethodPost, pathPackages, s.createPackage)
+	s.handle(http.MethodGet, pathPackages+"/"+reId, s.getPackage)
+	s.handle(http.MethodDelete, pathPackages+"/"+reId, s.deletePackage)
+	s.handle(http.MethodGet, pathPackages+"/"+reId+"/vnfd", s.getVnfd)
+	s.handle(http.MethodGet, pathPackages+"/"+reId+"/package_content", s.getPackageContent)
+	s.handle(http.MethodPut, pathPackages+"/"+reId+"/package_content", s.uploadPackageContent)
+
+	s.handle(http.MethodGet, "/telco/api/vnflcm/v2/extension/vnf_instances", s.getInstances)
+	s.handle(http.MethodPost, pathInstances, s.createInstance)
+	s.handle(http.MethodGet, pathInstances+"/"+reId, s.getInstance)
+	s.handle(http.MethodDelete, pathInstances+"/"+reId, s.deleteInstance)
+	s.handle(http.MethodPost, pathInstances+"/"+reId+"/instantiate", s.instantiate)
+	s.handle(http.MethodPost, pathInstances+"/"+reId+"/terminate", s.terminate)
+	s.handle(http.MethodPost, pathInstances+"/"+reId+"/scale", s.scale)
+	s.handle(http.MethodPost, pathInstances+"/"+reId+"/rollback", s.rollback)
+	s.handle(http.MethodPost, pathInstances+"/"+reId+"/retry", s.retryInstance)
+	s.handle(http.MethodPost, "/hybridity/api/vnflcm/v1/vnf_instances/"+reId+"/update_state", s.updateState)
+	s.handle(http.MethodGet, "/hybridity/api/vnflcm/v1/vnf_instances/"+reId+"/vnf_lcm_op_occs", s.instanceOperations)
+}
+
+func (s *Server) getExtensions(w http.ResponseWriter, _ *http.Request, _ []string) {
+	ext := response.Extensions{ExtensionsList: s.state.Extensions}
+	if ext.ExtensionsList == nil {
+		ext.ExtensionsList = []response.Extension{}
+	}
+	writeJSON(w, http.StatusOK, ext)
+}
+
+func (s *Server) getExtensionTypes(w http.ResponseWriter, _ *http.Request, _ []string) {
+
+	var types models.ExtensionTypes
+	_ = convert(map[string]interface{}{
+		"types": []map[string]interface{}{
+			{"type": models.ExtensionTypeRepository, "subTypes": []string{models.ExtensionHarborSubType}, "uri": pathExtensions},
+			{"type": models.ExtensionTypeSVNFM, "subTypes": []string{}, "uri": pathExtensions},
+		},
+	}, &types)
+
+	writeJSON(w, http.StatusOK, types)
+}
+
+func (s *Server) getExtension(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, ext := s.state.findExtension(args[0])
+	if ext == nil {
+		notFound(w, "extension", args[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response.Extensions{ExtensionsList: []response.Extension{*ext}})
+}
+
+// extensionFromSpec converts extension spec to extension, vim info
+// resolved from registered cloud providers by vim name.
+func (s *Server) extensionFromSpec(spec *specs.SpecExtension, ext *response.Extension) {
+
+	ext.Name = spec.Name
+	ext.Version = spec.Version
+	ext.Type = spec.Type
+	ext.ExtensionKey = spec.ExtensionKey
+	ext.ExtensionSubtype = spec.ExtensionSubtype
+	ext.Products = spec.Products
+	ext.AutoScaleEnabled = spec.AutoScaleEnabled
+	ext.AutoHealEnabled = spec.AutoHealEnabled
+
+	if spec.InterfaceInfo != nil {
+		ext.InterfaceInfo = &response.ExtensionInterfaceInfo{
+			Url:                spec.InterfaceInfo.Url,
+			Description:        spec.InterfaceInfo.Description,
+			TrustedCertificate: spec.InterfaceInfo.TrustedCertificate,
+		}
+	}
+	if spec.AccessInfo != nil {
+		ext.AccessInfo = &response.ExtensionAccessInfo{
+			Username: spec.AccessInfo.Username,
+			Password: spec.AccessInfo.Password,
+		}
+	}
+	if spec.AdditionalParameters != nil {
+		ext.AdditionalParameters = &response.ExtensionAdditionalParameters{
+			TrustAllCerts: spec.AdditionalParameters.TrustAllCerts,
+		}
+	}
+
+	ext.VimInfo = nil
+	for _, v := range spec.VimInfo {
+		info := response.ExtensionVimInfo{
+			VimName:       v.VimName,
+			VimId:         v.VimId,
+			VimSystemUUID: v.VimSystemUUID,
+		}
+		if _, t := s.state.findTenant(v.VimName); t != nil {
+			info.VimId = t.TenantID
+			info.VimSystemUUID = t.HcxUUID
+		}
+		ext.VimInfo = append(ext.VimInfo, info)
+	}
+}
+
+func (s *Server) createExtension(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var spec specs.SpecExtension
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	if len(spec.Name) == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "extension name is mandatory")
+		return
+	}
+
+	for _, e := range s.state.Extensions {
+		if e.Name == spec.Name {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("extension with name %s already exists", spec.Name))
+			return
+		}
+	}
+
+	ext := response.Extension{
+		ExtensionId: newId(),
+		State:       response.ActiveRepo,
+	}
+	s.extensionFromSpec(&spec, &ext)
+	s.state.Extensions = append(s.state.Extensions, ext)
+
+	writeJSON(w, http.StatusOK, ext)
+}
+
+func (s *Server) updateExtension(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, ext := s.state.findExtension(args[0])
+	if ext == nil {
+		notFound(w, "extension", args[0])
+		return
+	}
+
+	var spec specs.SpecExtension
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	s.extensionFromSpec(&spec, ext)
+	writeJSON(w, http.StatusOK, client.ExtensionUpdateReplay{ExtensionId: ext.ExtensionId, Updated: true})
+}
+
+func (s *Server) deleteExtension(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	i, ext := s.state.findExtension(args[0])
+	if ext == nil {
+		notFound(w, "extension", args[0])
+		return
+	}
+
+	s.state.Extensions = append(s.state.Extensions[:i], s.state.Extensions[i+1:]...)
+	writeJSON(w, http.StatusOK, client.ExtensionDeleteReplay{ExtensionId: args[0], Deleted: true})
+}
+
+// getRepos returns repository extensions attached to a vim
+func (s *Server) getRepos(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var q specs.RepoQuery
+	if !readJSON(w, r, &q) {
+		return
+	}
+
+	vimId := q.QueryFilter.ExtraFilter.VimID
+	_, t := s.state.findTenant(vimId)
+	if len(vimId) > 0 && t == nil {
+		writeJSON(w, http.StatusOK, response.ReposList{Items: []response.RepoSpec{}})
+		return
+	}
+
+	repos := response.ReposList{Items: []response.RepoSpec{}}
+	for _, e := range s.state.Extensions {
+		if e.Type != models.ExtensionTypeRepository || !attached(&e, t) {
+			continue
+		}
+
+		spec := response.RepoSpec{ID: e.ExtensionId, State: e.State}
+		if e.InterfaceInfo != nil {
+			spec.Repos = append(spec.Repos, response.Repos{Name: e.InterfaceInfo.Url, Type: e.ExtensionSubtype})
+		}
+		repos.Items = append(repos.Items, spec)
+	}
+
+	writeJSON(w, http.StatusOK, repos)
+}
+
+// attached return true if extension attached to a tenant, nil tenant matches all
+func attached(e *response.Extension, t *response.TenantsDetails) bool {
+
+	if t == nil {
+		return true
+	}
+
+	for _, v := range e.VimInfo {
+		if v.VimId == t.TenantID || v.VimId == t.VimID || v.VimName == t.VimName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPackages returns vnf packages, optionally filtered by SOL013 filter
+func (s *Server) getPackages(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	filter, err := client.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	pkgs := []response.VnfPackage{}
+	for _, p := range s.state.Packages {
+		ok, err := filter.Match(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+			return
+		}
+		if ok {
+			pkgs = append(pkgs, p)
+		}
+	}
+
+	start, end, ok := s.page(w, r, len(pkgs))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pkgs[start:end])
+}
+
+func (s *Server) createPackage(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var req client.PackageUpload
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.UserDefinedData.Name) == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "userDefinedData name is mandatory")
+		return
+	}
+
+	now := s.now()
+	pkg := response.VnfPackage{
+		PID:              newId(),
+		OnboardingState:  "CREATED",
+		OperationalState: "DISABLED",
+		UsageState:       "NOT_IN_USE",
+		VnfmInfo:         []interface{}{},
+		UserDefinedData: &models.UserDefinedData{
+			Name:         req.UserDefinedData.Name,
+			Tags:         req.UserDefinedData.Tags,
+			CreationDate: now,
+			LastUpdated:  now,
+			CreationUser: s.opts.Username,
+		},
+	}
+	s.state.Packages = append(s.state.Packages, pkg)
+
+	var resp client.PackageCreatedSuccess
+	resp.Id = pkg.PID
+	resp.OnboardingState = pkg.OnboardingState
+	resp.OperationalState = pkg.OperationalState
+	resp.UsageState = pkg.UsageState
+	resp.VnfmInfo = pkg.VnfmInfo
+	resp.UserDefinedData.Name = pkg.UserDefinedData.Name
+	resp.UserDefinedData.Tags = pkg.UserDefinedData.Tags
+	resp.Links.Self.Href = baseUrl(r) + pathPackages + "/" + pkg.PID
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) getPackage(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, pkg := s.state.findPackage(args[0])
+	if pkg == nil {
+		notFound(w, "vnf package", args[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pkg)
+}
+
+func (s *Server) deletePackage(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	i, pkg := s.state.findPackage(args[0])
+	if pkg == nil {
+		notFound(w, "vnf package", args[0])
+		return
+	}
+
+	for _, instance := range s.state.Instances {
+		if instance.VnfPkgId == pkg.PID {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("vnf package %s is used by instance %s", pkg.PID, instance.Name))
+			return
+		}
+	}
+
+	s.state.Packages = append(s.state.Packages[:i], s.state.Packages[i+1:]...)
+	delete(s.state.Contents, args[0])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getVnfd returns vnfd of onboarded package
+func (s *Server) getVnfd(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, pkg := s.state.findPackage(args[0])
+	if pkg == nil {
+		notFound(w, "vnf package", args[0])
+		return
+	}
+	if !pkg.IsOnboarded() {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("vnf package %s is not onboarded", pkg.PID))
+		return
+	}
+
+	var vdu response.VduPackage
+	vdu.Description = pkg.VnfProductName
+	vdu.Vnf.NodeType = "tosca.nodes.nfv.VNF"
+	vdu.Vnf.Properties = &response.ToscaProperties{
+		DescriptorId:      pkg.VnfdID,
+		Provider:          pkg.VnfProvider,
+		ProductName:       pkg.VnfProductName,
+		Version:           pkg.VnfdVersion,
+		Id:                pkg.VnfdID,
+		SoftwareVersion:   pkg.VnfSoftwareVersion,
+		DescriptorVersion: pkg.VnfdVersion,
+		FlavourId:         "default",
+		VnfmInfo:          []string{"gvnfmdriver"},
+	}
+
+	// package carries a single helm vdu named after the product
+	_ = convert([]map[string]interface{}{{
+		"vdu_id": pkg.VnfProductName,
+		"type":   "tosca.nodes.nfv.Vdu.Compute.Helm",
+		"properties": map[string]string{
+			"name":        pkg.VnfProductName,
+			"helmVersion": "3",
+		},
+	}}, &vdu.Vdus)
+
+	writeJSON(w, http.StatusOK, vdu)
+}
+
+func (s *Server) getPackageContent(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	b, ok := s.state.Contents[args[0]]
+	if !ok {
+		notFound(w, "package content", args[0])
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// uploadPackageContent stores CSAR and on-boards a package
+func (s *Server) uploadPackageContent(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, pkg := s.state.findPackage(args[0])
+	if pkg == nil {
+		notFound(w, "vnf package", args[0])
+		return
+	}
+
+	f, _, err := r.FormFile(client.MultipartFilePart)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	s.state.Contents[pkg.PID] = b
+	pkg.OnboardingState = "ONBOARDED"
+	pkg.OperationalState = "ENABLED"
+	if len(pkg.VnfdID) == 0 {
+		pkg.VnfdID = newId()
+	}
+	if len(pkg.VnfProductName) == 0 {
+		pkg.VnfProductName = pkg.UserDefinedData.Name
+	}
+	if len(pkg.VnfProvider) == 0 {
+		pkg.VnfProvider = "VMware"
+	}
+	if len(pkg.VnfSoftwareVersion) == 0 {
+		pkg.VnfSoftwareVersion = "1.0.0"
+	}
+	if len(pkg.VnfdVersion) == 0 {
+		pkg.VnfdVersion = "1.0.0"
+	}
+	pkg.UserDefinedData.NfType = nfTypeCnf
+	pkg.UserDefinedData.LastUpdated = s.now()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// lcmLinks links TCA attach to a CNF instance
+func lcmLinks(r *http.Request, id string) models.PolicyLinks {
+
+	base := baseUrl(r) + pathInstances + "/" + id
+	return models.PolicyLinks{
+		Self:        models.CnfPolicyUri{Href: base},
+		Instantiate: models.CnfPolicyUri{Href: base + "/instantiate"},
+		Terminate:   models.CnfPolicyUri{Href: base + "/terminate"},
+		Scale:       models.CnfPolicyUri{Href: base + "/scale"},
+		Rollback:    models.CnfPolicyUri{Href: base + "/rollback"},
+		Retry:       models.CnfPolicyUri{Href: base + "/retry"},
+		UpdateState: models.CnfPolicyUri{
+			Href: baseUrl(r) + "/hybridity/api/vnflcm/v1/vnf_instances/" + id + "/update_state",
+		},
+	}
+}
+
+// extended renders instance the way vnflcm extension api does
+func (s *Server) extended(r *http.Request, i *Instance) response.CnfLcmExtended {
+
+	return response.CnfLcmExtended{
+		CID:                    i.Id,
+		VnfInstanceName:        i.Name,
+		VnfInstanceDescription: i.Description,
+		VnfdID:                 i.VnfdId,
+		VnfPkgID:               i.VnfPkgId,
+		VnfCatalogName:         i.CatalogName,
+		VnfProvider:            i.Provider,
+		VnfProductName:         i.ProductName,
+		VnfSoftwareVersion:     i.SoftwareVersion,
+		VnfdVersion:            i.VnfdVersion,
+		InstantiationState:     i.InstantiationState,
+		NfType:                 i.NfType,
+		Links:                  lcmLinks(r, i.Id),
+		LastUpdated:            i.Updated,
+		CreationDate:           i.Created,
+		CreationUser:           s.opts.Username,
+		LastUpdateUser:         s.opts.Username,
+		VimConnectionInfo:      i.VimConnectionInfo,
+		LcmOperation:           i.LcmOperation,
+		LcmOperationState:      i.LcmOperationState,
+		Meta: response.CnfMetadata{
+			VnfPkgID:          i.VnfPkgId,
+			VnfCatalogName:    i.CatalogName,
+			NfType:            i.NfType,
+			LcmOperation:      i.LcmOperation,
+			LcmOperationState: i.LcmOperationState,
+		},
+	}
+}
+
+// getInstances returns CNF instances, optionally filtered by SOL013 filter
+func (s *Server) getInstances(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	filter, err := client.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	instances := []response.CnfLcmExtended{}
+	for _, i := range s.state.Instances {
+		e := s.extended(r, i)
+		ok, err := filter.Match(e)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+			return
+		}
+		if ok {
+			instances = append(instances, e)
+		}
+	}
+
+	start, end, ok := s.page(w, r, len(instances))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, instances[start:end])
+}
+
+func (s *Server) getInstance(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, i := s.state.findInstance(args[0])
+	if i == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response.LcmInfo{
+		Id:                     i.Id,
+		VnfInstanceName:        i.Name,
+		VnfInstanceDescription: i.Description,
+		VnfdId:                 i.VnfdId,
+		VnfProvider:            i.Provider,
+		VnfProductName:         i.ProductName,
+		VnfSoftwareVersion:     i.SoftwareVersion,
+		VnfdVersion:            i.VnfdVersion,
+		VimConnectionInfo:      i.VimConnectionInfo,
+		InstantiationState:     i.InstantiationState,
+		Metadata: &response.ExtendedMetadata{
+			VnfPkgId:          i.VnfPkgId,
+			VnfCatalogName:    i.CatalogName,
+			NfType:            i.NfType,
+			LcmOperation:      i.LcmOperation,
+			LcmOperationState: i.LcmOperationState,
+		},
+		Links: lcmLinks(r, i.Id),
+	})
+}
+
+func (s *Server) createInstance(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var req specs.LcmCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	var pkg *response.VnfPackage
+	for i := range s.state.Packages {
+		if s.state.Packages[i].VnfdID == req.VnfdId && len(req.VnfdId) > 0 {
+			pkg = &s.state.Packages[i]
+		}
+	}
+	if pkg == nil {
+		writeError(w, http.StatusBadRequest, "Bad Request",
+			fmt.Sprintf("vnfd %s not found", req.VnfdId))
+		return
+	}
+
+	now := s.now()
+	instance := &Instance{
+		Id:                 newId(),
+		Name:               req.VnfInstanceName,
+		Description:        req.VnfInstanceDescription,
+		VnfdId:             pkg.VnfdID,
+		VnfPkgId:           pkg.PID,
+		Provider:           pkg.VnfProvider,
+		ProductName:        pkg.VnfProductName,
+		SoftwareVersion:    pkg.VnfSoftwareVersion,
+		VnfdVersion:        pkg.VnfdVersion,
+		NfType:             nfTypeCnf,
+		InstantiationState: notInstantiated,
+		Created:            now,
+		Updated:            now,
+	}
+	if pkg.UserDefinedData != nil {
+		instance.CatalogName = pkg.UserDefinedData.Name
+	}
+
+	s.state.Instances = append(s.state.Instances, instance)
+	pkg.UsageState = "IN_USE"
+
+	var resp response.VNFInstantiate
+	resp.Id = instance.Id
+	resp.VnfInstanceName = instance.Name
+	resp.VnfInstanceDescription = instance.Description
+	resp.VnfdId = instance.VnfdId
+	resp.VnfProvider = instance.Provider
+	resp.VnfProductName = instance.ProductName
+	resp.VnfSoftwareVersion = instance.SoftwareVersion
+	resp.VnfdVersion = instance.VnfdVersion
+	resp.InstantiationState = instance.InstantiationState
+	resp.Metadata.VnfPkgId = instance.VnfPkgId
+	resp.Metadata.VnfCatalogName = instance.CatalogName
+	resp.Metadata.NfType = instance.NfType
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// lcmOperation starts lcm operation task, operation rejected
+// if previous operation still in progress.
+func (s *Server) lcmOperation(w http.ResponseWriter, action string, id string, f func(*Instance) bool) {
+
+	_, instance := s.state.findInstance(id)
+	if instance == nil {
+		notFound(w, "vnf instance", id)
+		return
+	}
+
+	if instance.LcmOperationState == LcmStateProcessing {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("operation %s in progress for %s", instance.LcmOperation, instance.Name))
+		return
+	}
+
+	if f != nil && !f(instance) {
+		return
+	}
+
+	task := s.startTask(action, entityInstance, instance.Id, instance.Name, "", nil)
+	w.Header().Set("Location", task.OperationId)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) instantiate(w http.ResponseWriter, r *http.Request, args []string) {
+
+	var req specs.LcmInstantiateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.lcmOperation(w, actionInstantiate, args[0], func(i *Instance) bool {
+		if i.InstantiationState == instantiated {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("instance %s already instantiated", i.Name))
+			return false
+		}
+		i.VimConnectionInfo = mergeVimConnectionInfo(i.VimConnectionInfo, req.VimConnectionInfo)
+		return true
+	})
+}
+
+// mergeVimConnectionInfo placement in instantiate request only
+// carries connection id and target node pool, vim stays as is.
+func mergeVimConnectionInfo(current, req []models.VimConnectionInfo) []models.VimConnectionInfo {
+
+	merged := append([]models.VimConnectionInfo(nil), current...)
+	for _, r := range req {
+		found := false
+		for k := range merged {
+			if merged[k].Id != r.Id {
+				continue
+			}
+			found = true
+			if r.Extra == nil || len(r.Extra.NodePoolId) == 0 {
+				break
+			}
+			extra := models.VimExtra{}
+			if merged[k].Extra != nil {
+				extra = *merged[k].Extra
+			}
+			extra.NodePoolId = r.Extra.NodePoolId
+			merged[k].Extra = &extra
+			break
+		}
+		if !found {
+			merged = append(merged, r)
+		}
+	}
+
+	return merged
+}
+
+func (s *Server) terminate(w http.ResponseWriter, r *http.Request, args []string) {
+
+	var req specs.LcmTerminateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.lcmOperation(w, actionTerminate, args[0], func(i *Instance) bool {
+		if i.InstantiationState != instantiated {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("instance %s is not instantiated", i.Name))
+			return false
+		}
+		return true
+	})
+}
+
+func (s *Server) scale(w http.ResponseWriter, r *http.Request, args []string) {
+
+	var req specs.LcmReconfigureRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.lcmOperation(w, actionScale, args[0], func(i *Instance) bool {
+		if i.InstantiationState != instantiated {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("instance %s is not instantiated", i.Name))
+			return false
+		}
+		return true
+	})
+}
+
+// rollback rolls back failed operation
+func (s *Server) rollback(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, instance := s.state.findInstance(args[0])
+	if instance == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	if instance.LcmOperationState != LcmStateFailedTemp {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("instance %s operation is not in %s state", instance.Name, LcmStateFailedTemp))
+		return
+	}
+
+	if instance.LcmOperation == actionInstantiate {
+		instance.InstantiationState = notInstantiated
+	}
+	instance.LcmOperationState = "ROLLED_BACK"
+	instance.Updated = s.now()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// retryInstance retries last failed operation
+func (s *Server) retryInstance(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, instance := s.state.findInstance(args[0])
+	if instance == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	if instance.LcmOperationState != LcmStateFailedTemp {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("instance %s operation is not in %s state", instance.Name, LcmStateFailedTemp))
+		return
+	}
+
+	var last *Task
+	for _, t := range s.state.Tasks {
+		if t.Item.EntityDetails.Id == instance.Id {
+			last = t
+		}
+	}
+	if last == nil {
+		writeError(w, http.StatusConflict, "Conflict", "no operation to retry")
+		return
+	}
+
+	s.resetTask(last)
+	s.markPending(last)
+	s.advanceTask(last)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// updateState resets instance state
+func (s *Server) updateState(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, instance := s.state.findInstance(args[0])
+	if instance == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	now := s.now()
+	instance.LcmOperationState = LcmStateCompleted
+	instance.Updated = now
+
+	resp := response.InstanceUpdate{
+		Id:               newId(),
+		OperationState:   instance.LcmOperationState,
+		StateEnteredTime: toMillis(now),
+		StartTime:        toMillis(now),
+		EndTime:          toMillis(now),
+		InstanceId:       instance.Id,
+		Operation:        instance.LcmOperation,
+		EntityName:       instance.Name,
+		EntityType:       entityInstance,
+		LastUpdated:      now,
+		LastUpdateUser:   s.opts.Username,
+		CreationDate:     now,
+		CreationUser:     s.opts.Username,
+	}
+	resp.TaskId = resp.Id
+	resp.OperationParams.NfInstanceId = instance.Id
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) deleteInstance(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	i, instance := s.state.findInstance(args[0])
+	if instance == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	if instance.InstantiationState == instantiated || instance.LcmOperationState == LcmStateProcessing {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("instance %s must be terminated before delete", instance.Name))
+		return
+	}
+
+	s.state.Instances = append(s.state.Instances[:i], s.state.Instances[i+1:]...)
+
+	inUse := false
+	for _, other := range s.state.Instances {
+		if other.VnfPkgId == instance.VnfPkgId {
+			inUse = true
+		}
+	}
+	if _, pkg := s.state.findPackage(instance.VnfPkgId); pkg != nil && !inUse {
+		pkg.UsageState = "NOT_IN_USE"
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,691 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+// defaultState mirrors objects lib/api unit tests expect in TCA,
+// cloud provider edge registered with management cluster
+// edge-mgmt-test01 and workload cluster edge-test01, catalog
+// unit_test instantiated as unit_test_instance and repositories.
+const defaultState = `
+{
+  "tenants": [
+    {
+      "tenantId": "BDC07231F50A4536AA6DCF6B8C04BA5C",
+      "vimName": "edge",
+      "tenantName": "DEFAULT",
+      "hcxCloudUrl": "https://tca-pod03-cp.cnfdemo.io",
+      "username": "administrator@vsphere.local",
+      "vimType": "VC",
+      "vimUrl": "https://tca-pod03-vc.cnfdemo.io",
+      "hcxUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+      "hcxTenantId": "BDC07231F50A4536AA6DCF6B8C04BA5C",
+      "vimId": "vmware_BDC07231F50A4536AA6DCF6B8C04BA5C",
+      "connection": {
+        "status": "ok",
+        "remoteStatus": "ok",
+        "vimConnectionStatus": "ok"
+      },
+      "compatible": true,
+      "id": "vmware_BDC07231F50A4536AA6DCF6B8C04BA5C",
+      "name": "edge",
+      "hasSupportedKubernetesVersion": true,
+      "isCustomizable": false
+    },
+    {
+      "tenantId": "5166B75EC34E45B7BAEB96875CD19253",
+      "vimName": "edge-mgmt-test01",
+      "tenantName": "edge-mgmt-test01",
+      "hcxCloudUrl": "https://tca-pod03-cp.cnfdemo.io",
+      "vimType": "kubernetes",
+      "vimUrl": "https://10.241.7.224:6443",
+      "hcxUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+      "hcxTenantId": "5166B75EC34E45B7BAEB96875CD19253",
+      "vimId": "vmware_5166B75EC34E45B7BAEB96875CD19253",
+      "connection": {
+        "status": "ok",
+        "remoteStatus": "ok",
+        "vimConnectionStatus": "ok"
+      },
+      "compatible": true,
+      "id": "vmware_5166B75EC34E45B7BAEB96875CD19253",
+      "name": "edge-mgmt-test01",
+      "clusterName": "edge-mgmt-test01",
+      "hasSupportedKubernetesVersion": true,
+      "clusterStatus": "ACTIVE",
+      "isCustomizable": false
+    },
+    {
+      "tenantId": "81EBC3E950E044C398EC36044AACD5A0",
+      "vimName": "edge-test01",
+      "tenantName": "edge-test01",
+      "hcxCloudUrl": "https://tca-pod03-cp.cnfdemo.io",
+      "vimType": "kubernetes",
+      "vimUrl": "https://10.241.7.191:6443",
+      "hcxUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+      "hcxTenantId": "81EBC3E950E044C398EC36044AACD5A0",
+      "vimId": "vmware_81EBC3E950E044C398EC36044AACD5A0",
+      "connection": {
+        "status": "ok",
+        "remoteStatus": "ok",
+        "vimConnectionStatus": "ok"
+      },
+      "compatible": true,
+      "id": "vmware_81EBC3E950E044C398EC36044AACD5A0",
+      "name": "edge-test01",
+      "clusterName": "edge-test01",
+      "hasSupportedKubernetesVersion": true,
+      "clusterStatus": "ACTIVE",
+      "isCustomizable": false
+    }
+  ],
+  "clusters": [
+    {
+      "id": "9ceb62ef-c48d-4504-86c5-cc9ce6ae1aae",
+      "clusterName": "edge-mgmt-test01",
+      "clusterType": "MANAGEMENT",
+      "vsphereClusterName": "hubsite",
+      "hcxUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+      "status": "ACTIVE",
+      "activeTasksCount": 0,
+      "clusterTemplate": {
+        "name": "edge-mgmt-template",
+        "version": "v1.20.4+vmware.1",
+        "id": "55e69a3c-d92b-40ca-be51-9c6585b89ad7"
+      },
+      "clusterUrl": "https://10.241.7.224:6443",
+      "endpointIP": "10.241.7.224",
+      "masterNodes": [
+        {
+          "cpu": 4,
+          "memory": 16384,
+          "name": "master",
+          "networks": [
+            {
+              "label": "MANAGEMENT",
+              "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+              "nameservers": [
+                "10.246.2.9"
+              ]
+            }
+          ],
+          "storage": 50,
+          "replica": 1,
+          "labels": [],
+          "cloneMode": "linkedClone"
+        }
+      ],
+      "workerNodes": [
+        {
+          "cpu": 4,
+          "memory": 131072,
+          "name": "default-pool01",
+          "networks": [
+            {
+              "label": "MANAGEMENT",
+              "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+              "nameservers": [
+                "10.246.2.9"
+              ]
+            }
+          ],
+          "storage": 80,
+          "replica": 1,
+          "labels": [
+            "type=pool01"
+          ],
+          "cloneMode": "linkedClone",
+          "config": {
+            "cpuManagerPolicy": {
+              "type": "kubernetes",
+              "policy": "default"
+            }
+          }
+        }
+      ],
+      "vimId": "vmware_BDC07231F50A4536AA6DCF6B8C04BA5C"
+    },
+    {
+      "id": "6df10113-3c76-48ce-a742-0869fadd60b4",
+      "clusterName": "edge-test01",
+      "clusterType": "WORKLOAD",
+      "vsphereClusterName": "hubsite",
+      "managementClusterId": "9ceb62ef-c48d-4504-86c5-cc9ce6ae1aae",
+      "hcxUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+      "status": "ACTIVE",
+      "activeTasksCount": 0,
+      "clusterTemplate": {
+        "name": "edge-workload-template",
+        "version": "v1.20.4+vmware.1",
+        "id": "c3e006c1-e6aa-4591-950b-6f3bedd944d3"
+      },
+      "clusterUrl": "https://10.241.7.191:6443",
+      "endpointIP": "10.241.7.191",
+      "masterNodes": [
+        {
+          "cpu": 4,
+          "memory": 16384,
+          "name": "master",
+          "networks": [
+            {
+              "label": "MANAGEMENT",
+              "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+              "nameservers": [
+                "10.246.2.9"
+              ]
+            }
+          ],
+          "storage": 50,
+          "replica": 1,
+          "labels": [],
+          "cloneMode": "linkedClone"
+        }
+      ],
+      "workerNodes": [
+        {
+          "cpu": 4,
+          "memory": 131072,
+          "name": "default-pool01",
+          "networks": [
+            {
+              "label": "MANAGEMENT",
+              "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+              "nameservers": [
+                "10.246.2.9"
+              ]
+            }
+          ],
+          "storage": 80,
+          "replica": 1,
+          "labels": [
+            "type=pool01"
+          ],
+          "cloneMode": "linkedClone",
+          "config": {
+            "cpuManagerPolicy": {
+              "type": "kubernetes",
+              "policy": "default"
+            }
+          }
+        }
+      ],
+      "vimId": "vmware_BDC07231F50A4536AA6DCF6B8C04BA5C"
+    }
+  ],
+  "nodePools": {
+    "9ceb62ef-c48d-4504-86c5-cc9ce6ae1aae": [
+      {
+        "cloneMode": "linkedClone",
+        "cpu": 4,
+        "id": "1b5e2a43-3e9b-4d0c-a1f4-4f0a8cfd29c7",
+        "labels": [
+          "type=pool01"
+        ],
+        "memory": 131072,
+        "name": "default-pool01",
+        "status": "ACTIVE",
+        "networks": [
+          {
+            "label": "MANAGEMENT",
+            "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+            "nameservers": [
+              "10.246.2.9"
+            ]
+          }
+        ],
+        "placementParams": [
+          {
+            "name": "hubsite",
+            "type": "ClusterComputeResource"
+          },
+          {
+            "name": "vsanDatastore",
+            "type": "Datastore"
+          },
+          {
+            "name": "k8s",
+            "type": "ResourcePool"
+          }
+        ],
+        "replica": 1,
+        "storage": 80,
+        "config": {
+          "cpuManagerPolicy": {
+            "type": "kubernetes",
+            "policy": "default"
+          }
+        },
+        "nodes": [
+          {
+            "vmName": "default-pool01-11b0a260"
+          }
+        ]
+      }
+    ],
+    "6df10113-3c76-48ce-a742-0869fadd60b4": [
+      {
+        "cloneMode": "linkedClone",
+        "cpu": 4,
+        "id": "f532cde9-e574-40b6-856d-78fdfc8be3b9",
+        "labels": [
+          "type=pool01"
+        ],
+        "memory": 131072,
+        "name": "default-pool01",
+        "status": "ACTIVE",
+        "networks": [
+          {
+            "label": "MANAGEMENT",
+            "networkName": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+            "nameservers": [
+              "10.246.2.9"
+            ]
+          }
+        ],
+        "placementParams": [
+          {
+            "name": "hubsite",
+            "type": "ClusterComputeResource"
+          },
+          {
+            "name": "vsanDatastore",
+            "type": "Datastore"
+          },
+          {
+            "name": "k8s",
+            "type": "ResourcePool"
+          }
+        ],
+        "replica": 1,
+        "storage": 80,
+        "config": {
+          "cpuManagerPolicy": {
+            "type": "kubernetes",
+            "policy": "default"
+          }
+        },
+        "nodes": [
+          {
+            "vmName": "default-pool01-fa67be37"
+          }
+        ]
+      }
+    ]
+  },
+  "templates": [
+    {
+      "clusterType": "MANAGEMENT",
+      "clusterConfig": {
+        "kubernetesVersion": "v1.20.4+vmware.1"
+      },
+      "masterNodes": [
+        {
+          "cpu": 4,
+          "memory": 16384,
+          "name": "master",
+          "networks": [
+            {
+              "label": "MANAGEMENT"
+            }
+          ],
+          "storage": 50,
+          "replica": 1,
+          "labels": [],
+          "cloneMode": "linkedClone"
+        }
+      ],
+      "name": "edge-mgmt-template",
+      "id": "55e69a3c-d92b-40ca-be51-9c6585b89ad7",
+      "workerNodes": [
+        {
+          "cpu": 4,
+          "memory": 131072,
+          "name": "default-pool01",
+          "networks": [
+            {
+              "label": "MANAGEMENT"
+            }
+          ],
+          "storage": 80,
+          "replica": 1,
+          "labels": [
+            "type=pool01"
+          ],
+          "cloneMode": "linkedClone",
+          "config": {
+            "cpuManagerPolicy": {
+              "type": "kubernetes",
+              "policy": "default"
+            }
+          }
+        }
+      ]
+    },
+    {
+      "clusterType": "WORKLOAD",
+      "clusterConfig": {
+        "cni": [
+          {
+            "name": "multus"
+          },
+          {
+            "name": "calico"
+          }
+        ],
+        "csi": [
+          {
+            "name": "vsphere-csi",
+            "properties": {
+              "name": "vsphere-sc",
+              "isDefault": true,
+              "timeout": "300"
+            }
+          },
+          {
+            "name": "nfs_client",
+            "properties": {
+              "name": "nfs-client",
+              "isDefault": false
+            }
+          }
+        ],
+        "kubernetesVersion": "v1.20.4+vmware.1",
+        "tools": [
+          {
+            "name": "helm",
+            "version": "2.17.0"
+          }
+        ]
+      },
+      "masterNodes": [
+        {
+          "cpu": 4,
+          "memory": 16384,
+          "name": "master",
+          "networks": [
+            {
+              "label": "MANAGEMENT"
+            }
+          ],
+          "storage": 50,
+          "replica": 1,
+          "labels": [],
+          "cloneMode": "linkedClone"
+        }
+      ],
+      "name": "edge-workload-template",
+      "id": "c3e006c1-e6aa-4591-950b-6f3bedd944d3",
+      "workerNodes": [
+        {
+          "cpu": 4,
+          "memory": 131072,
+          "name": "default-pool01",
+          "networks": [
+            {
+              "label": "MANAGEMENT"
+            }
+          ],
+          "storage": 80,
+          "replica": 1,
+          "labels": [
+            "type=pool01"
+          ],
+          "cloneMode": "linkedClone",
+          "config": {
+            "cpuManagerPolicy": {
+              "type": "kubernetes",
+              "policy": "default"
+            }
+          }
+        }
+      ]
+    }
+  ],
+  "extensions": [
+    {
+      "extensionId": "0b4c6e2e-6c4e-4a55-9b8f-2c7c3e3f9a11",
+      "name": "Repo",
+      "type": "Repository",
+      "interfaceInfo": {
+        "url": "https://Repo.cnfdemo.io/chartrepo/library"
+      },
+      "accessInfo": {
+        "username": "admin"
+      },
+      "additionalParameters": {
+        "trustAllCerts": true
+      },
+      "state": "ENABLED",
+      "extensionSubtype": "Harbor",
+      "vimInfo": [
+        {
+          "vimName": "edge",
+          "vimId": "BDC07231F50A4536AA6DCF6B8C04BA5C",
+          "vimSystemUUID": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545"
+        }
+      ],
+      "version": "2.x",
+      "vnfCount": 0,
+      "vnfCatalogCount": 0,
+      "autoScaleEnabled": false,
+      "autoHealEnabled": false
+    },
+    {
+      "extensionId": "5b3b6d0e-7a1c-4b8e-9f3a-6a1e2c0d4f21",
+      "name": "test_repo",
+      "type": "Repository",
+      "extensionSubtype": "Harbor",
+      "version": "v2.x",
+      "interfaceInfo": {
+        "url": "https://1.1.1.1"
+      },
+      "accessInfo": {
+        "username": "admin",
+        "password": "Vk13YXJlMSE="
+      },
+      "additionalParameters": {
+        "trustAllCerts": true
+      },
+      "state": "ENABLED"
+    }
+  ],
+  "packages": [
+    {
+      "id": "7e2a0f0c-5d35-4c2f-9a77-3f4b8d0f1e10",
+      "vnfdId": "nginx_d8d1e5e8-7e58-4a31-9d89-2b1c3a0d9f57",
+      "vnfProvider": "VMware",
+      "vnfProductName": "nginx",
+      "vnfSoftwareVersion": "1.0.0",
+      "vnfdVersion": "1.0.0",
+      "onboardingState": "ONBOARDED",
+      "operationalState": "ENABLED",
+      "usageState": "IN_USE",
+      "userDefinedData": {
+        "name": "unit_test",
+        "nfType": "CNF"
+      }
+    }
+  ],
+  "instances": [
+    {
+      "id": "a3f6c0b2-48d6-4d6c-a6b4-1a4bd1d7e2c3",
+      "name": "unit_test_instance",
+      "description": "unit test instance",
+      "vnfdId": "nginx_d8d1e5e8-7e58-4a31-9d89-2b1c3a0d9f57",
+      "vnfPkgId": "7e2a0f0c-5d35-4c2f-9a77-3f4b8d0f1e10",
+      "catalogName": "unit_test",
+      "provider": "VMware",
+      "productName": "nginx",
+      "softwareVersion": "1.0.0",
+      "vnfdVersion": "1.0.0",
+      "nfType": "CNF",
+      "instantiationState": "INSTANTIATED",
+      "lcmOperation": "INSTANTIATE",
+      "lcmOperationState": "COMPLETED",
+      "vimConnectionInfo": [
+        {
+          "id": "vmware_81EBC3E950E044C398EC36044AACD5A0",
+          "vimType": "kubernetes",
+          "extra": {
+            "nodePoolId": "f532cde9-e574-40b6-856d-78fdfc8be3b9",
+            "nodePoolName": "default-pool01",
+            "vimName": "edge-test01"
+          },
+          "vimId": "81EBC3E950E044C398EC36044AACD5A0"
+        }
+      ],
+      "created": "2021-06-01T10:00:00Z",
+      "updated": "2021-06-01T10:00:00Z"
+    }
+  ],
+  "versions": {
+    "items": [
+      {
+        "clusterType": "MANAGEMENT",
+        "supportedVersions": [
+          "v1.20.4+vmware.1",
+          "v1.20.5+vmware.2",
+          "v1.21.2+vmware.1"
+        ]
+      },
+      {
+        "clusterType": "WORKLOAD",
+        "supportedVersions": [
+          "v1.20.4+vmware.1",
+          "v1.20.5+vmware.2",
+          "v1.21.2+vmware.1"
+        ]
+      }
+    ]
+  },
+  "consumption": {
+    "licenseQuantity": 100,
+    "consumedQuantity": 8,
+    "transformationFactor": 1,
+    "licenseUnit": "CPU_PACKAGE",
+    "licenseDisplayUnit": "CPU Package",
+    "rawUsageUnit": "vCPU",
+    "lastSyncTimestamp": 0,
+    "details": [
+      {
+        "vimId": "vmware_BDC07231F50A4536AA6DCF6B8C04BA5C",
+        "vimName": "edge",
+        "vimUrl": "https://tca-pod03-vc.cnfdemo.io",
+        "vimType": "VC",
+        "tenantName": "DEFAULT",
+        "consumedQuantity": 8
+      }
+    ]
+  },
+  "inventory": {
+    "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545": {
+      "clusters": {
+        "items": [
+          {
+            "entity_id": "domain-c8",
+            "name": "hubsite",
+            "entityType": "cluster",
+            "numOfHosts": 4,
+            "datastore": [
+              {
+                "entity_id": "datastore-13",
+                "name": "vsanDatastore",
+                "Summary": {
+                  "accessible": "true",
+                  "capacity": 7681529004032,
+                  "freeSpace": 5207418290176,
+                  "maintenanceMode": "normal",
+                  "multipleHostAccess": "true",
+                  "type": "vsan",
+                  "url": "ds:///vmfs/volumes/vsan:528724284ea01639-d098d64191b96c2a/"
+                }
+              }
+            ],
+            "memory": 1649013194752,
+            "cpu": 224,
+            "k8ClusterDeployed": 0,
+            "numK8sMgmtClusterDeployed": 0,
+            "numK8sWorkloadClusterDeployed": 0
+          }
+        ]
+      },
+      "folders": {
+        "success": true,
+        "completed": true,
+        "time": 0,
+        "data": {
+          "items": [
+            {
+              "entity_id": "group-v1032",
+              "name": "tkg",
+              "entityType": "folder",
+              "id": "group-v1032",
+              "displayName": "tkg",
+              "objectAttributes": {
+                "childType": [
+                  "Folder",
+                  "VirtualMachine",
+                  "VirtualApp"
+                ]
+              }
+            }
+          ]
+        }
+      },
+      "resourcePools": {
+        "success": true,
+        "completed": true,
+        "time": 0,
+        "data": {
+          "items": [
+            {
+              "entity_id": "resgroup-1030",
+              "name": "k8s",
+              "entityType": "resourcePool"
+            }
+          ]
+        }
+      },
+      "networks": [
+        {
+          "status": "ACTIVE",
+          "tenantId": "20210212053126765-51947d48-91b1-447e-ba40-668eb411f545",
+          "id": "dvportgroup-69009",
+          "name": "tkg-dhcp-vlan1007-10.241.7.0",
+          "dvsName": "core02-services",
+          "fullNetworkPath": "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0",
+          "networkType": "vlan",
+          "isShared": false,
+          "type": "DistributedVirtualPortgroup"
+        }
+      ],
+      "vmTemplates": {
+        "items": [
+          {
+            "id": "vm-1040",
+            "entity_id": "vm-1040",
+            "entityType": "vm",
+            "name": "photon-3-kube-v1.20.4+vmware.1",
+            "isTemplate": true,
+            "snapshotsCount": 0,
+            "mountedISOCount": 0,
+            "powerState": "poweredOff"
+          }
+        ]
+      }
+    }
+  }
+}`
@@ -0,0 +1,586 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"net/http"
+	"strings"
+)
+
+const (
+	pathClusters  = "/hybridity/api/infra/k8s/clusters"
+	pathCluster   = "/hybridity/api/infra/k8s/cluster"
+	pathTemplates = "/hybridity/api/infra/cluster-templates"
+	pathOperation = "/hybridity/api/infra/k8s/operations"
+	reId          = "([^/]+)"
+
+	entityCluster  = "Cluster"
+	entityNodePool = "NodePool"
+
+	// placementCompute placement type of vSphere cluster
+	placementCompute = "ClusterComputeResource"
+)
+
+// registerInfra registers k8s clusters, node pools, templates and task api
+func (s *Server) registerInfra() {
+
+	s.handle(http.MethodGet, pathClusters, s.getClusters)
+	s.handle(http.MethodPost, pathClusters, s.createCluster)
+	s.handle(http.MethodGet, pathClusters+"/"+reId, s.getCluster)
+	s.handle(http.MethodDelete, pathClusters+"/"+reId, s.deleteCluster)
+	s.handle(http.MethodGet, pathClusters+"/"+reId+"/tasks", s.clusterTasks)
+	s.handle(http.MethodPut, pathClusters+"/"+reId+"/changePassword", s.changePassword)
+	s.handle(http.MethodPut, pathClusters+"/"+reId+"/upgrade", s.upgradeCluster)
+
+	s.handle(http.MethodGet, pathCluster+"/"+reId+"/nodepools", s.getPools)
+	s.handle(http.MethodPost, pathCluster+"/"+reId+"/nodepool", s.createPool)
+	s.handle(http.MethodGet, pathCluster+"/"+reId+"/nodepool/"+reId, s.getPool)
+	s.handle(http.MethodPut, pathCluster+"/"+reId+"/nodepool/"+reId, s.updatePool)
+	s.handle(http.MethodDelete, pathCluster+"/"+reId+"/nodepool/"+reId, s.deletePool)
+	// upgrade api doesn't have /api prefix
+	s.handle(http.MethodPost, "/hybridity/infra/k8s/cluster/"+reId+"/nodepool/"+reId+"/upgrade", s.upgradePool)
+
+	s.handle(http.MethodPost, "/hybridity/api/infra/k8s/tasks", s.tasksQuery)
+	s.handle(http.MethodPost, pathOperation+"/"+reId+"/retry", s.retryTask)
+	s.handle(http.MethodPost, pathOperation+"/"+reId+"/abort", s.abortTask)
+	s.handle(http.MethodGet, "/hybridity/api/infra/k8s/supportedK8sVersions", s.getVersions)
+
+	s.handle(http.MethodGet, pathTemplates, s.getTemplates)
+	s.handle(http.MethodPost, pathTemplates, s.createTemplate)
+	s.handle(http.MethodGet, pathTemplates+"/"+reId, s.getTemplate)
+	s.handle(http.MethodPut, pathTemplates+"/"+reId, s.updateTemplate)
+	s.handle(http.MethodDelete, pathTemplates+"/"+reId, s.deleteTemplate)
+}
+
+// convert copies src to dst via json, used for types
+// that share json layout.
+func convert(src interface{}, dst interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func (s *Server) getClusters(w http.ResponseWriter, _ *http.Request, _ []string) {
+	clusters := s.state.Clusters
+	if clusters == nil {
+		clusters = []response.ClusterSpec{}
+	}
+	writeJSON(w, http.StatusOK, clusters)
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, _ *http.Request, args []string) {
+	_, c := s.state.findCluster(args[0])
+	if c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// clusterNodes builds cluster nodes from a template and spec nodes
+func clusterNodes(nodes []models.TypeNode, size func(name string) *response.ClusterNodeSpec) []response.ClusterNodeSpec {
+
+	var result []response.ClusterNodeSpec
+	for _, n := range nodes {
+		node := response.ClusterNodeSpec{Name: n.Name, Replica: 1, CloneMode: specs.LinkedClone}
+		if t := size(n.Name); t != nil {
+			node = *t
+		}
+		node.Networks = n.Networks
+		result = append(result, node)
+	}
+
+	return result
+}
+
+// newPool builds node pool from a cluster node
+func newPool(node response.ClusterNodeSpec, placement []models.PlacementParams) response.NodesSpecs {
+
+	pool := response.NodesSpecs{
+		Id:              newId(),
+		Name:            node.Name,
+		CloneMode:       node.CloneMode,
+		Cpu:             node.Cpu,
+		Memory:          node.Memory,
+		Storage:         node.Storage,
+		Replica:         node.Replica,
+		Labels:          node.Labels,
+		PlacementParams: placement,
+	}
+
+	for _, n := range node.Networks {
+		pool.Networks = append(pool.Networks, models.Network{
+			Label:       n.Label,
+			NetworkName: n.NetworkName,
+			Nameservers: n.Nameservers,
+		})
+	}
+
+	if node.Config != nil {
+		pool.Config = &models.NodePoolConfig{}
+		_ = convert(node.Config, pool.Config)
+	}
+
+	pool.Nodes = poolNodes(pool.Name, pool.Replica)
+	return pool
+}
+
+// poolNodes generates pool vm names
+func poolNodes(name string, replica int) []models.Nodes {
+	var nodes []models.Nodes
+	for i := 0; i < replica; i++ {
+		nodes = append(nodes, models.Nodes{
+			VmName: fmt.Sprintf("%s-%s", name, newId()[:8]),
+		})
+	}
+	return nodes
+}
+
+// createCluster creates cluster and it's node pools from
+// a cluster spec, node sizes taken from a cluster template.
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var spec specs.SpecCluster
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	for _, c := range s.state.Clusters {
+		if c.ClusterName == spec.Name {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("cluster with name %s already exists", spec.Name))
+			return
+		}
+	}
+
+	_, template := s.state.findTemplate(spec.ClusterTemplateId)
+	if template == nil {
+		writeError(w, http.StatusBadRequest, "Bad Request",
+			fmt.Sprintf("cluster template %s not found", spec.ClusterTemplateId))
+		return
+	}
+
+	var tenant *response.TenantsDetails
+	for i := range s.state.Tenants {
+		if len(spec.HcxCloudUrl) > 0 && s.state.Tenants[i].HcxCloudURL == spec.HcxCloudUrl {
+			tenant = &s.state.Tenants[i]
+			break
+		}
+	}
+	if tenant == nil {
+		writeError(w, http.StatusBadRequest, "Bad Request",
+			fmt.Sprintf("cloud provider %s not found", spec.HcxCloudUrl))
+		return
+	}
+
+	if strings.ToUpper(spec.ClusterType) == string(specs.ClusterWorkload) {
+		if _, mgmt := s.state.findCluster(spec.ManagementClusterId); mgmt == nil {
+			writeError(w, http.StatusBadRequest, "Bad Request",
+				fmt.Sprintf("management cluster %s not found", spec.ManagementClusterId))
+			return
+		}
+	}
+
+	var k8sVersion string
+	if template.ClusterConfig != nil {
+		k8sVersion = template.ClusterConfig.KubernetesVersion
+	}
+
+	cluster := response.ClusterSpec{
+		Id:                  newId(),
+		ClusterName:         spec.Name,
+		ClusterType:         strings.ToUpper(spec.ClusterType),
+		ManagementClusterId: spec.ManagementClusterId,
+		HcxUUID:             tenant.HcxUUID,
+		VimId:               tenant.VimID,
+		ClusterTemplate: &response.ClusterSpecTemplate{
+			Name:    template.Name,
+			Version: k8sVersion,
+			Id:      template.Id,
+		},
+		EndpointIP: spec.EndpointIP,
+		ClusterUrl: "https://" + spec.EndpointIP + ":6443",
+	}
+
+	for _, p := range spec.PlacementParams {
+		if p.Type == placementCompute {
+			cluster.VsphereClusterName = p.Name
+		}
+	}
+
+	cluster.MasterNodes = clusterNodes(spec.MasterNodes, func(name string) *response.ClusterNodeSpec {
+		for _, n := range template.MasterNodes {
+			if n.Name == name {
+				return &response.ClusterNodeSpec{Name: n.Name, Cpu: n.Cpu, Memory: n.Memory, Storage: n.Storage,
+					Replica: n.Replica, Labels: n.Labels, CloneMode: n.CloneMode}
+			}
+		}
+		return nil
+	})
+
+	cluster.WorkerNodes = clusterNodes(spec.WorkerNodes, func(name string) *response.ClusterNodeSpec {
+		for _, n := range template.WorkerNodes {
+			if n.Name == name {
+				node := &response.ClusterNodeSpec{Name: n.Name, Cpu: n.Cpu, Memory: n.Memory, Storage: n.Storage,
+					Replica: n.Replica, Labels: n.Labels, CloneMode: n.CloneMode}
+				if n.Config.CpuManagerPolicy != nil || n.Config.HealthCheckSpec != nil {
+					node.Config = &models.NodeConfig{}
+					_ = convert(n.Config, node.Config)
+				}
+				return node
+			}
+		}
+		return nil
+	})
+
+	var pools []response.NodesSpecs
+	for i, n := range cluster.WorkerNodes {
+		placement := spec.WorkerNodes[i].PlacementParams
+		if len(placement) == 0 {
+			placement = spec.PlacementParams
+		}
+		pools = append(pools, newPool(n, placement))
+	}
+
+	s.state.Clusters = append(s.state.Clusters, cluster)
+	s.state.NodePools[cluster.Id] = pools
+
+	task := s.startTask(actionClusterCreate, entityCluster, cluster.Id, cluster.ClusterName, cluster.Id, nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) deleteCluster(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, c := s.state.findCluster(args[0])
+	if c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	if c.ClusterType == string(specs.ClusterManagement) {
+		for _, other := range s.state.Clusters {
+			if other.ManagementClusterId == c.Id {
+				writeError(w, http.StatusBadRequest, "Bad Request",
+					fmt.Sprintf("management cluster %s has workload cluster %s", c.ClusterName, other.ClusterName))
+				return
+			}
+		}
+	}
+
+	task := s.startTask(actionClusterDelete, entityCluster, c.Id, c.ClusterName, c.Id, nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) changePassword(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, c := s.state.findCluster(args[0])
+	if c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	var req client.PasswordUpdateSpec
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.ClusterPassword) == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "clusterPassword is empty")
+		return
+	}
+
+	task := s.startTask(actionClusterPassword, entityCluster, c.Id, c.ClusterName, c.Id, nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) upgradeCluster(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, c := s.state.findCluster(args[0])
+	if c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	var req client.ClusterUpgradeSpec
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if _, tmpl := s.state.findTemplate(req.ClusterTemplateId); tmpl == nil {
+		notFound(w, "cluster template", req.ClusterTemplateId)
+		return
+	}
+
+	task := s.startTask(actionClusterUpgrade, entityCluster, c.Id, c.ClusterName, c.Id, req)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) getPools(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	if _, c := s.state.findCluster(args[0]); c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	pools := response.NodePool{Pools: s.state.NodePools[args[0]]}
+	if pools.Pools == nil {
+		pools.Pools = []response.NodesSpecs{}
+	}
+
+	writeJSON(w, http.StatusOK, pools)
+}
+
+func (s *Server) getPool(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, p := s.state.findPool(args[0], args[1])
+	if p == nil {
+		notFound(w, "node pool", args[1])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+// updatePoolSpec applies node pool spec to existing pool
+func updatePool(p *response.NodesSpecs, spec *specs.SpecNodePool) {
+
+	if spec.Replica > 0 && spec.Replica != p.Replica {
+		p.Replica = spec.Replica
+		p.Nodes = poolNodes(p.Name, p.Replica)
+	}
+	if spec.Cpu > 0 {
+		p.Cpu = spec.Cpu
+	}
+	if spec.Memory > 0 {
+		p.Memory = spec.Memory
+	}
+	if spec.Storage > 0 {
+		p.Storage = spec.Storage
+	}
+	if len(spec.CloneMode) > 0 {
+		p.CloneMode = spec.CloneMode
+	}
+	if spec.Labels != nil {
+		p.Labels = spec.Labels
+	}
+	if spec.Networks != nil {
+		p.Networks = spec.Networks
+	}
+	if spec.PlacementParams != nil {
+		p.PlacementParams = spec.PlacementParams
+	}
+	if spec.Config != nil {
+		p.Config = &models.NodePoolConfig{}
+		_ = convert(spec.Config, p.Config)
+	}
+}
+
+// validatePool validates node pool spec the way TCA does,
+// returns error message or empty string.
+func validatePool(spec *specs.SpecNodePool) string {
+	switch {
+	case len(spec.Name) == 0:
+		return "node pool name is empty"
+	case spec.Cpu <= 0:
+		return "node pool cpu must be greater than zero"
+	case spec.Memory <= 0:
+		return "node pool memory must be greater than zero"
+	case spec.Replica <= 0:
+		return "node pool replica must be greater than zero"
+	case len(spec.Networks) == 0:
+		return "node pool must have at least one network"
+	}
+	return ""
+}
+
+func (s *Server) createPool(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, c := s.state.findCluster(args[0])
+	if c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	var spec specs.SpecNodePool
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	if msg := validatePool(&spec); len(msg) > 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", msg)
+		return
+	}
+
+	for _, p := range s.state.NodePools[c.Id] {
+		if p.Name == spec.Name {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("node pool with name %s already exists", spec.Name))
+			return
+		}
+	}
+
+	pool := response.NodesSpecs{Id: newId(), Name: spec.Name, Replica: 1, CloneMode: specs.LinkedClone}
+	updatePool(&pool, &spec)
+	pool.Nodes = poolNodes(pool.Name, pool.Replica)
+	s.state.NodePools[c.Id] = append(s.state.NodePools[c.Id], pool)
+
+	task := s.startTask(actionPoolCreate, entityNodePool, pool.Id, pool.Name, c.Id, nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) updatePool(w http.ResponseWriter, r *http.Request, args []string) {
+
+	_, p := s.state.findPool(args[0], args[1])
+	if p == nil {
+		notFound(w, "node pool", args[1])
+		return
+	}
+
+	var spec specs.SpecNodePool
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	// TCA rejects changes to node sizing of existing pool
+	if spec.Cpu != p.Cpu || spec.Memory != p.Memory || spec.Storage != p.Storage {
+		writeError(w, http.StatusBadRequest, "Bad Request",
+			"Only Update of labels, replicas, and machine health check for node pools supported.")
+		return
+	}
+
+	task := s.startTask(actionPoolUpdate, entityNodePool, p.Id, p.Name, args[0], spec)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) deletePool(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, p := s.state.findPool(args[0], args[1])
+	if p == nil {
+		notFound(w, "node pool", args[1])
+		return
+	}
+
+	task := s.startTask(actionPoolDelete, entityNodePool, p.Id, p.Name, args[0], nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) upgradePool(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, p := s.state.findPool(args[0], args[1])
+	if p == nil {
+		notFound(w, "node pool", args[1])
+		return
+	}
+
+	task := s.startTask(actionPoolUpgrade, entityNodePool, p.Id, p.Name, args[0], nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) getVersions(w http.ResponseWriter, _ *http.Request, _ []string) {
+	writeJSON(w, http.StatusOK, s.state.Versions)
+}
+
+func (s *Server) getTemplates(w http.ResponseWriter, _ *http.Request, _ []string) {
+	templates := s.state.Templates
+	if templates == nil {
+		templates = []response.ClusterTemplateSpec{}
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (s *Server) getTemplate(w http.ResponseWriter, _ *http.Request, args []string) {
+	_, t := s.state.findTemplate(args[0])
+	if t == nil {
+		notFound(w, "cluster template", args[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) createTemplate(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var t response.ClusterTemplateSpec
+	if !readJSON(w, r, &t) {
+		return
+	}
+
+	if len(t.Name) == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "template name is empty")
+		return
+	}
+
+	for _, other := range s.state.Templates {
+		if other.Name == t.Name {
+			writeError(w, http.StatusConflict, "Conflict",
+				fmt.Sprintf("cluster template with name %s already exists", t.Name))
+			return
+		}
+	}
+
+	if len(t.Id) == 0 {
+		t.Id = newId()
+	}
+
+	s.state.Templates = append(s.state.Templates, t)
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) updateTemplate(w http.ResponseWriter, r *http.Request, args []string) {
+
+	i, old := s.state.findTemplate(args[0])
+	if old == nil {
+		notFound(w, "cluster template", args[0])
+		return
+	}
+
+	var t response.ClusterTemplateSpec
+	if !readJSON(w, r, &t) {
+		return
+	}
+
+	t.Id = old.Id
+	s.state.Templates[i] = t
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) deleteTemplate(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	i, t := s.state.findTemplate(args[0])
+	if t == nil {
+		notFound(w, "cluster template", args[0])
+		return
+	}
+
+	for _, c := range s.state.Clusters {
+		if c.ClusterTemplate != nil && c.ClusterTemplate.Id == t.Id {
+			writeError(w, http.StatusBadRequest, "Bad Request",
+				fmt.Sprintf("cluster template %s used by cluster %s", t.Name, c.ClusterName))
+			return
+		}
+	}
+
+	s.state.Templates = append(s.state.Templates[:i], s.state.Templates[i+1:]...)
+	writeJSON(w, http.StatusOK, map[string]string{"id": args[0]})
+}
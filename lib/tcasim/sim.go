@@ -0,0 +1,334 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/spyroot/tcactl/lib/client"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUsername default username simulator accepts
+	DefaultUsername = "administrator@vsphere.local"
+
+	// DefaultPassword default password simulator accepts
+	DefaultPassword = "VMware1!"
+
+	// DefaultStepDuration default time each task step takes
+	DefaultStepDuration = 2 * time.Second
+
+	// authorizationHeader - TCA authorization header
+	authorizationHeader = "x-hm-authorization"
+
+	// uriSessions - TCA session api
+	uriSessions = "/hybridity/api/sessions"
+)
+
+// Options simulator options
+type Options struct {
+	// Username and Password simulator accepts
+	Username string
+	Password string
+
+	// StepDuration time each task step takes, zero
+	// finishes a task on first query.
+	StepDuration time.Duration
+
+	// StateFile if set state saved after each mutation
+	StateFile string
+
+	// PageSize number of entities vnflcm and vnfpkgm list
+	// calls return per page, zero returns all entities.
+	PageSize int
+
+	// RetryLag number of task queries a retried operation still
+	// reports FAILED before it restarts, as TCA picks retry up
+	// asynchronously. Zero restarts operation on retry.
+	RetryLag int
+}
+
+// handlerFunc route handler, args are regexp sub matches
+type handlerFunc func(w http.ResponseWriter, r *http.Request, args []string)
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler handlerFunc
+}
+
+// Server in-memory TCA simulator. Server implements http.Handler,
+// all state kept in State and shared between calls.
+type Server struct {
+	opts     Options
+	state    *State
+	sessions map[string]time.Time
+	failures map[string]bool
+	routes   []route
+	lock     sync.Mutex
+
+	// now used to compute task progress
+	now func() time.Time
+}
+
+// NewServer creates a new simulator for a given state,
+// nil state creates simulator with default state.
+func NewServer(state *State, opts Options) *Server {
+
+	if state == nil {
+		state = DefaultState()
+	}
+
+	if len(opts.Username) == 0 {
+		opts.Username = DefaultUsername
+	}
+	if len(opts.Password) == 0 {
+		opts.Password = DefaultPassword
+	}
+
+	s := &Server{
+		opts:     opts,
+		state:    state.init(),
+		sessions: make(map[string]time.Time),
+		failures: make(map[string]bool),
+		now:      time.Now,
+	}
+
+	s.registerInfra()
+	s.registerVim()
+	s.registerCatalog()
+
+	return s
+}
+
+// State returns simulator state
+func (s *Server) State() *State {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state
+}
+
+// handle register handler for a method and path pattern
+func (s *Server) handle(method string, pattern string, h handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: regexp.MustCompile("^" + pattern + "$"),
+		handler: h,
+	})
+}
+
+// ServeHTTP dispatch request to a handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	w.Header().Set("X-Request-Id", uuid.New().String())
+	glog.Infof("tcasim %s %s", r.Method, r.URL.RequestURI())
+
+	if r.URL.Path == uriSessions {
+		s.session(w, r)
+		return
+	}
+
+	if _, ok := s.sessions[r.Header.Get(authorizationHeader)]; !ok {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "session key is missing or expired")
+		return
+	}
+
+	s.advanceTasks()
+
+	methodAllowed := true
+	for _, rt := range s.routes {
+		m := rt.pattern.FindStringSubmatch(r.URL.Path)
+		if m == nil {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = false
+			continue
+		}
+
+		rt.handler(w, r, m[1:])
+		if r.Method != http.MethodGet {
+			s.save()
+		}
+		return
+	}
+
+	if !methodAllowed {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed",
+			fmt.Sprintf("method %s not supported for %s", r.Method, r.URL.Path))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("%s not found", r.URL.Path))
+}
+
+// session handles login and logout
+func (s *Server) session(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if req.Username != s.opts.Username || req.Password != s.opts.Password {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid username or password")
+			return
+		}
+
+		key := uuid.New().String()
+		s.sessions[key] = s.now()
+		w.Header().Set(authorizationHeader, key)
+		writeJSON(w, http.StatusOK, map[string]string{"username": req.Username})
+
+	case http.MethodDelete:
+		delete(s.sessions, r.Header.Get(authorizationHeader))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "")
+	}
+}
+
+// ExpireSessions drop all sessions, next request
+// with old session key will be rejected.
+func (s *Server) ExpireSessions() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sessions = make(map[string]time.Time)
+}
+
+// save persist state if state file set
+func (s *Server) save() {
+	if len(s.opts.StateFile) == 0 {
+		return
+	}
+	if err := s.state.Save(s.opts.StateFile); err != nil {
+		glog.Errorf("failed save simulator state: %v", err)
+	}
+}
+
+// baseUrl return scheme and host request sent to
+func baseUrl(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// page returns a window of n entities for a SOL013 paged list call,
+// if more entities left it sets Link header with nextpage_opaque_marker.
+func (s *Server) page(w http.ResponseWriter, r *http.Request, n int) (int, int, bool) {
+
+	start := 0
+	if marker := r.URL.Query().Get(client.NextPageMarker); len(marker) > 0 {
+		b, err := base64.RawURLEncoding.DecodeString(marker)
+		if err == nil {
+			start, err = strconv.Atoi(string(b))
+		}
+		if err != nil || start < 0 || start > n {
+			writeError(w, http.StatusBadRequest, "Bad Request", "invalid "+client.NextPageMarker)
+			return 0, 0, false
+		}
+	}
+
+	if s.opts.PageSize <= 0 || start+s.opts.PageSize >= n {
+		return start, n, true
+	}
+
+	end := start + s.opts.PageSize
+	q := r.URL.Query()
+	q.Set(client.NextPageMarker, base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end))))
+	w.Header().Set("Link", fmt.Sprintf("<%s%s?%s>; rel=\"next\"", baseUrl(r), r.URL.Path, q.Encode()))
+
+	return start, end, true
+}
+
+// newId return new entity id
+func newId() string {
+	return uuid.New().String()
+}
+
+// writeJSON writes json respond
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+// writeError writes error in format TCA reports errors
+func writeError(w http.ResponseWriter, code int, kind string, msg string) {
+
+	b, _ := json.Marshal(map[string]string{
+		"error":   kind,
+		"message": msg,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+// notFound writes 404 for a given entity
+func notFound(w http.ResponseWriter, kind string, id string) {
+	writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("%s %s not found", kind, id))
+}
+
+// readJSON decodes request body, on error writes 400 and returns false
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return false
+	}
+
+	if len(strings.TrimSpace(string(b))) == 0 {
+		return true
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,347 @@
+package tcasim
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWorkloadClusterId = "6df10113-3c76-48ce-a742-0869fadd60b4"
+	testPoolId            = "f532cde9-e574-40b6-856d-78fdfc8be3b9"
+	testTenantId          = "BDC07231F50A4536AA6DCF6B8C04BA5C"
+)
+
+// fakeClock clock simulator uses to compute task progress
+type fakeClock struct {
+	s   *Server
+	now time.Time
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.s.lock.Lock()
+	defer c.s.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestServer starts simulator with default state and returns authenticated client
+func newTestServer(t *testing.T, opts Options) (*Server, *fakeClock, *client.RestClient) {
+
+	s := NewServer(nil, opts)
+	clock := &fakeClock{s: s, now: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)}
+	s.now = func() time.Time { return clock.now }
+
+	server := httptest.NewTLSServer(s)
+	t.Cleanup(server.Close)
+
+	c, err := client.NewRestClient(server.URL, true, DefaultUsername, DefaultPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := c.GetAuthorization(context.Background())
+	if !ok || err != nil {
+		t.Fatalf("GetAuthorization() ok = %v error = %v", ok, err)
+	}
+
+	return s, clock, c
+}
+
+func testPoolSpec(name string) *specs.SpecNodePool {
+	return &specs.SpecNodePool{
+		Name:      name,
+		CloneMode: specs.LinkedClone,
+		Cpu:       2,
+		Memory:    16384,
+		Replica:   2,
+		Storage:   50,
+		Labels:    []string{"type=" + name},
+		Networks: []models.Network{
+			{Label: "MANAGEMENT", NetworkName: "/Datacenter/network/tkg-dhcp-vlan1007-10.241.7.0"},
+		},
+		PlacementParams: []models.PlacementParams{
+			{Name: "hubsite", Type: placementCompute},
+		},
+	}
+}
+
+func TestServer_Authorization(t *testing.T) {
+
+	server := httptest.NewTLSServer(NewServer(nil, Options{}))
+	defer server.Close()
+
+	c, err := client.NewRestClient(server.URL, true, DefaultUsername, "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := c.GetAuthorization(context.Background())
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
+
+func TestServer_ExpiredSession(t *testing.T) {
+
+	s, _, c := newTestServer(t, Options{})
+	s.ExpireSessions()
+
+	clusters, err := c.GetClusters(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, clusters.Clusters, 2)
+}
+
+func TestServer_DefaultState(t *testing.T) {
+
+	_, _, c := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	cluster, err := c.GetCluster(ctx, testWorkloadClusterId)
+	assert.NoError(t, err)
+	assert.Equal(t, "edge-test01", cluster.ClusterName)
+
+	pool, err := c.GetClusterNodePool(ctx, testWorkloadClusterId, testPoolId)
+	assert.NoError(t, err)
+	assert.Equal(t, "default-pool01", pool.Name)
+
+	tenants, err := c.GetVimTenants(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tenants.TenantsList, 3)
+
+	repos, err := c.RepositoriesQuery(ctx, &specs.RepoQuery{
+		QueryFilter: specs.Filter{ExtraFilter: specs.AdditionalFilters{VimID: testTenantId}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, repos.Items, 1)
+
+	_, err = c.GetCluster(ctx, "868636c9-868f-49fb-a6df-6a0d2d137141")
+	assert.Error(t, err)
+}
+
+func TestServer_NodePoolTask(t *testing.T) {
+
+	_, clock, c := newTestServer(t, Options{StepDuration: 10 * time.Second})
+	ctx := context.Background()
+
+	task, err := c.CreateNewNodePool(ctx, testPoolSpec("pool02"), testWorkloadClusterId)
+	assert.NoError(t, err)
+
+	tasks, err := c.GetClustersTask(ctx, specs.NewClusterTaskQuery(task.Id))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks.Items, 1) {
+		assert.Equal(t, TaskStatusRunning, tasks.Items[0].Status)
+		assert.Equal(t, task.OperationId, tasks.Items[0].TaskId)
+	}
+
+	pool, err := c.GetClusterNodePool(ctx, testWorkloadClusterId, task.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCreating, pool.Status)
+
+	clock.advance(15 * time.Second)
+	tasks, err = c.GetClustersTask(ctx, specs.NewClusterTaskQuery(task.Id))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks.Items, 1) {
+		assert.Equal(t, TaskStatusRunning, tasks.Items[0].Status)
+		assert.Greater(t, tasks.Items[0].Progress, 0)
+	}
+
+	clock.advance(time.Minute)
+	tasks, err = c.GetClustersTask(ctx, specs.NewClusterTaskQuery(task.Id))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks.Items, 1) {
+		assert.Equal(t, TaskStatusSuccess, tasks.Items[0].Status)
+		assert.Equal(t, 100, tasks.Items[0].Progress)
+	}
+
+	pool, err = c.GetClusterNodePool(ctx, testWorkloadClusterId, task.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, pool.Status)
+	assert.Len(t, pool.Nodes, 2)
+}
+
+func TestServer_InjectFailure(t *testing.T) {
+
+	s, _, c := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	s.InjectFailure(testPoolId)
+	task, err := c.UpgradeNodePool(ctx, testWorkloadClusterId, testPoolId)
+	assert.NoError(t, err)
+
+	pool, err := c.GetClusterNodePool(ctx, testWorkloadClusterId, testPoolId)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFailed, pool.Status)
+
+	tasks, err := c.GetClustersTask(ctx, specs.NewClusterTaskQuery(testPoolId))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks.Items, 1) {
+		assert.Equal(t, TaskStatusFailed, tasks.Items[0].Status)
+	}
+
+	_, err = c.NodePoolRetryTask(ctx, task.OperationId)
+	assert.NoError(t, err)
+
+	pool, err = c.GetClusterNodePool(ctx, testWorkloadClusterId, testPoolId)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, pool.Status)
+
+	_, err = c.NodePoolRetryTask(ctx, task.OperationId)
+	assert.Error(t, err, "retry of successful task must fail")
+}
+
+func TestServer_CatalogAndLcm(t *testing.T) {
+
+	_, _, c := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	created, err := c.CreateVnfPkgmVnfd(ctx, client.NewPackageUpload("sim_test"))
+	assert.NoError(t, err)
+	assert.Contains(t, created.Links.Self.Href, created.Id)
+
+	ok, err := c.UploadVnfPkgmVnfd(ctx, created.Id, []byte("csar"), "sim_test.csar")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	b, err := c.GetVnfPkgmContent(ctx, created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "csar", string(b))
+
+	pkgs, err := c.GetVnfPkgm(ctx, "(eq,userDefinedData/name,sim_test)", "")
+	assert.NoError(t, err)
+	if !assert.Len(t, pkgs.Entity, 1) {
+		return
+	}
+	pkg := pkgs.Entity[0]
+	assert.True(t, pkg.IsOnboarded())
+	assert.True(t, pkg.IsCnf())
+
+	vnfd, err := c.GetVnfPkgmVnfd(ctx, pkg.PID)
+	assert.NoError(t, err)
+	assert.Equal(t, pkg.VnfdID, vnfd.Vnf.Properties.DescriptorId)
+
+	instance, err := c.CreateInstance(ctx, &specs.LcmCreateRequest{
+		VnfdId:          pkg.VnfdID,
+		VnfInstanceName: "sim_test_instance",
+	})
+	assert.NoError(t, err)
+
+	err = c.InstanceInstantiate(ctx, instance.Id, specs.LcmInstantiateRequest{
+		FlavourID: "default",
+		VimConnectionInfo: []models.VimConnectionInfo{
+			{Id: testTenantId, VimType: models.VimTypeKubernetes},
+		},
+	})
+	assert.NoError(t, err)
+
+	lcm, err := c.GetRunningVnflcm(ctx, instance.Id)
+	assert.NoError(t, err)
+	assert.True(t, lcm.IsInstantiated())
+	assert.Equal(t, LcmStateCompleted, lcm.Metadata.LcmOperationState)
+
+	_, err = c.DeleteVnfPkgmVnfd(ctx, pkg.PID)
+	assert.Error(t, err, "package used by instance must not be deleted")
+
+	err = c.TerminateInstance(ctx, lcm.Links.Terminate.Href, &specs.LcmTerminateRequest{})
+	assert.NoError(t, err)
+
+	lcm, err = c.GetRunningVnflcm(ctx, instance.Id)
+	assert.NoError(t, err)
+	assert.False(t, lcm.IsInstantiated())
+
+	occs, err := c.GetVnflcmOpOccs(ctx, instance.Id, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, occs.Items, 1) {
+		assert.Equal(t, actionTerminate, occs.Items[0].Type, "latest operation first")
+		assert.Equal(t, 2, occs.Paging.TotalSize)
+	}
+
+	assert.NoError(t, c.DeleteInstance(ctx, instance.Id))
+	ok, err = c.DeleteVnfPkgmVnfd(ctx, pkg.PID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestServer_Extensions(t *testing.T) {
+
+	_, _, c := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	id, err := c.CreateExtension(ctx, &specs.SpecExtension{
+		Name:             "harbor",
+		Version:          "2.x",
+		Type:             models.ExtensionTypeRepository,
+		ExtensionSubtype: models.ExtensionHarborSubType,
+		VimInfo:          []specs.VimInfo{{VimName: "edge-test01"}},
+		InterfaceInfo:    &specs.SpecInterfaceInfo{Url: "https://harbor.cnfdemo.io"},
+	})
+	assert.NoError(t, err)
+
+	ext, err := c.GetExtension(ctx, id)
+	assert.NoError(t, err)
+	if assert.Len(t, ext.ExtensionsList, 1) && assert.Len(t, ext.ExtensionsList[0].VimInfo, 1) {
+		assert.NotEmpty(t, ext.ExtensionsList[0].VimInfo[0].VimId)
+	}
+
+	deleted, err := c.DeleteExtension(ctx, id)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = c.GetExtension(ctx, id)
+	assert.Error(t, err)
+}
+
+func TestState_SaveLoad(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcasim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stateFile := filepath.Join(dir, "state.json")
+	_, _, c := newTestServer(t, Options{StateFile: stateFile})
+
+	task, err := c.CreateNewNodePool(context.Background(), testPoolSpec("pool03"), testWorkloadClusterId)
+	assert.NoError(t, err)
+
+	st, err := LoadState(stateFile)
+	assert.NoError(t, err)
+	_, pool := st.findPool(testWorkloadClusterId, task.Id)
+	if assert.NotNil(t, pool) {
+		assert.Equal(t, "pool03", pool.Name)
+	}
+}
+
+func TestServer_Paging(t *testing.T) {
+
+	_, _, c := newTestServer(t, Options{PageSize: 1})
+	ctx := context.Background()
+
+	_, err := c.CreateVnfPkgmVnfd(ctx, client.NewPackageUpload("sim_test"))
+	assert.NoError(t, err)
+
+	page, next, err := c.GetVnfPkgmPage(ctx, "", "")
+	assert.NoError(t, err)
+	assert.Len(t, page.Entity, 1)
+	assert.NotEmpty(t, next)
+
+	page, next, err = c.GetVnfPkgmPage(ctx, "", next)
+	assert.NoError(t, err)
+	assert.Len(t, page.Entity, 1)
+	assert.Empty(t, next, "last page must not have next page link")
+
+	_, _, err = c.GetVnfPkgmPage(ctx, "", "invalid")
+	assert.Error(t, err)
+
+	pkgs, err := c.GetVnfPkgm(ctx, "(eq,userDefinedData/name,sim_test)", "")
+	assert.NoError(t, err)
+	assert.Len(t, pkgs.Entity, 1)
+}
@@ -0,0 +1,213 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"encoding/json"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/models"
+	"io/ioutil"
+	"time"
+)
+
+// Inventory VMware VC inventory of a cloud provider
+type Inventory struct {
+	Clusters      models.VMwareClusters `json:"clusters"`
+	Folders       models.Folders        `json:"folders"`
+	ResourcePools models.ResourcePool   `json:"resourcePools"`
+	Networks      []models.NetworkSpec  `json:"networks"`
+	VmTemplates   models.VcInventory    `json:"vmTemplates"`
+}
+
+// Instance CNF instance, simulator renders it
+// as LcmInfo or CnfLcmExtended.
+type Instance struct {
+	Id                 string                     `json:"id"`
+	Name               string                     `json:"name"`
+	Description        string                     `json:"description"`
+	VnfdId             string                     `json:"vnfdId"`
+	VnfPkgId           string                     `json:"vnfPkgId"`
+	CatalogName        string                     `json:"catalogName"`
+	Provider           string                     `json:"provider"`
+	ProductName        string                     `json:"productName"`
+	SoftwareVersion    string                     `json:"softwareVersion"`
+	VnfdVersion        string                     `json:"vnfdVersion"`
+	NfType             string                     `json:"nfType"`
+	InstantiationState string                     `json:"instantiationState"`
+	LcmOperation       string                     `json:"lcmOperation"`
+	LcmOperationState  string                     `json:"lcmOperationState"`
+	VimConnectionInfo  []models.VimConnectionInfo `json:"vimConnectionInfo"`
+	Created            time.Time                  `json:"created"`
+	Updated            time.Time                  `json:"updated"`
+}
+
+// State simulator state, everything TCA knows about.
+type State struct {
+	Tenants     []response.TenantsDetails        `json:"tenants"`
+	Clusters    []response.ClusterSpec           `json:"clusters"`
+	NodePools   map[string][]response.NodesSpecs `json:"nodePools"`
+	Templates   []response.ClusterTemplateSpec   `json:"templates"`
+	Tasks       []*Task                          `json:"tasks"`
+	Extensions  []response.Extension             `json:"extensions"`
+	Packages    []response.VnfPackage            `json:"packages"`
+	Contents    map[string][]byte                `json:"contents"`
+	Instances   []*Instance                      `json:"instances"`
+	Versions    client.SupportedVersion          `json:"versions"`
+	Consumption models.ConsumptionResp           `json:"consumption"`
+	Inventory   map[string]*Inventory            `json:"inventory"`
+}
+
+// init make sure all maps allocated
+func (st *State) init() *State {
+	if st.NodePools == nil {
+		st.NodePools = make(map[string][]response.NodesSpecs)
+	}
+	if st.Contents == nil {
+		st.Contents = make(map[string][]byte)
+	}
+	if st.Inventory == nil {
+		st.Inventory = make(map[string]*Inventory)
+	}
+	return st
+}
+
+// DefaultState returns a state that matches lib/api unit test fixtures,
+// cloud provider edge, management cluster edge-mgmt-test01,
+// workload cluster edge-test01 and a pool default-pool01.
+func DefaultState() *State {
+
+	var st State
+	if err := json.Unmarshal([]byte(defaultState), &st); err != nil {
+		panic(err)
+	}
+
+	return st.init()
+}
+
+// LoadState loads state from a file
+func LoadState(fileName string) (*State, error) {
+
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var st State
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, err
+	}
+
+	return st.init(), nil
+}
+
+// Save saves state to a file
+func (st *State) Save(fileName string) error {
+
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0600)
+}
+
+// findTenant return tenant by tenant id, vim id or name
+func (st *State) findTenant(id string) (int, *response.TenantsDetails) {
+	for i := range st.Tenants {
+		t := &st.Tenants[i]
+		if t.TenantID == id || t.VimID == id || t.ID == id || t.Name == id {
+			return i, t
+		}
+	}
+	return -1, nil
+}
+
+// findCluster return cluster by id
+func (st *State) findCluster(id string) (int, *response.ClusterSpec) {
+	for i := range st.Clusters {
+		if st.Clusters[i].Id == id {
+			return i, &st.Clusters[i]
+		}
+	}
+	return -1, nil
+}
+
+// findPool return node pool by cluster id and pool id
+func (st *State) findPool(clusterId string, poolId string) (int, *response.NodesSpecs) {
+	pools := st.NodePools[clusterId]
+	for i := range pools {
+		if pools[i].Id == poolId {
+			return i, &pools[i]
+		}
+	}
+	return -1, nil
+}
+
+// findTemplate return cluster template by id
+func (st *State) findTemplate(id string) (int, *response.ClusterTemplateSpec) {
+	for i := range st.Templates {
+		if st.Templates[i].Id == id {
+			return i, &st.Templates[i]
+		}
+	}
+	return -1, nil
+}
+
+// findExtension return extension by id
+func (st *State) findExtension(id string) (int, *response.Extension) {
+	for i := range st.Extensions {
+		if st.Extensions[i].ExtensionId == id {
+			return i, &st.Extensions[i]
+		}
+	}
+	return -1, nil
+}
+
+// findPackage return vnf package by id
+func (st *State) findPackage(id string) (int, *response.VnfPackage) {
+	for i := range st.Packages {
+		if st.Packages[i].PID == id {
+			return i, &st.Packages[i]
+		}
+	}
+	return -1, nil
+}
+
+// findInstance return CNF instance by id
+func (st *State) findInstance(id string) (int, *Instance) {
+	for i, instance := range st.Instances {
+		if instance.Id == id {
+			return i, instance
+		}
+	}
+	return -1, nil
+}
+
+// findInventory return inventory of a cloud provider by hcx uuid,
+// if there is only one cloud provider it's inventory returned.
+func (st *State) findInventory(id string) *Inventory {
+	if inv, ok := st.Inventory[id]; ok {
+		return inv
+	}
+	if len(st.Inventory) == 1 {
+		for _, inv := range st.Inventory {
+			return inv
+		}
+	}
+	return nil
+}
@@ -0,0 +1,546 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"encoding/json"
+	"github.com/spyroot/tcactl/lib/client"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	TaskStatusQueued  = "QUEUED"
+	TaskStatusRunning = "RUNNING"
+	TaskStatusSuccess = "SUCCESS"
+	TaskStatusFailed  = "FAILED"
+
+	StatusActive    = "ACTIVE"
+	StatusCreating  = "CREATING"
+	StatusUpdating  = "UPDATING"
+	StatusUpgrading = "UPGRADING"
+	StatusDeleting  = "DELETING"
+	StatusFailed    = "FAILED"
+
+	LcmStateProcessing = "PROCESSING"
+	LcmStateCompleted  = "COMPLETED"
+	LcmStateFailedTemp = "FAILED_TEMP"
+)
+
+// task actions, action defines steps and effect of a task
+const (
+	actionClusterCreate   = "Cluster Creation"
+	actionClusterDelete   = "Cluster Deletion"
+	actionClusterPassword = "Cluster Password Update"
+	actionClusterUpgrade  = "Cluster Upgrade"
+	actionPoolCreate      = "Node Pool Creation"
+	actionPoolUpdate      = "Update Node Pool"
+	actionPoolDelete      = "Node Pool Deletion"
+	actionPoolUpgrade     = "Node Pool Upgrade"
+	actionTenantDelete    = "Vim Deletion"
+	actionInstantiate     = "INSTANTIATE"
+	actionTerminate       = "TERMINATE"
+	actionScale           = "SCALE"
+)
+
+// actionSteps steps each task action goes through
+var actionSteps = map[string][]string{
+	actionClusterCreate:   {"Validate request", "Deploy control plane", "Deploy worker nodes", "Install add-ons"},
+	actionClusterDelete:   {"Delete worker nodes", "Delete control plane"},
+	actionClusterPassword: {"Update node password"},
+	actionClusterUpgrade:  {"Validate upgrade", "Upgrade control plane"},
+	actionPoolCreate:      {"Validate request", "Deploy worker nodes", "Apply node configuration"},
+	actionPoolUpdate:      {"Validate request", "Reconcile worker nodes"},
+	actionPoolDelete:      {"Drain worker nodes", "Delete worker nodes"},
+	actionPoolUpgrade:     {"Validate upgrade", "Upgrade worker nodes"},
+	actionTenantDelete:    {"Remove cloud provider"},
+	actionInstantiate:     {"Grant", "Deploy helm charts"},
+	actionTerminate:       {"Delete helm charts"},
+	actionScale:           {"Grant", "Update helm charts"},
+}
+
+// Task a simulated TCA task, progress derived from task start time.
+type Task struct {
+	Item      models.TaskItems `json:"item"`
+	Action    string           `json:"action"`
+	ClusterId string           `json:"clusterId,omitempty"`
+	// Fail task fails on last step
+	Fail bool `json:"fail,omitempty"`
+	// Applied effect of a task applied to state
+	Applied bool `json:"applied,omitempty"`
+	// Payload request that applied when task finish
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// RetryLag task queries left before retried task restarts
+	RetryLag int `json:"retryLag,omitempty"`
+}
+
+// IsFinished return true if task finished
+func (t *Task) IsFinished() bool {
+	return t.Item.Status == TaskStatusSuccess || t.Item.Status == TaskStatusFailed
+}
+
+// toMillis time in milliseconds as TCA reports
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// startTask creates a new task for entity and returns TcaTask
+// caller responds with.
+func (s *Server) startTask(action string, entityType string, entityId string, entityName string,
+	clusterId string, payload interface{}) *models.TcaTask {
+
+	t := &Task{
+		Action:    action,
+		ClusterId: clusterId,
+	}
+
+	t.Item.TaskId = newId()
+	t.Item.Type = action
+	t.Item.EntityDetails.Id = entityId
+	t.Item.EntityDetails.Type = entityType
+	t.Item.EntityDetails.Name = entityName
+
+	if payload != nil {
+		t.Payload, _ = json.Marshal(payload)
+	}
+
+	if s.failures[entityId] {
+		t.Fail = true
+		delete(s.failures, entityId)
+	}
+
+	s.resetTask(t)
+	s.state.Tasks = append(s.state.Tasks, t)
+	s.markPending(t)
+	s.advanceTask(t)
+
+	return &models.TcaTask{Id: entityId, OperationId: t.Item.TaskId}
+}
+
+// resetTask moves task to initial state
+func (s *Server) resetTask(t *Task) {
+
+	now := toMillis(s.now())
+
+	t.Applied = false
+	t.Item.Status = TaskStatusQueued
+	t.Item.Progress = 0
+	t.Item.Message = ""
+	t.Item.StartTime = now
+	t.Item.EndTime = 0
+	t.Item.Steps = nil
+
+	for _, title := range actionSteps[t.Action] {
+		t.Item.Steps = append(t.Item.Steps, models.TaskSteps{
+			Title:  title,
+			Status: TaskStatusQueued,
+		})
+	}
+}
+
+// restartTask restarts retried task
+func (s *Server) restartTask(t *Task) {
+	s.resetTask(t)
+	s.markPending(t)
+	s.advanceTask(t)
+}
+
+// advanceTasks updates progress of all running tasks
+func (s *Server) advanceTasks() {
+	for _, t := range s.state.Tasks {
+		s.advanceTask(t)
+	}
+}
+
+// advanceTask updates task progress based on elapsed time, once
+// all steps done task finish and effect applied to state.
+func (s *Server) advanceTask(t *Task) {
+
+	if t.IsFinished() {
+		return
+	}
+
+	var (
+		now     = s.now()
+		started = time.Unix(0, t.Item.StartTime*int64(time.Millisecond))
+		elapsed = now.Sub(started)
+		steps   = len(t.Item.Steps)
+		total   = s.opts.StepDuration * time.Duration(steps)
+	)
+
+	if s.opts.StepDuration <= 0 || elapsed >= total {
+		s.finishTask(t)
+		return
+	}
+
+	current := int(elapsed / s.opts.StepDuration)
+	for i := range t.Item.Steps {
+		step := &t.Item.Steps[i]
+		stepStart := t.Item.StartTime + int64(i)*int64(s.opts.StepDuration/time.Millisecond)
+		switch {
+		case i < current:
+			step.Status = TaskStatusSuccess
+			step.Progress = 100
+			step.StartTime = stepStart
+			step.EndTime = stepStart + int64(s.opts.StepDuration/time.Millisecond)
+		case i == current:
+			step.Status = TaskStatusRunning
+			step.StartTime = stepStart
+			step.Progress = int((elapsed - time.Duration(i)*s.opts.StepDuration) * 100 / s.opts.StepDuration)
+		}
+	}
+
+	t.Item.Status = TaskStatusRunning
+	t.Item.Progress = int(elapsed * 100 / total)
+	t.Item.Message = t.Item.Steps[current].Title
+}
+
+// finishTask completes a task either success or failure.
+func (s *Server) finishTask(t *Task) {
+
+	now := toMillis(s.now())
+	for i := range t.Item.Steps {
+		step := &t.Item.Steps[i]
+		step.Status = TaskStatusSuccess
+		step.Progress = 100
+		if step.StartTime == 0 {
+			step.StartTime = now
+		}
+		step.EndTime = now
+	}
+
+	t.Item.EndTime = now
+	if t.Fail && len(t.Item.Steps) > 0 {
+		last := &t.Item.Steps[len(t.Item.Steps)-1]
+		last.Status = TaskStatusFailed
+		last.Progress = 0
+		last.Message = "simulated failure"
+		last.Errors = &[]models.TaskErrors{{
+			ErrorCode: "SIMULATED",
+			Message:   "simulated failure on step " + last.Title,
+		}}
+		t.Item.Status = TaskStatusFailed
+		t.Item.Message = "Failed: " + last.Title
+	} else {
+		t.Item.Status = TaskStatusSuccess
+		t.Item.Progress = 100
+		t.Item.Message = "Completed"
+	}
+
+	s.applyTask(t)
+}
+
+// applyTask applies effect of a finished task to state
+func (s *Server) applyTask(t *Task) {
+
+	if t.Applied {
+		return
+	}
+	t.Applied = true
+
+	failed := t.Item.Status == TaskStatusFailed
+	id := t.Item.EntityDetails.Id
+
+	switch t.Action {
+	case actionClusterCreate:
+		if _, c := s.state.findCluster(id); c != nil {
+			c.Status = StatusActive
+			if failed {
+				c.Status = StatusFailed
+				c.Error = t.Item.Message
+				return
+			}
+			s.registerClusterVim(c)
+		}
+	case actionClusterDelete:
+		if i, c := s.state.findCluster(id); c != nil {
+			if failed {
+				c.Status = StatusFailed
+				return
+			}
+			if j, vim := s.state.findTenant(c.ClusterName); vim != nil && vim.VimType == models.VimTypeKubernetes {
+				s.state.Tenants = append(s.state.Tenants[:j], s.state.Tenants[j+1:]...)
+			}
+			s.state.Clusters = append(s.state.Clusters[:i], s.state.Clusters[i+1:]...)
+			delete(s.state.NodePools, id)
+		}
+	case actionClusterUpgrade:
+		if _, c := s.state.findCluster(id); c != nil {
+			c.Status = StatusActive
+			if failed {
+				c.Status = StatusFailed
+				return
+			}
+			var spec client.ClusterUpgradeSpec
+			if err := json.Unmarshal(t.Payload, &spec); err == nil {
+				if _, tmpl := s.state.findTemplate(spec.ClusterTemplateId); tmpl != nil && c.ClusterTemplate != nil {
+					c.ClusterTemplate.Id = tmpl.Id
+					c.ClusterTemplate.Name = tmpl.Name
+					if tmpl.ClusterConfig != nil {
+						c.ClusterTemplate.Version = tmpl.ClusterConfig.KubernetesVersion
+					}
+				}
+			}
+		}
+	case actionPoolCreate, actionPoolUpgrade:
+		if _, p := s.state.findPool(t.ClusterId, id); p != nil {
+			p.Status = StatusActive
+			if failed {
+				p.Status = StatusFailed
+			}
+		}
+	case actionPoolUpdate:
+		if _, p := s.state.findPool(t.ClusterId, id); p != nil {
+			p.Status = StatusFailed
+			if !failed {
+				var spec specs.SpecNodePool
+				if err := json.Unmarshal(t.Payload, &spec); err == nil {
+					updatePool(p, &spec)
+				}
+				p.Status = StatusActive
+			}
+		}
+	case actionPoolDelete:
+		if i, p := s.state.findPool(t.ClusterId, id); p != nil {
+			if failed {
+				p.Status = StatusFailed
+				return
+			}
+			pools := s.state.NodePools[t.ClusterId]
+			s.state.NodePools[t.ClusterId] = append(pools[:i], pools[i+1:]...)
+		}
+	case actionTenantDelete:
+		if i, tenant := s.state.findTenant(id); tenant != nil && !failed {
+			s.state.Tenants = append(s.state.Tenants[:i], s.state.Tenants[i+1:]...)
+		}
+	case actionInstantiate, actionTerminate, actionScale:
+		if _, instance := s.state.findInstance(id); instance != nil {
+			instance.Updated = s.now()
+			if failed {
+				instance.LcmOperationState = LcmStateFailedTemp
+				return
+			}
+			instance.LcmOperationState = LcmStateCompleted
+			switch t.Action {
+			case actionInstantiate:
+				instance.InstantiationState = "INSTANTIATED"
+			case actionTerminate:
+				instance.InstantiationState = "NOT_INSTANTIATED"
+			}
+		}
+	}
+}
+
+// findTask return task by task id
+func (s *Server) findTask(id string) *Task {
+	for _, t := range s.state.Tasks {
+		if t.Item.TaskId == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// InjectFailure next task started for entity id fails,
+// if entity has running tasks they fail as well.
+func (s *Server) InjectFailure(entityId string) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, t := range s.state.Tasks {
+		if t.Item.EntityDetails.Id == entityId && !t.IsFinished() {
+			t.Fail = true
+			return
+		}
+	}
+
+	s.failures[entityId] = true
+}
+
+// tasksQuery handles task query by entity ids
+func (s *Server) tasksQuery(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	var q specs.ClusterTaskQuery
+	if !readJSON(w, r, &q) {
+		return
+	}
+
+	ids := make(map[string]bool)
+	for _, id := range q.Filter.EntityIds {
+		ids[id] = true
+	}
+
+	var resp models.ClusterTask
+	resp.Items = make([]models.TaskItems, 0)
+	for _, t := range s.state.Tasks {
+		if len(ids) == 0 || ids[t.Item.EntityDetails.Id] || ids[t.Item.TaskId] {
+			resp.Items = append(resp.Items, t.Item)
+			if t.RetryLag > 0 {
+				t.RetryLag--
+				if t.RetryLag == 0 {
+					s.restartTask(t)
+				}
+			}
+		}
+	}
+
+	resp.Paging.PageSize = len(resp.Items)
+	resp.Paging.TotalSize = len(resp.Items)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// clusterTasks handles list of task for a cluster
+func (s *Server) clusterTasks(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	if _, c := s.state.findCluster(args[0]); c == nil {
+		notFound(w, "cluster", args[0])
+		return
+	}
+
+	var resp models.ClusterTask
+	resp.Items = make([]models.TaskItems, 0)
+	for _, t := range s.state.Tasks {
+		if t.ClusterId == args[0] || t.Item.EntityDetails.Id == args[0] {
+			resp.Items = append(resp.Items, t.Item)
+		}
+	}
+
+	resp.Paging.PageSize = len(resp.Items)
+	resp.Paging.TotalSize = len(resp.Items)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// instanceOperations handles list of LCM operation occurrences
+// of an instance, latest first.
+func (s *Server) instanceOperations(w http.ResponseWriter, r *http.Request, args []string) {
+
+	if _, instance := s.state.findInstance(args[0]); instance == nil {
+		notFound(w, "vnf instance", args[0])
+		return
+	}
+
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize <= 0 {
+		pageSize = len(s.state.Tasks)
+	}
+
+	var resp models.ClusterTask
+	resp.Items = make([]models.TaskItems, 0)
+	for i := len(s.state.Tasks) - 1; i >= 0; i-- {
+		t := s.state.Tasks[i]
+		if t.Item.EntityDetails.Id != args[0] {
+			continue
+		}
+		resp.Paging.TotalSize++
+		if len(resp.Items) < pageSize {
+			resp.Items = append(resp.Items, t.Item)
+		}
+	}
+
+	resp.Paging.PageSize = pageSize
+	resp.Paging.PageNumber = 1
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// retryTask handles retry of failed operation
+func (s *Server) retryTask(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	t := s.findTask(args[0])
+	if t == nil {
+		notFound(w, "operation", args[0])
+		return
+	}
+
+	if t.Item.Status != TaskStatusFailed {
+		writeError(w, http.StatusBadRequest, "Bad Request", "only failed operation can be retried")
+		return
+	}
+
+	t.Fail = s.failures[t.Item.EntityDetails.Id]
+	delete(s.failures, t.Item.EntityDetails.Id)
+	if s.opts.RetryLag > 0 {
+		t.RetryLag = s.opts.RetryLag
+	} else {
+		s.restartTask(t)
+	}
+
+	writeJSON(w, http.StatusOK, models.TcaTask{Id: t.Item.EntityDetails.Id, OperationId: t.Item.TaskId})
+}
+
+// abortTask handles abort of running operation
+func (s *Server) abortTask(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	t := s.findTask(args[0])
+	if t == nil {
+		notFound(w, "operation", args[0])
+		return
+	}
+
+	if t.IsFinished() {
+		writeError(w, http.StatusBadRequest, "Bad Request", "operation already finished")
+		return
+	}
+
+	now := toMillis(s.now())
+	for i := range t.Item.Steps {
+		if t.Item.Steps[i].Status != TaskStatusSuccess {
+			t.Item.Steps[i].Status = TaskStatusFailed
+			t.Item.Steps[i].EndTime = now
+		}
+	}
+
+	t.Item.Status = TaskStatusFailed
+	t.Item.Message = "Operation aborted"
+	t.Item.EndTime = now
+	s.applyTask(t)
+
+	writeJSON(w, http.StatusOK, models.TcaTask{Id: t.Item.EntityDetails.Id, OperationId: t.Item.TaskId})
+}
+
+// markPending sets entity status to in progress status of a task action
+func (s *Server) markPending(t *Task) {
+
+	id := t.Item.EntityDetails.Id
+	switch t.Action {
+	case actionClusterCreate, actionClusterDelete, actionClusterUpgrade:
+		if _, c := s.state.findCluster(id); c != nil {
+			c.Status = map[string]string{
+				actionClusterCreate:  StatusCreating,
+				actionClusterDelete:  StatusDeleting,
+				actionClusterUpgrade: StatusUpgrading,
+			}[t.Action]
+		}
+	case actionPoolCreate, actionPoolUpdate, actionPoolDelete, actionPoolUpgrade:
+		if _, p := s.state.findPool(t.ClusterId, id); p != nil {
+			p.Status = map[string]string{
+				actionPoolCreate:  StatusCreating,
+				actionPoolUpdate:  StatusUpdating,
+				actionPoolDelete:  StatusDeleting,
+				actionPoolUpgrade: StatusUpgrading,
+			}[t.Action]
+		}
+	case actionInstantiate, actionTerminate, actionScale:
+		if _, instance := s.state.findInstance(id); instance != nil {
+			instance.LcmOperation = t.Action
+			instance.LcmOperationState = LcmStateProcessing
+		}
+	}
+}
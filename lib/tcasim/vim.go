@@ -0,0 +1,286 @@
+// Package tcasim
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package tcasim
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/spyroot/tcactl/lib/client/response"
+	"github.com/spyroot/tcactl/lib/client/specs"
+	"github.com/spyroot/tcactl/lib/models"
+	"net/http"
+	"strings"
+)
+
+const (
+	pathTenants = "/hybridity/api/vims/v1/tenants"
+
+	entityVim = "Vim"
+
+	vimTypeVC = "VC"
+)
+
+// registerVim registers vim, tenants, inventory and consumption api
+func (s *Server) registerVim() {
+
+	s.handle(http.MethodGet, pathTenants, s.getTenants)
+	s.handle(http.MethodPost, pathTenants, s.postTenants)
+	s.handle(http.MethodDelete, pathTenants+"/"+reId, s.deleteTenant)
+	s.handle(http.MethodGet, "/hybridity/api/vims/v1/"+reId+"/tenants", s.getVim)
+
+	s.handle(http.MethodPost, "/hybridity/api/infra/inventory/vc/clusters", s.getVcClusters)
+	s.handle(http.MethodPost, "/hybridity/api/infra/inventory/vc/templates", s.getVcTemplates)
+	s.handle(http.MethodPost, "/hybridity/api/nfv/networks", s.queryNetworks)
+	s.handle(http.MethodGet, "/hybridity/api/nfv/networks/"+reId, s.getNetworks)
+	s.handle(http.MethodPost, "/hybridity/api/service/inventory/containers", s.getContainers)
+	s.handle(http.MethodGet, "/api/service/inventory/containers", s.getContainerView)
+
+	s.handle(http.MethodGet, "/hybridity/api/licensing/consumption", s.getConsumption)
+}
+
+func (s *Server) getTenants(w http.ResponseWriter, _ *http.Request, _ []string) {
+	tenants := response.Tenants{TenantsList: s.state.Tenants}
+	if tenants.TenantsList == nil {
+		tenants.TenantsList = []response.TenantsDetails{}
+	}
+	writeJSON(w, http.StatusOK, tenants)
+}
+
+// postTenants either query tenants or registers a new cloud provider
+func (s *Server) postTenants(w http.ResponseWriter, r *http.Request, args []string) {
+
+	if r.URL.Query().Get("action") == "query" {
+		var f specs.TenantsNfFilter
+		if !readJSON(w, r, &f) {
+			return
+		}
+		s.getTenants(w, r, args)
+		return
+	}
+
+	var spec specs.SpecCloudProvider
+	if !readJSON(w, r, &spec) {
+		return
+	}
+
+	if len(spec.HcxCloudUrl) == 0 || len(spec.VimName) == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "hcxCloudUrl and vimName are mandatory")
+		return
+	}
+
+	if _, t := s.state.findTenant(spec.VimName); t != nil {
+		writeError(w, http.StatusConflict, "Conflict",
+			fmt.Sprintf("cloud provider with name %s already exists", spec.VimName))
+		return
+	}
+
+	tenantName := spec.TenantName
+	if len(tenantName) == 0 {
+		tenantName = "DEFAULT"
+	}
+
+	now := s.now().UTC()
+	tenantId := strings.ToUpper(strings.ReplaceAll(newId(), "-", ""))
+	tenant := response.TenantsDetails{
+		TenantID:    tenantId,
+		VimName:     spec.VimName,
+		TenantName:  tenantName,
+		HcxCloudURL: spec.HcxCloudUrl,
+		Username:    spec.Username,
+		VimType:     vimTypeVC,
+		VimURL:      strings.Replace(spec.HcxCloudUrl, "-cp", "-vc", 1),
+		HcxUUID:     fmt.Sprintf("%s%03d-%s", now.Format("20060102150405"), now.Nanosecond()/1e6, uuid.New().String()),
+		HcxTenantID: tenantId,
+		VimID:       "vmware_" + tenantId,
+		ID:          "vmware_" + tenantId,
+		Name:        spec.VimName,
+		Compatible:  true,
+		VimConn: &models.VimConnection{
+			Status:              "ok",
+			RemoteStatus:        "ok",
+			VimConnectionStatus: "ok",
+		},
+		HasSupportedKubernetesVersion: true,
+	}
+	tenant.Audit.CreationUser = s.opts.Username
+	tenant.Audit.CreationTimestamp = now.Format("2006-01-02T15:04:05.000Z")
+
+	s.state.Tenants = append(s.state.Tenants, tenant)
+
+	var resp models.RegistrationRespond
+	_ = convert(tenant, &resp)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// registerClusterVim registers k8s cluster as kubernetes vim,
+// the way TCA does when cluster created.
+func (s *Server) registerClusterVim(c *response.ClusterSpec) {
+
+	var hcxCloudUrl string
+	for _, t := range s.state.Tenants {
+		if t.HcxUUID == c.HcxUUID {
+			hcxCloudUrl = t.HcxCloudURL
+		}
+	}
+
+	tenantId := strings.ToUpper(strings.ReplaceAll(newId(), "-", ""))
+	s.state.Tenants = append(s.state.Tenants, response.TenantsDetails{
+		TenantID:    tenantId,
+		VimName:     c.ClusterName,
+		TenantName:  c.ClusterName,
+		HcxCloudURL: hcxCloudUrl,
+		VimType:     models.VimTypeKubernetes,
+		VimURL:      c.ClusterUrl,
+		HcxUUID:     c.HcxUUID,
+		HcxTenantID: tenantId,
+		VimID:       "vmware_" + tenantId,
+		ID:          "vmware_" + tenantId,
+		Name:        c.ClusterName,
+		ClusterName: c.ClusterName,
+		Compatible:  true,
+		VimConn: &models.VimConnection{
+			Status:              "ok",
+			RemoteStatus:        "ok",
+			VimConnectionStatus: "ok",
+		},
+		HasSupportedKubernetesVersion: true,
+		ClusterStatus:                 c.Status,
+	})
+}
+
+func (s *Server) deleteTenant(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, t := s.state.findTenant(args[0])
+	if t == nil {
+		notFound(w, "tenant", args[0])
+		return
+	}
+
+	task := s.startTask(actionTenantDelete, entityVim, t.TenantID, t.Name, "", nil)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) getVim(w http.ResponseWriter, _ *http.Request, args []string) {
+
+	_, t := s.state.findTenant(args[0])
+	if t == nil {
+		notFound(w, "vim", args[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response.TenantSpecs{
+		VimId:   t.VimID,
+		VimName: t.VimName,
+		Tenants: []response.TenantsDetails{*t},
+	})
+}
+
+// inventoryQuery generic inventory query, all inventory
+// queries filter by cloud endpoint id or tenant id.
+type inventoryQuery struct {
+	Filter struct {
+		EntityTypes []string           `json:"entityTypes"`
+		Cloud       *specs.FilterCloud `json:"cloud"`
+		TenantId    string             `json:"tenantId"`
+		ClusterId   string             `json:"clusterId"`
+	} `json:"filter"`
+}
+
+// inventory decodes inventory query and returns inventory
+func (s *Server) inventory(w http.ResponseWriter, r *http.Request) (*inventoryQuery, *Inventory, bool) {
+
+	var q inventoryQuery
+	if !readJSON(w, r, &q) {
+		return nil, nil, false
+	}
+
+	id := q.Filter.TenantId
+	if q.Filter.Cloud != nil {
+		id = q.Filter.Cloud.EndpointId
+	}
+
+	inv := s.state.findInventory(id)
+	if inv == nil {
+		inv = &Inventory{}
+	}
+
+	return &q, inv, true
+}
+
+func (s *Server) getVcClusters(w http.ResponseWriter, r *http.Request, _ []string) {
+	if _, inv, ok := s.inventory(w, r); ok {
+		writeJSON(w, http.StatusOK, inv.Clusters)
+	}
+}
+
+func (s *Server) getVcTemplates(w http.ResponseWriter, r *http.Request, _ []string) {
+	if _, inv, ok := s.inventory(w, r); ok {
+		writeJSON(w, http.StatusOK, inv.VmTemplates)
+	}
+}
+
+func (s *Server) queryNetworks(w http.ResponseWriter, r *http.Request, _ []string) {
+	if _, inv, ok := s.inventory(w, r); ok {
+		writeJSON(w, http.StatusOK, models.CloudNetworks{Network: inv.Networks})
+	}
+}
+
+func (s *Server) getNetworks(w http.ResponseWriter, _ *http.Request, args []string) {
+	inv := s.state.findInventory(args[0])
+	if inv == nil {
+		inv = &Inventory{}
+	}
+	writeJSON(w, http.StatusOK, models.CloudNetworks{Network: inv.Networks})
+}
+
+// getContainers returns folders or resource pools
+func (s *Server) getContainers(w http.ResponseWriter, r *http.Request, _ []string) {
+
+	q, inv, ok := s.inventory(w, r)
+	if !ok {
+		return
+	}
+
+	for _, t := range q.Filter.EntityTypes {
+		if t == models.EntityTypeResourcePool {
+			writeJSON(w, http.StatusOK, inv.ResourcePools)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, inv.Folders)
+}
+
+// getContainerView returns container view of all cloud providers
+func (s *Server) getContainerView(w http.ResponseWriter, _ *http.Request, _ []string) {
+
+	var view models.VmwareContainerView
+	view.Success = true
+	view.Completed = true
+	for _, inv := range s.state.Inventory {
+		var pools models.VmwareContainerView
+		if err := convert(inv.ResourcePools, &pools); err == nil {
+			view.Data.Items = append(view.Data.Items, pools.Data.Items...)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) getConsumption(w http.ResponseWriter, _ *http.Request, _ []string) {
+	writeJSON(w, http.StatusOK, s.state.Consumption)
+}
@@ -0,0 +1,252 @@
+// Package io
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//
+// Mustafa mbayramo@vmware.com
+
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// XmlItemElement element name of a list item that is not an object field
+	XmlItemElement = "item"
+
+	// XmlItemsElement root element name of a list
+	XmlItemsElement = "items"
+
+	// XmlEntryElement element name of a field that isn't valid xml name,
+	// field name stored in key attribute.
+	XmlEntryElement = "entry"
+
+	// XmlKeyAttr attribute that holds a field name of entry element
+	XmlKeyAttr = "key"
+)
+
+// xmlName valid xml element name
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// xmlStart returns start element for a field name
+func xmlStart(name string) xml.StartElement {
+	if xmlName.MatchString(name) && !strings.HasPrefix(strings.ToLower(name), "xml") {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: XmlEntryElement},
+		Attr: []xml.Attr{{Name: xml.Name{Local: XmlKeyAttr}, Value: name}},
+	}
+}
+
+// jsonToXml converts next json value to xml element name. Object fields
+// became child elements in order of json fields, list that is a value
+// of an object field became repeated elements, any other list
+// became element with item child elements. Null values are omitted.
+func jsonToXml(dec *json.Decoder, enc *xml.Encoder, name string, isField bool) error {
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			start := xmlStart(name)
+			if err := enc.EncodeToken(start); err != nil {
+				return err
+			}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return fmt.Errorf("unexpected json token %v", keyTok)
+				}
+				if err := jsonToXml(dec, enc, key, true); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			return enc.EncodeToken(start.End())
+		case '[':
+			if isField {
+				for dec.More() {
+					if err := jsonToXml(dec, enc, name, false); err != nil {
+						return err
+					}
+				}
+				_, err := dec.Token()
+				return err
+			}
+			start := xmlStart(name)
+			if err := enc.EncodeToken(start); err != nil {
+				return err
+			}
+			for dec.More() {
+				if err := jsonToXml(dec, enc, XmlItemElement, false); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			return enc.EncodeToken(start.End())
+		}
+	case nil:
+		return nil
+	}
+
+	start := xmlStart(name)
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(xml.CharData(fmt.Sprint(tok))); err != nil {
+		return err
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// XmlMarshalIndent encodes any json serializable value as xml document
+// with a given root element. Element names are json field names.
+func XmlMarshalIndent(v interface{}, root string, indent string) ([]byte, error) {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", indent)
+
+	if err := jsonToXml(dec, enc, root, false); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// xmlElement a decoded xml element
+type xmlElement struct {
+	name     string
+	text     strings.Builder
+	children []*xmlElement
+}
+
+// value converts element to a generic value, element without child
+// elements is a string, otherwise a map, repeated child elements
+// became a list.
+func (e *xmlElement) value() interface{} {
+
+	if len(e.children) == 0 {
+		return strings.TrimSpace(e.text.String())
+	}
+
+	count := map[string]int{}
+	for _, c := range e.children {
+		count[c.name]++
+	}
+
+	m := map[string]interface{}{}
+	for _, c := range e.children {
+		if count[c.name] == 1 {
+			m[c.name] = c.value()
+			continue
+		}
+		l, _ := m[c.name].([]interface{})
+		m[c.name] = append(l, c.value())
+	}
+
+	return m
+}
+
+// XmlUnmarshal decodes xml document to a generic value, the root element
+// name is returned and the root value is either a string or map
+// where repeated elements are lists.
+func XmlUnmarshal(r io.Reader) (string, interface{}, error) {
+
+	dec := xml.NewDecoder(r)
+
+	var (
+		stack []*xmlElement
+		root  *xmlElement
+	)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &xmlElement{name: t.Name.Local}
+			if e.name == XmlEntryElement {
+				for _, a := range t.Attr {
+					if a.Name.Local == XmlKeyAttr {
+						e.name = a.Value
+					}
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			} else if root != nil {
+				return "", nil, fmt.Errorf("xml document must have a single root element")
+			} else {
+				root = e
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return "", nil, fmt.Errorf("empty xml document")
+	}
+
+	return root.name, root.value(), nil
+}
@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"github.com/golang/glog"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -173,3 +175,77 @@ func ZipDir(source, target string) error {
 
 	return err
 }
+
+// ZipFiles writes files to target zip file, a key of
+// a map is a file path inside an archive.
+func ZipFiles(files map[string][]byte, target string) error {
+
+	zipFile, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	archive := zip.NewWriter(zipFile)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:   filepath.ToSlash(name),
+			Method: zip.Deflate,
+		})
+		if err != nil {
+			archive.Close()
+			return err
+		}
+		if _, err = writer.Write(files[name]); err != nil {
+			archive.Close()
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// UnzipFiles reads all files of zip archive to memory,
+// a key of a returned map is a file path inside an archive.
+func UnzipFiles(src string) (map[string][]byte, error) {
+
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(f.Name))
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("%s is an illegal filepath", f.Name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files[name] = b
+	}
+
+	return files, nil
+}
@@ -0,0 +1,288 @@
+// Package netutils
+// Copyright 2020-2021 Author.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Mustafa mbayramo@vmware.com
+package netutils
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TransportSpec TLS and proxy settings of a connection
+// to TCA, Harbor or vCenter.
+type TransportSpec struct {
+
+	// Proxy http, https or socks5 proxy url, if empty
+	// HTTPS_PROXY / NO_PROXY environment used.
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty" mapstructure:"proxy"`
+
+	// CaFile PEM bundle of CA certificates trusted in addition to system pool
+	CaFile string `json:"ca-file,omitempty" yaml:"ca-file,omitempty" mapstructure:"ca-file"`
+
+	// CertFile PEM client certificate for mutual TLS
+	CertFile string `json:"cert-file,omitempty" yaml:"cert-file,omitempty" mapstructure:"cert-file"`
+
+	// KeyFile PEM client certificate key for mutual TLS
+	KeyFile string `json:"key-file,omitempty" yaml:"key-file,omitempty" mapstructure:"key-file"`
+
+	// PinSha256 SHA-256 fingerprints of server certificates, if set
+	// connection accepted only if server presents pinned certificate.
+	PinSha256 []string `json:"pin-sha256,omitempty" yaml:"pin-sha256,omitempty" mapstructure:"pin-sha256"`
+
+	// TlsMinVersion min TLS version 1.0, 1.1, 1.2 or 1.3, default 1.2
+	TlsMinVersion string `json:"tls-min-version,omitempty" yaml:"tls-min-version,omitempty" mapstructure:"tls-min-version"`
+
+	// Insecure skips server certificate verification,
+	// ignored if CaFile or PinSha256 set.
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty" mapstructure:"insecure"`
+}
+
+// tlsVersions supported min TLS versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTlsVersion parse TLS version 1.0 - 1.3, empty string is TLS 1.2
+func ParseTlsVersion(v string) (uint16, error) {
+
+	v = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "tls")
+	if len(v) == 0 {
+		return tls.VersionTLS12, nil
+	}
+
+	ver, ok := tlsVersions[v]
+	if !ok {
+		return 0, fmt.Errorf("unsupported tls version %s, supported 1.0, 1.1, 1.2, 1.3", v)
+	}
+
+	return ver, nil
+}
+
+// Fingerprint returns hex encoded SHA-256 fingerprint of DER certificate
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizePin accepts fingerprint in hex with optional colons, i.e.
+// output of openssl x509 -fingerprint -sha256
+func normalizePin(pin string) (string, error) {
+
+	p := strings.ToLower(strings.TrimSpace(pin))
+	p = strings.TrimPrefix(p, "sha256:")
+	p = strings.ReplaceAll(p, ":", "")
+
+	if b, err := hex.DecodeString(p); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid sha256 fingerprint %s", pin)
+	}
+
+	return p, nil
+}
+
+// verifyPins returns function that accepts a chain only if it
+// leads to a pinned certificate. If chain verified by TLS, pin
+// checked against verified chains. Otherwise server certificate
+// must be pinned, or signed by the next certificate in a chain
+// up to a pinned one, so a pinned certificate appended to a
+// foreign certificate is rejected.
+func verifyPins(pins []string) (func([][]byte, [][]*x509.Certificate) error, error) {
+
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		p, err := normalizePin(pin)
+		if err != nil {
+			return nil, err
+		}
+		pinned[p] = true
+	}
+
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("server presented no certificate")
+		}
+		mismatch := fmt.Errorf("server certificate %s doesn't match pinned fingerprint", Fingerprint(rawCerts[0]))
+
+		if len(verifiedChains) > 0 {
+			for _, chain := range verifiedChains {
+				for _, cert := range chain {
+					if pinned[Fingerprint(cert.Raw)] {
+						return nil
+					}
+				}
+			}
+			return mismatch
+		}
+
+		var child *x509.Certificate
+		for _, der := range rawCerts {
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return fmt.Errorf("failed parse server certificate: %v", err)
+			}
+			if child != nil {
+				if err := child.CheckSignatureFrom(cert); err != nil {
+					return mismatch
+				}
+			}
+			if pinned[Fingerprint(der)] {
+				return nil
+			}
+			child = cert
+		}
+
+		return mismatch
+	}, nil
+}
+
+// TLSConfig returns tls config for a spec, nil spec
+// returns config with default verification.
+func (s *TransportSpec) TLSConfig() (*tls.Config, error) {
+
+	if s == nil {
+		return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+	}
+
+	minVer, err := ParseTlsVersion(s.TlsMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{MinVersion: minVer}
+
+	if len(s.CertFile) > 0 || len(s.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed load client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(s.CaFile) > 0 {
+		pem, err := ioutil.ReadFile(s.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed read ca bundle: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca bundle %s contains no PEM certificates", s.CaFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	// with CA bundle chain verified as usual and pin checked on top.
+	// Without it, pinned certificate is trusted on its own, so
+	// appliance self-signed certificate works.
+	if len(s.PinSha256) > 0 {
+		verify, err := verifyPins(s.PinSha256)
+		if err != nil {
+			return nil, err
+		}
+		cfg.InsecureSkipVerify = len(s.CaFile) == 0
+		cfg.VerifyPeerCertificate = verify
+		return cfg, nil
+	}
+
+	cfg.InsecureSkipVerify = s.Insecure && len(s.CaFile) == 0
+	return cfg, nil
+}
+
+// ProxyFunc returns proxy function for a spec, http, https and
+// socks5 proxies supported. Empty proxy uses environment.
+func (s *TransportSpec) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+
+	if s == nil || len(s.Proxy) == 0 {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %v", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %s, supported http, https, socks5", u.Scheme)
+	}
+	if len(u.Host) == 0 {
+		return nil, fmt.Errorf("proxy url %s has no host", s.Proxy)
+	}
+
+	return http.ProxyURL(u), nil
+}
+
+// ApplyTo applies spec to existing transport, so caller
+// can configure transport created by a third party client.
+func (s *TransportSpec) ApplyTo(tr *http.Transport) error {
+
+	if tr == nil {
+		return fmt.Errorf("transport is nil")
+	}
+
+	cfg, err := s.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	proxy, err := s.ProxyFunc()
+	if err != nil {
+		return err
+	}
+
+	tr.TLSClientConfig = cfg
+	tr.Proxy = proxy
+
+	return nil
+}
+
+// NewTransport returns http transport for a spec,
+// nil spec returns transport with default settings.
+func NewTransport(spec *TransportSpec) (*http.Transport, error) {
+
+	tr := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if err := spec.ApplyTo(tr); err != nil {
+		return nil, err
+	}
+
+	return tr, nil
+}
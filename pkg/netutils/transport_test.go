@@ -0,0 +1,233 @@
+package netutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePem writes PEM block to a file in dir
+func writePem(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	f := filepath.Join(dir, name)
+	err := ioutil.WriteFile(f, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	assert.NoError(t, err)
+	return f
+}
+
+// newClientCert writes self-signed client certificate and key,
+// returns cert file, key file and certificate.
+func newClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tcactl"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return writePem(t, dir, "client.pem", "CERTIFICATE", der),
+		writePem(t, dir, "client.key", "EC PRIVATE KEY", keyDer), cert
+}
+
+// newServerCert returns server certificate and key signed by parent,
+// self-signed if parent is nil.
+func newServerCert(t *testing.T, cn string, isCa bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  isCa,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert, key
+}
+
+// newChainServer starts TLS server presenting chain, first
+// certificate must match the key.
+func newChainServer(key *ecdsa.PrivateKey, chain ...*x509.Certificate) *httptest.Server {
+
+	tlsCert := tls.Certificate{PrivateKey: key}
+	for _, cert := range chain {
+		tlsCert.Certificate = append(tlsCert.Certificate, cert.Raw)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{tlsCert}}
+	server.StartTLS()
+	return server
+}
+
+// get issues GET request with transport built from a spec
+func get(spec *TransportSpec, url string) error {
+
+	tr, err := NewTransport(spec)
+	if err != nil {
+		return err
+	}
+	defer tr.CloseIdleConnections()
+
+	resp, err := (&http.Client{Transport: tr}).Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestNewTransport(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := writePem(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	pin := Fingerprint(server.Certificate().Raw)
+
+	tests := []struct {
+		name    string
+		spec    *TransportSpec
+		wantErr bool
+	}{
+		{"default verification", &TransportSpec{}, true},
+		{"insecure", &TransportSpec{Insecure: true}, false},
+		{"ca bundle", &TransportSpec{CaFile: caFile}, false},
+		{"pin", &TransportSpec{PinSha256: []string{pin}}, false},
+		{"pin with colons", &TransportSpec{PinSha256: []string{strings.ToUpper(pin[:2] + ":" + pin[2:])}}, false},
+		{"pin mismatch", &TransportSpec{PinSha256: []string{strings.Repeat("0", 64)}}, true},
+		{"pin mismatch insecure", &TransportSpec{PinSha256: []string{strings.Repeat("0", 64)}, Insecure: true}, true},
+		{"invalid pin", &TransportSpec{PinSha256: []string{"abc"}}, true},
+		{"ca bundle and pin", &TransportSpec{CaFile: caFile, PinSha256: []string{pin}}, false},
+		{"ca bundle and pin mismatch", &TransportSpec{CaFile: caFile, PinSha256: []string{strings.Repeat("0", 64)}}, true},
+		{"missing ca", &TransportSpec{CaFile: filepath.Join(dir, "none.pem")}, true},
+		{"invalid tls version", &TransportSpec{Insecure: true, TlsMinVersion: "2.0"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := get(tt.spec, server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTransport_MutualTls(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tcactl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile, cert := newClientCert(t, dir)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	assert.Error(t, get(&TransportSpec{Insecure: true}, server.URL), "server must reject client without cert")
+	assert.NoError(t, get(&TransportSpec{Insecure: true, CertFile: certFile, KeyFile: keyFile}, server.URL))
+
+	assert.Error(t, get(&TransportSpec{Insecure: true, CertFile: certFile}, server.URL), "key is required")
+}
+
+func TestNewTransport_PinChain(t *testing.T) {
+
+	ca, caKey := newServerCert(t, "ca", true, nil, nil)
+	leaf, leafKey := newServerCert(t, "leaf", false, ca, caKey)
+	foreign, foreignKey := newServerCert(t, "foreign", false, nil, nil)
+
+	server := newChainServer(leafKey, leaf, ca)
+	defer server.Close()
+
+	assert.NoError(t, get(&TransportSpec{PinSha256: []string{Fingerprint(leaf.Raw)}}, server.URL))
+	assert.NoError(t, get(&TransportSpec{PinSha256: []string{Fingerprint(ca.Raw)}}, server.URL),
+		"leaf signed by pinned ca must be accepted")
+
+	forged := newChainServer(foreignKey, foreign, leaf, ca)
+	defer forged.Close()
+
+	assert.Error(t, get(&TransportSpec{PinSha256: []string{Fingerprint(leaf.Raw)}}, forged.URL),
+		"pinned certificate appended to a foreign leaf must be rejected")
+	assert.Error(t, get(&TransportSpec{PinSha256: []string{Fingerprint(ca.Raw)}}, forged.URL),
+		"pinned ca appended to a foreign leaf must be rejected")
+}
+
+func TestNewTransport_MinVersion(t *testing.T) {
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	assert.NoError(t, get(&TransportSpec{Insecure: true, TlsMinVersion: "1.2"}, server.URL))
+	assert.Error(t, get(&TransportSpec{Insecure: true, TlsMinVersion: "tls1.3"}, server.URL))
+}
+
+func TestTransportSpec_ProxyFunc(t *testing.T) {
+
+	req, _ := http.NewRequest(http.MethodGet, "https://tca.example.com", nil)
+
+	for _, p := range []string{"http://proxy:3128", "https://proxy:3128", "socks5://proxy:1080"} {
+		proxy, err := (&TransportSpec{Proxy: p}).ProxyFunc()
+		if assert.NoError(t, err) {
+			u, err := proxy(req)
+			assert.NoError(t, err)
+			assert.Equal(t, p, u.String())
+		}
+	}
+
+	_, err := (&TransportSpec{Proxy: "ftp://proxy"}).ProxyFunc()
+	assert.Error(t, err)
+
+	_, err = (&TransportSpec{Proxy: "http://"}).ProxyFunc()
+	assert.Error(t, err)
+}
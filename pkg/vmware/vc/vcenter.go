@@ -23,8 +23,11 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"github.com/spyroot/tcactl/pkg/netutils"
 	"github.com/spyroot/tcactl/pkg/os"
 	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
 	"net/url"
 )
@@ -63,8 +66,17 @@ func StringOrEnv(str string, envName string) (string, error) {
 	return "", fmt.Errorf("empty %s string", envName)
 }
 
-// Connect Function open connection to vCenter or ESXi.
+// Connect Function open connection to vCenter or ESXi,
+// server certificate is not verified.
 func Connect(ctx context.Context, hostname string, username string, password string) (*govmomi.Client, error) {
+	return ConnectWithTransport(ctx, hostname, username, password, &netutils.TransportSpec{Insecure: true})
+}
+
+// ConnectWithTransport open connection to vCenter or ESXi, spec holds
+// proxy, CA bundle, client certificate and pinned fingerprints.
+// nil spec skips server certificate verification.
+func ConnectWithTransport(ctx context.Context, hostname string, username string,
+	password string, spec *netutils.TransportSpec) (*govmomi.Client, error) {
 
 	flag.Parse()
 	var vcUrl *url.URL
@@ -97,13 +109,31 @@ func Connect(ctx context.Context, hostname string, username string, password str
 		return nil, errors.New("failed extract VC url https://vc_fqdn")
 	}
 
-	client, err := govmomi.NewClient(ctx, vcUrl, true)
+	if spec == nil {
+		spec = &netutils.TransportSpec{Insecure: true}
+	}
+
+	// same as govmomi.NewClient, but soap transport
+	// configured before first request.
+	soapClient := soap.NewClient(vcUrl, spec.Insecure)
+	if err := spec.ApplyTo(soapClient.DefaultTransport()); err != nil {
+		return nil, err
+	}
+
+	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return nil, err
 	}
 
-	if client == nil {
-		return nil, errors.New("nil vc client")
+	client := &govmomi.Client{
+		Client:         vimClient,
+		SessionManager: session.NewManager(vimClient),
+	}
+
+	if vcUrl.User != nil {
+		if err := client.Login(ctx, vcUrl.User); err != nil {
+			return nil, err
+		}
 	}
 
 	return client, nil
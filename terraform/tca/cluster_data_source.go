@@ -161,13 +161,13 @@ func dataSourceClusters() *schema.Resource {
 	}
 }
 
-func GetApi(m interface{}) (*api.TcaApi, error) {
+func GetApi(ctx context.Context, m interface{}) (*api.TcaApi, error) {
 	tca := m.(*api.TcaApi)
 	if tca == nil {
 		return nil, fmt.Errorf("nil instance")
 	}
 
-	_, err := tca.GetAuthorization()
+	_, err := tca.GetAuthorization(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +183,7 @@ func dataSourceClusterRead(ctx context.Context, d *schema.ResourceData, m interf
 
 	var diags diag.Diagnostics
 
-	tca, err := GetApi(m)
+	tca, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -56,6 +56,18 @@ func New() *schema.Provider {
 				Sensitive:   false,
 				DefaultFunc: schema.EnvDefaultFunc("TCA_SSL_VERIFY", nil),
 			},
+			"tca_rate_limit": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Max number of TCA requests per second, 0 unlimited.",
+				DefaultFunc: schema.EnvDefaultFunc("TCA_RATE_LIMIT", 0.0),
+			},
+			"tca_max_in_flight": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Max number of concurrent TCA requests, 0 unlimited.",
+				DefaultFunc: schema.EnvDefaultFunc("TCA_MAX_IN_FLIGHT", 0),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"tca_templates": resourceClusterTemplate(),
@@ -107,5 +119,14 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diag.FromErr(err)
 	}
 
+	// terraform applies resources in parallel
+	limit := client.RateLimit{
+		Rate:        d.Get("tca_rate_limit").(float64),
+		MaxInFlight: d.Get("tca_max_in_flight").(int),
+	}
+	if !limit.IsUnlimited() {
+		c.SetRateLimiter(client.NewRateLimiter(limit, nil))
+	}
+
 	return c, diags
 }
@@ -61,7 +61,7 @@ func resourceClusterTemplate() *schema.Resource {
 
 func ClusterTemplateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	_, err := GetApi(m)
+	_, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -101,7 +101,7 @@ func ClusterTemplateCreate(ctx context.Context, d *schema.ResourceData, m interf
 // ClusterTemplateRead
 func ClusterTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	tca, err := GetApi(m)
+	tca, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -111,7 +111,7 @@ func ClusterTemplateRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	//	orderID := d.Id()
 
-	templates, err := tca.GetClusterTemplates()
+	templates, err := tca.GetClusterTemplates(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -126,7 +126,7 @@ func ClusterTemplateRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 //
 func ClusterTemplateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	_, err := GetApi(m)
+	_, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -177,7 +177,7 @@ func ClusterTemplateDelete(ctx context.Context, d *schema.ResourceData, m interf
 	var diags diag.Diagnostics
 
 	templateId := d.Id()
-	err := tca.DeleteTemplate(templateId)
+	err := tca.DeleteTemplate(ctx, templateId)
 	if err != nil {
 		return diag.FromErr(err)
 	}
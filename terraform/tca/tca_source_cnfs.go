@@ -73,13 +73,13 @@ func dataSourceCnfRead(ctx context.Context, d *schema.ResourceData, m interface{
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	tca, err := GetApi(m)
+	tca, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	log.Println("[INFO] Getting list of cnfs")
 
-	cnfs, err := tca.GetAllInstances()
+	cnfs, err := tca.GetAllInstances(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -176,7 +176,7 @@ func dataSourceOrderRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	var diags diag.Diagnostics
 
-	tca, err := GetApi(m)
+	tca, err := GetApi(ctx, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -192,7 +192,7 @@ func dataSourceOrderRead(ctx context.Context, d *schema.ResourceData, m interfac
 	//
 	//orderID, ok := d.Get("id").(string)
 	//
-	templates, err := tca.GetClusterTemplates()
+	templates, err := tca.GetClusterTemplates(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}